@@ -0,0 +1,187 @@
+// Package ahocorasick provides an Aho-Corasick automaton for finding many
+// patterns in a text in a single pass.
+package ahocorasick
+
+import (
+	"io"
+	"slices"
+	"sort"
+
+	"github.com/hanyangtay/go-datastructures/deque"
+)
+
+// readSize is the size of the chunks read by Match
+const readSize = 32 << 10
+
+type state struct {
+	// transitions of the trie in order of their byte
+	labels []byte
+	next   []int32
+
+	fail int32 // state of the longest proper suffix in the trie
+	dict int32 // nearest state along fail links where a pattern ends, or -1
+	out  []int // patterns ending here
+}
+
+// step returns the trie transition of s for c, or -1 if there is none
+func (s *state) step(c byte) int32 {
+	i := sort.Search(len(s.labels), func(i int) bool { return s.labels[i] >= c })
+	if i < len(s.labels) && s.labels[i] == c {
+		return s.next[i]
+	}
+	return -1
+}
+
+// Matcher finds all occurrences of a fixed set of patterns in a text in
+// O(n + k) time for k matches, independent of the number of patterns. It is
+// a trie of the patterns with failure links, which lead from every state to
+// the longest suffix of its path that also starts a pattern, so that the
+// text is never read twice. Transitions are stored sparsely, so memory is
+// proportional to the total length of the patterns.
+//
+// A Matcher is immutable and may be used concurrently.
+type Matcher struct {
+	states  []state
+	lengths []int // length of every pattern
+}
+
+// Match is an occurrence of a pattern
+type Match struct {
+	Pattern int   // index of the pattern
+	Offset  int64 // offset of the first byte of the occurrence in the text
+}
+
+// New returns a matcher for patterns. Empty patterns never match.
+// Time complexity: O(total length of the patterns)
+func New(patterns [][]byte) *Matcher {
+	m := &Matcher{states: []state{{dict: -1}}, lengths: make([]int, len(patterns))}
+
+	for p, pattern := range patterns {
+		m.lengths[p] = len(pattern)
+		if len(pattern) == 0 {
+			continue
+		}
+
+		s := int32(0)
+		for _, c := range pattern {
+			next := m.states[s].step(c)
+			if next < 0 {
+				next = int32(len(m.states))
+				m.states = append(m.states, state{dict: -1})
+
+				st := &m.states[s]
+				i := sort.Search(len(st.labels), func(i int) bool { return st.labels[i] >= c })
+				st.labels = slices.Insert(st.labels, i, c)
+				st.next = slices.Insert(st.next, i, next)
+			}
+			s = next
+		}
+		m.states[s].out = append(m.states[s].out, p)
+	}
+
+	// set failure links in breadth first order, so that the links of all
+	// shallower states are known. Children of the root fail to the root.
+	var queue deque.Deque[int32]
+	for _, s := range m.states[0].next {
+		queue.PushBack(s)
+	}
+	for queue.Len() > 0 {
+		s := queue.PopFront()
+
+		st := &m.states[s]
+		for i, c := range st.labels {
+			child := st.next[i]
+			queue.PushBack(child)
+
+			f := st.fail
+			for f != 0 && m.states[f].step(c) < 0 {
+				f = m.states[f].fail
+			}
+			if t := m.states[f].step(c); t >= 0 {
+				f = t
+			}
+
+			cs := &m.states[child]
+			cs.fail = f
+			if len(m.states[f].out) > 0 {
+				cs.dict = f
+			} else {
+				cs.dict = m.states[f].dict
+			}
+		}
+	}
+
+	return m
+}
+
+// Len returns the number of patterns
+func (m *Matcher) Len() int { return len(m.lengths) }
+
+// advance returns the state after reading c in state s
+func (m *Matcher) advance(s int32, c byte) int32 {
+	for {
+		if t := m.states[s].step(c); t >= 0 {
+			return t
+		}
+		if s == 0 {
+			return 0
+		}
+		s = m.states[s].fail
+	}
+}
+
+// scan feeds data, which starts at offset in the text, through the
+// automaton from state s. It returns the final state, and false if fn asked
+// to stop.
+func (m *Matcher) scan(s int32, data []byte, offset int64, fn func(Match) bool) (int32, bool) {
+	for i, c := range data {
+		s = m.advance(s, c)
+		end := offset + int64(i) + 1
+
+		for t := s; t >= 0; t = m.states[t].dict {
+			for _, p := range m.states[t].out {
+				if !fn(Match{Pattern: p, Offset: end - int64(m.lengths[p])}) {
+					return s, false
+				}
+			}
+		}
+	}
+	return s, true
+}
+
+// Match reads r to the end and calls fn for every occurrence of a pattern, in
+// order of the position where it ends, until fn returns false. Overlapping
+// occurrences are all reported. Input is read in chunks, so the text may
+// exceed memory. Errors from r other than io.EOF are returned.
+func (m *Matcher) Match(r io.Reader, fn func(Match) bool) error {
+	buf := make([]byte, readSize)
+	s, offset := int32(0), int64(0)
+	for {
+		n, err := r.Read(buf)
+
+		var more bool
+		s, more = m.scan(s, buf[:n], offset, fn)
+		if !more {
+			return nil
+		}
+		offset += int64(n)
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// FindAll returns all occurrences of the patterns in text, in order of the
+// position where they end
+func (m *Matcher) FindAll(text []byte) []Match {
+	var matches []Match
+	m.scan(0, text, 0, func(match Match) bool {
+		matches = append(matches, match)
+		return true
+	})
+	return matches
+}
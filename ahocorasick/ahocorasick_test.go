@@ -0,0 +1,97 @@
+package ahocorasick
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+)
+
+// naiveMatches returns the set of occurrences of patterns in text, found by
+// comparing every pattern at every offset
+func naiveMatches(patterns [][]byte, text []byte) map[Match]bool {
+	matches := make(map[Match]bool)
+	for i, p := range patterns {
+		if len(p) == 0 {
+			continue
+		}
+		for j := 0; j+len(p) <= len(text); j++ {
+			if bytes.Equal(text[j:j+len(p)], p) {
+				matches[Match{Pattern: i, Offset: int64(j)}] = true
+			}
+		}
+	}
+	return matches
+}
+
+func TestFindAll(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) []byte {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "abc"[rng.Intn(3)]
+		}
+		return b
+	}
+
+	var patterns [][]byte
+	for i := 0; i < 30; i++ {
+		patterns = append(patterns, random(rng.Intn(5)))
+	}
+	m := New(patterns)
+	if m.Len() != len(patterns) {
+		t.Fatalf("got %d patterns, want %d", m.Len(), len(patterns))
+	}
+
+	text := random(2000)
+	want := naiveMatches(patterns, text)
+	got := m.FindAll(text)
+	if len(got) != len(want) {
+		t.Fatalf("found %d matches, want %d", len(got), len(want))
+	}
+
+	end := int64(-1)
+	for _, match := range got {
+		if !want[match] {
+			t.Fatalf("found %+v, which does not occur", match)
+		}
+		e := match.Offset + int64(len(patterns[match.Pattern]))
+		if e < end {
+			t.Fatalf("match ending at %d after one ending at %d", e, end)
+		}
+		end = e
+	}
+}
+
+func TestMatchReader(t *testing.T) {
+	patterns := [][]byte{[]byte("he"), []byte("she"), []byte("his"), []byte("hers")}
+	m := New(patterns)
+	text := bytes.Repeat([]byte("ushers "), 5000)
+
+	var got []Match
+	err := m.Match(iotest.HalfReader(bytes.NewReader(text)), func(match Match) bool {
+		got = append(got, match)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := m.FindAll(text); len(got) != len(want) || len(got) != 3*5000 {
+		t.Fatalf("got %d matches from the reader, want %d", len(got), len(want))
+	}
+
+	calls := 0
+	m.Match(bytes.NewReader(text), func(Match) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Fatalf("Match went on for %d calls after fn returned false", calls)
+	}
+
+	failing := iotest.ErrReader(errors.New("broken"))
+	if err := m.Match(failing, func(Match) bool { return true }); err == nil {
+		t.Fatal("read error was not returned")
+	}
+}
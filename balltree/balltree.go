@@ -0,0 +1,249 @@
+// Package balltree provides a ball tree for nearest neighbour and radius
+// queries over vectors under any metric.
+package balltree
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hanyangtay/go-datastructures/topk"
+)
+
+// Metric returns the distance between two vectors of the same length. It
+// must be symmetric and satisfy the triangle inequality.
+type Metric func(a, b []float64) float64
+
+// Euclidean is the straight line distance
+func Euclidean(a, b []float64) float64 {
+	d := 0.0
+	for i := range a {
+		d += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Sqrt(d)
+}
+
+// Manhattan is the sum of the distances along every axis
+func Manhattan(a, b []float64) float64 {
+	d := 0.0
+	for i := range a {
+		d += math.Abs(a[i] - b[i])
+	}
+	return d
+}
+
+// Chebyshev is the greatest distance along any axis
+func Chebyshev(a, b []float64) float64 {
+	d := 0.0
+	for i := range a {
+		d = max(d, math.Abs(a[i]-b[i]))
+	}
+	return d
+}
+
+// Entry is a vector with an associated value
+type Entry[V any] struct {
+	Point []float64
+	Value V
+}
+
+// Neighbor is an entry returned by a query, with its distance to the query
+// point
+type Neighbor[V any] struct {
+	Entry[V]
+	Dist float64
+}
+
+// node is a ball around center holding all entries of its subtree: a leaf
+// with entries, or an inner node with two children
+type node[V any] struct {
+	center      []float64
+	radius      float64
+	entries     []Entry[V]
+	left, right *node[V]
+}
+
+// Tree is a ball tree: a binary tree of nested balls, each split into two
+// around a pair of distant points. Pruning relies only on the triangle
+// inequality, not on axes, so unlike a k-d tree it stays effective in high
+// dimensions when the data has low intrinsic dimension, e.g. embeddings,
+// and works with any metric. The tree is static; build a new one to change
+// its entries.
+type Tree[V any] struct {
+	root   *node[V]
+	metric Metric
+	dims   int
+	length int
+}
+
+// Build returns a tree of entries under metric, with at most leafSize
+// entries per leaf. The entries slice is reordered. It panics if leafSize is
+// not positive or if the points do not all have the same length.
+// Time complexity: O(n log n)
+func Build[V any](entries []Entry[V], metric Metric, leafSize int) *Tree[V] {
+	if leafSize < 1 {
+		panic("balltree: leaf size must be positive")
+	}
+
+	t := &Tree[V]{metric: metric, length: len(entries)}
+	if len(entries) == 0 {
+		return t
+	}
+	t.dims = len(entries[0].Point)
+	for _, e := range entries {
+		t.check(e.Point)
+	}
+
+	t.root = t.build(entries, leafSize)
+	return t
+}
+
+func (t *Tree[V]) check(p []float64) {
+	if len(p) != t.dims {
+		panic("balltree: point has wrong number of dimensions")
+	}
+}
+
+func (t *Tree[V]) build(entries []Entry[V], leafSize int) *node[V] {
+	n := &node[V]{center: make([]float64, t.dims)}
+	for _, e := range entries {
+		for i, x := range e.Point {
+			n.center[i] += x
+		}
+	}
+	for i := range n.center {
+		n.center[i] /= float64(len(entries))
+	}
+
+	far := 0
+	dist := make([]float64, len(entries))
+	for i, e := range entries {
+		dist[i] = t.metric(n.center, e.Point)
+		if dist[i] > dist[far] {
+			far = i
+		}
+	}
+	n.radius = dist[far]
+
+	if len(entries) <= leafSize || n.radius == 0 {
+		n.entries = entries
+		return n
+	}
+
+	// split between the point farthest from the center and the point
+	// farthest from that one, by which of them is nearer
+	a := entries[far].Point
+	for i, e := range entries {
+		dist[i] = t.metric(a, e.Point)
+		if dist[i] > dist[far] {
+			far = i
+		}
+	}
+	b := entries[far].Point
+	for i, e := range entries {
+		dist[i] -= t.metric(b, e.Point)
+	}
+	sort.Sort(byDist[V]{entries, dist})
+
+	mid := len(entries) / 2
+	n.left = t.build(entries[:mid], leafSize)
+	n.right = t.build(entries[mid:], leafSize)
+	return n
+}
+
+type byDist[V any] struct {
+	entries []Entry[V]
+	dist    []float64
+}
+
+func (s byDist[V]) Len() int           { return len(s.entries) }
+func (s byDist[V]) Less(i, j int) bool { return s.dist[i] < s.dist[j] }
+func (s byDist[V]) Swap(i, j int) {
+	s.entries[i], s.entries[j] = s.entries[j], s.entries[i]
+	s.dist[i], s.dist[j] = s.dist[j], s.dist[i]
+}
+
+// Len returns the number of entries
+func (t *Tree[V]) Len() int { return t.length }
+
+// KNN returns the k entries nearest to p, nearest first. It panics if p does
+// not have the length of the points.
+func (t *Tree[V]) KNN(p []float64, k int) []Neighbor[V] {
+	if t.root == nil || k < 1 {
+		return nil
+	}
+	t.check(p)
+
+	best := topk.New(k, func(a, b Neighbor[V]) bool { return a.Dist > b.Dist })
+	bound := func() float64 {
+		if !best.Full() {
+			return math.Inf(1)
+		}
+		return best.Min().Dist
+	}
+
+	var search func(n *node[V], d float64)
+	search = func(n *node[V], d float64) {
+		// d is the distance from p to the center of n
+		if beyond(d, n.radius, bound()) {
+			return
+		}
+
+		if n.entries != nil {
+			for _, e := range n.entries {
+				best.Add(Neighbor[V]{e, t.metric(p, e.Point)})
+			}
+			return
+		}
+
+		dl, dr := t.metric(p, n.left.center), t.metric(p, n.right.center)
+		if dl <= dr {
+			search(n.left, dl)
+			search(n.right, dr)
+		} else {
+			search(n.right, dr)
+			search(n.left, dl)
+		}
+	}
+	search(t.root, t.metric(p, t.root.center))
+
+	return best.Result()
+}
+
+// beyond reports whether a ball of the given radius, whose center is at
+// distance d, lies farther than r, allowing for rounding errors in the
+// triangle inequality
+func beyond(d, radius, r float64) bool {
+	return d > (r+radius)*(1+1e-9)
+}
+
+// Radius returns all entries within distance r of p, nearest first. It
+// panics if p does not have the length of the points.
+func (t *Tree[V]) Radius(p []float64, r float64) []Neighbor[V] {
+	if t.root == nil {
+		return nil
+	}
+	t.check(p)
+
+	var result []Neighbor[V]
+	var search func(n *node[V])
+	search = func(n *node[V]) {
+		if beyond(t.metric(p, n.center), n.radius, r) {
+			return
+		}
+
+		if n.entries != nil {
+			for _, e := range n.entries {
+				if d := t.metric(p, e.Point); d <= r {
+					result = append(result, Neighbor[V]{e, d})
+				}
+			}
+			return
+		}
+		search(n.left)
+		search(n.right)
+	}
+	search(t.root)
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Dist < result[j].Dist })
+	return result
+}
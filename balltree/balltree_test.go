@@ -0,0 +1,71 @@
+package balltree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func randomPoint(rng *rand.Rand, dims int) []float64 {
+	p := make([]float64, dims)
+	for i := range p {
+		p[i] = rng.Float64() * 100
+	}
+	return p
+}
+
+// TestQueries compares KNN and Radius against the distances of all points
+// under each metric
+func TestQueries(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var entries []Entry[int]
+	for i := 0; i < 500; i++ {
+		entries = append(entries, Entry[int]{randomPoint(rng, 4), i})
+	}
+
+	for name, metric := range map[string]Metric{"euclidean": Euclidean, "manhattan": Manhattan, "chebyshev": Chebyshev} {
+		for _, leafSize := range []int{1, 8} {
+			tr := Build(append([]Entry[int](nil), entries...), metric, leafSize)
+			if tr.Len() != len(entries) {
+				t.Fatalf("got length %d, want %d", tr.Len(), len(entries))
+			}
+
+			for i := 0; i < 30; i++ {
+				q := randomPoint(rng, 4)
+				want := make([]float64, len(entries))
+				for j, e := range entries {
+					want[j] = metric(q, e.Point)
+				}
+				sort.Float64s(want)
+
+				got := tr.KNN(q, 7)
+				if len(got) != 7 {
+					t.Fatalf("%s: KNN returned %d entries, want 7", name, len(got))
+				}
+				for j, n := range got {
+					if n.Dist != want[j] || metric(q, n.Point) != n.Dist {
+						t.Fatalf("%s: KNN[%d] at distance %v, want %v", name, j, n.Dist, want[j])
+					}
+				}
+
+				r := want[20]
+				within := tr.Radius(q, r)
+				if len(within) != sort.SearchFloat64s(want, r+1e-12) {
+					t.Fatalf("%s: Radius returned %d entries, want %d", name, len(within), sort.SearchFloat64s(want, r+1e-12))
+				}
+				for j := 1; j < len(within); j++ {
+					if within[j].Dist < within[j-1].Dist {
+						t.Fatalf("%s: Radius returned entries out of order", name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	tr := Build[int](nil, Euclidean, 4)
+	if tr.KNN([]float64{1}, 3) != nil || tr.Radius([]float64{1}, 3) != nil {
+		t.Fatal("empty tree returned entries")
+	}
+}
@@ -0,0 +1,181 @@
+// Package bitset provides a dense set of small non-negative integers, packed
+// one bit per integer into 64 bit words, and a bit vector with rank and
+// select queries.
+package bitset
+
+import (
+	"math/bits"
+)
+
+// BitSet is a set of the integers from 0 up to its length, such as the node
+// IDs of a graph, using one bit per possible element. It takes an eighth of
+// the memory of a []bool, and the set operations work on 64 elements at a
+// time. The zero value is an empty set of length zero.
+type BitSet struct {
+	words  []uint64
+	length int
+}
+
+// New returns an empty set of the given length. It panics if length is
+// negative.
+func New(length int) *BitSet {
+	if length < 0 {
+		panic("bitset: negative length")
+	}
+	return &BitSet{words: make([]uint64, (length+63)/64), length: length}
+}
+
+// Len returns the length of the set, one more than the largest element it
+// can hold
+func (b *BitSet) Len() int { return b.length }
+
+func (b *BitSet) check(i int) {
+	if i < 0 || i >= b.length {
+		panic("bitset: index out of range")
+	}
+}
+
+// Set adds i to the set. It panics if i is out of range.
+func (b *BitSet) Set(i int) {
+	b.check(i)
+	b.words[i/64] |= 1 << uint(i%64)
+}
+
+// Clear removes i from the set. It panics if i is out of range.
+func (b *BitSet) Clear(i int) {
+	b.check(i)
+	b.words[i/64] &^= 1 << uint(i%64)
+}
+
+// Test reports whether i is in the set. It panics if i is out of range.
+func (b *BitSet) Test(i int) bool {
+	b.check(i)
+	return b.words[i/64]&(1<<uint(i%64)) != 0
+}
+
+// TestAndSet adds i to the set and reports whether it was already there, as
+// when marking nodes visited. It panics if i is out of range.
+func (b *BitSet) TestAndSet(i int) bool {
+	b.check(i)
+	word, bit := &b.words[i/64], uint64(1)<<uint(i%64)
+	old := *word&bit != 0
+	*word |= bit
+	return old
+}
+
+// Grow extends the length of the set to at least length, keeping its
+// elements. It never shrinks the set.
+func (b *BitSet) Grow(length int) {
+	if length <= b.length {
+		return
+	}
+	if n := (length + 63) / 64; n > len(b.words) {
+		b.words = append(b.words, make([]uint64, n-len(b.words))...)
+	}
+	b.length = length
+}
+
+// Reset removes all elements, keeping the length
+func (b *BitSet) Reset() { clear(b.words) }
+
+// Count returns the number of elements
+func (b *BitSet) Count() int {
+	n := 0
+	for _, w := range b.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// NextSet returns the least element not less than i, or false if there is
+// none. Iterate over the set as
+//
+//	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+//		...
+//	}
+func (b *BitSet) NextSet(i int) (int, bool) {
+	if i < 0 {
+		i = 0
+	}
+	if i >= b.length {
+		return 0, false
+	}
+
+	k := i / 64
+	w := b.words[k] >> uint(i%64)
+	if w != 0 {
+		return i + bits.TrailingZeros64(w), true
+	}
+	for k++; k < len(b.words); k++ {
+		if b.words[k] != 0 {
+			return k*64 + bits.TrailingZeros64(b.words[k]), true
+		}
+	}
+	return 0, false
+}
+
+// Each calls fn for every element in increasing order until fn returns
+// false
+func (b *BitSet) Each(fn func(i int) bool) {
+	for k, w := range b.words {
+		for w != 0 {
+			if !fn(k*64 + bits.TrailingZeros64(w)) {
+				return
+			}
+			w &= w - 1
+		}
+	}
+}
+
+// Clone returns a copy of the set
+func (b *BitSet) Clone() *BitSet {
+	return &BitSet{words: append([]uint64(nil), b.words...), length: b.length}
+}
+
+// Equal reports whether b and other have the same length and elements
+func (b *BitSet) Equal(other *BitSet) bool {
+	if b.length != other.length {
+		return false
+	}
+	for k, w := range b.words {
+		if w != other.words[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// And removes the elements of b that are not in other
+func (b *BitSet) And(other *BitSet) {
+	n := min(len(b.words), len(other.words))
+	for k := 0; k < n; k++ {
+		b.words[k] &= other.words[k]
+	}
+	clear(b.words[n:])
+}
+
+// AndNot removes the elements of b that are in other
+func (b *BitSet) AndNot(other *BitSet) {
+	n := min(len(b.words), len(other.words))
+	for k := 0; k < n; k++ {
+		b.words[k] &^= other.words[k]
+	}
+}
+
+// Or adds the elements of other to b, growing b to the length of other if
+// it is shorter
+func (b *BitSet) Or(other *BitSet) {
+	b.Grow(other.length)
+	for k, w := range other.words {
+		b.words[k] |= w
+	}
+}
+
+// Xor replaces b by the elements in exactly one of b and other, growing b to
+// the length of other if it is shorter
+func (b *BitSet) Xor(other *BitSet) {
+	b.Grow(other.length)
+	for k, w := range other.words {
+		b.words[k] ^= w
+	}
+}
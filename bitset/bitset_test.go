@@ -0,0 +1,127 @@
+package bitset
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestAgainstBools checks a set against a []bool under random operations,
+// including growth
+func TestAgainstBools(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	b := New(100)
+	want := make([]bool, 100)
+
+	for i := 0; i < 5000; i++ {
+		j := rng.Intn(len(want))
+		switch rng.Intn(5) {
+		case 0:
+			b.Set(j)
+			want[j] = true
+		case 1:
+			b.Clear(j)
+			want[j] = false
+		case 2:
+			if b.TestAndSet(j) != want[j] {
+				t.Fatalf("TestAndSet(%d) = %v, want %v", j, !want[j], want[j])
+			}
+			want[j] = true
+		case 3:
+			if rng.Intn(50) == 0 {
+				n := len(want) + rng.Intn(100)
+				b.Grow(n)
+				want = append(want, make([]bool, n-len(want))...)
+			}
+		default:
+			k := j
+			for k < len(want) && !want[k] {
+				k++
+			}
+			if next, ok := b.NextSet(j); ok != (k < len(want)) || ok && next != k {
+				t.Fatalf("NextSet(%d) = %d, %v, want %d", j, next, ok, k)
+			}
+		}
+	}
+
+	count := 0
+	for i, w := range want {
+		if b.Test(i) != w {
+			t.Fatalf("Test(%d) = %v, want %v", i, !w, w)
+		}
+		if w {
+			count++
+		}
+	}
+	if b.Len() != len(want) || b.Count() != count {
+		t.Fatalf("got length %d and count %d, want %d and %d", b.Len(), b.Count(), len(want), count)
+	}
+
+	var each []int
+	b.Each(func(i int) bool {
+		each = append(each, i)
+		return true
+	})
+	next := -1
+	for _, i := range each {
+		n, ok := b.NextSet(next + 1)
+		if !ok || n != i {
+			t.Fatalf("NextSet(%d) = %d, %v, want %d", next+1, n, ok, i)
+		}
+		next = n
+	}
+	if len(each) != count {
+		t.Fatalf("Each visited %d elements, want %d", len(each), count)
+	}
+
+	c := b.Clone()
+	b.Reset()
+	if b.Count() != 0 || c.Count() != count || b.Equal(c) {
+		t.Fatal("Reset changed the clone")
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	a, b := New(300), New(200)
+	for i := 0; i < 150; i++ {
+		a.Set(rng.Intn(300))
+		b.Set(rng.Intn(200))
+	}
+
+	for name, c := range map[string]struct {
+		apply func(x *BitSet)
+		want  func(x, y bool) bool
+	}{
+		"And":    {func(x *BitSet) { x.And(b) }, func(x, y bool) bool { return x && y }},
+		"AndNot": {func(x *BitSet) { x.AndNot(b) }, func(x, y bool) bool { return x && !y }},
+		"Or":     {func(x *BitSet) { x.Or(b) }, func(x, y bool) bool { return x || y }},
+		"Xor":    {func(x *BitSet) { x.Xor(b) }, func(x, y bool) bool { return x != y }},
+	} {
+		x := a.Clone()
+		c.apply(x)
+		if x.Len() != 300 {
+			t.Fatalf("%s: got length %d, want 300", name, x.Len())
+		}
+		for i := 0; i < 300; i++ {
+			if x.Test(i) != c.want(a.Test(i), i < 200 && b.Test(i)) {
+				t.Fatalf("%s: element %d is wrong", name, i)
+			}
+		}
+	}
+
+	// Or and Xor grow a shorter set
+	x := b.Clone()
+	x.Or(a)
+	if x.Len() != 300 {
+		t.Fatalf("got length %d after Or with a longer set, want 300", x.Len())
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Set(64) of a set of length 64 did not panic")
+		}
+	}()
+	New(64).Set(64)
+}
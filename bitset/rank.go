@@ -0,0 +1,153 @@
+package bitset
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	blockWords = 8               // words per rank block
+	blockBits  = blockWords * 64 // bits per rank block
+	sampleRate = 8 * blockBits   // ones, or zeros, between select samples
+)
+
+// RankSelect is an immutable bit vector that counts the ones before any
+// position (rank) in constant time and finds the k-th one or zero (select)
+// in nearly constant time, the building block of succinct structures such
+// as wavelet trees. Besides the bits it stores the number of ones before
+// every block of 512 bits and the block of every 4096th one and zero, about
+// a seventh of the size of the bits. As a set of integers, Rank gives the
+// number of elements less than i, so the position of i in the sorted
+// elements, and Select the k-th smallest element.
+type RankSelect struct {
+	words  []uint64
+	length int
+	ranks  []int // ones before every block, and in all
+	ones   []int // block of every sampleRate-th one
+	zeros  []int // block of every sampleRate-th zero
+}
+
+// NewRankSelect returns a bit vector holding the bits of b, a copy that does
+// not change with b. Time complexity: O(n)
+func NewRankSelect(b *BitSet) *RankSelect {
+	r := &RankSelect{
+		words:  append([]uint64(nil), b.words[:(b.length+63)/64]...),
+		length: b.length,
+	}
+
+	blocks := (len(r.words) + blockWords - 1) / blockWords
+	r.ranks = make([]int, blocks+1)
+	ones := 0
+	for j := 0; j < blocks; j++ {
+		r.ranks[j] = ones
+		for k := j * blockWords; k < min((j+1)*blockWords, len(r.words)); k++ {
+			ones += bits.OnesCount64(r.words[k])
+		}
+		for len(r.ones)*sampleRate < ones {
+			r.ones = append(r.ones, j)
+		}
+		for len(r.zeros)*sampleRate < min((j+1)*blockBits, r.length)-ones {
+			r.zeros = append(r.zeros, j)
+		}
+	}
+	r.ranks[blocks] = ones
+	return r
+}
+
+// Len returns the number of bits
+func (r *RankSelect) Len() int { return r.length }
+
+// Count returns the number of ones
+func (r *RankSelect) Count() int { return r.ranks[len(r.ranks)-1] }
+
+// Test reports whether bit i is one. It panics if i is out of range.
+func (r *RankSelect) Test(i int) bool {
+	if i < 0 || i >= r.length {
+		panic("bitset: index out of range")
+	}
+	return r.words[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Rank returns the number of ones before position i, for i from 0 to Len.
+// It panics if i is out of range. Time complexity: O(1)
+func (r *RankSelect) Rank(i int) int {
+	if i < 0 || i > r.length {
+		panic("bitset: index out of range")
+	}
+
+	n := r.ranks[i/blockBits]
+	for k := i / blockBits * blockWords; k < i/64; k++ {
+		n += bits.OnesCount64(r.words[k])
+	}
+	if i%64 != 0 {
+		n += bits.OnesCount64(r.words[i/64] & (1<<uint(i%64) - 1))
+	}
+	return n
+}
+
+// Rank0 returns the number of zeros before position i, for i from 0 to
+// Len. It panics if i is out of range. Time complexity: O(1)
+func (r *RankSelect) Rank0(i int) int { return i - r.Rank(i) }
+
+// Select returns the position of the k-th one, counting from 0. It panics
+// unless k is less than Count. Time complexity: O(1) unless the ones are
+// very unevenly spread, O(log n) at worst
+func (r *RankSelect) Select(k int) int {
+	if k < 0 || k >= r.Count() {
+		panic("bitset: index out of range")
+	}
+	j := r.block(r.ones, k, func(j int) int { return r.ranks[j] })
+	return r.scan(j, k-r.ranks[j], false)
+}
+
+// Select0 returns the position of the k-th zero, counting from 0. It panics
+// unless k is less than Len minus Count. Time complexity: O(1) unless the
+// zeros are very unevenly spread, O(log n) at worst
+func (r *RankSelect) Select0(k int) int {
+	if k < 0 || k >= r.length-r.Count() {
+		panic("bitset: index out of range")
+	}
+	zeros := func(j int) int { return j*blockBits - r.ranks[j] }
+	j := r.block(r.zeros, k, zeros)
+	return r.scan(j, k-zeros(j), true)
+}
+
+// block returns the last block with at most k ones, or zeros, before it,
+// searching between the samples around k
+func (r *RankSelect) block(samples []int, k int, before func(j int) int) int {
+	lo, hi := samples[k/sampleRate], len(r.ranks)-1
+	if s := k/sampleRate + 1; s < len(samples) {
+		hi = samples[s] + 1
+	}
+	return lo + sort.Search(hi-lo, func(j int) bool { return before(lo+j) > k }) - 1
+}
+
+// scan returns the position of the k-th one, or zero, of block j
+func (r *RankSelect) scan(j, k int, zeros bool) int {
+	for i := j * blockWords; ; i++ {
+		w := r.words[i]
+		if zeros {
+			w = ^w
+		}
+		if c := bits.OnesCount64(w); k >= c {
+			k -= c
+			continue
+		}
+		return i*64 + selectWord(w, k)
+	}
+}
+
+// selectWord returns the position of the k-th one of w, which has more than
+// k ones
+func selectWord(w uint64, k int) int {
+	pos := 0
+	for c := bits.OnesCount8(uint8(w)); k >= c; c = bits.OnesCount8(uint8(w)) {
+		k -= c
+		w >>= 8
+		pos += 8
+	}
+	for ; k > 0; k-- {
+		w &= w - 1
+	}
+	return pos + bits.TrailingZeros64(w)
+}
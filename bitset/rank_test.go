@@ -0,0 +1,76 @@
+package bitset
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestRankSelect compares rank and select against a scan of the bits, for
+// dense, sparse and uneven bit vectors
+func TestRankSelect(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, c := range []struct {
+		n       int
+		density func(i int) float64
+	}{
+		{0, nil},
+		{1, func(int) float64 { return 1 }},
+		{100000, func(int) float64 { return 0.5 }},
+		{100000, func(int) float64 { return 0.01 }},
+		{100000, func(int) float64 { return 0.99 }},
+		{100000, func(i int) float64 { return float64(i%50000) / 50000 }},
+	} {
+		b := New(c.n)
+		for i := 0; i < c.n; i++ {
+			if rng.Float64() < c.density(i) {
+				b.Set(i)
+			}
+		}
+		r := NewRankSelect(b)
+		if r.Len() != c.n || r.Count() != b.Count() {
+			t.Fatalf("got length %d and count %d, want %d and %d", r.Len(), r.Count(), c.n, b.Count())
+		}
+
+		ones, zeros := 0, 0
+		for i := 0; i <= c.n; i++ {
+			if r.Rank(i) != ones || r.Rank0(i) != zeros {
+				t.Fatalf("n=%d: Rank(%d) = %d, want %d", c.n, i, r.Rank(i), ones)
+			}
+			if i == c.n {
+				break
+			}
+			if r.Test(i) {
+				if s := r.Select(ones); s != i {
+					t.Fatalf("n=%d: Select(%d) = %d, want %d", c.n, ones, s, i)
+				}
+				ones++
+			} else {
+				if s := r.Select0(zeros); s != i {
+					t.Fatalf("n=%d: Select0(%d) = %d, want %d", c.n, zeros, s, i)
+				}
+				zeros++
+			}
+		}
+
+		// the bits are copied
+		if c.n > 0 {
+			b.Clear(0)
+			b.Set(0)
+			b.Reset()
+			if r.Count() != ones {
+				t.Fatal("changing the set changed the bit vector")
+			}
+		}
+	}
+}
+
+func TestSelectOutOfRange(t *testing.T) {
+	b := New(10)
+	b.Set(3)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Select(1) of a vector with one bit set did not panic")
+		}
+	}()
+	NewRankSelect(b).Select(1)
+}
@@ -0,0 +1,176 @@
+// Package bloom provides Bloom filters, compact probabilistic sets which may
+// report false positives but never false negatives.
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+
+	"github.com/hanyangtay/go-datastructures/internal/wire"
+)
+
+// ErrShapeMismatch is returned when combining filters that differ in their
+// number of bits or hash functions
+var ErrShapeMismatch = errors.New("bloom: filters differ in size or number of hashes")
+
+// ErrInvalidData is returned when decoding data that is not an encoded filter
+var ErrInvalidData = errors.New("bloom: invalid encoding")
+
+// Estimate returns the number of bits m and hash functions k for a filter
+// holding n items with a false positive rate of at most p, from
+// m = -n ln p / (ln 2)^2 and k = m/n ln 2.
+func Estimate(n uint, p float64) (m, k uint) {
+	if n == 0 {
+		n = 1
+	}
+	m = uint(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = uint(math.Round(float64(m) / float64(n) * math.Ln2))
+	return max(m, 1), max(k, 1)
+}
+
+// Filter is a set of byte strings represented by m bits: each item sets the
+// k bits chosen by its hashes, and is reported present if all of them are
+// set. The k hashes are derived from two halves of a 64 bit FNV-1a hash,
+// which is stable, so encoded filters remain valid across processes.
+type Filter struct {
+	m, k  uint
+	words []uint64
+}
+
+// New returns an empty filter of m bits using k hash functions. It panics if
+// m or k is zero.
+func New(m, k uint) *Filter {
+	if m == 0 || k == 0 {
+		panic("bloom: filter needs at least one bit and one hash function")
+	}
+	return &Filter{m: m, k: k, words: make([]uint64, (m+63)/64)}
+}
+
+// NewWithEstimates returns an empty filter sized by Estimate for n items and
+// a false positive rate of p
+func NewWithEstimates(n uint, p float64) *Filter {
+	return New(Estimate(n, p))
+}
+
+// Cap returns the number of bits m
+func (f *Filter) Cap() uint { return f.m }
+
+// K returns the number of hash functions
+func (f *Filter) K() uint { return f.k }
+
+// hashes returns the two base hashes of data, the second one odd so that
+// the probe sequence does not repeat early
+func hashes(data []byte) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write(data)
+	sum := h.Sum64()
+	return sum, bits.RotateLeft64(sum, 32) | 1
+}
+
+// Add adds data to the set
+func (f *Filter) Add(data []byte) {
+	h1, h2 := hashes(data)
+	for i := uint(0); i < f.k; i++ {
+		bit := (h1 + uint64(i)*h2) % uint64(f.m)
+		f.words[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// AddString adds s to the set
+func (f *Filter) AddString(s string) { f.Add([]byte(s)) }
+
+// Test reports whether data may be in the set. It is always true for data
+// that was added, and true with about the false positive rate otherwise.
+func (f *Filter) Test(data []byte) bool {
+	h1, h2 := hashes(data)
+	for i := uint(0); i < f.k; i++ {
+		bit := (h1 + uint64(i)*h2) % uint64(f.m)
+		if f.words[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestString reports whether s may be in the set
+func (f *Filter) TestString(s string) bool { return f.Test([]byte(s)) }
+
+// FalsePositiveRate returns the expected false positive rate of the filter
+// after n distinct items have been added, (1 - e^(-kn/m))^k
+func (f *Filter) FalsePositiveRate(n uint) float64 {
+	return math.Pow(1-math.Exp(-float64(f.k)*float64(n)/float64(f.m)), float64(f.k))
+}
+
+// Union adds every item of other to f, as if they had been added to f
+// directly. ErrShapeMismatch is returned if the filters differ in shape.
+func (f *Filter) Union(other *Filter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrShapeMismatch
+	}
+	for i, w := range other.words {
+		f.words[i] |= w
+	}
+	return nil
+}
+
+// Intersect removes from f every item that is not in other. Items of both
+// filters are kept, but the false positive rate may be higher than for a
+// filter of the common items alone. ErrShapeMismatch is returned if the
+// filters differ in shape.
+func (f *Filter) Intersect(other *Filter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrShapeMismatch
+	}
+	for i, w := range other.words {
+		f.words[i] &= w
+	}
+	return nil
+}
+
+// Reset empties the filter
+func (f *Filter) Reset() { clear(f.words) }
+
+// encodingVersion is the version of the layout written by MarshalBinary
+const encodingVersion = 1
+
+// MarshalBinary encodes the filter as m and k followed by its bits, all as
+// little endian 64 bit words, after the header common to the encodings of
+// this module
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	data := wire.Append(nil, "bloom.Filter", encodingVersion)
+	data = binary.LittleEndian.AppendUint64(data, uint64(f.m))
+	data = binary.LittleEndian.AppendUint64(data, uint64(f.k))
+	for _, w := range f.words {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the filter with one encoded by MarshalBinary.
+// ErrInvalidData is returned if data is malformed.
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	data, err := wire.Parse(data, "bloom.Filter", encodingVersion)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	if len(data) < 16 {
+		return ErrInvalidData
+	}
+
+	m := binary.LittleEndian.Uint64(data)
+	k := binary.LittleEndian.Uint64(data[8:])
+	if m == 0 || k == 0 || uint64(len(data)-16) != (m+63)/64*8 {
+		return ErrInvalidData
+	}
+
+	f.m, f.k = uint(m), uint(k)
+	f.words = make([]uint64, (m+63)/64)
+	for i := range f.words {
+		f.words[i] = binary.LittleEndian.Uint64(data[16+8*i:])
+	}
+	return nil
+}
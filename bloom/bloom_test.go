@@ -0,0 +1,118 @@
+package bloom
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/hanyangtay/go-datastructures/internal/wire"
+)
+
+func TestNoFalseNegatives(t *testing.T) {
+	f := NewWithEstimates(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		f.AddString(fmt.Sprint("item", i))
+	}
+	for i := 0; i < 1000; i++ {
+		if !f.TestString(fmt.Sprint("item", i)) {
+			t.Fatalf("item%d was added but is not found", i)
+		}
+	}
+
+	// the false positive rate stays near the target
+	positives := 0
+	for i := 0; i < 10000; i++ {
+		if f.TestString(fmt.Sprint("other", i)) {
+			positives++
+		}
+	}
+	if rate := float64(positives) / 10000; rate > 0.02 {
+		t.Fatalf("got false positive rate %v, want about 0.01", rate)
+	}
+	if rate := f.FalsePositiveRate(1000); math.Abs(rate-0.01) > 0.002 {
+		t.Fatalf("got expected false positive rate %v, want about 0.01", rate)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	m, k := Estimate(1000, 0.01)
+	// m = -1000 ln 0.01 / (ln 2)^2 = 9585.06, k = 9585/1000 ln 2 = 6.6
+	if m != 9586 || k != 7 {
+		t.Fatalf("Estimate(1000, 0.01) = %d, %d, want 9586, 7", m, k)
+	}
+}
+
+func TestUnionIntersect(t *testing.T) {
+	a, b := New(1024, 4), New(1024, 4)
+	a.AddString("a")
+	a.AddString("both")
+	b.AddString("b")
+	b.AddString("both")
+
+	u := New(1024, 4)
+	u.Union(a)
+	if err := u.Union(b); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"a", "b", "both"} {
+		if !u.TestString(s) {
+			t.Fatalf("union lost %q", s)
+		}
+	}
+
+	if err := a.Intersect(b); err != nil {
+		t.Fatal(err)
+	}
+	if !a.TestString("both") {
+		t.Fatal("intersection lost the common item")
+	}
+
+	if err := a.Union(New(2048, 4)); !errors.Is(err, ErrShapeMismatch) {
+		t.Fatalf("got %v for filters of different sizes, want ErrShapeMismatch", err)
+	}
+	if err := a.Intersect(New(1024, 3)); !errors.Is(err, ErrShapeMismatch) {
+		t.Fatalf("got %v for different hash counts, want ErrShapeMismatch", err)
+	}
+
+	a.Reset()
+	if a.TestString("both") {
+		t.Fatal("item found after Reset")
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	f := New(1000, 5)
+	for i := 0; i < 100; i++ {
+		f.AddString(fmt.Sprint(i))
+	}
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var g Filter
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if g.Cap() != f.Cap() || g.K() != f.K() {
+		t.Fatalf("decoded a filter of %d bits and %d hashes, want %d and %d", g.Cap(), g.K(), f.Cap(), f.K())
+	}
+	for i := 0; i < 100; i++ {
+		if !g.TestString(fmt.Sprint(i)) {
+			t.Fatalf("decoded filter lost %d", i)
+		}
+	}
+
+	header := wire.Append(nil, "bloom.Filter", encodingVersion)
+	for name, bad := range map[string][]byte{
+		"truncated":     data[:len(data)-1],
+		"headerless":    data[len(header):],
+		"newer version": append(wire.Append(nil, "bloom.Filter", encodingVersion+1), data[len(header):]...),
+		"other kind":    append(wire.Append(nil, "hyperloglog.Sketch", encodingVersion), data[len(header):]...),
+	} {
+		if err := g.UnmarshalBinary(bad); !errors.Is(err, ErrInvalidData) {
+			t.Fatalf("%s: got %v, want ErrInvalidData", name, err)
+		}
+	}
+}
@@ -0,0 +1,429 @@
+// Package bplustree provides a generic in-memory B+ tree ordered map, tuned
+// for sequential scans.
+package bplustree
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hanyangtay/go-datastructures/ordered"
+)
+
+var _ ordered.Map[int, int] = (*BPlusTree[int, int])(nil)
+
+// node is a leaf holding entries, or an inner node holding separator keys:
+// children[i] holds the keys from keys[i-1] up to but excluding keys[i].
+// Every node holds between degree-1 and 2*degree-1 keys, except for the
+// root, which may hold fewer.
+type node[K, V any] struct {
+	keys     []K
+	values   []V           // leaves only
+	children []*node[K, V] // inner nodes only
+
+	// neighbouring leaves in key order
+	prev, next *node[K, V]
+}
+
+func (n *node[K, V]) leaf() bool { return n.children == nil }
+
+// BPlusTree is an ordered map from keys to values, with keys ordered by a
+// less function. Unlike in a B-tree, entries are only stored in the leaves,
+// which are linked in key order, so that range scans and iterators move from
+// leaf to leaf without climbing the tree; inner nodes only hold copies of
+// keys to guide searches. This suits sequential, time-series style access.
+// Nodes hold up to 2*degree-1 keys.
+type BPlusTree[K, V any] struct {
+	root   *node[K, V]
+	degree int
+	length int
+	less   func(a, b K) bool
+}
+
+// New returns an empty tree of the given degree ordered by less. It panics if
+// degree is less than 2.
+func New[K, V any](degree int, less func(a, b K) bool) *BPlusTree[K, V] {
+	if degree < 2 {
+		panic("bplustree: degree must be at least 2")
+	}
+	return &BPlusTree[K, V]{root: &node[K, V]{}, degree: degree, less: less}
+}
+
+// Len returns the number of entries
+func (t *BPlusTree[K, V]) Len() int { return t.length }
+
+// lowerBound returns the index of the first key of n not less than key
+func (t *BPlusTree[K, V]) lowerBound(n *node[K, V], key K) int {
+	lo, hi := 0, len(n.keys)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if t.less(n.keys[mid], key) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// child returns the index of the child of the inner node n that covers key
+func (t *BPlusTree[K, V]) child(n *node[K, V], key K) int {
+	lo, hi := 0, len(n.keys)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if t.less(key, n.keys[mid]) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// findLeaf returns the leaf that covers key
+func (t *BPlusTree[K, V]) findLeaf(key K) *node[K, V] {
+	n := t.root
+	for !n.leaf() {
+		n = n.children[t.child(n, key)]
+	}
+	return n
+}
+
+// equal reports whether key i of n equals key
+func (t *BPlusTree[K, V]) equal(n *node[K, V], i int, key K) bool {
+	return i < len(n.keys) && !t.less(key, n.keys[i])
+}
+
+// Get returns the value stored under key, and whether it was found
+func (t *BPlusTree[K, V]) Get(key K) (V, bool) {
+	n := t.findLeaf(key)
+	if i := t.lowerBound(n, key); t.equal(n, i, key) {
+		return n.values[i], true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores value under key, replacing any previous value.
+// Time complexity: O(degree log n / log degree)
+func (t *BPlusTree[K, V]) Put(key K, value V) {
+	sep, right := t.put(t.root, key, value)
+	if right != nil {
+		t.root = &node[K, V]{
+			keys:     []K{sep},
+			children: []*node[K, V]{t.root, right},
+		}
+	}
+}
+
+// put inserts into the subtree at n. If n overflows, it is split, and the
+// new right half is returned along with the key separating it from n.
+func (t *BPlusTree[K, V]) put(n *node[K, V], key K, value V) (K, *node[K, V]) {
+	var zero K
+
+	if n.leaf() {
+		i := t.lowerBound(n, key)
+		if t.equal(n, i, key) {
+			n.values[i] = value
+			return zero, nil
+		}
+		n.keys = slices.Insert(n.keys, i, key)
+		n.values = slices.Insert(n.values, i, value)
+		t.length++
+
+		if len(n.keys) < 2*t.degree {
+			return zero, nil
+		}
+
+		// split the leaf, the first key of the right half separates them
+		mid := len(n.keys) / 2
+		right := &node[K, V]{
+			keys:   append([]K(nil), n.keys[mid:]...),
+			values: append([]V(nil), n.values[mid:]...),
+			prev:   n,
+			next:   n.next,
+		}
+		if n.next != nil {
+			n.next.prev = right
+		}
+		n.next = right
+		n.keys, n.values = truncate(n.keys, mid), truncate(n.values, mid)
+		return right.keys[0], right
+	}
+
+	i := t.child(n, key)
+	sep, split := t.put(n.children[i], key, value)
+	if split == nil {
+		return zero, nil
+	}
+
+	n.keys = slices.Insert(n.keys, i, sep)
+	n.children = slices.Insert(n.children, i+1, split)
+	if len(n.keys) < 2*t.degree {
+		return zero, nil
+	}
+
+	// split the inner node, its middle key moves up
+	mid := len(n.keys) / 2
+	sep = n.keys[mid]
+	right := &node[K, V]{
+		keys:     append([]K(nil), n.keys[mid+1:]...),
+		children: append([]*node[K, V](nil), n.children[mid+1:]...),
+	}
+	n.keys, n.children = truncate(n.keys, mid), truncate(n.children, mid+1)
+	return sep, right
+}
+
+// Delete removes key and reports whether it was present.
+// Time complexity: O(degree log n / log degree)
+func (t *BPlusTree[K, V]) Delete(key K) bool {
+	if !t.delete(t.root, key) {
+		return false
+	}
+
+	if !t.root.leaf() && len(t.root.keys) == 0 {
+		t.root = t.root.children[0]
+	}
+	t.length--
+	return true
+}
+
+// delete removes key from the subtree at n, rebalancing children that
+// underflow on the way back up
+func (t *BPlusTree[K, V]) delete(n *node[K, V], key K) bool {
+	if n.leaf() {
+		i := t.lowerBound(n, key)
+		if !t.equal(n, i, key) {
+			return false
+		}
+		n.keys, n.values = slices.Delete(n.keys, i, i+1), slices.Delete(n.values, i, i+1)
+		return true
+	}
+
+	i := t.child(n, key)
+	if !t.delete(n.children[i], key) {
+		return false
+	}
+	if len(n.children[i].keys) < t.degree-1 {
+		t.rebalance(n, i)
+	}
+	return true
+}
+
+// rebalance refills child i of n, which has one key too few, by borrowing
+// from a sibling or merging with one
+func (t *BPlusTree[K, V]) rebalance(n *node[K, V], i int) {
+	child := n.children[i]
+
+	if i > 0 && len(n.children[i-1].keys) >= t.degree {
+		left := n.children[i-1]
+		last := len(left.keys) - 1
+
+		if child.leaf() {
+			child.keys = slices.Insert(child.keys, 0, left.keys[last])
+			child.values = slices.Insert(child.values, 0, left.values[last])
+			left.keys, left.values = truncate(left.keys, last), truncate(left.values, last)
+			n.keys[i-1] = child.keys[0]
+		} else {
+			child.keys = slices.Insert(child.keys, 0, n.keys[i-1])
+			child.children = slices.Insert(child.children, 0, left.children[last+1])
+			n.keys[i-1] = left.keys[last]
+			left.keys, left.children = truncate(left.keys, last), truncate(left.children, last+1)
+		}
+		return
+	}
+
+	if i < len(n.children)-1 && len(n.children[i+1].keys) >= t.degree {
+		right := n.children[i+1]
+
+		if child.leaf() {
+			child.keys = append(child.keys, right.keys[0])
+			child.values = append(child.values, right.values[0])
+			right.keys, right.values = slices.Delete(right.keys, 0, 1), slices.Delete(right.values, 0, 1)
+			n.keys[i] = right.keys[0]
+		} else {
+			child.keys = append(child.keys, n.keys[i])
+			child.children = append(child.children, right.children[0])
+			n.keys[i] = right.keys[0]
+			right.keys, right.children = slices.Delete(right.keys, 0, 1), slices.Delete(right.children, 0, 1)
+		}
+		return
+	}
+
+	if i == len(n.children)-1 {
+		i--
+	}
+	t.merge(n, i)
+}
+
+// merge joins child i+1 of n into child i
+func (t *BPlusTree[K, V]) merge(n *node[K, V], i int) {
+	left, right := n.children[i], n.children[i+1]
+
+	if left.leaf() {
+		left.keys = append(left.keys, right.keys...)
+		left.values = append(left.values, right.values...)
+		left.next = right.next
+		if right.next != nil {
+			right.next.prev = left
+		}
+	} else {
+		left.keys = append(append(left.keys, n.keys[i]), right.keys...)
+		left.children = append(left.children, right.children...)
+	}
+
+	n.keys = slices.Delete(n.keys, i, i+1)
+	n.children = slices.Delete(n.children, i+1, i+2)
+}
+
+// first and last return the outermost leaves
+func (t *BPlusTree[K, V]) first() *node[K, V] {
+	n := t.root
+	for !n.leaf() {
+		n = n.children[0]
+	}
+	return n
+}
+
+func (t *BPlusTree[K, V]) last() *node[K, V] {
+	n := t.root
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+	return n
+}
+
+// Min returns the entry with the least key, or false if the tree is empty
+func (t *BPlusTree[K, V]) Min() (K, V, bool) {
+	return entry(t.first(), 0)
+}
+
+// Max returns the entry with the greatest key, or false if the tree is empty
+func (t *BPlusTree[K, V]) Max() (K, V, bool) {
+	n := t.last()
+	return entry(n, len(n.keys)-1)
+}
+
+// Floor returns the entry with the greatest key not greater than key, or
+// false if there is none
+func (t *BPlusTree[K, V]) Floor(key K) (K, V, bool) {
+	n := t.findLeaf(key)
+	i := t.lowerBound(n, key)
+	if t.equal(n, i, key) {
+		return entry(n, i)
+	}
+
+	// the floor precedes the ceiling, possibly in an earlier leaf
+	for i == 0 && n.prev != nil {
+		n = n.prev
+		i = len(n.keys)
+	}
+	return entry(n, i-1)
+}
+
+// Ceiling returns the entry with the least key not less than key, or false
+// if there is none
+func (t *BPlusTree[K, V]) Ceiling(key K) (K, V, bool) {
+	it := t.Seek(key)
+	if !it.Next() {
+		return entry[K, V](nil, 0)
+	}
+	return it.Key(), it.Value(), true
+}
+
+// Ascend calls fn for every entry in key order until fn returns false
+func (t *BPlusTree[K, V]) Ascend(fn func(key K, value V) bool) {
+	for n := t.first(); n != nil; n = n.next {
+		for i := range n.keys {
+			if !fn(n.keys[i], n.values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Range calls fn in key order for every entry with a key from lo up to but
+// excluding hi, until fn returns false. After locating lo, it scans the
+// linked leaves sequentially.
+func (t *BPlusTree[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	for it := t.Seek(lo); it.Next() && t.less(it.Key(), hi); {
+		if !fn(it.Key(), it.Value()) {
+			return
+		}
+	}
+}
+
+// Seek returns an iterator over the entries with keys not less than key, in
+// key order
+func (t *BPlusTree[K, V]) Seek(key K) *Iterator[K, V] {
+	n := t.findLeaf(key)
+	return &Iterator[K, V]{leaf: n, i: t.lowerBound(n, key) - 1}
+}
+
+// Iterator is a cursor over the entries of a B+ tree in key order:
+//
+//	for it := t.Seek(key); it.Next(); {
+//		k, v := it.Key(), it.Value()
+//		...
+//	}
+//
+// The tree must not be modified while iterating.
+type Iterator[K, V any] struct {
+	leaf *node[K, V]
+	i    int
+}
+
+// Next advances to the next entry, returning false once there are none left
+func (it *Iterator[K, V]) Next() bool {
+	it.i++
+	for it.leaf != nil && it.i >= len(it.leaf.keys) {
+		it.leaf, it.i = it.leaf.next, 0
+	}
+	return it.leaf != nil
+}
+
+// Key returns the key of the current entry
+func (it *Iterator[K, V]) Key() K { return it.leaf.keys[it.i] }
+
+// Value returns the value of the current entry
+func (it *Iterator[K, V]) Value() V { return it.leaf.values[it.i] }
+
+// ScanPrefix calls fn in key order for every entry of t whose key starts
+// with prefix, until fn returns false. t must be ordered by byte-wise string
+// comparison, such as ordered.Less[string].
+func ScanPrefix[V any](t *BPlusTree[string, V], prefix string, fn func(key string, value V) bool) {
+	for it := t.Seek(prefix); it.Next() && strings.HasPrefix(it.Key(), prefix); {
+		if !fn(it.Key(), it.Value()) {
+			return
+		}
+	}
+}
+
+// entry unpacks entry i of n, reporting false if there is none
+func entry[K, V any](n *node[K, V], i int) (K, V, bool) {
+	if n == nil || i < 0 || i >= len(n.keys) {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return n.keys[i], n.values[i], true
+}
+
+// truncate shortens s to n elements, clearing the rest so that they do not
+// retain memory
+func truncate[T any](s []T, n int) []T {
+	var zero T
+	for i := n; i < len(s); i++ {
+		s[i] = zero
+	}
+	return s[:n]
+}
+
+// MarshalBinary encodes the tree with ordered.Marshal
+func (t *BPlusTree[K, V]) MarshalBinary() ([]byte, error) {
+	return ordered.Marshal[K, V](t)
+}
+
+// UnmarshalBinary replaces the entries of the tree as ordered.UnmarshalInto does
+func (t *BPlusTree[K, V]) UnmarshalBinary(data []byte) error {
+	return ordered.UnmarshalInto[K, V](t, data, func() { t.root, t.length = &node[K, V]{}, 0 })
+}
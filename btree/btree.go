@@ -0,0 +1,502 @@
+// Package btree provides a generic in-memory B-tree ordered map.
+package btree
+
+import (
+	"github.com/hanyangtay/go-datastructures/ordered"
+	"slices"
+)
+
+var _ ordered.Map[int, int] = (*BTree[int, int])(nil)
+
+// node holds between degree-1 and 2*degree-1 sorted keys, except for the
+// root, which may hold fewer. Inner nodes have one more child than keys:
+// children[i] holds the keys between keys[i-1] and keys[i].
+type node[K, V any] struct {
+	keys     []K
+	values   []V
+	children []*node[K, V] // nil for leaves
+}
+
+func (n *node[K, V]) leaf() bool { return n.children == nil }
+
+// BTree is an ordered map from keys to values, with keys ordered by a less
+// function. Every node stores many keys in contiguous slices, so a lookup
+// touches far fewer cache lines than in a binary tree, which makes B-trees
+// considerably faster once the map holds millions of keys. The degree sets
+// the node size: nodes hold up to 2*degree-1 keys. Degrees between 16 and 64
+// work well in memory.
+type BTree[K, V any] struct {
+	root   *node[K, V]
+	degree int
+	length int
+	less   func(a, b K) bool
+}
+
+// New returns an empty tree of the given degree ordered by less. It panics if
+// degree is less than 2.
+func New[K, V any](degree int, less func(a, b K) bool) *BTree[K, V] {
+	if degree < 2 {
+		panic("btree: degree must be at least 2")
+	}
+	return &BTree[K, V]{degree: degree, less: less}
+}
+
+// FromSorted builds a tree of the given degree from keys in strictly
+// increasing order and their values, in O(n). Nodes are filled close to
+// capacity, which suits maps that are mostly read. It panics if the keys are
+// not strictly increasing or the slices differ in length.
+func FromSorted[K, V any](degree int, less func(a, b K) bool, keys []K, values []V) *BTree[K, V] {
+	t := New[K, V](degree, less)
+	if len(keys) != len(values) {
+		panic("btree: FromSorted with different number of keys and values")
+	}
+	for i := 1; i < len(keys); i++ {
+		if !less(keys[i-1], keys[i]) {
+			panic("btree: FromSorted with keys not in strictly increasing order")
+		}
+	}
+	if len(keys) == 0 {
+		return t
+	}
+
+	maxKeys := 2*degree - 1
+
+	// distribute the entries over as few leaves as possible, with a
+	// separating entry between neighbouring leaves to move up a level
+	leaves := (len(keys) + maxKeys + 1) / (maxKeys + 1)
+	level := make([]*node[K, V], 0, leaves)
+	var sepKeys []K
+	var sepValues []V
+
+	base, extra := (len(keys)-leaves+1)/leaves, (len(keys)-leaves+1)%leaves
+	for i, j := 0, 0; i < leaves; i++ {
+		size := base
+		if i < extra {
+			size++
+		}
+		level = append(level, t.newNode(keys[j:j+size], values[j:j+size], nil))
+		j += size
+
+		if i < leaves-1 {
+			sepKeys = append(sepKeys, keys[j])
+			sepValues = append(sepValues, values[j])
+			j++
+		}
+	}
+
+	// group the nodes of each level under parents, until one is left
+	maxChildren := 2 * degree
+	for len(level) > 1 {
+		parents := (len(level) + maxChildren - 1) / maxChildren
+		next := make([]*node[K, V], 0, parents)
+		var nextKeys []K
+		var nextValues []V
+
+		base, extra := len(level)/parents, len(level)%parents
+		for i, j := 0, 0; i < parents; i++ {
+			size := base
+			if i < extra {
+				size++
+			}
+			next = append(next, t.newNode(sepKeys[j:j+size-1], sepValues[j:j+size-1], level[j:j+size]))
+
+			if i < parents-1 {
+				nextKeys = append(nextKeys, sepKeys[j+size-1])
+				nextValues = append(nextValues, sepValues[j+size-1])
+			}
+			j += size
+		}
+
+		level, sepKeys, sepValues = next, nextKeys, nextValues
+	}
+
+	t.root = level[0]
+	t.length = len(keys)
+	return t
+}
+
+// newNode returns a node holding copies of keys, values and children, with
+// room for a full node
+func (t *BTree[K, V]) newNode(keys []K, values []V, children []*node[K, V]) *node[K, V] {
+	n := &node[K, V]{
+		keys:   append(make([]K, 0, 2*t.degree-1), keys...),
+		values: append(make([]V, 0, 2*t.degree-1), values...),
+	}
+	if children != nil {
+		n.children = append(make([]*node[K, V], 0, 2*t.degree), children...)
+	}
+	return n
+}
+
+// Len returns the number of entries
+func (t *BTree[K, V]) Len() int { return t.length }
+
+// search returns the index of the first key of n not less than key, and
+// whether it equals key
+func (t *BTree[K, V]) search(n *node[K, V], key K) (int, bool) {
+	lo, hi := 0, len(n.keys)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if t.less(n.keys[mid], key) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, lo < len(n.keys) && !t.less(key, n.keys[lo])
+}
+
+// Get returns the value stored under key, and whether it was found
+func (t *BTree[K, V]) Get(key K) (V, bool) {
+	for n := t.root; n != nil; {
+		i, found := t.search(n, key)
+		if found {
+			return n.values[i], true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores value under key, replacing any previous value.
+// Time complexity: O(degree log n / log degree)
+func (t *BTree[K, V]) Put(key K, value V) {
+	if t.root == nil {
+		t.root = t.newNode(nil, nil, nil)
+	}
+
+	// split a full root first, so that there is room for a key moving up
+	if len(t.root.keys) == 2*t.degree-1 {
+		old := t.root
+		t.root = t.newNode(nil, nil, []*node[K, V]{old})
+		t.splitChild(t.root, 0)
+	}
+
+	// descend, splitting full nodes on the way
+	n := t.root
+	for {
+		i, found := t.search(n, key)
+		if found {
+			n.values[i] = value
+			return
+		}
+
+		if n.leaf() {
+			n.keys = slices.Insert(n.keys, i, key)
+			n.values = slices.Insert(n.values, i, value)
+			t.length++
+			return
+		}
+
+		if len(n.children[i].keys) == 2*t.degree-1 {
+			t.splitChild(n, i)
+			switch {
+			case t.less(n.keys[i], key):
+				i++
+			case !t.less(key, n.keys[i]):
+				n.values[i] = value
+				return
+			}
+		}
+		n = n.children[i]
+	}
+}
+
+// splitChild splits the full child i of n in two, moving its middle entry up
+// into n
+func (t *BTree[K, V]) splitChild(n *node[K, V], i int) {
+	child := n.children[i]
+	mid := t.degree - 1
+
+	var children []*node[K, V]
+	if !child.leaf() {
+		children = child.children[mid+1:]
+	}
+	right := t.newNode(child.keys[mid+1:], child.values[mid+1:], children)
+
+	n.keys = slices.Insert(n.keys, i, child.keys[mid])
+	n.values = slices.Insert(n.values, i, child.values[mid])
+	n.children = slices.Insert(n.children, i+1, right)
+
+	child.keys = truncate(child.keys, mid)
+	child.values = truncate(child.values, mid)
+	if !child.leaf() {
+		child.children = truncate(child.children, mid+1)
+	}
+}
+
+// Delete removes key and reports whether it was present.
+// Time complexity: O(degree log n / log degree)
+func (t *BTree[K, V]) Delete(key K) bool {
+	if t.root == nil {
+		return false
+	}
+
+	found := t.delete(t.root, key)
+
+	// shrink the tree once the root has run out of keys
+	if len(t.root.keys) == 0 {
+		if t.root.leaf() {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+
+	if found {
+		t.length--
+	}
+	return found
+}
+
+// delete removes key from the subtree at n, which holds at least degree keys
+// unless it is the root, so that it can give up a key
+func (t *BTree[K, V]) delete(n *node[K, V], key K) bool {
+	i, found := t.search(n, key)
+
+	if n.leaf() {
+		if found {
+			n.keys = slices.Delete(n.keys, i, i+1)
+			n.values = slices.Delete(n.values, i, i+1)
+		}
+		return found
+	}
+
+	if found {
+		switch {
+		case len(n.children[i].keys) >= t.degree:
+			// replace the key by its predecessor
+			pred := n.children[i]
+			for !pred.leaf() {
+				pred = pred.children[len(pred.children)-1]
+			}
+			last := len(pred.keys) - 1
+			n.keys[i], n.values[i] = pred.keys[last], pred.values[last]
+			return t.delete(n.children[i], n.keys[i])
+		case len(n.children[i+1].keys) >= t.degree:
+			// replace the key by its successor
+			succ := n.children[i+1]
+			for !succ.leaf() {
+				succ = succ.children[0]
+			}
+			n.keys[i], n.values[i] = succ.keys[0], succ.values[0]
+			return t.delete(n.children[i+1], n.keys[i])
+		default:
+			t.merge(n, i)
+			return t.delete(n.children[i], key)
+		}
+	}
+
+	if len(n.children[i].keys) < t.degree {
+		i = t.fill(n, i)
+	}
+	return t.delete(n.children[i], key)
+}
+
+// fill gives child i of n, which has the minimum number of keys, another key
+// by borrowing from a sibling or merging with one. It returns the new index
+// of the child.
+func (t *BTree[K, V]) fill(n *node[K, V], i int) int {
+	child := n.children[i]
+
+	if i > 0 && len(n.children[i-1].keys) >= t.degree {
+		// rotate the last key of the left sibling through n
+		left := n.children[i-1]
+		last := len(left.keys) - 1
+
+		child.keys = slices.Insert(child.keys, 0, n.keys[i-1])
+		child.values = slices.Insert(child.values, 0, n.values[i-1])
+		n.keys[i-1], n.values[i-1] = left.keys[last], left.values[last]
+		left.keys = truncate(left.keys, last)
+		left.values = truncate(left.values, last)
+
+		if !child.leaf() {
+			child.children = slices.Insert(child.children, 0, left.children[last+1])
+			left.children = truncate(left.children, last+1)
+		}
+		return i
+	}
+
+	if i < len(n.children)-1 && len(n.children[i+1].keys) >= t.degree {
+		// rotate the first key of the right sibling through n
+		right := n.children[i+1]
+
+		child.keys = append(child.keys, n.keys[i])
+		child.values = append(child.values, n.values[i])
+		n.keys[i], n.values[i] = right.keys[0], right.values[0]
+		right.keys = slices.Delete(right.keys, 0, 1)
+		right.values = slices.Delete(right.values, 0, 1)
+
+		if !child.leaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = slices.Delete(right.children, 0, 1)
+		}
+		return i
+	}
+
+	if i < len(n.children)-1 {
+		t.merge(n, i)
+		return i
+	}
+	t.merge(n, i-1)
+	return i - 1
+}
+
+// merge joins child i+1 of n and the key separating it from child i into
+// child i
+func (t *BTree[K, V]) merge(n *node[K, V], i int) {
+	left, right := n.children[i], n.children[i+1]
+
+	left.keys = append(append(left.keys, n.keys[i]), right.keys...)
+	left.values = append(append(left.values, n.values[i]), right.values...)
+	if !left.leaf() {
+		left.children = append(left.children, right.children...)
+	}
+
+	n.keys = slices.Delete(n.keys, i, i+1)
+	n.values = slices.Delete(n.values, i, i+1)
+	n.children = slices.Delete(n.children, i+1, i+2)
+}
+
+// Min returns the entry with the least key, or false if the tree is empty
+func (t *BTree[K, V]) Min() (K, V, bool) {
+	n := t.root
+	if n == nil {
+		return entry[K, V](nil, 0)
+	}
+	for !n.leaf() {
+		n = n.children[0]
+	}
+	return entry(n, 0)
+}
+
+// Max returns the entry with the greatest key, or false if the tree is empty
+func (t *BTree[K, V]) Max() (K, V, bool) {
+	n := t.root
+	if n == nil {
+		return entry[K, V](nil, 0)
+	}
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+	return entry(n, len(n.keys)-1)
+}
+
+// Floor returns the entry with the greatest key not greater than key, or
+// false if there is none
+func (t *BTree[K, V]) Floor(key K) (K, V, bool) {
+	var best *node[K, V]
+	besti := 0
+	for n := t.root; n != nil; {
+		i, found := t.search(n, key)
+		if found {
+			return entry(n, i)
+		}
+		if i > 0 {
+			best, besti = n, i-1
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return entry(best, besti)
+}
+
+// Ceiling returns the entry with the least key not less than key, or false
+// if there is none
+func (t *BTree[K, V]) Ceiling(key K) (K, V, bool) {
+	var best *node[K, V]
+	besti := 0
+	for n := t.root; n != nil; {
+		i, found := t.search(n, key)
+		if found {
+			return entry(n, i)
+		}
+		if i < len(n.keys) {
+			best, besti = n, i
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return entry(best, besti)
+}
+
+// Ascend calls fn for every entry in key order until fn returns false
+func (t *BTree[K, V]) Ascend(fn func(key K, value V) bool) {
+	if t.root != nil {
+		t.walk(t.root, nil, nil, fn)
+	}
+}
+
+// Range calls fn in key order for every entry with a key from lo up to but
+// excluding hi, until fn returns false
+func (t *BTree[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	if t.root != nil {
+		t.walk(t.root, &lo, &hi, fn)
+	}
+}
+
+// walk calls fn in order for the entries below n with keys in [lo, hi),
+// where nil bounds are open, and reports whether the walk should go on
+func (t *BTree[K, V]) walk(n *node[K, V], lo, hi *K, fn func(key K, value V) bool) bool {
+	i := 0
+	if lo != nil {
+		i, _ = t.search(n, *lo)
+	}
+
+	for ; i <= len(n.keys); i++ {
+		if !n.leaf() && !t.walk(n.children[i], lo, hi, fn) {
+			return false
+		}
+		if i == len(n.keys) {
+			break
+		}
+		if hi != nil && !t.less(n.keys[i], *hi) {
+			return false
+		}
+		if !fn(n.keys[i], n.values[i]) {
+			return false
+		}
+
+		// only the leftmost subtree visited can hold keys below lo
+		lo = nil
+	}
+	return true
+}
+
+// entry unpacks entry i of n, reporting false if n is nil
+func entry[K, V any](n *node[K, V], i int) (K, V, bool) {
+	if n == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return n.keys[i], n.values[i], true
+}
+
+// truncate shortens s to n elements, clearing the rest so that they do not
+// retain memory
+func truncate[T any](s []T, n int) []T {
+	var zero T
+	for i := n; i < len(s); i++ {
+		s[i] = zero
+	}
+	return s[:n]
+}
+
+// MarshalBinary encodes the tree with ordered.Marshal
+func (t *BTree[K, V]) MarshalBinary() ([]byte, error) {
+	return ordered.Marshal[K, V](t)
+}
+
+// UnmarshalBinary replaces the entries of the tree as ordered.UnmarshalInto does
+func (t *BTree[K, V]) UnmarshalBinary(data []byte) error {
+	return ordered.UnmarshalInto[K, V](t, data, func() { t.root, t.length = nil, 0 })
+}
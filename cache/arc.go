@@ -0,0 +1,173 @@
+package cache
+
+// the lists of an ARC cache
+const (
+	arcT1 = iota // cached, used once recently
+	arcT2        // cached, used at least twice recently
+	arcB1        // evicted from T1, keys only
+	arcB2        // evicted from T2, keys only
+)
+
+type arcEntry[K comparable, V any] struct {
+	key   K
+	value V
+	list  int
+}
+
+// ARC is an adaptive replacement cache, which balances between evicting by
+// recency and by frequency on its own. Entries used once are kept in a list
+// T1, entries used again in a list T2, both in LRU order, and the keys of
+// entries recently evicted from either in ghost lists B1 and B2. A miss on a
+// ghost key shows that the list it was evicted from was too short, and
+// shifts the target size of T1 accordingly. This resists scans that would
+// flush an LRU cache and adapts to shifting hot sets that would defeat LFU.
+// All operations take O(1); ghost keys take up to capacity extra entries.
+type ARC[K comparable, V any] struct {
+	capacity int
+	target   int // target length of T1
+	items    map[K]*element[arcEntry[K, V]]
+	lists    [4]list[arcEntry[K, V]] // most recently used first
+	onEvict  func(key K, value V)
+}
+
+var _ Cache[int, int] = (*ARC[int, int])(nil)
+
+// NewARC returns an empty ARC cache of the given capacity. If onEvict is not
+// nil, it is called with every entry evicted to make room, but not with
+// entries removed or replaced explicitly. It panics if capacity is not
+// positive.
+func NewARC[K comparable, V any](capacity int, onEvict func(key K, value V)) *ARC[K, V] {
+	if capacity < 1 {
+		panic("cache: capacity must be positive")
+	}
+	c := &ARC[K, V]{capacity: capacity, items: make(map[K]*element[arcEntry[K, V]]), onEvict: onEvict}
+	for i := range c.lists {
+		c.lists[i].init()
+	}
+	return c
+}
+
+// move moves e to the front of list
+func (c *ARC[K, V]) move(e *element[arcEntry[K, V]], list int) {
+	c.lists[e.value.list].remove(e)
+	c.lists[list].insertAfter(e, &c.lists[list].root)
+	e.value.list = list
+}
+
+// drop deletes the least recently used entry of a ghost list
+func (c *ARC[K, V]) drop(list int) {
+	e := c.lists[list].back()
+	c.lists[list].remove(e)
+	delete(c.items, e.value.key)
+}
+
+// replace evicts the least recently used entry of T1 or T2 into its ghost
+// list, choosing T1 if it exceeds its target. inB2 is set if the entry to be
+// cached was found in B2. The cache must not be empty.
+func (c *ARC[K, V]) replace(inB2 bool) {
+	t1 := c.lists[arcT1].len
+	from, to := arcT2, arcB2
+	if t1 > 0 && (t1 > c.target || inB2 && t1 == c.target || c.lists[arcT2].len == 0) {
+		from, to = arcT1, arcB1
+	}
+
+	e := c.lists[from].back()
+	c.move(e, to)
+	key, value := e.value.key, e.value.value
+	var zero V
+	e.value.value = zero
+	if c.onEvict != nil {
+		c.onEvict(key, value)
+	}
+}
+
+// Get returns the value cached under key, and whether it was found, and
+// moves the entry to T2 as it has now been used more than once
+func (c *ARC[K, V]) Get(key K) (V, bool) {
+	e, ok := c.items[key]
+	if !ok || e.value.list >= arcB1 {
+		var zero V
+		return zero, false
+	}
+	c.move(e, arcT2)
+	return e.value.value, true
+}
+
+// Peek returns the value cached under key, and whether it was found,
+// without counting a use
+func (c *ARC[K, V]) Peek(key K) (V, bool) {
+	if e, ok := c.items[key]; ok && e.value.list < arcB1 {
+		return e.value.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put caches value under key. A new key enters T1, unless it was recently
+// evicted, in which case it enters T2 and adapts the target size of T1.
+func (c *ARC[K, V]) Put(key K, value V) {
+	e, ok := c.items[key]
+	switch {
+	case ok && e.value.list < arcB1:
+		e.value.value = value
+		c.move(e, arcT2)
+		return
+
+	case ok:
+		// a ghost hit: grow the list the key was evicted from
+		b1, b2 := c.lists[arcB1].len, c.lists[arcB2].len
+		inB2 := e.value.list == arcB2
+		if inB2 {
+			c.target = max(c.target-max(b1/b2, 1), 0)
+		} else {
+			c.target = min(c.target+max(b2/b1, 1), c.capacity)
+		}
+		if c.Len() == c.capacity {
+			c.replace(inB2)
+		}
+		e.value.value = value
+		c.move(e, arcT2)
+		return
+	}
+
+	t1, b1 := c.lists[arcT1].len, c.lists[arcB1].len
+	full := c.Len() == c.capacity
+	switch {
+	case t1 == c.capacity:
+		// T1 fills the cache, evict from it without a ghost
+		e := c.lists[arcT1].back()
+		c.lists[arcT1].remove(e)
+		delete(c.items, e.value.key)
+		if c.onEvict != nil {
+			c.onEvict(e.value.key, e.value.value)
+		}
+		full = false
+	case t1+b1 == c.capacity:
+		c.drop(arcB1)
+	case t1+b1+c.lists[arcT2].len+c.lists[arcB2].len == 2*c.capacity:
+		c.drop(arcB2)
+	}
+	if full {
+		c.replace(false)
+	}
+
+	c.items[key] = c.lists[arcT1].pushFront(arcEntry[K, V]{key, value, arcT1})
+}
+
+// Remove removes key and reports whether it was cached. A ghost of key is
+// forgotten as well.
+func (c *ARC[K, V]) Remove(key K) bool {
+	e, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.lists[e.value.list].remove(e)
+	delete(c.items, key)
+	return e.value.list < arcB1
+}
+
+// Len returns the number of entries
+func (c *ARC[K, V]) Len() int { return c.lists[arcT1].len + c.lists[arcT2].len }
+
+// Cap returns the greatest number of entries
+func (c *ARC[K, V]) Cap() int { return c.capacity }
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"testing"
+)
+
+func TestARCBounded(t *testing.T) {
+	evicted := make(map[int]int)
+	checkBounded(t, NewARC(50, func(key, value int) { evicted[key] = value }), evicted)
+}
+
+// TestARCScan checks that a hot set used repeatedly survives a scan of keys
+// used once, which would flush an LRU cache of the same capacity
+func TestARCScan(t *testing.T) {
+	arc, lru := NewARC[int, int](100, nil), NewLRU[int, int](100, nil)
+	for round := 0; round < 3; round++ {
+		for key := 0; key < 50; key++ {
+			for _, c := range []Cache[int, int]{arc, lru} {
+				if _, ok := c.Get(key); !ok {
+					c.Put(key, key)
+				}
+			}
+		}
+	}
+	for key := 1000; key < 1200; key++ {
+		arc.Put(key, key)
+		lru.Put(key, key)
+	}
+
+	hits := 0
+	for key := 0; key < 50; key++ {
+		if _, ok := arc.Peek(key); ok {
+			hits++
+		}
+		if _, ok := lru.Peek(key); ok {
+			t.Fatalf("LRU kept %d through the scan", key)
+		}
+	}
+	if hits != 50 {
+		t.Fatalf("ARC kept %d of 50 hot keys through the scan", hits)
+	}
+}
+
+// TestARCGhostHit checks that putting a recently evicted key caches it
+// again as a frequently used entry
+func TestARCGhostHit(t *testing.T) {
+	c := NewARC[int, int](2, nil)
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(3, 3) // evicts 1 into B1
+	if _, ok := c.Peek(1); ok || c.Len() != 2 {
+		t.Fatalf("got 1 still cached with length %d, want evicted", c.Len())
+	}
+
+	c.Put(1, 10)
+	if v, ok := c.Get(1); !ok || v != 10 || c.Len() != 2 {
+		t.Fatalf("Get(1) = %d, %v with length %d, want 10 with length 2", v, ok, c.Len())
+	}
+	if c.Remove(2) && c.Remove(3) {
+		t.Fatal("both 2 and 3 still cached after the ghost hit")
+	}
+}
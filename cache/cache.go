@@ -0,0 +1,90 @@
+// Package cache provides capacity bounded in-memory caches with different
+// eviction policies behind a common interface.
+package cache
+
+import "sync"
+
+// Cache is a map of bounded capacity which evicts entries to make room for
+// new ones. Implementations differ in the entries they choose to evict.
+// Unless stated otherwise they are not safe for concurrent use; see
+// Synchronized.
+type Cache[K comparable, V any] interface {
+	// Get returns the value cached under key, and whether it was found,
+	// counting as a use of the entry
+	Get(key K) (V, bool)
+
+	// Peek is like Get, but does not count as a use
+	Peek(key K) (V, bool)
+
+	// Put caches value under key, replacing any previous value and evicting
+	// another entry if the cache is full
+	Put(key K, value V)
+
+	// Remove removes key and reports whether it was cached
+	Remove(key K) bool
+
+	// Len returns the number of entries
+	Len() int
+
+	// Cap returns the greatest number of entries
+	Cap() int
+}
+
+// Synchronized wraps a cache for concurrent use by serializing all calls
+// through a mutex. Since Get updates the state of the cache, readers cannot
+// share the lock. Eviction callbacks run while the lock is held and must not
+// call back into the cache.
+type Synchronized[K comparable, V any] struct {
+	mu    sync.Mutex
+	cache Cache[K, V]
+}
+
+var _ Cache[int, int] = (*Synchronized[int, int])(nil)
+
+// NewSynchronized returns a concurrency safe view of c, which must not be
+// used directly afterwards
+func NewSynchronized[K comparable, V any](c Cache[K, V]) *Synchronized[K, V] {
+	return &Synchronized[K, V]{cache: c}
+}
+
+// Get returns the value cached under key, and whether it was found
+func (s *Synchronized[K, V]) Get(key K) (V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache.Get(key)
+}
+
+// Peek returns the value cached under key without counting it as a use
+func (s *Synchronized[K, V]) Peek(key K) (V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache.Peek(key)
+}
+
+// Put caches value under key
+func (s *Synchronized[K, V]) Put(key K, value V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache.Put(key, value)
+}
+
+// Remove removes key and reports whether it was cached
+func (s *Synchronized[K, V]) Remove(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache.Remove(key)
+}
+
+// Len returns the number of entries
+func (s *Synchronized[K, V]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache.Len()
+}
+
+// Cap returns the greatest number of entries
+func (s *Synchronized[K, V]) Cap() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache.Cap()
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// checkBounded puts random keys into c, checking that values are found
+// until evicted, that it never holds more than its capacity, and that every
+// entry dropped to make room is passed to the eviction callback, which must
+// record into evicted
+func checkBounded(t *testing.T, c Cache[int, int], evicted map[int]int) {
+	t.Helper()
+	rng := rand.New(rand.NewSource(1))
+	cached := make(map[int]int)
+
+	for i := 0; i < 5000; i++ {
+		key := rng.Intn(3 * c.Cap())
+		switch rng.Intn(4) {
+		case 0:
+			if c.Remove(key) != (cached[key] != 0) {
+				t.Fatalf("Remove(%d) disagrees with the model", key)
+			}
+			delete(cached, key)
+		case 1:
+			if v, ok := c.Get(key); ok != (cached[key] != 0) || v != cached[key] {
+				t.Fatalf("Get(%d) = %d, %v, want %d", key, v, ok, cached[key])
+			}
+		default:
+			c.Put(key, i+1)
+			cached[key] = i + 1
+		}
+
+		for key, v := range evicted {
+			if cached[key] != v {
+				t.Fatalf("evicted %d=%d, which was not cached", key, v)
+			}
+			delete(cached, key)
+			delete(evicted, key)
+		}
+		if c.Len() != len(cached) || c.Len() > c.Cap() {
+			t.Fatalf("got length %d, want %d, at most %d", c.Len(), len(cached), c.Cap())
+		}
+	}
+
+	for key, v := range cached {
+		if got, ok := c.Peek(key); !ok || got != v {
+			t.Fatalf("Peek(%d) = %d, %v, want %d", key, got, ok, v)
+		}
+	}
+}
+
+func TestSynchronized(t *testing.T) {
+	c := NewSynchronized[int, int](NewLRU[int, int](100, nil))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				c.Put(g*1000+i, i)
+				c.Get(g*1000 + i/2)
+				c.Remove(g*1000 + i/3)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if c.Len() > c.Cap() || c.Cap() != 100 {
+		t.Fatalf("got length %d and capacity %d, want at most 100", c.Len(), c.Cap())
+	}
+}
@@ -0,0 +1,269 @@
+package cache
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hanyangtay/go-datastructures/indexedpq"
+	"github.com/hanyangtay/go-datastructures/internal/wire"
+)
+
+// ErrInvalidData is returned when decoding data that is not an encoded cache
+// of the kind decoded into
+var ErrInvalidData = errors.New("cache: invalid encoding")
+
+// encodingVersion is the version of the layouts written by MarshalBinary
+const encodingVersion = 1
+
+// The encodings of the caches hold their capacity and entries, with keys and
+// values encoded by encoding/gob, and the state of the eviction policy, so
+// that a decoded cache evicts as the encoded one would have. Lists of
+// entries are in order of use, least recently used first, which is the
+// order in which putting them rebuilds the lists.
+
+type lruPayload[K comparable, V any] struct {
+	Capacity int
+	Keys     []K
+	Values   []V
+}
+
+type lfuPayload[K comparable, V any] struct {
+	Capacity int
+	Keys     []K
+	Values   []V
+	Freqs    []int // in increasing order
+}
+
+type arcPayload[K comparable, V any] struct {
+	Capacity int
+	Target   int
+	Keys     [4][]K // of T1, T2, B1 and B2
+	Values   [2][]V // of T1 and T2
+}
+
+type ttlPayload[K comparable, V any] struct {
+	Capacity int
+	TTL      time.Duration
+	Keys     []K
+	Values   []V
+	Expiries []time.Time // the zero time for entries that never expire
+}
+
+// decode decodes data encoded as kind into p, wrapping any error in
+// ErrInvalidData
+func decode(data []byte, kind string, p interface{}) error {
+	if err := wire.Unmarshal(data, kind, encodingVersion, p); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	return nil
+}
+
+// distinct reports whether no key occurs more than once in lists
+func distinct[K comparable](lists ...[]K) bool {
+	seen := make(map[K]bool)
+	for _, keys := range lists {
+		for _, key := range keys {
+			if seen[key] {
+				return false
+			}
+			seen[key] = true
+		}
+	}
+	return true
+}
+
+// MarshalBinary encodes the capacity and entries of the cache in order of
+// use
+func (c *LRU[K, V]) MarshalBinary() ([]byte, error) {
+	p := lruPayload[K, V]{Capacity: c.capacity}
+	for e := c.order.back(); e != nil; e = c.order.prev(e) {
+		p.Keys = append(p.Keys, e.value.key)
+		p.Values = append(p.Values, e.value.value)
+	}
+	return wire.Marshal("cache.LRU", encodingVersion, &p)
+}
+
+// UnmarshalBinary replaces the capacity and entries of the cache with those
+// encoded by MarshalBinary, keeping its eviction callback. ErrInvalidData is
+// returned if data is malformed.
+func (c *LRU[K, V]) UnmarshalBinary(data []byte) error {
+	var p lruPayload[K, V]
+	if err := decode(data, "cache.LRU", &p); err != nil {
+		return err
+	}
+	if p.Capacity < 1 || len(p.Keys) != len(p.Values) || len(p.Keys) > p.Capacity || !distinct(p.Keys) {
+		return ErrInvalidData
+	}
+
+	c.capacity = p.Capacity
+	c.items = make(map[K]*element[entry[K, V]], len(p.Keys))
+	c.order.init()
+	for i, key := range p.Keys {
+		c.items[key] = c.order.pushFront(entry[K, V]{key, p.Values[i]})
+	}
+	return nil
+}
+
+// MarshalBinary encodes the capacity and entries of the cache with their
+// number of uses, in order of use within the same number
+func (c *LFU[K, V]) MarshalBinary() ([]byte, error) {
+	p := lfuPayload[K, V]{Capacity: c.capacity}
+	for b := c.buckets.front(); b != nil; b = c.buckets.next(b) {
+		entries := &b.value.entries
+		for e := entries.back(); e != nil; e = entries.prev(e) {
+			p.Keys = append(p.Keys, e.value.key)
+			p.Values = append(p.Values, e.value.value)
+			p.Freqs = append(p.Freqs, b.value.freq)
+		}
+	}
+	return wire.Marshal("cache.LFU", encodingVersion, &p)
+}
+
+// UnmarshalBinary replaces the capacity and entries of the cache with those
+// encoded by MarshalBinary, keeping its eviction callback. ErrInvalidData is
+// returned if data is malformed.
+func (c *LFU[K, V]) UnmarshalBinary(data []byte) error {
+	var p lfuPayload[K, V]
+	if err := decode(data, "cache.LFU", &p); err != nil {
+		return err
+	}
+	n := len(p.Keys)
+	if p.Capacity < 1 || len(p.Values) != n || len(p.Freqs) != n || n > p.Capacity || !distinct(p.Keys) {
+		return ErrInvalidData
+	}
+	for i, freq := range p.Freqs {
+		if freq < 1 || i > 0 && freq < p.Freqs[i-1] {
+			return ErrInvalidData
+		}
+	}
+
+	c.capacity, c.length = p.Capacity, n
+	c.items = make(map[K]*element[lfuEntry[K, V]], n)
+	c.buckets.init()
+	var b *element[*lfuBucket[K, V]]
+	for i, key := range p.Keys {
+		if b == nil || b.value.freq != p.Freqs[i] {
+			b = c.bucketAfter(b, p.Freqs[i])
+		}
+		c.items[key] = b.value.entries.pushFront(lfuEntry[K, V]{key, p.Values[i], b})
+	}
+	return nil
+}
+
+// MarshalBinary encodes the capacity, the target size of T1, and the entries
+// of T1 and T2 and the ghost keys of B1 and B2, all in order of use
+func (c *ARC[K, V]) MarshalBinary() ([]byte, error) {
+	p := arcPayload[K, V]{Capacity: c.capacity, Target: c.target}
+	for i := range c.lists {
+		l := &c.lists[i]
+		for e := l.back(); e != nil; e = l.prev(e) {
+			p.Keys[i] = append(p.Keys[i], e.value.key)
+			if i < arcB1 {
+				p.Values[i] = append(p.Values[i], e.value.value)
+			}
+		}
+	}
+	return wire.Marshal("cache.ARC", encodingVersion, &p)
+}
+
+// UnmarshalBinary replaces the state of the cache with that encoded by
+// MarshalBinary, keeping its eviction callback. ErrInvalidData is returned
+// if data is malformed.
+func (c *ARC[K, V]) UnmarshalBinary(data []byte) error {
+	var p arcPayload[K, V]
+	if err := decode(data, "cache.ARC", &p); err != nil {
+		return err
+	}
+	t1, t2, b1, b2 := len(p.Keys[arcT1]), len(p.Keys[arcT2]), len(p.Keys[arcB1]), len(p.Keys[arcB2])
+	switch {
+	case p.Capacity < 1 || p.Target < 0 || p.Target > p.Capacity:
+		return ErrInvalidData
+	case len(p.Values[arcT1]) != t1 || len(p.Values[arcT2]) != t2:
+		return ErrInvalidData
+	case t1+t2 > p.Capacity || t1+b1 > p.Capacity || t1+t2+b1+b2 > 2*p.Capacity:
+		return ErrInvalidData
+	case !distinct(p.Keys[:]...):
+		return ErrInvalidData
+	}
+
+	c.capacity, c.target = p.Capacity, p.Target
+	c.items = make(map[K]*element[arcEntry[K, V]], t1+t2+b1+b2)
+	for i := range c.lists {
+		c.lists[i].init()
+		for j, key := range p.Keys[i] {
+			e := arcEntry[K, V]{key: key, list: i}
+			if i < arcB1 {
+				e.value = p.Values[i][j]
+			}
+			c.items[key] = c.lists[i].pushFront(e)
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the capacity, the default time to live, and the
+// entries of the cache with their expiry times, including entries that have
+// expired but not been removed yet
+func (c *TTL[K, V]) MarshalBinary() ([]byte, error) {
+	c.mu.Lock()
+	p := ttlPayload[K, V]{Capacity: c.capacity, TTL: c.ttl}
+	for key, e := range c.items {
+		p.Keys = append(p.Keys, key)
+		p.Values = append(p.Values, e.value)
+		p.Expiries = append(p.Expiries, e.expiry.Priority())
+	}
+	c.mu.Unlock()
+	return wire.Marshal("cache.TTL", encodingVersion, &p)
+}
+
+// UnmarshalBinary replaces the capacity, default time to live and entries
+// of the cache with those encoded by MarshalBinary, keeping its eviction
+// callback. Entries keep their expiry times, so those that expired in the
+// meantime are removed as usual. ErrInvalidData is returned if data is
+// malformed.
+func (c *TTL[K, V]) UnmarshalBinary(data []byte) error {
+	var p ttlPayload[K, V]
+	if err := decode(data, "cache.TTL", &p); err != nil {
+		return err
+	}
+	n := len(p.Keys)
+	if p.Capacity < 1 || len(p.Values) != n || len(p.Expiries) != n || n > p.Capacity || !distinct(p.Keys) {
+		return ErrInvalidData
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capacity, c.ttl = p.Capacity, p.TTL
+	c.items = make(map[K]*ttlEntry[K, V], n)
+	c.expiries = indexedpq.New[K](expiresBefore)
+	for i, key := range p.Keys {
+		c.items[key] = &ttlEntry[K, V]{value: p.Values[i], expiry: c.expiries.Push(key, p.Expiries[i])}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the wrapped cache, which must implement
+// encoding.BinaryMarshaler as the caches of this package do
+func (s *Synchronized[K, V]) MarshalBinary() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.cache.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("cache: %T cannot be encoded", s.cache)
+	}
+	return m.MarshalBinary()
+}
+
+// UnmarshalBinary decodes data into the wrapped cache, which must implement
+// encoding.BinaryUnmarshaler as the caches of this package do
+func (s *Synchronized[K, V]) UnmarshalBinary(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.cache.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("cache: %T cannot be decoded into", s.cache)
+	}
+	return u.UnmarshalBinary(data)
+}
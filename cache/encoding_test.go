@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"encoding"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// encodable is a cache of this package
+type encodable interface {
+	Cache[int, int]
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// TestEncoding checks that a decoded cache holds the same entries as the
+// encoded one, and goes on to evict the same entries
+func TestEncoding(t *testing.T) {
+	for name, newCache := range map[string]func(onEvict func(key, value int)) encodable{
+		"LRU": func(onEvict func(key, value int)) encodable { return NewLRU(20, onEvict) },
+		"LFU": func(onEvict func(key, value int)) encodable { return NewLFU(20, onEvict) },
+		"ARC": func(onEvict func(key, value int)) encodable { return NewARC(20, onEvict) },
+		"TTL": func(onEvict func(key, value int)) encodable { return NewTTL(20, time.Hour, onEvict) },
+	} {
+		var got, want []int
+		a := newCache(func(key, _ int) { want = append(want, key) })
+		rng := rand.New(rand.NewSource(1))
+		for i := 0; i < 200; i++ {
+			if key := rng.Intn(60); rng.Intn(2) == 0 {
+				a.Put(key, i)
+			} else {
+				a.Get(key)
+			}
+		}
+
+		data, err := a.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := newCache(func(key, _ int) { got = append(got, key) })
+		if err := b.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if b.Len() != a.Len() || b.Cap() != a.Cap() {
+			t.Fatalf("%s: decoded length %d and capacity %d, want %d and %d", name, b.Len(), b.Cap(), a.Len(), a.Cap())
+		}
+		for key := 0; key < 60; key++ {
+			va, oka := a.Peek(key)
+			vb, okb := b.Peek(key)
+			if va != vb || oka != okb {
+				t.Fatalf("%s: decoded Peek(%d) = %d, %v, want %d, %v", name, key, vb, okb, va, oka)
+			}
+		}
+
+		want = want[:0]
+		for i := 0; i < 200; i++ {
+			key := rng.Intn(100)
+			for _, c := range []Cache[int, int]{a, b} {
+				if i%3 == 0 {
+					c.Get(key)
+				} else {
+					c.Put(key, i)
+				}
+			}
+		}
+		// TTL evicts by expiry, which ties between entries put at once
+		if name == "TTL" {
+			continue
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s: decoded cache evicted %d entries, want %d", name, len(got), len(want))
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("%s: decoded cache evicted %v, want %v", name, got, want)
+			}
+		}
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	c := NewLRU[int, int](2, nil)
+	c.Put(1, 1)
+	data, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewLFU[int, int](2, nil).UnmarshalBinary(data); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("got %v decoding an LRU cache into an LFU cache, want ErrInvalidData", err)
+	}
+	if err := c.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("got %v for truncated data, want ErrInvalidData", err)
+	}
+
+	s := NewSynchronized[int, int](NewLRU[int, int](2, nil))
+	if err := s.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := s.Get(1); !ok || v != 1 {
+		t.Fatalf("Get(1) = %d, %v after decoding into a synchronized cache, want 1", v, ok)
+	}
+}
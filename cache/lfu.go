@@ -0,0 +1,154 @@
+package cache
+
+// lfuBucket holds the entries used freq times, most recently used first
+type lfuBucket[K comparable, V any] struct {
+	freq    int
+	entries list[lfuEntry[K, V]]
+}
+
+type lfuEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	bucket *element[*lfuBucket[K, V]]
+}
+
+// LFU is a cache which evicts the least frequently used entry, and of
+// several such the least recently used one. All operations take O(1):
+// entries are grouped in buckets by their number of uses, and the buckets
+// kept in a list in increasing order of uses, so that a use moves an entry
+// to the next bucket at most.
+type LFU[K comparable, V any] struct {
+	capacity int
+	length   int
+	items    map[K]*element[lfuEntry[K, V]]
+	buckets  list[*lfuBucket[K, V]] // least frequently used first
+	onEvict  func(key K, value V)
+}
+
+var _ Cache[int, int] = (*LFU[int, int])(nil)
+
+// NewLFU returns an empty LFU cache of the given capacity. If onEvict is not
+// nil, it is called with every entry evicted to make room, but not with
+// entries removed or replaced explicitly. It panics if capacity is not
+// positive.
+func NewLFU[K comparable, V any](capacity int, onEvict func(key K, value V)) *LFU[K, V] {
+	if capacity < 1 {
+		panic("cache: capacity must be positive")
+	}
+	c := &LFU[K, V]{capacity: capacity, items: make(map[K]*element[lfuEntry[K, V]]), onEvict: onEvict}
+	c.buckets.init()
+	return c
+}
+
+// bucketAfter returns the bucket for freq, which must directly follow at in
+// frequency, creating it if necessary. at is nil for the front.
+func (c *LFU[K, V]) bucketAfter(at *element[*lfuBucket[K, V]], freq int) *element[*lfuBucket[K, V]] {
+	if at == nil {
+		at = &c.buckets.root
+	}
+	if next := c.buckets.next(at); next != nil && next.value.freq == freq {
+		return next
+	}
+
+	b := &lfuBucket[K, V]{freq: freq}
+	b.entries.init()
+	return c.buckets.insertAfter(&element[*lfuBucket[K, V]]{value: b}, at)
+}
+
+// unlink removes e from its bucket, and the bucket if it becomes empty
+func (c *LFU[K, V]) unlink(e *element[lfuEntry[K, V]]) {
+	b := e.value.bucket
+	b.value.entries.remove(e)
+	if b.value.entries.len == 0 {
+		c.buckets.remove(b)
+	}
+}
+
+// use counts a use of e by moving it to the next bucket
+func (c *LFU[K, V]) use(e *element[lfuEntry[K, V]]) {
+	b := e.value.bucket
+	next := c.bucketAfter(b, b.value.freq+1)
+	c.unlink(e)
+	next.value.entries.insertAfter(e, &next.value.entries.root)
+	e.value.bucket = next
+}
+
+// Get returns the value cached under key, and whether it was found, and
+// counts a use of the entry
+func (c *LFU[K, V]) Get(key K) (V, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.use(e)
+	return e.value.value, true
+}
+
+// Peek returns the value cached under key, and whether it was found,
+// without counting a use
+func (c *LFU[K, V]) Peek(key K) (V, bool) {
+	if e, ok := c.items[key]; ok {
+		return e.value.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put caches value under key. Replacing a value counts as a use; a new entry
+// starts with one use, after evicting the least frequently used entry if the
+// cache is full.
+func (c *LFU[K, V]) Put(key K, value V) {
+	if e, ok := c.items[key]; ok {
+		e.value.value = value
+		c.use(e)
+		return
+	}
+
+	if c.length == c.capacity {
+		c.evict()
+	}
+
+	b := c.bucketAfter(nil, 1)
+	c.items[key] = b.value.entries.pushFront(lfuEntry[K, V]{key, value, b})
+	c.length++
+}
+
+// evict removes the least recently used of the least frequently used
+// entries
+func (c *LFU[K, V]) evict() {
+	e := c.buckets.front().value.entries.back()
+	c.unlink(e)
+	delete(c.items, e.value.key)
+	c.length--
+	if c.onEvict != nil {
+		c.onEvict(e.value.key, e.value.value)
+	}
+}
+
+// Remove removes key and reports whether it was cached
+func (c *LFU[K, V]) Remove(key K) bool {
+	e, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.unlink(e)
+	delete(c.items, key)
+	c.length--
+	return true
+}
+
+// Len returns the number of entries
+func (c *LFU[K, V]) Len() int { return c.length }
+
+// Cap returns the greatest number of entries
+func (c *LFU[K, V]) Cap() int { return c.capacity }
+
+// Frequency returns the number of uses of the entry for key, or 0 if it is
+// not cached
+func (c *LFU[K, V]) Frequency(key K) int {
+	if e, ok := c.items[key]; ok {
+		return e.value.bucket.value.freq
+	}
+	return 0
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"testing"
+)
+
+func TestLFUBounded(t *testing.T) {
+	evicted := make(map[int]int)
+	checkBounded(t, NewLFU(50, func(key, value int) { evicted[key] = value }), evicted)
+}
+
+func TestLFUOrder(t *testing.T) {
+	var evicted []string
+	c := NewLFU(3, func(key string, _ int) { evicted = append(evicted, key) })
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+
+	// a is used three times, b and c twice; of those b least recently
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Put("c", 30)
+	c.Peek("b")
+	c.Put("d", 4)
+	c.Put("e", 5)
+	c.Put("f", 6)
+
+	// d and e are used once, so b goes first and then the newer entries
+	if want := []string{"b", "d", "e"}; len(evicted) != 3 || evicted[0] != want[0] || evicted[1] != want[1] || evicted[2] != want[2] {
+		t.Fatalf("evicted %v, want %v", evicted, want)
+	}
+	for key, want := range map[string]int{"a": 1, "c": 30, "f": 6} {
+		if v, ok := c.Peek(key); !ok || v != want {
+			t.Fatalf("Peek(%s) = %d, %v, want %d", key, v, ok, want)
+		}
+	}
+}
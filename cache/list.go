@@ -0,0 +1,83 @@
+package cache
+
+// element is an element of a list
+type element[T any] struct {
+	value      T
+	prev, next *element[T]
+}
+
+// list is a circular doubly linked list with a sentinel, like container/list
+// but without boxing values or checking ownership
+type list[T any] struct {
+	root element[T]
+	len  int
+}
+
+func (l *list[T]) init() *list[T] {
+	l.root.prev, l.root.next = &l.root, &l.root
+	l.len = 0
+	return l
+}
+
+// front returns the first element, or nil if the list is empty
+func (l *list[T]) front() *element[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// back returns the last element, or nil if the list is empty
+func (l *list[T]) back() *element[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// insertAfter links e after at
+func (l *list[T]) insertAfter(e, at *element[T]) *element[T] {
+	e.prev, e.next = at, at.next
+	at.next.prev = e
+	at.next = e
+	l.len++
+	return e
+}
+
+// pushFront inserts value at the front and returns its element
+func (l *list[T]) pushFront(value T) *element[T] {
+	return l.insertAfter(&element[T]{value: value}, &l.root)
+}
+
+// remove unlinks e from the list
+func (l *list[T]) remove(e *element[T]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next = nil, nil
+	l.len--
+}
+
+// moveToFront moves e, an element of l, to the front
+func (l *list[T]) moveToFront(e *element[T]) {
+	if l.root.next == e {
+		return
+	}
+	l.remove(e)
+	l.insertAfter(e, &l.root)
+}
+
+// next returns the element after e, or nil if e is the last one
+func (l *list[T]) next(e *element[T]) *element[T] {
+	if e.next == &l.root {
+		return nil
+	}
+	return e.next
+}
+
+// prev returns the element before e, or nil if e is the first one
+func (l *list[T]) prev(e *element[T]) *element[T] {
+	if e.prev == &l.root {
+		return nil
+	}
+	return e.prev
+}
@@ -0,0 +1,108 @@
+package cache
+
+// entry is a cached key and value
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// LRU is a cache which evicts the least recently used entry. All operations
+// take O(1): entries are kept in a list in order of use, and found by a map
+// into the list.
+type LRU[K comparable, V any] struct {
+	capacity int
+	items    map[K]*element[entry[K, V]]
+	order    list[entry[K, V]] // most recently used first
+	onEvict  func(key K, value V)
+}
+
+var _ Cache[int, int] = (*LRU[int, int])(nil)
+
+// NewLRU returns an empty LRU cache of the given capacity. If onEvict is not
+// nil, it is called with every entry evicted to make room, but not with
+// entries removed or replaced explicitly. It panics if capacity is not
+// positive.
+func NewLRU[K comparable, V any](capacity int, onEvict func(key K, value V)) *LRU[K, V] {
+	if capacity < 1 {
+		panic("cache: capacity must be positive")
+	}
+	c := &LRU[K, V]{capacity: capacity, items: make(map[K]*element[entry[K, V]]), onEvict: onEvict}
+	c.order.init()
+	return c
+}
+
+// Get returns the value cached under key, and whether it was found, and
+// marks the entry as most recently used
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.moveToFront(e)
+	return e.value.value, true
+}
+
+// Peek returns the value cached under key, and whether it was found,
+// without marking the entry as used
+func (c *LRU[K, V]) Peek(key K) (V, bool) {
+	if e, ok := c.items[key]; ok {
+		return e.value.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put caches value under key as the most recently used entry, evicting the
+// least recently used one if the cache is full
+func (c *LRU[K, V]) Put(key K, value V) {
+	if e, ok := c.items[key]; ok {
+		e.value.value = value
+		c.order.moveToFront(e)
+		return
+	}
+
+	if c.order.len == c.capacity {
+		c.evict()
+	}
+	c.items[key] = c.order.pushFront(entry[K, V]{key, value})
+}
+
+// evict removes the least recently used entry
+func (c *LRU[K, V]) evict() {
+	e := c.order.back()
+	c.order.remove(e)
+	delete(c.items, e.value.key)
+	if c.onEvict != nil {
+		c.onEvict(e.value.key, e.value.value)
+	}
+}
+
+// Remove removes key and reports whether it was cached
+func (c *LRU[K, V]) Remove(key K) bool {
+	e, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.order.remove(e)
+	delete(c.items, key)
+	return true
+}
+
+// Len returns the number of entries
+func (c *LRU[K, V]) Len() int { return c.order.len }
+
+// Cap returns the greatest number of entries
+func (c *LRU[K, V]) Cap() int { return c.capacity }
+
+// Oldest returns the least recently used entry, which is evicted next, or
+// false if the cache is empty
+func (c *LRU[K, V]) Oldest() (K, V, bool) {
+	e := c.order.back()
+	if e == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return e.value.key, e.value.value, true
+}
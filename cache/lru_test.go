@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"testing"
+)
+
+func TestLRUBounded(t *testing.T) {
+	evicted := make(map[int]int)
+	checkBounded(t, NewLRU(50, func(key, value int) { evicted[key] = value }), evicted)
+}
+
+func TestLRUOrder(t *testing.T) {
+	var evicted []string
+	c := NewLRU(3, func(key string, _ int) { evicted = append(evicted, key) })
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+
+	// a is used and b only peeked at, so b is the least recently used
+	c.Get("a")
+	c.Peek("b")
+	if key, _, _ := c.Oldest(); key != "b" {
+		t.Fatalf("oldest entry is %q, want b", key)
+	}
+	c.Put("d", 4)
+
+	// replacing c makes it the most recently used
+	c.Put("c", 30)
+	c.Put("e", 5)
+	c.Put("f", 6)
+	if want := []string{"b", "a", "d"}; len(evicted) != 3 || evicted[0] != want[0] || evicted[1] != want[1] || evicted[2] != want[2] {
+		t.Fatalf("evicted %v, want %v", evicted, want)
+	}
+	if v, ok := c.Get("c"); !ok || v != 30 {
+		t.Fatalf("Get(c) = %d, %v, want 30", v, ok)
+	}
+
+	// explicit removals are not evictions
+	c.Remove("c")
+	if len(evicted) != 3 || c.Len() != 2 {
+		t.Fatalf("got %d evictions and length %d after Remove, want 3 and 2", len(evicted), c.Len())
+	}
+}
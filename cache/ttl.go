@@ -0,0 +1,229 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hanyangtay/go-datastructures/indexedpq"
+)
+
+type ttlEntry[K comparable, V any] struct {
+	value  V
+	expiry *indexedpq.Handle[K, time.Time] // zero time if it never expires
+}
+
+// expiresBefore orders expiry times, the zero time meaning never
+func expiresBefore(a, b time.Time) bool {
+	return !a.IsZero() && (b.IsZero() || a.Before(b))
+}
+
+// TTL is a cache in which entries expire a fixed time after they are put,
+// given per entry or by a default. Expired entries are never returned; they
+// are removed lazily when found, by Sweep, or in the background after
+// StartSweeper. When full, the cache evicts the entry closest to expiry.
+//
+// Unlike the other caches, TTL is safe for concurrent use. Callbacks run
+// after the lock is released, so they may use the cache.
+type TTL[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	items    map[K]*ttlEntry[K, V]
+	expiries *indexedpq.PriorityQueue[K, time.Time]
+	onEvict  func(key K, value V)
+	now      func() time.Time
+}
+
+var _ Cache[int, int] = (*TTL[int, int])(nil)
+
+// NewTTL returns an empty TTL cache of the given capacity, in which entries
+// expire after ttl by default, or never if ttl is not positive. If onEvict
+// is not nil, it is called with every entry that expires or is evicted to
+// make room, but not with entries removed or replaced explicitly. It panics
+// if capacity is not positive.
+func NewTTL[K comparable, V any](capacity int, ttl time.Duration, onEvict func(key K, value V)) *TTL[K, V] {
+	if capacity < 1 {
+		panic("cache: capacity must be positive")
+	}
+	return &TTL[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		items:    make(map[K]*ttlEntry[K, V]),
+		expiries: indexedpq.New[K](expiresBefore),
+		onEvict:  onEvict,
+		now:      time.Now,
+	}
+}
+
+// evicted is an entry removed from the cache whose callback is pending
+type evicted[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// notify runs the eviction callback for every entry of es
+func (c *TTL[K, V]) notify(es []evicted[K, V]) {
+	if c.onEvict != nil {
+		for _, e := range es {
+			c.onEvict(e.key, e.value)
+		}
+	}
+}
+
+// expired reports whether e has expired by now
+func expired[K comparable, V any](e *ttlEntry[K, V], now time.Time) bool {
+	at := e.expiry.Priority()
+	return !at.IsZero() && !now.Before(at)
+}
+
+// remove deletes key, whose entry is e, and appends it to es
+func (c *TTL[K, V]) remove(key K, e *ttlEntry[K, V], es []evicted[K, V]) []evicted[K, V] {
+	c.expiries.Remove(e.expiry)
+	delete(c.items, key)
+	return append(es, evicted[K, V]{key, e.value})
+}
+
+// sweep removes all entries expired by now and appends them to es
+func (c *TTL[K, V]) sweep(now time.Time, es []evicted[K, V]) []evicted[K, V] {
+	for c.expiries.Len() > 0 {
+		key := c.expiries.Peek().Value()
+		e := c.items[key]
+		if !expired(e, now) {
+			break
+		}
+		es = c.remove(key, e, es)
+	}
+	return es
+}
+
+// lookup returns the live entry for key, removing it if it has expired
+func (c *TTL[K, V]) lookup(key K) (*ttlEntry[K, V], []evicted[K, V]) {
+	e, ok := c.items[key]
+	if !ok {
+		return nil, nil
+	}
+	if expired(e, c.now()) {
+		return nil, c.remove(key, e, nil)
+	}
+	return e, nil
+}
+
+// Get returns the value cached under key, and whether it was found and has
+// not expired
+func (c *TTL[K, V]) Get(key K) (V, bool) {
+	var value V
+	c.mu.Lock()
+	e, es := c.lookup(key)
+	if e != nil {
+		value = e.value
+	}
+	c.mu.Unlock()
+	c.notify(es)
+	return value, e != nil
+}
+
+// Peek is the same as Get, as entries are not ordered by use
+func (c *TTL[K, V]) Peek(key K) (V, bool) { return c.Get(key) }
+
+// TTL returns the time left until the entry for key expires, 0 if it never
+// does, and whether it was found and has not expired
+func (c *TTL[K, V]) TTL(key K) (time.Duration, bool) {
+	c.mu.Lock()
+	e, es := c.lookup(key)
+	var left time.Duration
+	if e != nil {
+		if at := e.expiry.Priority(); !at.IsZero() {
+			left = at.Sub(c.now())
+		}
+	}
+	c.mu.Unlock()
+	c.notify(es)
+	return left, e != nil
+}
+
+// Put caches value under key with the default time to live
+func (c *TTL[K, V]) Put(key K, value V) { c.PutWithTTL(key, value, c.ttl) }
+
+// PutWithTTL caches value under key, to expire after ttl, or never if ttl is
+// not positive. If the cache is full, expired entries are removed, and if
+// there are none, the entry closest to expiry is evicted.
+func (c *TTL[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	now := c.now()
+	var at time.Time
+	if ttl > 0 {
+		at = now.Add(ttl)
+	}
+
+	c.mu.Lock()
+	var es []evicted[K, V]
+	if e, ok := c.items[key]; ok {
+		e.value = value
+		c.expiries.Update(e.expiry, at)
+	} else {
+		if len(c.items) == c.capacity {
+			es = c.sweep(now, es)
+		}
+		if len(c.items) == c.capacity {
+			victim := c.expiries.Peek().Value()
+			es = c.remove(victim, c.items[victim], es)
+		}
+		c.items[key] = &ttlEntry[K, V]{value: value, expiry: c.expiries.Push(key, at)}
+	}
+	c.mu.Unlock()
+	c.notify(es)
+}
+
+// Remove removes key and reports whether it was cached and had not expired
+func (c *TTL[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	e, es := c.lookup(key)
+	if e != nil {
+		c.expiries.Remove(e.expiry)
+		delete(c.items, key)
+	}
+	c.mu.Unlock()
+	c.notify(es)
+	return e != nil
+}
+
+// Len returns the number of entries, including expired ones not yet removed
+func (c *TTL[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Cap returns the greatest number of entries
+func (c *TTL[K, V]) Cap() int { return c.capacity }
+
+// Sweep removes all expired entries and returns their number.
+// Time complexity: O(k log n) for k expired entries
+func (c *TTL[K, V]) Sweep() int {
+	c.mu.Lock()
+	es := c.sweep(c.now(), nil)
+	c.mu.Unlock()
+	c.notify(es)
+	return len(es)
+}
+
+// StartSweeper starts a goroutine calling Sweep every interval, so that
+// expired entries release their memory and callbacks run soon after expiry
+// even if they are never looked up. The returned function stops it.
+func (c *TTL[K, V]) StartSweeper(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				c.Sweep()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// clock is a fake time source advanced by hand
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestTTL(capacity int, ttl time.Duration) (*TTL[string, int], *clock, *[]string) {
+	clk := &clock{time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var evicted []string
+	c := NewTTL(capacity, ttl, func(key string, _ int) { evicted = append(evicted, key) })
+	c.now = clk.Now
+	return c, clk, &evicted
+}
+
+func TestTTLExpiry(t *testing.T) {
+	c, clk, evicted := newTestTTL(10, time.Minute)
+	c.Put("a", 1)
+	c.PutWithTTL("b", 2, time.Hour)
+	c.PutWithTTL("c", 3, 0)
+
+	clk.now = clk.now.Add(30 * time.Second)
+	if left, ok := c.TTL("a"); !ok || left != 30*time.Second {
+		t.Fatalf("TTL(a) = %v, %v, want 30s", left, ok)
+	}
+	if left, ok := c.TTL("c"); !ok || left != 0 {
+		t.Fatalf("TTL(c) = %v, %v, want 0 for an entry that never expires", left, ok)
+	}
+
+	clk.now = clk.now.Add(30 * time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("got a after it expired")
+	}
+	if len(*evicted) != 1 || (*evicted)[0] != "a" {
+		t.Fatalf("evicted %v, want [a]", *evicted)
+	}
+
+	// replacing an entry restarts its time to live
+	c.Put("b", 20)
+	clk.now = clk.now.Add(2 * time.Hour)
+	if n := c.Sweep(); n != 1 || c.Len() != 1 {
+		t.Fatalf("swept %d entries leaving %d, want 1 and 1", n, c.Len())
+	}
+	if v, ok := c.Get("c"); !ok || v != 3 {
+		t.Fatalf("Get(c) = %d, %v, want 3", v, ok)
+	}
+}
+
+func TestTTLEviction(t *testing.T) {
+	c, clk, evicted := newTestTTL(3, 0)
+	c.PutWithTTL("a", 1, 3*time.Minute)
+	c.PutWithTTL("b", 2, time.Minute)
+	c.Put("c", 3)
+
+	// the entry closest to expiry is evicted, entries that never expire last
+	c.Put("d", 4)
+	c.Put("e", 5)
+	if want := []string{"b", "a"}; len(*evicted) != 2 || (*evicted)[0] != want[0] || (*evicted)[1] != want[1] {
+		t.Fatalf("evicted %v, want %v", *evicted, want)
+	}
+
+	// expired entries make room before any live one is evicted
+	c.Remove("d")
+	c.PutWithTTL("f", 6, time.Second)
+	clk.now = clk.now.Add(time.Second)
+	c.Put("g", 7)
+	if len(*evicted) != 3 || (*evicted)[2] != "f" || c.Len() != 3 {
+		t.Fatalf("evicted %v with length %d, want f last", *evicted, c.Len())
+	}
+
+	if !c.Remove("c") || c.Remove("c") || len(*evicted) != 3 {
+		t.Fatal("Remove did not remove c exactly once without a callback")
+	}
+}
+
+func TestTTLSweeper(t *testing.T) {
+	done := make(chan string, 1)
+	c := NewTTL(10, time.Millisecond, func(key string, _ int) { done <- key })
+	stop := c.StartSweeper(time.Millisecond)
+	defer stop()
+
+	c.Put("a", 1)
+	select {
+	case key := <-done:
+		if key != "a" {
+			t.Fatalf("swept %q, want a", key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expired entry was never swept")
+	}
+	stop()
+}
@@ -0,0 +1,235 @@
+// Package cells decomposes a rectangle of the plane into a hierarchy of
+// square cells, in the manner of S2 cells on the sphere, and approximates
+// regions by a few cells. A cell ID is a 64 bit integer ordered along a
+// Hilbert curve, so that the descendants of a cell form one range of IDs:
+// regions can be queried against any ordered key-value store by scanning
+// the ranges of a covering, and data sharded by ID range.
+package cells
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/hanyangtay/go-datastructures/hilbert"
+	"github.com/hanyangtay/go-datastructures/pq"
+	"github.com/hanyangtay/go-datastructures/quadtree"
+)
+
+// MaxLevel is the level of the smallest cells, which are 2^-30 times as wide
+// as the grid
+const MaxLevel = 30
+
+// ID names a cell: its position along the Hilbert curve of its level, with
+// two bits per level, followed by a one bit and two zero bits per level
+// below it. Leaf cells are odd, and the IDs of the leaves inside a cell lie
+// between RangeMin and RangeMax of the cell.
+type ID uint64
+
+// Root is the cell covering the whole grid
+const Root ID = 1 << (2 * MaxLevel)
+
+// lsb returns the lowest set bit of id, which marks its level
+func (id ID) lsb() uint64 { return uint64(id) & -uint64(id) }
+
+// IsValid reports whether id names a cell
+func (id ID) IsValid() bool {
+	return id != 0 && id < ID(1)<<(2*MaxLevel+1) && bits.TrailingZeros64(uint64(id))%2 == 0
+}
+
+// Level returns the level of id, from 0 for Root to MaxLevel for leaves
+func (id ID) Level() int {
+	return MaxLevel - bits.TrailingZeros64(uint64(id))/2
+}
+
+// pos returns the position of id along the Hilbert curve of its level
+func (id ID) pos() uint64 {
+	return uint64(id) >> (2*(MaxLevel-id.Level()) + 1)
+}
+
+// Parent returns the cell containing id one level up. It panics if id is
+// Root.
+func (id ID) Parent() ID {
+	if id.Level() == 0 {
+		panic("cells: root cell has no parent")
+	}
+	lsb := id.lsb() << 2
+	return ID(uint64(id)&-lsb | lsb)
+}
+
+// Children returns the four cells inside id one level down, in Hilbert curve
+// order. It panics if id is a leaf.
+func (id ID) Children() [4]ID {
+	if id.Level() == MaxLevel {
+		panic("cells: leaf cell has no children")
+	}
+	lsb := id.lsb() >> 2
+	first := uint64(id) - id.lsb() + lsb
+	return [4]ID{ID(first), ID(first + 2*lsb), ID(first + 4*lsb), ID(first + 6*lsb)}
+}
+
+// RangeMin returns the first leaf inside id
+func (id ID) RangeMin() ID { return ID(uint64(id) - (id.lsb() - 1)) }
+
+// RangeMax returns the last leaf inside id
+func (id ID) RangeMax() ID { return ID(uint64(id) + (id.lsb() - 1)) }
+
+// Contains reports whether other lies inside id
+func (id ID) Contains(other ID) bool {
+	return id.RangeMin() <= other && other <= id.RangeMax()
+}
+
+// Intersects reports whether id and other overlap, i.e. one contains the
+// other
+func (id ID) Intersects(other ID) bool {
+	return other.RangeMin() <= id.RangeMax() && id.RangeMin() <= other.RangeMax()
+}
+
+// Region is an area that can be covered with cells
+type Region interface {
+	// ContainsRect reports whether r lies inside the region
+	ContainsRect(r quadtree.Rect) bool
+
+	// Intersects reports whether r and the region share a point
+	Intersects(r quadtree.Rect) bool
+}
+
+// Circle is the disc of the given radius around Center, as a Region
+type Circle struct {
+	Center quadtree.Point
+	Radius float64
+}
+
+// ContainsRect reports whether r lies inside c
+func (c Circle) ContainsRect(r quadtree.Rect) bool {
+	dx := max(c.Center.X-r.Min.X, r.Max.X-c.Center.X)
+	dy := max(c.Center.Y-r.Min.Y, r.Max.Y-c.Center.Y)
+	return dx*dx+dy*dy <= c.Radius*c.Radius
+}
+
+// Intersects reports whether r and c share a point
+func (c Circle) Intersects(r quadtree.Rect) bool {
+	dx := max(r.Min.X-c.Center.X, c.Center.X-r.Max.X, 0)
+	dy := max(r.Min.Y-c.Center.Y, c.Center.Y-r.Max.Y, 0)
+	return dx*dx+dy*dy <= c.Radius*c.Radius
+}
+
+// Grid lays the cell hierarchy over a rectangle: Root covers all of it, and
+// every cell is split into four equal quadrants one level down
+type Grid struct {
+	bounds quadtree.Rect
+}
+
+// NewGrid returns the grid over bounds. It panics if bounds is empty.
+func NewGrid(bounds quadtree.Rect) *Grid {
+	if !(bounds.Min.X < bounds.Max.X && bounds.Min.Y < bounds.Max.Y) {
+		panic("cells: grid bounds are empty")
+	}
+	return &Grid{bounds: bounds}
+}
+
+// Bounds returns the rectangle covered by Root
+func (g *Grid) Bounds() quadtree.Rect { return g.bounds }
+
+// ID returns the cell at level holding p. Points outside of the grid map to
+// the nearest cell on its boundary. It panics if level is out of range.
+func (g *Grid) ID(p quadtree.Point, level int) ID {
+	if level < 0 || level > MaxLevel {
+		panic("cells: level out of range")
+	}
+	if level == 0 {
+		return Root
+	}
+
+	cells := math.Ldexp(1, level)
+	scale := func(v, lo, hi float64) uint32 {
+		return uint32(max(min((v-lo)/(hi-lo)*cells, cells-1), 0))
+	}
+	x := scale(p.X, g.bounds.Min.X, g.bounds.Max.X)
+	y := scale(p.Y, g.bounds.Min.Y, g.bounds.Max.Y)
+
+	pos := hilbert.Encode(uint(level), x, y)
+	return ID((pos<<1 | 1) << (2 * (MaxLevel - level)))
+}
+
+// Rect returns the area of the cell id. It panics if id is not valid.
+func (g *Grid) Rect(id ID) quadtree.Rect {
+	if !id.IsValid() {
+		panic("cells: invalid cell ID")
+	}
+	level := id.Level()
+	if level == 0 {
+		return g.bounds
+	}
+
+	x, y := hilbert.Decode(uint(level), id.pos())
+	w := math.Ldexp(g.bounds.Max.X-g.bounds.Min.X, -level)
+	h := math.Ldexp(g.bounds.Max.Y-g.bounds.Min.Y, -level)
+	minX := g.bounds.Min.X + float64(x)*w
+	minY := g.bounds.Min.Y + float64(y)*h
+	return quadtree.Rect{
+		Min: quadtree.Point{X: minX, Y: minY},
+		Max: quadtree.Point{X: minX + w, Y: minY + h},
+	}
+}
+
+// Covering returns at most maxCells cells, sorted by ID, whose union covers
+// the part of region inside the grid, as tightly as it can. Starting from
+// Root, the largest cell of the covering that is not inside region is
+// replaced by the children of it that intersect region, for as long as that
+// keeps the number of cells within maxCells. It panics if maxCells is not
+// positive.
+func (g *Grid) Covering(region Region, maxCells int) []ID {
+	if maxCells < 1 {
+		panic("cells: maxCells must be positive")
+	}
+	if !region.Intersects(g.bounds) {
+		return nil
+	}
+
+	var result []ID
+	candidates := pq.New(func(a, b ID) bool { return a.Level() < b.Level() })
+	candidates.Push(Root)
+
+	for candidates.Len() > 0 {
+		id := candidates.Pop()
+		if id.Level() == MaxLevel || region.ContainsRect(g.Rect(id)) {
+			result = append(result, id)
+			continue
+		}
+
+		var children []ID
+		for _, c := range id.Children() {
+			if region.Intersects(g.Rect(c)) {
+				children = append(children, c)
+			}
+		}
+		if len(result)+candidates.Len()+len(children) > maxCells {
+			result = append(result, id)
+			continue
+		}
+		for _, c := range children {
+			candidates.Push(c)
+		}
+	}
+
+	return normalize(result)
+}
+
+// normalize sorts ids and replaces every four siblings by their parent
+func normalize(ids []ID) []ID {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	out := ids[:0]
+	for _, id := range ids {
+		out = append(out, id)
+		for n := len(out); n >= 4 && out[n-1].Level() > 0; n = len(out) {
+			parent := out[n-1].Parent()
+			if [4]ID(out[n-4:]) != parent.Children() {
+				break
+			}
+			out = append(out[:n-4], parent)
+		}
+	}
+	return out
+}
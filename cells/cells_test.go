@@ -0,0 +1,89 @@
+package cells
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/hanyangtay/go-datastructures/quadtree"
+)
+
+var grid = NewGrid(quadtree.Rect{Min: quadtree.Point{X: 0, Y: 0}, Max: quadtree.Point{X: 1024, Y: 512}})
+
+func TestHierarchy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := quadtree.Point{X: rng.Float64() * 1024, Y: rng.Float64() * 512}
+		leaf := grid.ID(p, MaxLevel)
+		if !leaf.IsValid() || leaf.Level() != MaxLevel || leaf%2 != 1 {
+			t.Fatalf("leaf %x of %v is not a valid leaf", leaf, p)
+		}
+
+		for level := MaxLevel - 1; level >= 0; level-- {
+			id := grid.ID(p, level)
+			if id != grid.ID(p, level+1).Parent() {
+				t.Fatalf("cell of %v at level %d is not the parent of the one below", p, level)
+			}
+			if !id.Contains(leaf) || !id.Intersects(leaf) || leaf.RangeMin() < id.RangeMin() || leaf.RangeMax() > id.RangeMax() {
+				t.Fatalf("cell %x does not contain leaf %x", id, leaf)
+			}
+			if !grid.Rect(id).Contains(p) {
+				t.Fatalf("rectangle of cell %x at level %d does not contain %v", id, level, p)
+			}
+
+			found := false
+			for _, c := range id.Children() {
+				found = found || c == grid.ID(p, level+1)
+				if c.Parent() != id || c.Level() != level+1 {
+					t.Fatalf("child %x of %x has parent %x", c, id, c.Parent())
+				}
+			}
+			if !found {
+				t.Fatalf("children of %x do not include the cell of %v", id, p)
+			}
+		}
+		if grid.ID(p, 0) != Root {
+			t.Fatalf("cell of %v at level 0 is %x, want Root", p, grid.ID(p, 0))
+		}
+	}
+}
+
+// TestCovering checks that coverings of random circles are sorted, stay
+// within the number of cells asked for, and contain every point of the
+// circle
+func TestCovering(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		c := Circle{quadtree.Point{X: rng.Float64() * 1024, Y: rng.Float64() * 512}, 1 + rng.Float64()*100}
+		maxCells := 1 + rng.Intn(20)
+		covering := grid.Covering(c, maxCells)
+
+		if len(covering) > maxCells {
+			t.Fatalf("covering has %d cells, want at most %d", len(covering), maxCells)
+		}
+		for j := 1; j < len(covering); j++ {
+			if covering[j-1].RangeMax() >= covering[j].RangeMin() {
+				t.Fatalf("cells %x and %x of the covering are out of order or overlap", covering[j-1], covering[j])
+			}
+		}
+
+		for j := 0; j < 100; j++ {
+			p := quadtree.Point{X: c.Center.X + (rng.Float64()*2-1)*c.Radius, Y: c.Center.Y + (rng.Float64()*2-1)*c.Radius}
+			dx, dy := p.X-c.Center.X, p.Y-c.Center.Y
+			if dx*dx+dy*dy > c.Radius*c.Radius || !grid.Bounds().Contains(p) {
+				continue
+			}
+			leaf := grid.ID(p, MaxLevel)
+			covered := false
+			for _, id := range covering {
+				covered = covered || id.Contains(leaf)
+			}
+			if !covered {
+				t.Fatalf("covering of %v misses %v", c, p)
+			}
+		}
+	}
+
+	if grid.Covering(Circle{quadtree.Point{X: -100, Y: -100}, 1}, 5) != nil {
+		t.Fatal("covering of a circle outside the grid is not empty")
+	}
+}
@@ -0,0 +1,123 @@
+// Package daryheap provides a generic d-ary heap, whose arity can be tuned to
+// the workload.
+package daryheap
+
+// DaryHeap is an array based min heap in which every node has up to d
+// children, ordered by a less function. Compared to a binary heap, a higher
+// arity makes the tree shallower, so Push moves items fewer levels, while Pop
+// compares more children per level. As the children of a node are adjacent
+// in memory, arities of 4 or 8 are often faster than 2 in practice, notably
+// for shortest path searches, which push more than they pop.
+type DaryHeap[T any] struct {
+	items []T
+	d     int
+	less  func(a, b T) bool
+}
+
+// New returns an empty heap of arity d ordered by less. It panics if d is
+// less than 2.
+func New[T any](d int, less func(a, b T) bool) *DaryHeap[T] {
+	if d < 2 {
+		panic("daryheap: arity must be at least 2")
+	}
+	return &DaryHeap[T]{d: d, less: less}
+}
+
+// From returns a heap of arity d holding items, ordered by less, in O(n). The
+// heap takes ownership of the slice.
+func From[T any](d int, items []T, less func(a, b T) bool) *DaryHeap[T] {
+	q := New(d, less)
+	q.items = items
+	if len(items) < 2 {
+		return q
+	}
+	for i := (len(items) - 2) / d; i >= 0; i-- {
+		q.down(i)
+	}
+	return q
+}
+
+// Arity returns the maximum number of children per node
+func (q *DaryHeap[T]) Arity() int { return q.d }
+
+// Len returns the number of items in the heap
+func (q *DaryHeap[T]) Len() int { return len(q.items) }
+
+// Push adds x to the heap. Time complexity: O(log n / log d)
+func (q *DaryHeap[T]) Push(x T) {
+	q.items = append(q.items, x)
+	q.up(len(q.items) - 1)
+}
+
+// Pop removes and returns the least item. It panics if the heap is empty.
+// Time complexity: O(d log n / log d)
+func (q *DaryHeap[T]) Pop() T {
+	top := q.items[0]
+	last := len(q.items) - 1
+
+	q.items[0] = q.items[last]
+
+	// clear the vacated slot so that it does not retain the item
+	var zero T
+	q.items[last] = zero
+	q.items = q.items[:last]
+
+	if last > 0 {
+		q.down(0)
+	}
+
+	return top
+}
+
+// Peek returns the least item without removing it. It panics if the heap is
+// empty.
+func (q *DaryHeap[T]) Peek() T {
+	return q.items[0]
+}
+
+// Reset removes all items, keeping the allocated storage for reuse
+func (q *DaryHeap[T]) Reset() {
+	var zero T
+	for i := range q.items {
+		q.items[i] = zero
+	}
+	q.items = q.items[:0]
+}
+
+func (q *DaryHeap[T]) up(i int) {
+	x := q.items[i]
+	for i > 0 {
+		parent := (i - 1) / q.d
+		if !q.less(x, q.items[parent]) {
+			break
+		}
+		q.items[i] = q.items[parent]
+		i = parent
+	}
+	q.items[i] = x
+}
+
+func (q *DaryHeap[T]) down(i int) {
+	n := len(q.items)
+	x := q.items[i]
+	for {
+		first := q.d*i + 1
+		if first >= n {
+			break
+		}
+
+		least := first
+		for c := first + 1; c < first+q.d && c < n; c++ {
+			if q.less(q.items[c], q.items[least]) {
+				least = c
+			}
+		}
+		if !q.less(q.items[least], x) {
+			break
+		}
+
+		q.items[i] = q.items[least]
+		i = least
+	}
+	q.items[i] = x
+}
@@ -0,0 +1,74 @@
+package daryheap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func less(a, b int) bool { return a < b }
+
+func TestPushPop(t *testing.T) {
+	for _, d := range []int{2, 3, 4, 8} {
+		rng := rand.New(rand.NewSource(int64(d)))
+		q := New(d, less)
+
+		var want []int
+		for i := 0; i < 500; i++ {
+			x := rng.Intn(100)
+			q.Push(x)
+			want = append(want, x)
+		}
+		sort.Ints(want)
+
+		for i, w := range want {
+			if p := q.Peek(); p != w {
+				t.Fatalf("d=%d, item %d: peeked %d, want %d", d, i, p, w)
+			}
+			if x := q.Pop(); x != w {
+				t.Fatalf("d=%d, item %d: popped %d, want %d", d, i, x, w)
+			}
+		}
+		if q.Len() != 0 {
+			t.Fatalf("d=%d: got length %d after popping all items", d, q.Len())
+		}
+	}
+}
+
+func TestFrom(t *testing.T) {
+	for _, d := range []int{2, 4, 7} {
+		for _, n := range []int{1, 2, 3, d, d + 1, 100} {
+			q := From(d, rand.New(rand.NewSource(int64(n))).Perm(n), less)
+			if q.Arity() != d {
+				t.Fatalf("got arity %d, want %d", q.Arity(), d)
+			}
+			for i := 0; i < n; i++ {
+				if x := q.Pop(); x != i {
+					t.Fatalf("d=%d, n=%d: popped %d, want %d", d, n, x, i)
+				}
+			}
+		}
+	}
+}
+
+func TestFromEmpty(t *testing.T) {
+	q := From(4, nil, less)
+	if q.Len() != 0 {
+		t.Fatalf("got length %d, want 0", q.Len())
+	}
+
+	q.Push(2)
+	q.Push(1)
+	if x := q.Pop(); x != 1 {
+		t.Fatalf("popped %d, want 1", x)
+	}
+}
+
+func TestBadArity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("arity 1 did not panic")
+		}
+	}()
+	New(1, less)
+}
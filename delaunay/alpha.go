@@ -0,0 +1,61 @@
+package delaunay
+
+import (
+	"github.com/hanyangtay/go-datastructures/geom"
+)
+
+// AlphaShape returns the boundary of the alpha shape of the points: the
+// union of the triangles whose circumcircle has a radius of at most radius.
+// Unlike the convex hull it follows concavities and leaves out holes and
+// gaps between clusters wider than about twice the radius, so it measures
+// the area a point set actually covers. As radius grows the shape grows
+// towards the convex hull; points in no small enough triangle are left out.
+//
+// Every connected part of the shape is bounded by one polygon running
+// counterclockwise, and each hole in it by one running clockwise, so the
+// sum of their signed areas is the area of the shape. Parts touching at a
+// single point are traced as separate polygons. Time complexity: O(n)
+func (t *Triangulation) AlphaShape(radius float64) []geom.Polygon {
+	// degenerate triangles have no finite circumradius and are never kept
+	kept := make([]bool, t.Len())
+	for i := range kept {
+		tri := t.Triangle(i)
+		kept[i] = circumradius(t.Points[tri[0]], t.Points[tri[1]], t.Points[tri[2]]) <= radius*radius
+	}
+
+	// a half-edge of a kept triangle is on the boundary if the triangle
+	// across it is not kept
+	boundary := func(e int) bool {
+		return kept[e/3] && (t.Halfedges[e] < 0 || !kept[t.Halfedges[e]/3])
+	}
+
+	visited := make([]bool, len(t.Triangles))
+	var rings []geom.Polygon
+	for start := range t.Triangles {
+		if visited[start] || !boundary(start) {
+			continue
+		}
+
+		var ring geom.Polygon
+		for e := start; !visited[e]; {
+			visited[e] = true
+			ring = append(ring, t.Points[t.Triangles[e]])
+
+			// turn around the end of e through kept triangles until the
+			// next boundary half-edge leaving it
+			e = Next(e)
+			for !boundary(e) {
+				e = Next(t.Halfedges[e])
+			}
+		}
+		rings = append(rings, ring)
+	}
+
+	return rings
+}
+
+// ConcaveHull returns the alpha shape of points for radius, triangulating
+// them first; see AlphaShape. Time complexity: O(n log n)
+func ConcaveHull(points []Point, radius float64) []geom.Polygon {
+	return Triangulate(points).AlphaShape(radius)
+}
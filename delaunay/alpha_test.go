@@ -0,0 +1,59 @@
+package delaunay
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hanyangtay/go-datastructures/geom"
+)
+
+// shapeArea returns the area of an alpha shape, holes counting negatively
+func shapeArea(rings []geom.Polygon) float64 {
+	a := 0.0
+	for _, ring := range rings {
+		a += ring.Area()
+	}
+	return a
+}
+
+func TestAlphaShape(t *testing.T) {
+	// two squares of points 10 apart, one with a hole in the middle
+	var points []Point
+	for x := 0; x <= 10; x++ {
+		for y := 0; y <= 10; y++ {
+			if x < 3 || x > 7 || y < 3 || y > 7 {
+				points = append(points, Point{X: float64(x), Y: float64(y)})
+			}
+			points = append(points, Point{X: float64(x) + 20, Y: float64(y)})
+		}
+	}
+
+	// unit squares split in two have a circumradius of √2/2; the hole is 6
+	// by 6 but for a half square in each corner
+	rings := ConcaveHull(points, 1)
+	if len(rings) != 3 {
+		t.Fatalf("got %d rings, want two outlines and a hole", len(rings))
+	}
+	if a, want := shapeArea(rings), 2*100-6*6+4*0.5; a != want {
+		t.Fatalf("got area %v, want %v", a, want)
+	}
+	holes := 0
+	for _, ring := range rings {
+		if ring.Area() < 0 {
+			holes++
+		}
+	}
+	if holes != 1 {
+		t.Fatalf("got %d clockwise rings, want 1", holes)
+	}
+
+	// a large radius gives the convex hull
+	rings = ConcaveHull(points, 1000)
+	if len(rings) != 1 || math.Abs(shapeArea(rings)-30*10) > 1e-9 {
+		t.Fatalf("got %d rings of area %v, want the convex hull of area 300", len(rings), shapeArea(rings))
+	}
+
+	if rings := ConcaveHull(points, 0.5); len(rings) != 0 {
+		t.Fatalf("got %d rings for a radius below any triangle", len(rings))
+	}
+}
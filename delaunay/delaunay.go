@@ -0,0 +1,556 @@
+// Package delaunay computes Delaunay triangulations of points in the plane,
+// their duals, Voronoi diagrams, and alpha shapes, the concave hulls made of
+// their small triangles.
+package delaunay
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hanyangtay/go-datastructures/geom"
+	"github.com/hanyangtay/go-datastructures/graph"
+)
+
+// Point is a position in the plane
+type Point = geom.Point
+
+// Triangulation is a Delaunay triangulation: no point lies inside the
+// circumcircle of any triangle. Triangles are stored as half-edges, three
+// per triangle: half-edge e of triangle e/3 starts at point Triangles[e]
+// and ends at the start of the next half-edge of the triangle, going
+// counterclockwise. Halfedges[e] is the opposite half-edge in the adjacent
+// triangle, or -1 on the convex hull.
+type Triangulation struct {
+	Points    []Point
+	Triangles []int
+	Halfedges []int
+
+	// Hull lists the points on the convex hull, counterclockwise
+	Hull []int
+}
+
+// Next returns the half-edge following e in its triangle
+func Next(e int) int {
+	if e%3 == 2 {
+		return e - 2
+	}
+	return e + 1
+}
+
+// Prev returns the half-edge preceding e in its triangle
+func Prev(e int) int {
+	if e%3 == 0 {
+		return e + 2
+	}
+	return e - 1
+}
+
+// Len returns the number of triangles
+func (t *Triangulation) Len() int { return len(t.Triangles) / 3 }
+
+// Triangle returns the points of triangle i, counterclockwise
+func (t *Triangulation) Triangle(i int) [3]int {
+	return [3]int{t.Triangles[3*i], t.Triangles[3*i+1], t.Triangles[3*i+2]}
+}
+
+// Adjacent returns the triangles sharing the edges of triangle i, starting
+// with the edge from its first point, or -1 where an edge is on the hull
+func (t *Triangulation) Adjacent(i int) [3]int {
+	var adj [3]int
+	for j := range adj {
+		adj[j] = -1
+		if h := t.Halfedges[3*i+j]; h >= 0 {
+			adj[j] = h / 3
+		}
+	}
+	return adj
+}
+
+// Edges calls fn once for every edge of the triangulation, with the indexes
+// of its points, until fn returns false
+func (t *Triangulation) Edges(fn func(a, b int) bool) {
+	for e, h := range t.Halfedges {
+		if e > h && !fn(t.Triangles[e], t.Triangles[Next(e)]) {
+			return
+		}
+	}
+}
+
+// Graph returns a graph with a node for every point, with the same index as
+// ID and the point as coordinates, in which the ends of every edge of the
+// triangulation are joined by a pair of opposing directed edges weighted by
+// their Euclidean distance. It connects every point to its natural
+// neighbours, and contains the minimum spanning tree and the nearest
+// neighbour graph of the points.
+func (t *Triangulation) Graph() *graph.DirectedGraph {
+	g := graph.NewDirectedGraph()
+	for _, p := range t.Points {
+		g.AddNode(&graph.Node{X: p.X, Y: p.Y})
+	}
+
+	t.Edges(func(a, b int) bool {
+		u, v := g.Nodes[a], g.Nodes[b]
+		w := graph.Dist(u, v)
+		g.AddDirectedEdge(&graph.Edge{ID: [2]int{a, b}, From: u, To: v, Weight: w})
+		g.AddDirectedEdge(&graph.Edge{ID: [2]int{b, a}, From: v, To: u, Weight: w})
+		return true
+	})
+	return g
+}
+
+// triangulator holds the state of the sweep
+type triangulator struct {
+	points    []Point
+	triangles []int
+	halfedges []int
+
+	// the hull is a circular list of points, with the half-edge from each
+	// to its successor; removed points are their own successor
+	hullStart          int
+	hullNext, hullPrev []int
+	hullTri            []int
+
+	// hullHash maps pseudo-angles around center to points on the hull,
+	// as starting points to find the hull edges visible from a new point
+	hullHash []int
+	center   Point
+
+	stack []int
+}
+
+// Triangulate returns the Delaunay triangulation of points, following the
+// sweep-hull algorithm of Delaunator: starting from a small triangle near
+// the middle, the points are added in order of their distance to it, each
+// joined to the edges of the hull it sees, and the triangle edges are
+// flipped until they are Delaunay again. The orientation and circle tests
+// are exact, so the result is valid for any input.
+//
+// Duplicate points are left out of the triangulation. If all points are
+// collinear, there are no triangles and the hull holds all distinct points
+// in order along the line. It panics if a coordinate is not finite.
+// Time complexity: O(n log n) on average
+func Triangulate(points []Point) *Triangulation {
+	for _, p := range points {
+		if math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) || math.IsNaN(p.X) || math.IsNaN(p.Y) {
+			panic("delaunay: point coordinates must be finite")
+		}
+	}
+
+	result := &Triangulation{Points: points}
+	n := len(points)
+	if n == 0 {
+		return result
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		minX, maxX = min(minX, p.X), max(maxX, p.X)
+		minY, maxY = min(minY, p.Y), max(maxY, p.Y)
+	}
+	mid := Point{X: (minX + maxX) / 2, Y: (minY + maxY) / 2}
+
+	// seed triangle: the point nearest the middle, the point nearest to
+	// that, and the point making the smallest circumcircle with both
+	i0 := nearest(points, mid, false)
+	i1 := nearest(points, points[i0], true)
+	i2, radius := -1, math.Inf(1)
+	for i, p := range points {
+		if i1 < 0 || geom.Orient(points[i0], points[i1], p) == 0 {
+			continue
+		}
+		if r := circumradius(points[i0], points[i1], p); r < radius {
+			i2, radius = i, r
+		}
+	}
+
+	if i2 < 0 {
+		result.Hull = collinearHull(points)
+		return result
+	}
+	if geom.Orient(points[i0], points[i1], points[i2]) < 0 {
+		i1, i2 = i2, i1
+	}
+
+	t := &triangulator{
+		points:   points,
+		hullNext: make([]int, n),
+		hullPrev: make([]int, n),
+		hullTri:  make([]int, n),
+		hullHash: make([]int, int(math.Ceil(math.Sqrt(float64(n))))),
+		center:   circumcenter(points[i0], points[i1], points[i2]),
+	}
+	t.triangles = make([]int, 0, 3*(2*n-5))
+	t.halfedges = make([]int, 0, 3*(2*n-5))
+	for i := range t.hullHash {
+		t.hullHash[i] = -1
+	}
+
+	t.hullStart = i0
+	t.hullNext[i0], t.hullNext[i1], t.hullNext[i2] = i1, i2, i0
+	t.hullPrev[i0], t.hullPrev[i1], t.hullPrev[i2] = i2, i0, i1
+	t.hullTri[i0], t.hullTri[i1], t.hullTri[i2] = 0, 1, 2
+	for _, i := range []int{i0, i1, i2} {
+		t.hullHash[t.hashKey(points[i])] = i
+	}
+	t.addTriangle(i0, i1, i2, -1, -1, -1)
+
+	order := make([]int, n)
+	dist := make([]float64, n)
+	for i, p := range points {
+		order[i] = i
+		dist[i] = squaredDist(p, t.center)
+	}
+	sort.Slice(order, func(a, b int) bool { return dist[order[a]] < dist[order[b]] })
+
+	for _, i := range order {
+		if i != i0 && i != i1 && i != i2 {
+			t.add(i)
+		}
+	}
+
+	result.Triangles, result.Halfedges = t.triangles, t.halfedges
+	for e := t.hullStart; ; {
+		result.Hull = append(result.Hull, e)
+		if e = t.hullNext[e]; e == t.hullStart {
+			break
+		}
+	}
+	return result
+}
+
+// add joins point i to the hull edges it sees. Points are added in order of
+// distance from the center, so they lie outside of the hull, except where
+// rounding of the center or collinear points put them inside or on it.
+func (t *triangulator) add(i int) {
+	p := t.points[i]
+
+	// find a visible edge, starting from a hull point at about the same
+	// angle around the center
+	start := 0
+	key := t.hashKey(p)
+	for j := range t.hullHash {
+		start = t.hullHash[(key+j)%len(t.hullHash)]
+		if start >= 0 && start != t.hullNext[start] {
+			break
+		}
+	}
+	start = t.hullPrev[start]
+
+	e := start
+	for !t.visible(p, e, t.hullNext[e]) {
+		if e = t.hullNext[e]; e == start {
+			t.insert(i)
+			return
+		}
+	}
+
+	tri := t.addTriangle(e, i, t.hullNext[e], -1, -1, t.hullTri[e])
+	t.hullTri[i] = t.legalize(tri + 2)
+	t.hullTri[e] = tri
+
+	// join the visible edges after e, removing their inner points from
+	// the hull
+	n := t.hullNext[e]
+	for q := t.hullNext[n]; t.visible(p, n, q); q = t.hullNext[n] {
+		tri = t.addTriangle(n, i, q, t.hullTri[i], -1, t.hullTri[n])
+		t.hullTri[i] = t.legalize(tri + 2)
+		t.hullNext[n] = n
+		n = q
+	}
+
+	// and those before e, if the search started in the middle of them
+	if e == start {
+		for q := t.hullPrev[e]; t.visible(p, q, e); q = t.hullPrev[e] {
+			tri = t.addTriangle(q, i, e, -1, t.hullTri[e], t.hullTri[q])
+			t.legalize(tri + 2)
+			t.hullTri[q] = tri
+			t.hullNext[e] = e
+			e = q
+		}
+	}
+
+	t.hullStart = e
+	t.hullPrev[i], t.hullNext[i] = e, n
+	t.hullNext[e], t.hullPrev[n] = i, i
+	t.hullHash[t.hashKey(p)] = i
+	t.hullHash[t.hashKey(t.points[e])] = e
+}
+
+// insert adds point i, which lies inside the hull or on its boundary, by
+// splitting the triangle containing it, or the two triangles sharing the
+// edge it lies on. Duplicates of existing points are left out.
+func (t *triangulator) insert(i int) {
+	p := t.points[i]
+
+	// visibility walk from the newest triangle, which terminates on a
+	// Delaunay triangulation
+	tri := len(t.triangles)/3 - 1
+	var side [3]int
+walk:
+	for {
+		for j := range side {
+			e := 3*tri + j
+			side[j] = geom.Orient(t.points[t.triangles[e]], t.points[t.triangles[Next(e)]], p)
+			if side[j] < 0 {
+				if t.halfedges[e] < 0 {
+					return
+				}
+				tri = t.halfedges[e] / 3
+				continue walk
+			}
+		}
+		break
+	}
+
+	e := -1
+	for j := range side {
+		if side[j] == 0 {
+			if e >= 0 {
+				// on two edges, i.e. at a vertex
+				return
+			}
+			e = 3*tri + j
+		}
+	}
+	if e < 0 {
+		t.splitTriangle(3*tri, i)
+	} else {
+		t.splitEdge(e, i)
+	}
+}
+
+// splitTriangle replaces the triangle a, b, c of half-edge ab by three
+// triangles around point p inside it
+func (t *triangulator) splitTriangle(ab, p int) {
+	bc, ca := Next(ab), Prev(ab)
+	a, b, c := t.triangles[ab], t.triangles[bc], t.triangles[ca]
+
+	// a, b, p reuses the slots of the triangle
+	t2 := t.addTriangle(b, c, p, t.halfedges[bc], -1, bc)
+	t3 := t.addTriangle(c, a, p, t.halfedges[ca], ca, t2+1)
+	t.triangles[ca] = p
+	t.fixHull(t2, b)
+	t.fixHull(t3, c)
+
+	t.legalize(ab)
+	t.legalize(t2)
+	t.legalize(t3)
+}
+
+// splitEdge replaces the triangles on both sides of half-edge ab, of the
+// triangle a, b, c, by triangles around point p on the edge
+func (t *triangulator) splitEdge(ab, p int) {
+	bc, ca := Next(ab), Prev(ab)
+	a, b, c := t.triangles[ab], t.triangles[bc], t.triangles[ca]
+	ba := t.halfedges[ab]
+	hca := t.halfedges[ca]
+
+	// p, b, c reuses the slots of the triangle
+	t.triangles[ab] = p
+	t2 := t.addTriangle(a, p, c, -1, ca, hca)
+	t.fixHull(t2+2, c)
+
+	if ba < 0 {
+		// ab is on the hull, which gains p between a and b
+		t.hullNext[a], t.hullPrev[p] = p, a
+		t.hullNext[p], t.hullPrev[b] = b, p
+		t.hullTri[a], t.hullTri[p] = t2, ab
+		t.hullHash[t.hashKey(t.points[p])] = p
+
+		t.legalize(bc)
+		t.legalize(t2 + 2)
+		return
+	}
+
+	// the other side b, a, d becomes p, a, d and b, p, d
+	ad, db := Next(ba), Prev(ba)
+	d := t.triangles[db]
+	hdb := t.halfedges[db]
+	t.triangles[ba] = p
+	t4 := t.addTriangle(b, p, d, ab, db, hdb)
+	t.fixHull(t4+2, d)
+	t.link(ba, t2)
+
+	t.legalize(bc)
+	t.legalize(t2 + 2)
+	t.legalize(ad)
+	t.legalize(t4 + 2)
+}
+
+// fixHull records e, the half-edge from point a, as the hull edge from a if
+// it has no opposite
+func (t *triangulator) fixHull(e, a int) {
+	if t.halfedges[e] < 0 {
+		t.hullTri[a] = e
+	}
+}
+
+// visible reports whether p lies strictly outside the hull edge from a to b
+func (t *triangulator) visible(p Point, a, b int) bool {
+	return geom.Orient(p, t.points[a], t.points[b]) < 0
+}
+
+// addTriangle appends the triangle a, b, c, whose half-edges are opposite to
+// ab, bc and ca, and returns its first half-edge
+func (t *triangulator) addTriangle(a, b, c, ab, bc, ca int) int {
+	e := len(t.triangles)
+	t.triangles = append(t.triangles, a, b, c)
+	t.halfedges = append(t.halfedges, -1, -1, -1)
+	t.link(e, ab)
+	t.link(e+1, bc)
+	t.link(e+2, ca)
+	return e
+}
+
+func (t *triangulator) link(a, b int) {
+	t.halfedges[a] = b
+	if b >= 0 {
+		t.halfedges[b] = a
+	}
+}
+
+// legalize flips the edge of half-edge a, and recursively the edges it
+// exposes, until all are Delaunay, and returns the half-edge that then holds
+// the edge preceding a in its triangle
+func (t *triangulator) legalize(a int) int {
+	t.stack = t.stack[:0]
+	var ar int
+	for {
+		b := t.halfedges[a]
+
+		// a and b are the halves of the edge between the triangles
+		// pr, pl, p0 and pl, pr, p1:
+		//
+		//           pl                    pl
+		//          /||\                  /  \
+		//       al/ || \bl            al/    \a
+		//        /  ||  \              /      \
+		//       /  a||b  \    flip    /___ar___\
+		//     p0\   ||   /p1   =>   p0\---bl---/p1
+		//        \  ||  /              \      /
+		//       ar\ || /br             b\    /br
+		//          \||/                  \  /
+		//           pr                    pr
+		a0 := a - a%3
+		ar = a0 + (a+2)%3
+		if b < 0 {
+			if len(t.stack) == 0 {
+				return ar
+			}
+			a = t.pop()
+			continue
+		}
+
+		b0 := b - b%3
+		al := a0 + (a+1)%3
+		bl := b0 + (b+2)%3
+		p0, pr, pl, p1 := t.triangles[ar], t.triangles[a], t.triangles[al], t.triangles[bl]
+
+		if geom.InCircle(t.points[p0], t.points[pr], t.points[pl], t.points[p1]) <= 0 {
+			if len(t.stack) == 0 {
+				return ar
+			}
+			a = t.pop()
+			continue
+		}
+
+		t.triangles[a], t.triangles[b] = p1, p0
+
+		// the flipped edge may have reached the hull on the other side,
+		// whose reference has to move along
+		hbl := t.halfedges[bl]
+		if hbl < 0 {
+			for e := t.hullStart; ; {
+				if t.hullTri[e] == bl {
+					t.hullTri[e] = a
+					break
+				}
+				if e = t.hullPrev[e]; e == t.hullStart {
+					break
+				}
+			}
+		}
+		t.link(a, hbl)
+		t.link(b, t.halfedges[ar])
+		t.link(ar, bl)
+
+		t.stack = append(t.stack, b0+(b+1)%3)
+	}
+}
+
+func (t *triangulator) pop() int {
+	a := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	return a
+}
+
+// hashKey returns the bucket of p in hullHash, by its pseudo-angle around
+// the center, increasing counterclockwise
+func (t *triangulator) hashKey(p Point) int {
+	dx, dy := p.X-t.center.X, p.Y-t.center.Y
+	angle := 0.0
+	if s := math.Abs(dx) + math.Abs(dy); s > 0 {
+		q := dx / s
+		if dy > 0 {
+			angle = (1 - q) / 4
+		} else {
+			angle = (3 + q) / 4
+		}
+	}
+	return int(angle*float64(len(t.hullHash))) % len(t.hullHash)
+}
+
+// nearest returns the index of the point nearest to p, or of the nearest
+// point different from p if distinct is set, or -1 if there is none
+func nearest(points []Point, p Point, distinct bool) int {
+	best, bestDist := -1, math.Inf(1)
+	for i, q := range points {
+		if distinct && q == p {
+			continue
+		}
+		if d := squaredDist(p, q); best < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// collinearHull returns the distinct points, which lie on a line, sorted
+// along it
+func collinearHull(points []Point) []int {
+	order := make([]int, len(points))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		p, q := points[order[a]], points[order[b]]
+		return p.X < q.X || p.X == q.X && p.Y < q.Y
+	})
+
+	hull := order[:0]
+	for _, i := range order {
+		if len(hull) == 0 || points[hull[len(hull)-1]] != points[i] {
+			hull = append(hull, i)
+		}
+	}
+	return hull
+}
+
+func squaredDist(a, b Point) float64 {
+	return (a.X-b.X)*(a.X-b.X) + (a.Y-b.Y)*(a.Y-b.Y)
+}
+
+// circumradius returns the squared radius of the circle through a, b and c
+func circumradius(a, b, c Point) float64 {
+	o := circumcenter(a, b, c)
+	return squaredDist(a, o)
+}
+
+func circumcenter(a, b, c Point) Point {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	ex, ey := c.X-a.X, c.Y-a.Y
+	bl, cl := dx*dx+dy*dy, ex*ex+ey*ey
+	d := 0.5 / (dx*ey - dy*ex)
+	return Point{X: a.X + (ey*bl-dy*cl)*d, Y: a.Y + (dx*cl-ex*bl)*d}
+}
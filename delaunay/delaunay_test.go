@@ -0,0 +1,116 @@
+package delaunay
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// orientation returns twice the signed area of a, b, c, which is exact for
+// the small integer coordinates of these tests
+func orientation(a, b, c Point) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// insideCircle is positive if d lies inside the circle through a, b, c, which
+// turn counterclockwise; exact for small integer coordinates
+func insideCircle(a, b, c, d Point) float64 {
+	ax, ay := a.X-d.X, a.Y-d.Y
+	bx, by := b.X-d.X, b.Y-d.Y
+	cx, cy := c.X-d.X, c.Y-d.Y
+	return (ax*ax+ay*ay)*(bx*cy-cx*by) - (bx*bx+by*by)*(ax*cy-cx*ay) + (cx*cx+cy*cy)*(ax*by-bx*ay)
+}
+
+// checkTriangulation checks that the triangles of t turn counterclockwise,
+// that the half-edges pair up, that no point lies inside the circumcircle
+// of a triangle, and that the triangles fill the hull
+func checkTriangulation(t *testing.T, tr *Triangulation) {
+	t.Helper()
+	area := 0.0
+	for i := 0; i < tr.Len(); i++ {
+		tri := tr.Triangle(i)
+		a, b, c := tr.Points[tri[0]], tr.Points[tri[1]], tr.Points[tri[2]]
+		if orientation(a, b, c) <= 0 {
+			t.Fatalf("triangle %d is not counterclockwise", i)
+		}
+		area += orientation(a, b, c)
+
+		for _, p := range tr.Points {
+			if insideCircle(a, b, c, p) > 0 {
+				t.Fatalf("%v lies inside the circumcircle of triangle %d", p, i)
+			}
+		}
+		for j, adj := range tr.Adjacent(i) {
+			e := 3*i + j
+			if h := tr.Halfedges[e]; h >= 0 && (tr.Halfedges[h] != e || adj != h/3 ||
+				tr.Triangles[h] != tr.Triangles[Next(e)] || tr.Triangles[Next(h)] != tr.Triangles[e]) {
+				t.Fatalf("half-edge %d and its opposite %d do not match", e, h)
+			}
+		}
+	}
+
+	hullArea := 0.0
+	for k, i := range tr.Hull {
+		j := tr.Hull[(k+1)%len(tr.Hull)]
+		hullArea += tr.Points[i].X*tr.Points[j].Y - tr.Points[j].X*tr.Points[i].Y
+	}
+	if len(tr.Triangles) > 0 && area != hullArea {
+		t.Fatalf("triangles cover an area of %v, the hull %v", area/2, hullArea/2)
+	}
+}
+
+func TestTriangulate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{3, 4, 10, 100, 500} {
+		points := make([]Point, n)
+		for i := range points {
+			points[i] = Point{X: float64(rng.Intn(1000)), Y: float64(rng.Intn(1000))}
+		}
+		checkTriangulation(t, Triangulate(points))
+	}
+}
+
+// TestDegenerate triangulates points on a grid, which are full of
+// cocircular and collinear points, with duplicates
+func TestDegenerate(t *testing.T) {
+	var points []Point
+	for x := 0; x < 12; x++ {
+		for y := 0; y < 12; y++ {
+			points = append(points, Point{X: float64(x), Y: float64(y)})
+		}
+	}
+	points = append(points, points[:20]...)
+	tr := Triangulate(points)
+	checkTriangulation(t, tr)
+
+	// a grid of 12 by 12 points splits into 2 triangles per unit square
+	if tr.Len() != 2*11*11 {
+		t.Fatalf("got %d triangles, want %d", tr.Len(), 2*11*11)
+	}
+
+	edges := 0
+	tr.Edges(func(a, b int) bool {
+		edges++
+		return true
+	})
+	g := tr.Graph()
+	if len(g.Nodes) != len(points) || g.EdgeCount() != 2*edges {
+		t.Fatalf("graph has %d nodes and %d edges, want %d and %d", len(g.Nodes), g.EdgeCount(), len(points), 2*edges)
+	}
+}
+
+func TestCollinear(t *testing.T) {
+	points := []Point{{X: 2, Y: 2}, {X: 0, Y: 0}, {X: 3, Y: 3}, {X: 1, Y: 1}, {X: 2, Y: 2}}
+	tr := Triangulate(points)
+	if tr.Len() != 0 {
+		t.Fatalf("got %d triangles of collinear points", tr.Len())
+	}
+	want := []int{1, 3, 0, 2}
+	if len(tr.Hull) != len(want) {
+		t.Fatalf("got hull %v, want %v", tr.Hull, want)
+	}
+	for i := range want {
+		if tr.Hull[i] != want[i] {
+			t.Fatalf("got hull %v, want %v", tr.Hull, want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package delaunay
+
+// Rect is an axis aligned rectangle
+type Rect struct {
+	Min, Max Point
+}
+
+// Voronoi returns the Voronoi cell of every point, clipped to bounds: the
+// polygon of the places inside bounds nearer to that point than to any
+// other, with its vertices counterclockwise. Cells are indexed like Points;
+// duplicate points left out of the triangulation have no cell, nor do
+// points whose cell misses bounds.
+//
+// The neighbours of a point in the Voronoi diagram are its neighbours in the
+// triangulation, so every cell is the rectangle cut by the perpendicular
+// bisectors between the point and its neighbours.
+// Time complexity: O(n)
+func (t *Triangulation) Voronoi(bounds Rect) [][]Point {
+	neighbors := make([][]int, len(t.Points))
+	join := func(a, b int) bool {
+		neighbors[a] = append(neighbors[a], b)
+		neighbors[b] = append(neighbors[b], a)
+		return true
+	}
+
+	inTriangulation := make([]bool, len(t.Points))
+	for _, i := range t.Hull {
+		inTriangulation[i] = true
+	}
+	if len(t.Triangles) > 0 {
+		t.Edges(join)
+		for _, i := range t.Triangles {
+			inTriangulation[i] = true
+		}
+	} else {
+		// collinear points border their neighbours along the line
+		for k := 1; k < len(t.Hull); k++ {
+			join(t.Hull[k-1], t.Hull[k])
+		}
+	}
+
+	box := []Point{
+		bounds.Min,
+		{X: bounds.Max.X, Y: bounds.Min.Y},
+		bounds.Max,
+		{X: bounds.Min.X, Y: bounds.Max.Y},
+	}
+
+	cells := make([][]Point, len(t.Points))
+	for i, p := range t.Points {
+		if !inTriangulation[i] {
+			continue
+		}
+
+		cell := append([]Point(nil), box...)
+		for _, j := range neighbors[i] {
+			cell = clipCloser(cell, p, t.Points[j])
+		}
+		if len(cell) > 0 {
+			cells[i] = cell
+		}
+	}
+	return cells
+}
+
+// clipCloser returns the part of the convex polygon poly nearer to p than to
+// q, or at the same distance
+func clipCloser(poly []Point, p, q Point) []Point {
+	mid := Point{X: (p.X + q.X) / 2, Y: (p.Y + q.Y) / 2}
+	dx, dy := q.X-p.X, q.Y-p.Y
+	side := func(v Point) float64 { return (v.X-mid.X)*dx + (v.Y-mid.Y)*dy }
+
+	out := make([]Point, 0, len(poly)+1)
+	for k, u := range poly {
+		v := poly[(k+1)%len(poly)]
+		su, sv := side(u), side(v)
+		if su <= 0 {
+			out = append(out, u)
+		}
+		if su < 0 && sv > 0 || su > 0 && sv < 0 {
+			f := su / (su - sv)
+			out = append(out, Point{X: u.X + (v.X-u.X)*f, Y: u.Y + (v.Y-u.Y)*f})
+		}
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package delaunay
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// insideConvex reports whether p lies inside the counterclockwise convex
+// polygon poly, allowing for rounding
+func insideConvex(poly []Point, p Point) bool {
+	for k, a := range poly {
+		if orientation(a, poly[(k+1)%len(poly)], p) < -1e-6 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestVoronoi checks that the cells tile the bounds, and that every point
+// of the bounds lies in the cell of its nearest site
+func TestVoronoi(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	points := make([]Point, 200)
+	for i := range points {
+		points[i] = Point{X: float64(rng.Intn(1000)), Y: float64(rng.Intn(1000))}
+	}
+	bounds := Rect{Point{X: -100, Y: -100}, Point{X: 1100, Y: 1100}}
+	cells := Triangulate(points).Voronoi(bounds)
+
+	area := 0.0
+	for i, cell := range cells {
+		if cell == nil {
+			// left out as a duplicate
+			for j := range points[:i] {
+				if points[j] == points[i] {
+					cell = []Point{}
+				}
+			}
+			if cell == nil {
+				t.Fatalf("point %d has no cell", i)
+			}
+			continue
+		}
+		for k, a := range cell {
+			b := cell[(k+1)%len(cell)]
+			area += a.X*b.Y - b.X*a.Y
+		}
+		if !insideConvex(cell, points[i]) {
+			t.Fatalf("cell %d does not contain its site", i)
+		}
+	}
+	if want := 2 * 1200.0 * 1200; math.Abs(area-want) > 1e-6*want {
+		t.Fatalf("cells cover an area of %v, want %v", area/2, want/2)
+	}
+
+	for k := 0; k < 1000; k++ {
+		p := Point{X: rng.Float64()*1200 - 100, Y: rng.Float64()*1200 - 100}
+		nearest := 0
+		for i, q := range points {
+			if math.Hypot(q.X-p.X, q.Y-p.Y) < math.Hypot(points[nearest].X-p.X, points[nearest].Y-p.Y) {
+				nearest = i
+			}
+		}
+		if !insideConvex(cells[nearest], p) {
+			t.Fatalf("%v is not in the cell of its nearest site %v", p, points[nearest])
+		}
+	}
+}
+
+func TestVoronoiCollinear(t *testing.T) {
+	points := []Point{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 4, Y: 0}}
+	cells := Triangulate(points).Voronoi(Rect{Point{X: -1, Y: -1}, Point{X: 5, Y: 1}})
+	for i, want := range []float64{2, 2, 2} {
+		cell := cells[i]
+		minX, maxX := math.Inf(1), math.Inf(-1)
+		for _, p := range cell {
+			minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+		}
+		if maxX-minX != want {
+			t.Fatalf("cell %d spans %v to %v, want a width of %v", i, minX, maxX, want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package deque provides a generic double ended queue.
+package deque
+
+// minCapacity is the capacity of a deque on its first push
+const minCapacity = 16
+
+// Deque is a double ended queue backed by a growable circular slice, whose
+// capacity is a power of two. Pushes and pops at either end take O(1)
+// amortized, and unlike the queue = queue[1:] idiom, memory freed at the
+// front is reused. The zero value is an empty deque ready to use.
+type Deque[T any] struct {
+	buf    []T
+	head   int // index of the front item
+	length int
+}
+
+// New returns an empty deque
+func New[T any]() *Deque[T] {
+	return &Deque[T]{}
+}
+
+// Len returns the number of items
+func (q *Deque[T]) Len() int { return q.length }
+
+// index returns the slice index of item i, counted from the front
+func (q *Deque[T]) index(i int) int {
+	return (q.head + i) & (len(q.buf) - 1)
+}
+
+// Grow makes room for at least n more items without reallocating
+func (q *Deque[T]) Grow(n int) {
+	if q.length+n <= len(q.buf) {
+		return
+	}
+
+	capacity := max(len(q.buf), minCapacity)
+	for capacity < q.length+n {
+		capacity *= 2
+	}
+
+	// unwrap the items to the start of the new slice
+	buf := make([]T, capacity)
+	if q.length > 0 {
+		copied := copy(buf, q.buf[q.head:min(q.head+q.length, len(q.buf))])
+		copy(buf[copied:q.length], q.buf)
+	}
+	q.buf, q.head = buf, 0
+}
+
+// PushFront adds v at the front.
+// Time complexity: O(1) amortized
+func (q *Deque[T]) PushFront(v T) {
+	q.Grow(1)
+	q.head = q.index(len(q.buf) - 1)
+	q.buf[q.head] = v
+	q.length++
+}
+
+// PushBack adds v at the back.
+// Time complexity: O(1) amortized
+func (q *Deque[T]) PushBack(v T) {
+	q.Grow(1)
+	q.buf[q.index(q.length)] = v
+	q.length++
+}
+
+// PopFront removes and returns the front item. It panics if the deque is
+// empty.
+func (q *Deque[T]) PopFront() T {
+	if q.length == 0 {
+		panic("deque: PopFront of empty deque")
+	}
+	var zero T
+	v := q.buf[q.head]
+	q.buf[q.head] = zero
+	q.head = q.index(1)
+	q.length--
+	return v
+}
+
+// PopBack removes and returns the back item. It panics if the deque is
+// empty.
+func (q *Deque[T]) PopBack() T {
+	if q.length == 0 {
+		panic("deque: PopBack of empty deque")
+	}
+	var zero T
+	i := q.index(q.length - 1)
+	v := q.buf[i]
+	q.buf[i] = zero
+	q.length--
+	return v
+}
+
+// Front returns the front item. It panics if the deque is empty.
+func (q *Deque[T]) Front() T {
+	if q.length == 0 {
+		panic("deque: Front of empty deque")
+	}
+	return q.buf[q.head]
+}
+
+// Back returns the back item. It panics if the deque is empty.
+func (q *Deque[T]) Back() T {
+	if q.length == 0 {
+		panic("deque: Back of empty deque")
+	}
+	return q.buf[q.index(q.length-1)]
+}
+
+// At returns item i, counted from the front. It panics if i is out of range.
+func (q *Deque[T]) At(i int) T {
+	if i < 0 || i >= q.length {
+		panic("deque: index out of range")
+	}
+	return q.buf[q.index(i)]
+}
+
+// Set replaces item i, counted from the front. It panics if i is out of
+// range.
+func (q *Deque[T]) Set(i int, v T) {
+	if i < 0 || i >= q.length {
+		panic("deque: index out of range")
+	}
+	q.buf[q.index(i)] = v
+}
+
+// Reset removes all items, keeping the allocated capacity
+func (q *Deque[T]) Reset() {
+	clear(q.buf)
+	q.head, q.length = 0, 0
+}
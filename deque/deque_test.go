@@ -0,0 +1,77 @@
+package deque
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestAgainstSlice checks the deque against a slice under a random mix of
+// operations at both ends
+func TestAgainstSlice(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	q := New[int]()
+	var want []int
+
+	for i := 0; i < 10000; i++ {
+		switch op := rng.Intn(6); {
+		case op == 0:
+			q.PushFront(i)
+			want = append([]int{i}, want...)
+		case op == 1:
+			q.PushBack(i)
+			want = append(want, i)
+		case op == 2 && len(want) > 0:
+			if x := q.PopFront(); x != want[0] {
+				t.Fatalf("PopFront() = %d, want %d", x, want[0])
+			}
+			want = want[1:]
+		case op == 3 && len(want) > 0:
+			if x := q.PopBack(); x != want[len(want)-1] {
+				t.Fatalf("PopBack() = %d, want %d", x, want[len(want)-1])
+			}
+			want = want[:len(want)-1]
+		case op == 4 && len(want) > 0:
+			j := rng.Intn(len(want))
+			q.Set(j, -i)
+			want[j] = -i
+		case op == 5:
+			q.Grow(rng.Intn(20))
+		}
+
+		if q.Len() != len(want) {
+			t.Fatalf("got length %d, want %d", q.Len(), len(want))
+		}
+		if len(want) > 0 && (q.Front() != want[0] || q.Back() != want[len(want)-1]) {
+			t.Fatalf("got ends %d and %d, want %d and %d", q.Front(), q.Back(), want[0], want[len(want)-1])
+		}
+		for j, w := range want {
+			if x := q.At(j); x != w {
+				t.Fatalf("At(%d) = %d, want %d", j, x, w)
+			}
+		}
+	}
+
+	q.Reset()
+	q.PushBack(1)
+	if q.Len() != 1 || q.Front() != 1 {
+		t.Fatalf("got length %d after Reset and one push, want 1", q.Len())
+	}
+}
+
+func TestEmptyPanics(t *testing.T) {
+	for name, fn := range map[string]func(q *Deque[int]){
+		"PopFront": func(q *Deque[int]) { q.PopFront() },
+		"PopBack":  func(q *Deque[int]) { q.PopBack() },
+		"Front":    func(q *Deque[int]) { q.Front() },
+		"Back":     func(q *Deque[int]) { q.Back() },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s of an empty deque did not panic", name)
+				}
+			}()
+			fn(New[int]())
+		}()
+	}
+}
@@ -0,0 +1,95 @@
+// Package dsu provides disjoint set forests, also known as union-find, which
+// track a partition of the integers 0 to n-1 under merges of its sets.
+package dsu
+
+// DSU is a partition of the elements 0 to n-1 into disjoint sets, each
+// starting out alone. Sets are merged by size and paths are compressed on
+// every lookup, so that any sequence of operations takes nearly constant
+// amortized time per operation.
+type DSU struct {
+	// parent of every element, or for roots, minus the size of the set
+	parent []int
+	count  int
+}
+
+// New returns a partition of n elements into singleton sets
+func New(n int) *DSU {
+	d := &DSU{parent: make([]int, n), count: n}
+	for i := range d.parent {
+		d.parent[i] = -1
+	}
+	return d
+}
+
+// Len returns the number of elements
+func (d *DSU) Len() int { return len(d.parent) }
+
+// Count returns the number of sets
+func (d *DSU) Count() int { return d.count }
+
+// Add adds a new element in a set of its own and returns it
+func (d *DSU) Add() int {
+	d.parent = append(d.parent, -1)
+	d.count++
+	return len(d.parent) - 1
+}
+
+// Find returns the representative of the set containing x, which is the same
+// for all its elements until the set is merged.
+// Time complexity: O(α(n)) amortized
+func (d *DSU) Find(x int) int {
+	root := x
+	for d.parent[root] >= 0 {
+		root = d.parent[root]
+	}
+
+	// point the whole path at the root
+	for x != root {
+		x, d.parent[x] = d.parent[x], root
+	}
+	return root
+}
+
+// Union merges the sets containing a and b and reports whether they were
+// separate.
+// Time complexity: O(α(n)) amortized
+func (d *DSU) Union(a, b int) bool {
+	a, b = d.Find(a), d.Find(b)
+	if a == b {
+		return false
+	}
+
+	// hang the smaller set below the larger one
+	if d.parent[a] > d.parent[b] {
+		a, b = b, a
+	}
+	d.parent[a] += d.parent[b]
+	d.parent[b] = a
+	d.count--
+	return true
+}
+
+// Connected reports whether a and b are in the same set
+func (d *DSU) Connected(a, b int) bool { return d.Find(a) == d.Find(b) }
+
+// Size returns the number of elements in the set containing x
+func (d *DSU) Size(x int) int { return -d.parent[d.Find(x)] }
+
+// Components returns the elements of every set, in increasing order within
+// each set, with sets ordered by their least element.
+// Time complexity: O(n α(n))
+func (d *DSU) Components() [][]int {
+	index := make(map[int]int, d.count)
+	components := make([][]int, 0, d.count)
+	for x := range d.parent {
+		root := d.Find(x)
+		i, ok := index[root]
+		if !ok {
+			i = len(components)
+			index[root] = i
+			components = append(components, make([]int, 0, -d.parent[root]))
+		}
+		components[i] = append(components[i], x)
+	}
+	return components
+}
@@ -0,0 +1,80 @@
+package dsu
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestAgainstLabels checks unions against a naive labelling of every element
+// with its set
+func TestAgainstLabels(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 100
+	d := New(n)
+	label := make([]int, n)
+	for i := range label {
+		label[i] = i
+	}
+
+	sets := n
+	for i := 0; i < 300; i++ {
+		a, b := rng.Intn(n), rng.Intn(n)
+		separate := label[a] != label[b]
+		if d.Union(a, b) != separate {
+			t.Fatalf("Union(%d, %d) = %v, want %v", a, b, !separate, separate)
+		}
+		if separate {
+			old := label[b]
+			for j := range label {
+				if label[j] == old {
+					label[j] = label[a]
+				}
+			}
+			sets--
+		}
+	}
+
+	if d.Count() != sets {
+		t.Fatalf("got %d sets, want %d", d.Count(), sets)
+	}
+	for a := 0; a < n; a++ {
+		size := 0
+		for b := 0; b < n; b++ {
+			if d.Connected(a, b) != (label[a] == label[b]) {
+				t.Fatalf("Connected(%d, %d) = %v", a, b, !(label[a] == label[b]))
+			}
+			if label[a] == label[b] {
+				size++
+			}
+		}
+		if d.Size(a) != size {
+			t.Fatalf("Size(%d) = %d, want %d", a, d.Size(a), size)
+		}
+	}
+}
+
+func TestComponents(t *testing.T) {
+	d := New(6)
+	d.Union(4, 1)
+	d.Union(2, 5)
+	d.Union(5, 4)
+	if x := d.Add(); x != 6 || d.Len() != 7 {
+		t.Fatalf("Add returned %d with length %d, want 6 and 7", x, d.Len())
+	}
+
+	want := [][]int{{0}, {1, 2, 4, 5}, {3}, {6}}
+	got := d.Components()
+	if len(got) != len(want) {
+		t.Fatalf("got components %v, want %v", got, want)
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) {
+			t.Fatalf("got components %v, want %v", got, want)
+		}
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Fatalf("got components %v, want %v", got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package dsu
+
+// Rollback is a partition of the elements 0 to n-1 into disjoint sets whose
+// unions can be undone in reverse order, as needed by backtracking search
+// and offline dynamic connectivity, e.g. over a segment tree of edge
+// lifetimes. Sets are merged by size but paths are not compressed, since
+// compression could not be undone cheaply; lookups take O(log n).
+type Rollback struct {
+	// parent of every element, or for roots, minus the size of the set
+	parent []int
+	count  int
+
+	history []merge
+}
+
+// merge records a union: the root hung below another, and the size of its
+// set, or -1 if the union found the elements already connected
+type merge struct {
+	child, size int
+}
+
+// NewRollback returns a partition of n elements into singleton sets
+func NewRollback(n int) *Rollback {
+	d := &Rollback{parent: make([]int, n), count: n}
+	for i := range d.parent {
+		d.parent[i] = -1
+	}
+	return d
+}
+
+// Len returns the number of elements
+func (d *Rollback) Len() int { return len(d.parent) }
+
+// Count returns the number of sets
+func (d *Rollback) Count() int { return d.count }
+
+// Find returns the representative of the set containing x.
+// Time complexity: O(log n)
+func (d *Rollback) Find(x int) int {
+	for d.parent[x] >= 0 {
+		x = d.parent[x]
+	}
+	return x
+}
+
+// Union merges the sets containing a and b and reports whether they were
+// separate. Every call can be undone, including those that merge nothing.
+// Time complexity: O(log n)
+func (d *Rollback) Union(a, b int) bool {
+	a, b = d.Find(a), d.Find(b)
+	if a == b {
+		d.history = append(d.history, merge{-1, 0})
+		return false
+	}
+
+	if d.parent[a] > d.parent[b] {
+		a, b = b, a
+	}
+	d.history = append(d.history, merge{b, -d.parent[b]})
+	d.parent[a] += d.parent[b]
+	d.parent[b] = a
+	d.count--
+	return true
+}
+
+// Connected reports whether a and b are in the same set
+func (d *Rollback) Connected(a, b int) bool { return d.Find(a) == d.Find(b) }
+
+// Size returns the number of elements in the set containing x
+func (d *Rollback) Size(x int) int { return -d.parent[d.Find(x)] }
+
+// Checkpoint returns the number of unions so far, which RollbackTo accepts
+// to undo all later ones
+func (d *Rollback) Checkpoint() int { return len(d.history) }
+
+// Undo reverts the last union not yet undone, and reports whether there was
+// one.
+// Time complexity: O(1)
+func (d *Rollback) Undo() bool {
+	if len(d.history) == 0 {
+		return false
+	}
+
+	m := d.history[len(d.history)-1]
+	d.history = d.history[:len(d.history)-1]
+	if m.child < 0 {
+		return true
+	}
+
+	a := d.parent[m.child]
+	d.parent[a] += m.size
+	d.parent[m.child] = -m.size
+	d.count++
+	return true
+}
+
+// RollbackTo undoes the unions made since checkpoint was taken. It panics if
+// those unions were already undone.
+func (d *Rollback) RollbackTo(checkpoint int) {
+	if checkpoint > len(d.history) {
+		panic("dsu: checkpoint is no longer valid")
+	}
+	for len(d.history) > checkpoint {
+		d.Undo()
+	}
+}
@@ -0,0 +1,72 @@
+package dsu
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestRollback undoes random unions and checks that the partition returns to
+// the one recorded at every checkpoint
+func TestRollback(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 50
+	d := NewRollback(n)
+
+	snapshot := func() [n]int {
+		var roots [n]int
+		for i := range roots {
+			roots[i] = d.Find(i)
+		}
+		return roots
+	}
+
+	type saved struct {
+		checkpoint, count int
+		roots             [n]int
+	}
+	var stack []saved
+
+	for i := 0; i < 500; i++ {
+		switch rng.Intn(4) {
+		case 0:
+			stack = append(stack, saved{d.Checkpoint(), d.Count(), snapshot()})
+		case 1:
+			if len(stack) == 0 {
+				continue
+			}
+			s := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			d.RollbackTo(s.checkpoint)
+			if d.Count() != s.count || snapshot() != s.roots {
+				t.Fatalf("rolling back to %d did not restore the partition", s.checkpoint)
+			}
+		default:
+			d.Union(rng.Intn(n), rng.Intn(n))
+		}
+	}
+
+	for d.Undo() {
+	}
+	if d.Count() != n || d.Len() != n {
+		t.Fatalf("got %d sets of %d elements after undoing all unions", d.Count(), d.Len())
+	}
+}
+
+func TestUndo(t *testing.T) {
+	d := NewRollback(4)
+	d.Union(0, 1)
+	d.Union(1, 0) // merges nothing, but is undone all the same
+	d.Union(2, 3)
+
+	if !d.Connected(0, 1) || d.Size(3) != 2 {
+		t.Fatal("unions were not applied")
+	}
+	d.Undo()
+	if d.Connected(2, 3) {
+		t.Fatal("Undo did not revert the last union")
+	}
+	d.Undo()
+	if !d.Connected(0, 1) {
+		t.Fatal("Undo reverted an earlier union")
+	}
+}
@@ -0,0 +1,153 @@
+// Package fenwick provides Fenwick trees, also called binary indexed trees,
+// for prefix sums over arrays that are updated in place.
+package fenwick
+
+// Number is a type that values can be summed in
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Tree holds an array of n values, all zero initially, and maintains their
+// prefix sums: both point updates and sums over ranges take O(log n). It
+// takes no more memory than the array itself, making it a lighter
+// alternative to a segment tree when only sums are needed.
+type Tree[T Number] struct {
+	// sums[i] holds the sum of values i-lowbit(i) up to but excluding i,
+	// where lowbit(i) is the lowest set bit of i
+	sums []T
+}
+
+// New returns a tree of n zero values
+func New[T Number](n int) *Tree[T] {
+	return &Tree[T]{sums: make([]T, n+1)}
+}
+
+// From returns a tree holding a copy of values.
+// Time complexity: O(n)
+func From[T Number](values []T) *Tree[T] {
+	t := &Tree[T]{sums: make([]T, len(values)+1)}
+	copy(t.sums[1:], values)
+	for i := 1; i < len(t.sums); i++ {
+		if j := i + i&-i; j < len(t.sums) {
+			t.sums[j] += t.sums[i]
+		}
+	}
+	return t
+}
+
+// Len returns the number of values
+func (t *Tree[T]) Len() int { return len(t.sums) - 1 }
+
+// Add adds delta to value i.
+// Time complexity: O(log n)
+func (t *Tree[T]) Add(i int, delta T) {
+	if i < 0 || i >= t.Len() {
+		panic("fenwick: index out of range")
+	}
+	for i++; i < len(t.sums); i += i & -i {
+		t.sums[i] += delta
+	}
+}
+
+// Set sets value i to value.
+// Time complexity: O(log n)
+func (t *Tree[T]) Set(i int, value T) {
+	t.Add(i, value-t.Sum(i, i+1))
+}
+
+// Prefix returns the sum of values 0 up to but excluding i.
+// Time complexity: O(log n)
+func (t *Tree[T]) Prefix(i int) T {
+	if i < 0 || i > t.Len() {
+		panic("fenwick: index out of range")
+	}
+	var sum T
+	for ; i > 0; i -= i & -i {
+		sum += t.sums[i]
+	}
+	return sum
+}
+
+// Sum returns the sum of values lo up to but excluding hi.
+// Time complexity: O(log n)
+func (t *Tree[T]) Sum(lo, hi int) T {
+	return t.Prefix(hi) - t.Prefix(lo)
+}
+
+// LowerBound returns the least i such that the values 0 through i sum to at
+// least sum, or Len() if there is none. All values must be non-negative, so
+// that prefix sums are sorted. With values counting occurrences of every
+// index, LowerBound(k+1) is the k-th smallest index counted, from 0.
+// Time complexity: O(log n)
+func (t *Tree[T]) LowerBound(sum T) int {
+	step := 1
+	for step*2 < len(t.sums) {
+		step *= 2
+	}
+
+	// descend the implicit tree, keeping the prefix up to i below sum
+	i := 0
+	for ; step > 0; step /= 2 {
+		if j := i + step; j < len(t.sums) && t.sums[j] < sum {
+			i = j
+			sum -= t.sums[j]
+		}
+	}
+	return i
+}
+
+// Tree2D holds a grid of values, all zero initially, and maintains the sums
+// of its rectangles: both point updates and sums take O(log rows log cols).
+type Tree2D[T Number] struct {
+	rows, cols int
+	sums       []T // (rows+1) x (cols+1), indexed as in Tree on both axes
+}
+
+// New2D returns a tree of rows x cols zero values
+func New2D[T Number](rows, cols int) *Tree2D[T] {
+	return &Tree2D[T]{rows: rows, cols: cols, sums: make([]T, (rows+1)*(cols+1))}
+}
+
+// Rows returns the number of rows
+func (t *Tree2D[T]) Rows() int { return t.rows }
+
+// Cols returns the number of columns
+func (t *Tree2D[T]) Cols() int { return t.cols }
+
+// Add adds delta to the value at row r and column c.
+// Time complexity: O(log rows log cols)
+func (t *Tree2D[T]) Add(r, c int, delta T) {
+	if r < 0 || r >= t.rows || c < 0 || c >= t.cols {
+		panic("fenwick: index out of range")
+	}
+	for i := r + 1; i <= t.rows; i += i & -i {
+		for j := c + 1; j <= t.cols; j += j & -j {
+			t.sums[i*(t.cols+1)+j] += delta
+		}
+	}
+}
+
+// Prefix returns the sum of the values in rows 0 up to but excluding r and
+// columns 0 up to but excluding c.
+// Time complexity: O(log rows log cols)
+func (t *Tree2D[T]) Prefix(r, c int) T {
+	if r < 0 || r > t.rows || c < 0 || c > t.cols {
+		panic("fenwick: index out of range")
+	}
+	var sum T
+	for i := r; i > 0; i -= i & -i {
+		for j := c; j > 0; j -= j & -j {
+			sum += t.sums[i*(t.cols+1)+j]
+		}
+	}
+	return sum
+}
+
+// Sum returns the sum of the values in rows r0 up to but excluding r1 and
+// columns c0 up to but excluding c1.
+// Time complexity: O(log rows log cols)
+func (t *Tree2D[T]) Sum(r0, c0, r1, c1 int) T {
+	return t.Prefix(r1, c1) - t.Prefix(r0, c1) - t.Prefix(r1, c0) + t.Prefix(r0, c0)
+}
@@ -0,0 +1,87 @@
+package fenwick
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPrefixSums(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = rng.Intn(10)
+	}
+	tree := From(values)
+
+	for i := 0; i < 500; i++ {
+		j := rng.Intn(len(values))
+		if rng.Intn(2) == 0 {
+			d := rng.Intn(10)
+			tree.Add(j, d)
+			values[j] += d
+		} else {
+			v := rng.Intn(10)
+			tree.Set(j, v)
+			values[j] = v
+		}
+
+		lo := rng.Intn(len(values) + 1)
+		hi := lo + rng.Intn(len(values)+1-lo)
+		want := 0
+		for _, v := range values[lo:hi] {
+			want += v
+		}
+		if got := tree.Sum(lo, hi); got != want {
+			t.Fatalf("Sum(%d, %d) = %d, want %d", lo, hi, got, want)
+		}
+	}
+
+	if tree.Len() != len(values) {
+		t.Fatalf("got length %d, want %d", tree.Len(), len(values))
+	}
+}
+
+func TestLowerBound(t *testing.T) {
+	// counts of the indices 1, 1, 4 and 7
+	tree := New[int](10)
+	for _, i := range []int{1, 4, 1, 7} {
+		tree.Add(i, 1)
+	}
+
+	for k, want := range []int{1, 1, 4, 7} {
+		if got := tree.LowerBound(k + 1); got != want {
+			t.Fatalf("LowerBound(%d) = %d, want %d", k+1, got, want)
+		}
+	}
+	if got := tree.LowerBound(5); got != 10 {
+		t.Fatalf("LowerBound past the total = %d, want 10", got)
+	}
+}
+
+func TestTree2D(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	tree := New2D[float64](8, 13)
+	var grid [8][13]float64
+
+	for i := 0; i < 200; i++ {
+		r, c := rng.Intn(8), rng.Intn(13)
+		d := float64(rng.Intn(5))
+		tree.Add(r, c, d)
+		grid[r][c] += d
+	}
+
+	for r0 := 0; r0 <= 8; r0++ {
+		for c0 := 0; c0 <= 13; c0 += 3 {
+			r1, c1 := r0+rng.Intn(9-r0), c0+rng.Intn(14-c0)
+			want := 0.0
+			for r := r0; r < r1; r++ {
+				for c := c0; c < c1; c++ {
+					want += grid[r][c]
+				}
+			}
+			if got := tree.Sum(r0, c0, r1, c1); got != want {
+				t.Fatalf("Sum(%d, %d, %d, %d) = %v, want %v", r0, c0, r1, c1, got, want)
+			}
+		}
+	}
+}
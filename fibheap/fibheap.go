@@ -0,0 +1,247 @@
+// Package fibheap provides a generic Fibonacci heap, the heap with the best
+// known amortized bounds for decrease-key heavy workloads.
+package fibheap
+
+// Handle refers to an entry of a FibHeap, and stays valid after the entry has
+// been removed
+type Handle[T, P any] struct {
+	value    T
+	priority P
+
+	parent, child *Handle[T, P]
+	left, right   *Handle[T, P] // circular list of siblings
+	degree        int
+	marked        bool // lost a child since it became a child itself
+	queued        bool
+}
+
+// Value returns the value of the entry
+func (h *Handle[T, P]) Value() T { return h.value }
+
+// Priority returns the current priority of the entry
+func (h *Handle[T, P]) Priority() P { return h.priority }
+
+// FibHeap is a min heap of values with priorities ordered by a less function,
+// stored as a list of heap ordered trees. Push, Meld and DecreaseKey run in
+// O(1) amortized time, Pop and Remove in O(log n) amortized time. The
+// constant factors are higher than those of a binary or pairing heap, so it
+// mainly pays off on very dense graphs.
+type FibHeap[T, P any] struct {
+	min  *Handle[T, P] // root with the least priority, part of the root list
+	size int
+	less func(a, b P) bool
+}
+
+// New returns an empty heap ordered by less
+func New[T, P any](less func(a, b P) bool) *FibHeap[T, P] {
+	return &FibHeap[T, P]{less: less}
+}
+
+// Len returns the number of entries in the heap
+func (q *FibHeap[T, P]) Len() int { return q.size }
+
+// Push adds value with the given priority and returns its handle.
+// Time complexity: O(1)
+func (q *FibHeap[T, P]) Push(value T, priority P) *Handle[T, P] {
+	h := &Handle[T, P]{value: value, priority: priority, queued: true}
+	h.left, h.right = h, h
+	q.addRoot(h)
+	q.size++
+	return h
+}
+
+// Peek returns the handle of the entry with the least priority without
+// removing it. It panics if the heap is empty.
+func (q *FibHeap[T, P]) Peek() *Handle[T, P] {
+	if q.min == nil {
+		panic("fibheap: Peek on empty heap")
+	}
+	return q.min
+}
+
+// Pop removes the entry with the least priority and returns its handle. It
+// panics if the heap is empty. Time complexity: O(log n) amortized
+func (q *FibHeap[T, P]) Pop() *Handle[T, P] {
+	h := q.min
+	if h == nil {
+		panic("fibheap: Pop on empty heap")
+	}
+
+	// promote the children to roots
+	for h.child != nil {
+		c := h.child
+		q.unlink(c)
+		c.parent, c.marked = nil, false
+		q.splice(h, c)
+	}
+
+	if h.right == h {
+		q.min = nil
+	} else {
+		q.min = h.right
+		q.unlink(h)
+		q.consolidate()
+	}
+
+	q.size--
+	h.left, h.right, h.queued = h, h, false
+	return h
+}
+
+// DecreaseKey lowers the priority of the entry of h. It panics if the entry
+// has been removed or the new priority is greater than the old one.
+// Time complexity: O(1) amortized
+func (q *FibHeap[T, P]) DecreaseKey(h *Handle[T, P], priority P) {
+	if !h.queued {
+		panic("fibheap: handle is not queued")
+	}
+	if q.less(h.priority, priority) {
+		panic("fibheap: DecreaseKey to a greater priority")
+	}
+
+	h.priority = priority
+	if p := h.parent; p != nil && q.less(h.priority, p.priority) {
+		q.cut(h)
+	}
+	if q.less(h.priority, q.min.priority) {
+		q.min = h
+	}
+}
+
+// Remove takes the entry of h out of the heap. It panics if the entry has
+// already been removed. Time complexity: O(log n) amortized
+func (q *FibHeap[T, P]) Remove(h *Handle[T, P]) {
+	if !h.queued {
+		panic("fibheap: handle is not queued")
+	}
+
+	// move h to the root list as if its priority were lowest, then pop it
+	if h.parent != nil {
+		q.cut(h)
+	}
+	q.min = h
+	q.Pop()
+}
+
+// Meld moves all entries of other into q, leaving other empty. Both heaps
+// must be ordered by the same less function. Handles of entries of other
+// remain valid for q. Time complexity: O(1)
+func (q *FibHeap[T, P]) Meld(other *FibHeap[T, P]) {
+	if other.min == nil {
+		return
+	}
+	if q.min == nil {
+		q.min = other.min
+	} else {
+		q.splice(q.min, other.min)
+		if q.less(other.min.priority, q.min.priority) {
+			q.min = other.min
+		}
+	}
+
+	q.size += other.size
+	other.min, other.size = nil, 0
+}
+
+// addRoot adds the single tree h to the root list
+func (q *FibHeap[T, P]) addRoot(h *Handle[T, P]) {
+	if q.min == nil {
+		q.min = h
+		return
+	}
+	q.splice(q.min, h)
+	if q.less(h.priority, q.min.priority) {
+		q.min = h
+	}
+}
+
+// splice joins the circular lists containing a and b
+func (q *FibHeap[T, P]) splice(a, b *Handle[T, P]) {
+	ar, bl := a.right, b.left
+	a.right, b.left = b, a
+	bl.right, ar.left = ar, bl
+}
+
+// unlink removes h from its sibling list, updating the child pointer of its
+// parent, and leaves it as a list of its own
+func (q *FibHeap[T, P]) unlink(h *Handle[T, P]) {
+	if p := h.parent; p != nil {
+		if h.right == h {
+			p.child = nil
+		} else if p.child == h {
+			p.child = h.right
+		}
+		p.degree--
+	}
+
+	h.left.right, h.right.left = h.right, h.left
+	h.left, h.right = h, h
+}
+
+// cut moves h to the root list, and cuts its parent as well if it already
+// lost a child before (cascading cut)
+func (q *FibHeap[T, P]) cut(h *Handle[T, P]) {
+	for h.parent != nil {
+		p := h.parent
+		q.unlink(h)
+		h.parent, h.marked = nil, false
+		q.splice(q.min, h)
+
+		if p.parent == nil {
+			return
+		}
+		if !p.marked {
+			p.marked = true
+			return
+		}
+		h = p
+	}
+}
+
+// consolidate links roots of equal degree until all roots differ in degree,
+// and finds the new minimum
+func (q *FibHeap[T, P]) consolidate() {
+	var roots []*Handle[T, P]
+	for h := q.min; ; {
+		roots = append(roots, h)
+		h = h.right
+		if h == q.min {
+			break
+		}
+	}
+
+	var byDegree []*Handle[T, P]
+	for _, h := range roots {
+		q.unlink(h)
+		for {
+			for h.degree >= len(byDegree) {
+				byDegree = append(byDegree, nil)
+			}
+			other := byDegree[h.degree]
+			if other == nil {
+				break
+			}
+			byDegree[h.degree] = nil
+
+			// make the root with the greater priority a child of the other
+			if q.less(other.priority, h.priority) {
+				h, other = other, h
+			}
+			other.parent = h
+			if h.child == nil {
+				h.child = other
+			} else {
+				q.splice(h.child, other)
+			}
+			h.degree++
+		}
+		byDegree[h.degree] = h
+	}
+
+	q.min = nil
+	for _, h := range byDegree {
+		if h != nil {
+			q.addRoot(h)
+		}
+	}
+}
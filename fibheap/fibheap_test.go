@@ -0,0 +1,98 @@
+package fibheap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func less(a, b int) bool { return a < b }
+
+// TestRandomOperations checks the heap against a map of the queued entries
+// under a random mix of pushes, decrease-keys, removals and pops
+func TestRandomOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	q := New[int](less)
+	queued := make(map[*Handle[int, int]]bool)
+
+	for i := 0; i < 5000; i++ {
+		var some *Handle[int, int]
+		for h := range queued {
+			some = h
+			break
+		}
+
+		switch op := rng.Intn(4); {
+		case op == 0 || some == nil:
+			queued[q.Push(i, rng.Intn(1000))] = true
+		case op == 1:
+			q.DecreaseKey(some, some.Priority()-rng.Intn(100))
+		case op == 2:
+			q.Remove(some)
+			delete(queued, some)
+		default:
+			min := some.Priority()
+			for h := range queued {
+				if h.Priority() < min {
+					min = h.Priority()
+				}
+			}
+			if h := q.Peek(); h.Priority() != min {
+				t.Fatalf("peeked priority %d, want %d", h.Priority(), min)
+			}
+			h := q.Pop()
+			if h.Priority() != min || !queued[h] {
+				t.Fatalf("popped priority %d, want %d", h.Priority(), min)
+			}
+			delete(queued, h)
+		}
+
+		if q.Len() != len(queued) {
+			t.Fatalf("got length %d, want %d", q.Len(), len(queued))
+		}
+	}
+}
+
+func TestMeld(t *testing.T) {
+	a, b := New[int](less), New[int](less)
+	for i := 0; i < 10; i++ {
+		a.Push(i, 2*i)
+		b.Push(i, 2*i+1)
+	}
+	h := b.Push(-1, 100)
+
+	a.Meld(b)
+	if a.Len() != 21 || b.Len() != 0 {
+		t.Fatalf("got lengths %d and %d after Meld, want 21 and 0", a.Len(), b.Len())
+	}
+
+	// handles of the melded heap stay valid
+	a.DecreaseKey(h, -1)
+	if p := a.Pop(); p != h {
+		t.Fatalf("popped %d, want the decreased entry", p.Value())
+	}
+	for want := 0; want < 20; want++ {
+		if p := a.Pop().Priority(); p != want {
+			t.Fatalf("popped priority %d, want %d", p, want)
+		}
+	}
+}
+
+func TestPanics(t *testing.T) {
+	q := New[int](less)
+	h := q.Push(1, 10)
+
+	mustPanic(t, "increasing a key", func() { q.DecreaseKey(h, 11) })
+	q.Pop()
+	mustPanic(t, "removing a popped entry", func() { q.Remove(h) })
+	mustPanic(t, "popping an empty heap", func() { q.Pop() })
+}
+
+func mustPanic(t *testing.T, what string, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("%s did not panic", what)
+		}
+	}()
+	fn()
+}
@@ -0,0 +1,263 @@
+// Package geohash encodes geographic coordinates as geohashes, strings over
+// a base 32 alphabet naming cells of a recursive latitude and longitude
+// grid, such that nearby points mostly share a prefix. Geohashes can be
+// stored and range scanned in any key-value store, to filter spatial data
+// before an exact test, e.g. with an R-tree.
+package geohash
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// MaxPrecision is the greatest length of a geohash string, whose cells
+// measure about 3.7cm by 1.9cm
+const MaxPrecision = 12
+
+const alphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// ErrInvalidHash is returned when decoding a string that is not a geohash
+var ErrInvalidHash = errors.New("geohash: invalid geohash")
+
+var decodeTable = func() (t [256]int8) {
+	for i := range t {
+		t[i] = -1
+	}
+	for i, c := range alphabet {
+		t[c] = int8(i)
+		t[strings.ToUpper(string(c))[0]] = int8(i)
+	}
+	return t
+}()
+
+// Box is the area between two latitudes and two longitudes, in degrees
+type Box struct {
+	MinLat, MinLng float64
+	MaxLat, MaxLng float64
+}
+
+// Center returns the latitude and longitude of the middle of b
+func (b Box) Center() (lat, lng float64) {
+	return (b.MinLat + b.MaxLat) / 2, (b.MinLng + b.MaxLng) / 2
+}
+
+// Contains reports whether the point at lat, lng lies inside b, including
+// its boundary
+func (b Box) Contains(lat, lng float64) bool {
+	return b.MinLat <= lat && lat <= b.MaxLat && b.MinLng <= lng && lng <= b.MaxLng
+}
+
+// Encode returns the geohash of the given length of the cell holding the
+// point at lat, lng. It panics if precision is not between 1 and
+// MaxPrecision, or if the coordinates are out of range.
+func Encode(lat, lng float64, precision int) string {
+	if precision < 1 || precision > MaxPrecision {
+		panic("geohash: precision out of range")
+	}
+
+	h := EncodeInt(lat, lng, 5*precision)
+	buf := make([]byte, precision)
+	for i := precision - 1; i >= 0; i-- {
+		buf[i] = alphabet[h&31]
+		h >>= 5
+	}
+	return string(buf)
+}
+
+// EncodeInt returns the geohash of the point at lat, lng as an integer of
+// the given number of bits, for precisions between those of strings. Bits
+// alternate between longitude and latitude, starting with longitude from the
+// most significant one. It panics if bits is not between 1 and 64, or if the
+// coordinates are out of range.
+func EncodeInt(lat, lng float64, bits int) uint64 {
+	if bits < 1 || bits > 64 {
+		panic("geohash: number of bits out of range")
+	}
+	if !(-90 <= lat && lat <= 90 && -180 <= lng && lng <= 180) {
+		panic("geohash: coordinates out of range")
+	}
+
+	latBits := bits / 2
+	lngBits := bits - latBits
+	return interleave(quantize(lng, 180, lngBits), quantize(lat, 90, latBits), lngBits, latBits)
+}
+
+// quantize returns the index of the cell holding x among 2^bits equal cells
+// from -limit to limit
+func quantize(x, limit float64, bits int) uint64 {
+	cells := math.Ldexp(1, bits)
+	i := math.Floor((x + limit) / (2 * limit) * cells)
+	return uint64(min(i, cells-1))
+}
+
+// interleave merges the bits of lng and lat, most significant first,
+// starting with lng
+func interleave(lng, lat uint64, lngBits, latBits int) uint64 {
+	var h uint64
+	for i := 0; i < lngBits+latBits; i++ {
+		h <<= 1
+		if i%2 == 0 {
+			h |= lng >> (lngBits - 1 - i/2) & 1
+		} else {
+			h |= lat >> (latBits - 1 - i/2) & 1
+		}
+	}
+	return h
+}
+
+// Decode returns the center of the cell named by hash, the point it
+// represents best. ErrInvalidHash is returned if hash is not a geohash.
+func Decode(hash string) (lat, lng float64, err error) {
+	b, err := BoundingBox(hash)
+	if err != nil {
+		return 0, 0, err
+	}
+	lat, lng = b.Center()
+	return lat, lng, nil
+}
+
+// BoundingBox returns the cell named by hash. ErrInvalidHash is returned if
+// hash is not a geohash.
+func BoundingBox(hash string) (Box, error) {
+	h, bits, err := parse(hash)
+	if err != nil {
+		return Box{}, err
+	}
+	return BoundingBoxInt(h, bits), nil
+}
+
+func parse(hash string) (uint64, int, error) {
+	if len(hash) < 1 || len(hash) > MaxPrecision {
+		return 0, 0, ErrInvalidHash
+	}
+
+	var h uint64
+	for i := 0; i < len(hash); i++ {
+		d := decodeTable[hash[i]]
+		if d < 0 {
+			return 0, 0, ErrInvalidHash
+		}
+		h = h<<5 | uint64(d)
+	}
+	return h, 5 * len(hash), nil
+}
+
+// BoundingBoxInt returns the cell named by the integer geohash h of the
+// given number of bits. It panics if bits is not between 1 and 64.
+func BoundingBoxInt(h uint64, bits int) Box {
+	if bits < 1 || bits > 64 {
+		panic("geohash: number of bits out of range")
+	}
+
+	latBits := bits / 2
+	lngBits := bits - latBits
+
+	var lat, lng uint64
+	for i := 0; i < bits; i++ {
+		bit := h >> (bits - 1 - i) & 1
+		if i%2 == 0 {
+			lng = lng<<1 | bit
+		} else {
+			lat = lat<<1 | bit
+		}
+	}
+
+	latSize := math.Ldexp(180, -latBits)
+	lngSize := math.Ldexp(360, -lngBits)
+	return Box{
+		MinLat: -90 + float64(lat)*latSize,
+		MinLng: -180 + float64(lng)*lngSize,
+		MaxLat: -90 + float64(lat+1)*latSize,
+		MaxLng: -180 + float64(lng+1)*lngSize,
+	}
+}
+
+// Direction is a compass direction for Neighbor
+type Direction int
+
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+)
+
+// offsets holds the steps in cells along latitude and longitude of every
+// direction
+var offsets = [8][2]float64{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+
+// Neighbor returns the geohash of the same length of the cell adjacent to
+// hash in direction d. Longitudes wrap around the antimeridian; there is no
+// cell north of the north pole nor south of the south pole, for which the
+// empty string is returned. ErrInvalidHash is returned if hash is not a
+// geohash.
+func Neighbor(hash string, d Direction) (string, error) {
+	b, err := BoundingBox(hash)
+	if err != nil {
+		return "", err
+	}
+	return neighbor(b, len(hash), d), nil
+}
+
+// Neighbors returns the geohashes of the eight cells around hash, indexed
+// by Direction, as returned by Neighbor
+func Neighbors(hash string) ([8]string, error) {
+	var result [8]string
+	b, err := BoundingBox(hash)
+	if err != nil {
+		return result, err
+	}
+	for d := range result {
+		result[d] = neighbor(b, len(hash), Direction(d))
+	}
+	return result, nil
+}
+
+func neighbor(b Box, precision int, d Direction) string {
+	lat, lng := b.Center()
+	lat += offsets[d][0] * (b.MaxLat - b.MinLat)
+	lng += offsets[d][1] * (b.MaxLng - b.MinLng)
+
+	if lat < -90 || lat > 90 {
+		return ""
+	}
+	if lng < -180 {
+		lng += 360
+	} else if lng > 180 {
+		lng -= 360
+	}
+	return Encode(lat, lng, precision)
+}
+
+// Cover returns the geohashes of the given length of all cells that overlap
+// b, e.g. to query a store keyed by geohash for the points inside b. A box
+// with MinLng greater than MaxLng crosses the antimeridian. The number of
+// cells grows with the square of the ratio of the box to the cell size, so
+// callers should pick a precision with cells not much smaller than b. It
+// panics if precision is out of range or b extends beyond the poles.
+func Cover(b Box, precision int) []string {
+	if b.MinLat > b.MaxLat || b.MinLat < -90 || b.MaxLat > 90 {
+		panic("geohash: box out of range")
+	}
+	if b.MinLng > b.MaxLng {
+		west := Box{MinLat: b.MinLat, MinLng: b.MinLng, MaxLat: b.MaxLat, MaxLng: 180}
+		east := Box{MinLat: b.MinLat, MinLng: -180, MaxLat: b.MaxLat, MaxLng: b.MaxLng}
+		return append(Cover(west, precision), Cover(east, precision)...)
+	}
+
+	cell, _ := BoundingBox(Encode(b.MinLat, b.MinLng, precision))
+	latSize, lngSize := cell.MaxLat-cell.MinLat, cell.MaxLng-cell.MinLng
+
+	var hashes []string
+	for lat := cell.MinLat; lat <= b.MaxLat && lat < 90; lat += latSize {
+		for lng := cell.MinLng; lng <= b.MaxLng && lng < 180; lng += lngSize {
+			hashes = append(hashes, Encode(lat+latSize/2, lng+lngSize/2, precision))
+		}
+	}
+	return hashes
+}
@@ -0,0 +1,119 @@
+package geohash
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestKnownHashes(t *testing.T) {
+	for _, c := range []struct {
+		lat, lng  float64
+		precision int
+		want      string
+	}{
+		{57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{42.605, -5.603, 5, "ezs42"},
+		{-25.382708, -49.265506, 8, "6gkzwgjz"},
+	} {
+		if got := Encode(c.lat, c.lng, c.precision); got != c.want {
+			t.Errorf("Encode(%v, %v, %d) = %q, want %q", c.lat, c.lng, c.precision, got, c.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		lat, lng := rng.Float64()*180-90, rng.Float64()*360-180
+		precision := 1 + rng.Intn(MaxPrecision)
+		hash := Encode(lat, lng, precision)
+
+		b, err := BoundingBox(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !b.Contains(lat, lng) {
+			t.Fatalf("box %v of %q does not contain %v, %v", b, hash, lat, lng)
+		}
+		if b != BoundingBoxInt(EncodeInt(lat, lng, 5*precision), 5*precision) {
+			t.Fatalf("string and integer boxes of %q differ", hash)
+		}
+
+		clat, clng, err := Decode(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if Encode(clat, clng, precision) != hash {
+			t.Fatalf("center of %q encodes to %q", hash, Encode(clat, clng, precision))
+		}
+	}
+
+	if _, err := BoundingBox("u4pa"); !errors.Is(err, ErrInvalidHash) {
+		t.Fatalf("got %v for a hash with an a, want ErrInvalidHash", err)
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	ns, err := Neighbors("u4pru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := BoundingBox("u4pru")
+	for d, n := range ns {
+		nb, err := BoundingBox(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// adjacent cells share a boundary
+		if nb.MinLat > b.MaxLat || nb.MaxLat < b.MinLat || nb.MinLng > b.MaxLng || nb.MaxLng < b.MinLng || n == "u4pru" {
+			t.Fatalf("neighbor %d of u4pru is %q, which is not adjacent", d, n)
+		}
+		if back, _ := Neighbor(n, Direction((d+4)%8)); back != "u4pru" {
+			t.Fatalf("neighbor %d of %q is %q, want u4pru", (d+4)%8, n, back)
+		}
+	}
+
+	// longitudes wrap, latitudes do not
+	east := Encode(0, 179.99, 4)
+	if n, _ := Neighbor(east, East); n != Encode(0, -179.99, 4) {
+		t.Fatalf("east of %q is %q, want %q", east, n, Encode(0, -179.99, 4))
+	}
+	if n, _ := Neighbor(Encode(89.99, 0, 4), North); n != "" {
+		t.Fatalf("north of the pole is %q", n)
+	}
+}
+
+func TestCover(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		lat, lng := rng.Float64()*160-80, rng.Float64()*340-170
+		b := Box{lat, lng, lat + rng.Float64()*5, lng + rng.Float64()*5}
+		cover := Cover(b, 3)
+
+		set := make(map[string]bool)
+		for _, h := range cover {
+			if set[h] {
+				t.Fatalf("Cover returned %q twice", h)
+			}
+			set[h] = true
+		}
+		for j := 0; j < 100; j++ {
+			plat := b.MinLat + rng.Float64()*(b.MaxLat-b.MinLat)
+			plng := b.MinLng + rng.Float64()*(b.MaxLng-b.MinLng)
+			if !set[Encode(plat, plng, 3)] {
+				t.Fatalf("Cover(%v) misses the cell of %v, %v", b, plat, plng)
+			}
+		}
+	}
+
+	// a box across the antimeridian
+	cover := Cover(Box{0, 179, 1, -179}, 4)
+	set := make(map[string]bool)
+	for _, h := range cover {
+		set[h] = true
+	}
+	if !set[Encode(0.5, 179.5, 4)] || !set[Encode(0.5, -179.5, 4)] || set[Encode(0.5, 0, 4)] {
+		t.Fatal("Cover of a box across the antimeridian is wrong")
+	}
+}
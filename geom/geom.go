@@ -0,0 +1,156 @@
+// Package geom provides primitives of plane geometry with exact decisions:
+// orientation and circle tests, segment intersection, point in polygon
+// tests, and polygon area and centroid.
+package geom
+
+// Point is a position in the plane
+type Point struct {
+	X, Y float64
+}
+
+// onSegment reports whether p, collinear with a and b, lies between them
+func onSegment(a, b, p Point) bool {
+	return min(a.X, b.X) <= p.X && p.X <= max(a.X, b.X) && min(a.Y, b.Y) <= p.Y && p.Y <= max(a.Y, b.Y)
+}
+
+// SegmentsIntersect reports whether the closed segments from a to b and
+// from c to d share a point. The result is exact.
+func SegmentsIntersect(a, b, c, d Point) bool {
+	o1, o2 := Orient(a, b, c), Orient(a, b, d)
+	o3, o4 := Orient(c, d, a), Orient(c, d, b)
+	if o1*o2 < 0 && o3*o4 < 0 {
+		return true
+	}
+
+	return o1 == 0 && onSegment(a, b, c) ||
+		o2 == 0 && onSegment(a, b, d) ||
+		o3 == 0 && onSegment(c, d, a) ||
+		o4 == 0 && onSegment(c, d, b)
+}
+
+// Intersection returns a point shared by the segments from a to b and from
+// c to d, and reports whether there is one: their crossing point, or for
+// collinear overlapping segments an end point of the overlap. Whether the
+// segments meet is decided exactly; the crossing point is rounded.
+func Intersection(a, b, c, d Point) (Point, bool) {
+	if !SegmentsIntersect(a, b, c, d) {
+		return Point{}, false
+	}
+
+	collinear := Orient(a, b, c) == 0 && Orient(a, b, d) == 0
+	rx, ry := b.X-a.X, b.Y-a.Y
+	sx, sy := d.X-c.X, d.Y-c.Y
+	if denom := rx*sy - ry*sx; !collinear && denom != 0 {
+		t := ((c.X-a.X)*sy - (c.Y-a.Y)*sx) / denom
+		t = min(max(t, 0), 1)
+		return Point{a.X + t*rx, a.Y + t*ry}, true
+	}
+
+	// an end point of one segment lies on the other
+	for _, p := range [4]Point{c, d, a, b} {
+		if Orient(a, b, p) == 0 && onSegment(a, b, p) && Orient(c, d, p) == 0 && onSegment(c, d, p) {
+			return p, true
+		}
+	}
+	return a, true
+}
+
+// Polygon is a closed polygon given by its vertices, the last joined to the
+// first
+type Polygon []Point
+
+// Area returns the signed area of poly, positive if its vertices run
+// counterclockwise, by the shoelace formula. The area of a polygon that
+// crosses itself counts the parts around which it winds twice twice.
+func (poly Polygon) Area() float64 {
+	a := 0.0
+	for i, p := range poly {
+		q := poly[(i+1)%len(poly)]
+		a += p.X*q.Y - q.X*p.Y
+	}
+	return a / 2
+}
+
+// Centroid returns the center of mass of the area of poly, or the mean of
+// its vertices if its area is zero
+func (poly Polygon) Centroid() Point {
+	if len(poly) == 0 {
+		return Point{}
+	}
+
+	// relative to the first vertex, to limit rounding on far away polygons
+	o := poly[0]
+	var a, cx, cy float64
+	for i, p := range poly {
+		q := poly[(i+1)%len(poly)]
+		px, py, qx, qy := p.X-o.X, p.Y-o.Y, q.X-o.X, q.Y-o.Y
+		w := px*qy - qx*py
+		a += w
+		cx += (px + qx) * w
+		cy += (py + qy) * w
+	}
+
+	if a == 0 {
+		var sx, sy float64
+		for _, p := range poly {
+			sx += p.X
+			sy += p.Y
+		}
+		n := float64(len(poly))
+		return Point{sx / n, sy / n}
+	}
+	return Point{o.X + cx/(3*a), o.Y + cy/(3*a)}
+}
+
+// ContainsEvenOdd reports whether p lies inside poly by the even-odd rule:
+// whether a ray from p crosses the boundary an odd number of times, which
+// counts the parts of a self-crossing polygon wound around twice as
+// outside. Points on the boundary are inside. The result is exact.
+func (poly Polygon) ContainsEvenOdd(p Point) bool {
+	inside := false
+	for i, a := range poly {
+		b := poly[(i+1)%len(poly)]
+		if Orient(a, b, p) == 0 && onSegment(a, b, p) {
+			return true
+		}
+		// edges crossing the horizontal ray to the right of p, counting
+		// each vertex with the edge above it
+		if (a.Y > p.Y) != (b.Y > p.Y) && (Orient(a, b, p) > 0) == (b.Y > a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// WindingNumber returns how many times poly winds counterclockwise around
+// p, negative for clockwise turns, and zero if p lies on the boundary. The
+// result is exact.
+func (poly Polygon) WindingNumber(p Point) int {
+	w := 0
+	for i, a := range poly {
+		b := poly[(i+1)%len(poly)]
+		o := Orient(a, b, p)
+		if o == 0 && onSegment(a, b, p) {
+			return 0
+		}
+		switch {
+		case a.Y <= p.Y && b.Y > p.Y && o > 0:
+			w++
+		case a.Y > p.Y && b.Y <= p.Y && o < 0:
+			w--
+		}
+	}
+	return w
+}
+
+// Contains reports whether p lies inside poly by the nonzero rule, or on
+// its boundary. The result is exact.
+func (poly Polygon) Contains(p Point) bool {
+	for i, a := range poly {
+		b := poly[(i+1)%len(poly)]
+		if Orient(a, b, p) == 0 && onSegment(a, b, p) {
+			return true
+		}
+	}
+	return poly.WindingNumber(p) != 0
+}
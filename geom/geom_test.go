@@ -0,0 +1,69 @@
+package geom
+
+import (
+	"testing"
+)
+
+func TestSegmentsIntersect(t *testing.T) {
+	for _, c := range []struct {
+		a, b, c, d Point
+		want       bool
+		at         Point
+	}{
+		{Point{0, 0}, Point{2, 2}, Point{0, 2}, Point{2, 0}, true, Point{1, 1}},
+		{Point{0, 0}, Point{1, 1}, Point{2, 2}, Point{3, 3}, false, Point{}},
+		{Point{0, 0}, Point{2, 2}, Point{1, 1}, Point{3, 3}, true, Point{1, 1}},
+		{Point{0, 0}, Point{2, 0}, Point{1, 0}, Point{1, 5}, true, Point{1, 0}},
+		{Point{0, 0}, Point{2, 0}, Point{1, 1e-300}, Point{1, 5}, false, Point{}},
+		{Point{0, 0}, Point{0, 0}, Point{0, 0}, Point{1, 1}, true, Point{0, 0}},
+	} {
+		if got := SegmentsIntersect(c.a, c.b, c.c, c.d); got != c.want {
+			t.Errorf("SegmentsIntersect(%v, %v, %v, %v) = %v, want %v", c.a, c.b, c.c, c.d, got, c.want)
+		}
+		if p, ok := Intersection(c.a, c.b, c.c, c.d); ok != c.want || p != c.at {
+			t.Errorf("Intersection(%v, %v, %v, %v) = %v, %v, want %v", c.a, c.b, c.c, c.d, p, ok, c.at)
+		}
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	square := Polygon{{0, 0}, {4, 0}, {4, 4}, {0, 4}}
+	if a := square.Area(); a != 16 {
+		t.Fatalf("got area %v, want 16", a)
+	}
+	reversed := Polygon{{0, 4}, {4, 4}, {4, 0}, {0, 0}}
+	if a := reversed.Area(); a != -16 {
+		t.Fatalf("got area %v of a clockwise square, want -16", a)
+	}
+	if c := (Polygon{{0, 0}, {6, 0}, {0, 6}}).Centroid(); c != (Point{2, 2}) {
+		t.Fatalf("got centroid %v, want 2, 2", c)
+	}
+	if c := (Polygon{{0, 0}, {2, 2}, {4, 4}}).Centroid(); c != (Point{2, 2}) {
+		t.Fatalf("got centroid %v of a flat polygon, want the mean 2, 2", c)
+	}
+
+	for _, c := range []struct {
+		p        Point
+		contains bool
+	}{
+		{Point{2, 2}, true},
+		{Point{4, 2}, true},
+		{Point{0, 0}, true},
+		{Point{5, 2}, false},
+		{Point{-1e-300, 2}, false},
+	} {
+		if square.Contains(c.p) != c.contains || square.ContainsEvenOdd(c.p) != c.contains {
+			t.Errorf("square contains %v: got %v, want %v", c.p, square.Contains(c.p), c.contains)
+		}
+	}
+
+	star := Polygon{{0, 0}, {2, 4}, {4, 0}, {-1, 3}, {5, 3}}
+	center := Point{2, 2}
+	// the pentagram runs clockwise, twice around its center
+	if star.WindingNumber(center) != -2 || !star.Contains(center) || star.ContainsEvenOdd(center) {
+		t.Fatalf("got winding number %d around the center of a pentagram, want -2", star.WindingNumber(center))
+	}
+	if star.WindingNumber(Point{2, 4}) != 0 || !star.Contains(Point{2, 4}) {
+		t.Fatal("vertex of the pentagram is not on its boundary")
+	}
+}
@@ -0,0 +1,86 @@
+package geom
+
+import (
+	"math/big"
+)
+
+// error bounds of the floating point evaluations of the predicates, after
+// Shewchuk's "Adaptive Precision Floating-Point Arithmetic and Fast Robust
+// Geometric Predicates", beyond which their sign is certain
+const (
+	epsilon       = 1.0 / (1 << 53)
+	orientBound   = (3 + 16*epsilon) * epsilon
+	inCircleBound = (10 + 96*epsilon) * epsilon
+)
+
+// Orient returns the sign of the area of the triangle a, b, c: positive if
+// they turn counterclockwise, negative if clockwise and zero if they are
+// collinear. The result is exact.
+func Orient(a, b, c Point) int {
+	left := (a.X - c.X) * (b.Y - c.Y)
+	right := (a.Y - c.Y) * (b.X - c.X)
+	det := left - right
+	if bound := orientBound * (abs(left) + abs(right)); det > bound || -det > bound {
+		return sign(det)
+	}
+
+	ax, ay, bx, by := sub(a.X, c.X), sub(a.Y, c.Y), sub(b.X, c.X), sub(b.Y, c.Y)
+	return mul(ax, by).Cmp(mul(ay, bx))
+}
+
+// InCircle returns a positive value if d lies inside the circle through a,
+// b and c, which turn counterclockwise, a negative value if it lies outside
+// and zero if it lies on the circle. The result is exact.
+func InCircle(a, b, c, d Point) int {
+	adx, ady := a.X-d.X, a.Y-d.Y
+	bdx, bdy := b.X-d.X, b.Y-d.Y
+	cdx, cdy := c.X-d.X, c.Y-d.Y
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	alift := adx*adx + ady*ady
+	blift := bdx*bdx + bdy*bdy
+	clift := cdx*cdx + cdy*cdy
+
+	det := alift*(bdxcdy-cdxbdy) + blift*(cdxady-adxcdy) + clift*(adxbdy-bdxady)
+	permanent := (abs(bdxcdy)+abs(cdxbdy))*alift + (abs(cdxady)+abs(adxcdy))*blift + (abs(adxbdy)+abs(bdxady))*clift
+	if bound := inCircleBound * permanent; det > bound || -det > bound {
+		return sign(det)
+	}
+
+	ax, ay := sub(a.X, d.X), sub(a.Y, d.Y)
+	bx, by := sub(b.X, d.X), sub(b.Y, d.Y)
+	cx, cy := sub(c.X, d.X), sub(c.Y, d.Y)
+	lift := func(x, y *big.Rat) *big.Rat { return new(big.Rat).Add(mul(x, x), mul(y, y)) }
+	cross := func(x1, y1, x2, y2 *big.Rat) *big.Rat { return new(big.Rat).Sub(mul(x1, y2), mul(y1, x2)) }
+
+	exact := mul(lift(ax, ay), cross(bx, by, cx, cy))
+	exact.Add(exact, mul(lift(bx, by), cross(cx, cy, ax, ay)))
+	exact.Add(exact, mul(lift(cx, cy), cross(ax, ay, bx, by)))
+	return exact.Sign()
+}
+
+func sub(x, y float64) *big.Rat {
+	r := new(big.Rat).SetFloat64(x)
+	return r.Sub(r, new(big.Rat).SetFloat64(y))
+}
+
+func mul(x, y *big.Rat) *big.Rat { return new(big.Rat).Mul(x, y) }
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func sign(x float64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+package geom
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// exactOrient computes the sign of Orient with rational arithmetic only
+func exactOrient(a, b, c Point) int {
+	r := func(x float64) *big.Rat { return new(big.Rat).SetFloat64(x) }
+	abx := new(big.Rat).Sub(r(b.X), r(a.X))
+	aby := new(big.Rat).Sub(r(b.Y), r(a.Y))
+	acx := new(big.Rat).Sub(r(c.X), r(a.X))
+	acy := new(big.Rat).Sub(r(c.Y), r(a.Y))
+	return new(big.Rat).Mul(abx, acy).Cmp(new(big.Rat).Mul(aby, acx))
+}
+
+// TestOrientNearlyCollinear checks Orient on points next to a line, where
+// floating point evaluation gets the sign wrong
+func TestOrientNearlyCollinear(t *testing.T) {
+	a, b := Point{X: 12, Y: 12}, Point{X: 24, Y: 24}
+	for i := 0; i < 64; i++ {
+		for j := 0; j < 64; j++ {
+			c := Point{X: 0.5 + float64(i)*math.Pow(2, -53), Y: 0.5 + float64(j)*math.Pow(2, -53)}
+			if got, want := Orient(a, b, c), exactOrient(a, b, c); got != want {
+				t.Fatalf("Orient(%v, %v, %v) = %d, want %d", a, b, c, got, want)
+			}
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a := Point{X: rng.Float64(), Y: rng.Float64()}
+		b := Point{X: rng.Float64(), Y: rng.Float64()}
+		c := Point{X: rng.Float64(), Y: rng.Float64()}
+		if Orient(a, b, c) != exactOrient(a, b, c) || Orient(a, b, c) != -Orient(b, a, c) {
+			t.Fatalf("Orient(%v, %v, %v) = %d, want %d", a, b, c, Orient(a, b, c), exactOrient(a, b, c))
+		}
+	}
+}
+
+func TestInCircle(t *testing.T) {
+	a, b, c := Point{X: 1, Y: 0}, Point{X: 0, Y: 1}, Point{X: -1, Y: 0}
+	for _, tc := range []struct {
+		d    Point
+		want int
+	}{
+		{Point{X: 0, Y: 0}, 1},
+		{Point{X: 0, Y: -1}, 0},
+		{Point{X: 0, Y: -1 - math.Pow(2, -52)}, -1},
+		{Point{X: 0, Y: -1 + math.Pow(2, -53)}, 1},
+		{Point{X: 5, Y: 5}, -1},
+	} {
+		if got := InCircle(a, b, c, tc.d); got != tc.want {
+			t.Errorf("InCircle(%v) = %d, want %d", tc.d, got, tc.want)
+		}
+	}
+}
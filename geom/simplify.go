@@ -0,0 +1,110 @@
+package geom
+
+import (
+	"math"
+
+	"github.com/hanyangtay/go-datastructures/indexedpq"
+)
+
+// segmentDist returns the distance from p to the segment from a to b
+func segmentDist(p, a, b Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = min(max(((p.X-a.X)*dx+(p.Y-a.Y)*dy)/l, 0), 1)
+	}
+	return math.Hypot(a.X+t*dx-p.X, a.Y+t*dy-p.Y)
+}
+
+// SimplifyDouglasPeucker returns the points of line to keep so that no point
+// left out lies farther than tolerance from the simplified line, by the
+// Douglas-Peucker algorithm: the point farthest from the segment between the
+// ends is kept if it lies beyond tolerance, and both halves are simplified
+// in turn. The end points are always kept.
+// Time complexity: O(n log n) on average, O(n²) at worst
+func SimplifyDouglasPeucker(line []Point, tolerance float64) []Point {
+	if len(line) < 3 {
+		return append([]Point(nil), line...)
+	}
+
+	keep := make([]bool, len(line))
+	keep[0], keep[len(line)-1] = true, true
+
+	stack := [][2]int{{0, len(line) - 1}}
+	for len(stack) > 0 {
+		lo, hi := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+
+		far, farDist := -1, tolerance
+		for i := lo + 1; i < hi; i++ {
+			if d := segmentDist(line[i], line[lo], line[hi]); d > farDist {
+				far, farDist = i, d
+			}
+		}
+		if far >= 0 {
+			keep[far] = true
+			stack = append(stack, [2]int{lo, far}, [2]int{far, hi})
+		}
+	}
+
+	var result []Point
+	for i, p := range line {
+		if keep[i] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// SimplifyVisvalingam returns the points of line to keep by the
+// Visvalingam-Whyatt algorithm: the point forming the triangle of least area
+// with its neighbours is dropped, for as long as that area is below minArea.
+// Unlike Douglas-Peucker, it removes small wiggles evenly rather than
+// keeping the points that stray the most, which tends to look more natural
+// for shapes such as coastlines. The end points are always kept.
+// Time complexity: O(n log n)
+func SimplifyVisvalingam(line []Point, minArea float64) []Point {
+	if len(line) < 3 {
+		return append([]Point(nil), line...)
+	}
+
+	n := len(line)
+	prev := make([]int, n)
+	next := make([]int, n)
+	for i := range line {
+		prev[i], next[i] = i-1, i+1
+	}
+
+	area := func(i int) float64 {
+		a, b, c := line[prev[i]], line[i], line[next[i]]
+		return math.Abs((b.X-a.X)*(c.Y-a.Y)-(c.X-a.X)*(b.Y-a.Y)) / 2
+	}
+
+	q := indexedpq.New[int](func(a, b float64) bool { return a < b })
+	handles := make([]*indexedpq.Handle[int, float64], n)
+	for i := 1; i < n-1; i++ {
+		handles[i] = q.Push(i, area(i))
+	}
+
+	removed := make([]bool, n)
+	for q.Len() > 0 && q.Peek().Priority() < minArea {
+		i := q.Pop().Value()
+		removed[i] = true
+		p, nx := prev[i], next[i]
+		next[p], prev[nx] = nx, p
+
+		for _, j := range [2]int{p, nx} {
+			if j > 0 && j < n-1 {
+				q.Update(handles[j], area(j))
+			}
+		}
+	}
+
+	var result []Point
+	for i, p := range line {
+		if !removed[i] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
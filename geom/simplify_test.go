@@ -0,0 +1,91 @@
+package geom
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// randomWalk returns a line of n points wandering from the origin
+func randomWalk(rng *rand.Rand, n int) []Point {
+	line := []Point{{0, 0}}
+	for len(line) < n {
+		p := line[len(line)-1]
+		line = append(line, Point{p.X + rng.Float64(), p.Y + rng.Float64()*2 - 1})
+	}
+	return line
+}
+
+// isSubsequence reports whether kept lists points of line in order, with
+// both ends
+func isSubsequence(kept, line []Point) bool {
+	if len(kept) < 2 || kept[0] != line[0] || kept[len(kept)-1] != line[len(line)-1] {
+		return false
+	}
+	i := 0
+	for _, p := range line {
+		if i < len(kept) && kept[i] == p {
+			i++
+		}
+	}
+	return i == len(kept)
+}
+
+func TestSimplifyDouglasPeucker(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, tolerance := range []float64{0, 0.5, 2, 100} {
+		line := randomWalk(rng, 500)
+		kept := SimplifyDouglasPeucker(line, tolerance)
+		if !isSubsequence(kept, line) {
+			t.Fatalf("tolerance %v: result is not a subsequence of the line with its ends", tolerance)
+		}
+
+		// every point left out lies within tolerance of the segment
+		// replacing it
+		j := 0
+		for _, p := range line {
+			if p == kept[j+1] {
+				j++
+				if j == len(kept)-1 {
+					break
+				}
+				continue
+			}
+			if d := segmentDist(p, kept[j], kept[j+1]); d > tolerance {
+				t.Fatalf("tolerance %v: %v lies %v from the simplified line", tolerance, p, d)
+			}
+		}
+		if tolerance == 100 && len(kept) != 2 {
+			t.Fatalf("kept %d points of a walk within 100 of its chord, want 2", len(kept))
+		}
+	}
+
+	straight := []Point{{0, 0}, {1, 1}, {2, 2}, {3, 3}}
+	if kept := SimplifyDouglasPeucker(straight, 0); len(kept) != 2 {
+		t.Fatalf("kept %d points of a straight line, want 2", len(kept))
+	}
+}
+
+func TestSimplifyVisvalingam(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	line := randomWalk(rng, 500)
+	prev := len(line) + 1
+	for _, minArea := range []float64{0, 0.1, 1, 10, math.Inf(1)} {
+		kept := SimplifyVisvalingam(line, minArea)
+		if !isSubsequence(kept, line) || len(kept) > prev {
+			t.Fatalf("min area %v: result is not a shorter subsequence of the line", minArea)
+		}
+		prev = len(kept)
+
+		// every remaining triangle is at least minArea
+		for i := 1; i+1 < len(kept); i++ {
+			a, b, c := kept[i-1], kept[i], kept[i+1]
+			if area := math.Abs((b.X-a.X)*(c.Y-a.Y)-(c.X-a.X)*(b.Y-a.Y)) / 2; area < minArea {
+				t.Fatalf("min area %v: kept a point of area %v", minArea, area)
+			}
+		}
+	}
+	if prev != 2 {
+		t.Fatalf("kept %d points for an infinite area, want 2", prev)
+	}
+}
@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"math"
+)
+
+// maxArcFlagRegions is the largest number of regions of ArcFlags, one per
+// bit of a flag word
+const maxArcFlagRegions = 64
+
+// ArcFlags prunes dijkstra searches with a flag per region on every edge.
+// The graph is divided into regions by a grid over the node coordinates, and
+// the flag of a region is set on an edge if the edge starts a shortest path
+// to some node of the region. A query towards a node only follows the edges
+// flagged for its region, skipping the rest of the graph.
+type ArcFlags struct {
+	// Region is the region of every node
+	Region []int
+
+	// Flags[n][i] has bit r set if the i-th edge of n.EdgeStart is on a
+	// shortest path to region r
+	Flags [][]uint64
+}
+
+// NewArcFlags divides g into a grid of at most regions regions, up to 64,
+// and flags its edges. Flags are computed by a backward dijkstra search
+// from every boundary node of a region, a node with an edge from another
+// region, so preprocessing is costly on large graphs; the result can be
+// persisted with SaveArtifact.
+func (g *DirectedGraph) NewArcFlags(regions int) *ArcFlags {
+	af := &ArcFlags{
+		Region: g.gridRegions(regions),
+		Flags:  make([][]uint64, len(g.Nodes)),
+	}
+
+	for _, n := range g.Nodes {
+		af.Flags[n.ID] = make([]uint64, len(n.EdgeStart))
+
+		// edges within a region lead to its nodes
+		for i, e := range n.EdgeStart {
+			if af.Region[e.To.ID] == af.Region[n.ID] {
+				af.Flags[n.ID][i] |= 1 << af.Region[n.ID]
+			}
+		}
+	}
+
+	for _, b := range g.Nodes {
+		r := af.Region[b.ID]
+
+		boundary := false
+		for _, e := range b.EdgeEnd {
+			if af.Region[e.From.ID] != r {
+				boundary = true
+				break
+			}
+		}
+		if !boundary {
+			continue
+		}
+
+		// flag the edges of the shortest path tree into b
+		dist := g.distancesFrom(b, true)
+		for _, n := range g.Nodes {
+			for i, e := range n.EdgeStart {
+				if d := dist[e.To.ID]; !math.IsInf(d, 1) && d+e.Weight == dist[n.ID] {
+					af.Flags[n.ID][i] |= 1 << r
+				}
+			}
+		}
+	}
+
+	return af
+}
+
+// gridRegions assigns every node of g to a cell of the largest square grid
+// of at most regions cells, at most 64, laid over the bounding box of the
+// nodes
+func (g *DirectedGraph) gridRegions(regions int) []int {
+	side := int(math.Sqrt(float64(min(max(regions, 1), maxArcFlagRegions))))
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, n := range g.Nodes {
+		minX, maxX = math.Min(minX, n.X), math.Max(maxX, n.X)
+		minY, maxY = math.Min(minY, n.Y), math.Max(maxY, n.Y)
+	}
+
+	cell := func(x, lo, hi float64) int {
+		if hi <= lo {
+			return 0
+		}
+		return min(int(float64(side)*(x-lo)/(hi-lo)), side-1)
+	}
+
+	region := make([]int, len(g.Nodes))
+	for _, n := range g.Nodes {
+		region[n.ID] = cell(n.Y, minY, maxY)*side + cell(n.X, minX, maxX)
+	}
+
+	return region
+}
+
+// ShortestPath returns a shortest path from u to v in g and its distance,
+// +Inf if there is no path, using a dijkstra search that only follows the
+// edges flagged for the region of v. af must have been computed for g.
+func (af *ArcFlags) ShortestPath(g *DirectedGraph, u, v *Node) ([]*Node, float64) {
+	flag := uint64(1) << af.Region[v.ID]
+
+	forwardDist := map[*Node]float64{u: 0}
+	next := make(map[*Node]*Node)
+
+	Q := newDistanceQueue()
+	Q.Push(&distanceNode{node: u, dist: 0})
+
+	for Q.Len() > 0 {
+		mid := Q.Pop()
+		if mid.dist > forwardDist[mid.node] {
+			continue
+		}
+
+		// terminates when final node is found
+		if mid.node == v {
+			return tracePath(next, u, v), mid.dist
+		}
+
+		for i, e := range mid.node.EdgeStart {
+			if af.Flags[mid.node.ID][i]&flag == 0 {
+				continue
+			}
+
+			n := e.To
+			acc_dist := mid.dist + e.Weight
+			if dist, ok := forwardDist[n]; !ok || acc_dist < dist {
+				forwardDist[n] = acc_dist
+				next[n] = mid.node
+				Q.Push(&distanceNode{node: n, dist: acc_dist})
+			}
+		}
+	}
+
+	// no path found
+	return nil, math.Inf(1)
+}
@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+)
+
+// artifactMagic identifies a file written by SaveArtifact
+const artifactMagic = "GDSARTF"
+
+// artifactVersion is the version of the artifact file layout
+const artifactVersion = 1
+
+var (
+	// ErrFingerprintMismatch is returned by LoadArtifact if the artifact was
+	// computed for a graph other than the one it is loaded for.
+	ErrFingerprintMismatch = errors.New("graph: artifact was computed for a different graph")
+
+	// ErrNotArtifact is returned by LoadArtifact if its input is not an
+	// artifact written by SaveArtifact.
+	ErrNotArtifact = errors.New("graph: input is not a graph artifact")
+)
+
+// artifactHeader precedes the payload of every artifact
+type artifactHeader struct {
+	Magic       string
+	Version     int
+	Kind        string
+	Fingerprint uint64
+}
+
+// Fingerprint returns a hash of the structure of the graph: its nodes with
+// their coordinates and costs, and its edges with their endpoints and
+// weights. Any change to these changes the fingerprint, invalidating results
+// precomputed for the old graph.
+func (g *DirectedGraph) Fingerprint() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+
+	write := func(x uint64) {
+		binary.LittleEndian.PutUint64(buf[:], x)
+		h.Write(buf[:])
+	}
+
+	write(uint64(len(g.Nodes)))
+	write(uint64(g.Coordinates))
+
+	for _, n := range g.Nodes {
+		write(uint64(n.ID))
+		write(math.Float64bits(n.X))
+		write(math.Float64bits(n.Y))
+		write(math.Float64bits(n.Cost))
+
+		write(uint64(len(n.EdgeStart)))
+		for _, e := range n.EdgeStart {
+			write(uint64(e.From.ID))
+			write(uint64(e.To.ID))
+			write(math.Float64bits(e.Weight))
+		}
+	}
+
+	return h.Sum64()
+}
+
+// SaveArtifact writes v, the result of some expensive preprocessing of g such
+// as a distance oracle, to w. The artifact is stored under a kind naming the
+// type of result, together with the fingerprint of g, so that it can only be
+// loaded back for the same graph. v is encoded with encoding/gob.
+func SaveArtifact(w io.Writer, g *DirectedGraph, kind string, v interface{}) error {
+	enc := gob.NewEncoder(w)
+
+	header := artifactHeader{
+		Magic:       artifactMagic,
+		Version:     artifactVersion,
+		Kind:        kind,
+		Fingerprint: g.Fingerprint(),
+	}
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+
+	return enc.Encode(v)
+}
+
+// LoadArtifact reads an artifact of the given kind written by SaveArtifact
+// from r into v, which must be a pointer. It returns ErrNotArtifact if the
+// header read does not identify an artifact, ErrFingerprintMismatch if g is
+// not the graph the artifact was computed for, and errors reading r wrapped.
+func LoadArtifact(r io.Reader, g *DirectedGraph, kind string, v interface{}) error {
+	dec := gob.NewDecoder(r)
+
+	var header artifactHeader
+	if err := dec.Decode(&header); err != nil {
+		return fmt.Errorf("graph: reading artifact header: %w", err)
+	}
+	if header.Magic != artifactMagic {
+		return ErrNotArtifact
+	}
+
+	if header.Version != artifactVersion {
+		return fmt.Errorf("graph: unsupported artifact version %d", header.Version)
+	}
+
+	if header.Kind != kind {
+		return fmt.Errorf("graph: artifact holds %q, not %q", header.Kind, kind)
+	}
+
+	if header.Fingerprint != g.Fingerprint() {
+		return ErrFingerprintMismatch
+	}
+
+	return dec.Decode(v)
+}
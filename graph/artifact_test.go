@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestPreprocessedShortestPaths(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(200, rng)
+
+	l := g.NewLandmarks(8)
+	if len(l.IDs) != 8 {
+		t.Fatalf("got %d landmarks, want 8", len(l.IDs))
+	}
+	ch := g.NewContractionHierarchy()
+	af := g.NewArcFlags(16)
+
+	for i := 0; i < 300; i++ {
+		u, v := g.Nodes[rng.Intn(len(g.Nodes))], g.Nodes[rng.Intn(len(g.Nodes))]
+		_, want := g.Dijkstra(u, v)
+
+		if h := l.Heuristic(u, v); h > want+1e-9 {
+			t.Fatalf("%d -> %d: landmark bound %v exceeds distance %v", u.ID, v.ID, h, want)
+		}
+
+		path, dist := l.ShortestPath(g, u, v)
+		checkPath(t, g, u, v, path, dist, want)
+
+		path, dist = ch.ShortestPath(g, u, v)
+		checkPath(t, g, u, v, path, dist, want)
+
+		path, dist = af.ShortestPath(g, u, v)
+		checkPath(t, g, u, v, path, dist, want)
+	}
+}
+
+func TestArtifactRoundTrip(t *testing.T) {
+	g := newRandomDirectedGraph(50, rand.New(rand.NewSource(2)))
+	ch := g.NewContractionHierarchy()
+
+	var buf bytes.Buffer
+	if err := SaveArtifact(&buf, g, "ch", ch); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	var loaded ContractionHierarchy
+	if err := LoadArtifact(bytes.NewReader(data), g, "ch", &loaded); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range g.Nodes {
+		_, want := ch.ShortestPath(g, g.Nodes[0], v)
+		if _, dist := loaded.ShortestPath(g, g.Nodes[0], v); dist != want {
+			t.Fatalf("0 -> %d: loaded hierarchy gives %v, want %v", v.ID, dist, want)
+		}
+	}
+
+	if err := LoadArtifact(bytes.NewReader(data), g, "landmarks", &loaded); err == nil {
+		t.Fatal("loading another kind succeeded")
+	}
+
+	g.Nodes[0].Cost++
+	if err := LoadArtifact(bytes.NewReader(data), g, "ch", &loaded); !errors.Is(err, ErrFingerprintMismatch) {
+		t.Fatalf("got %v for a changed graph, want ErrFingerprintMismatch", err)
+	}
+
+	if err := LoadArtifact(bytes.NewReader([]byte("not an artifact")), g, "ch", &loaded); err == nil {
+		t.Fatal("loading garbage succeeded")
+	}
+}
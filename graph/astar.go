@@ -1,73 +1,25 @@
 package graph
 
 import (
-	"container/heap"
 	"math"
 )
 
 // AStar returns a shortest path from u to v and the distance
-// in graph g. Heuristic: great circle distance, Time complexity: O(|E| * log |V|)
+// in graph g. Heuristic: g.Dist to v, which is admissible as long as no edge
+// weighs less than the distance between its endpoints in the graph's
+// coordinate system. Time complexity: O(|E| * log |V|)
 func (g *DirectedGraph) AStar(u, v *Node) ([]*Node, float64) {
-
-	forwardDist := make(map[*Node]float64)
-	forwardDist[u] = 0.0
-
-	Q := priorityQueue{{node: u, dist: 0 + Dist(u, v)}}
-	var mid *distanceNode
-	heap.Init(&Q)
-
-	next := make(map[*Node]*Node)
-
-	for len(Q) > 0 {
-		mid = heap.Pop(&Q).(*distanceNode)
-
-		// terminates when final node is found
-		if mid.node == v {
-			break
-		}
-
-		for _, e := range mid.node.EdgeStart {
-			n := e.To
-
-			new_distance := e.Weight
-
-			// total distance travelled so far
-			acc_dist := forwardDist[mid.node] + new_distance
-
-			// update shortest paths
-			if dist, ok := forwardDist[n]; !ok || acc_dist < dist {
-				heap.Push(&Q, &distanceNode{node: n, dist: acc_dist + Dist(n, v)})
-				forwardDist[n] = acc_dist
-				next[n] = mid.node
-			}
-
-		}
-	}
-
-	// no path found
-	if mid == nil || mid.node != v {
-		return nil, math.Inf(1)
-	}
-
-	// retrieve path from results
-	n := v
-	path := []*Node{v}
-
-	for n != u {
-		n = next[n]
-		path = append(path, n)
-	}
-
-	// reverse the path
-	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
-		path[i], path[j] = path[j], path[i]
-	}
-
-	return path, forwardDist[v]
+	path, dist, _ := g.shortestPath(u, v, searchOptions{
+		heuristic: func(n *Node) float64 { return g.Dist(n, v) },
+	})
+	g.checkHeuristic("AStar", u, v, dist)
+	return path, dist
 }
 
 // AStarBi returns a shortest path from u to all nodes
 // in the graph g. Time complexity: O(|E| * log |V|)
+// Like DijkstraBi, it skips superseded queue entries when popped rather than
+// removing them.
 func (g *DirectedGraph) AStarBi(u, v *Node) ([]*Node, float64) {
 
 	forwardDist := make(map[*Node]float64)
@@ -78,23 +30,30 @@ func (g *DirectedGraph) AStarBi(u, v *Node) ([]*Node, float64) {
 	forwardDist[u] = 0.0
 	backwardDist[v] = 0.0
 
-	Q := priorityQueue{{node: u, dist: 0, direction: true}, {node: v, dist: 0,
-		direction: false}}
-	heap.Init(&Q)
+	Q := newDistanceQueue()
+	Q.Push(&distanceNode{node: u, dist: 0, direction: true})
+	Q.Push(&distanceNode{node: v, dist: 0, direction: false})
 
 	lengthBestPath := math.Inf(1)
 	var midPathNode *Node
-	mid, mid_backward := &distanceNode{node: nil}, &distanceNode{node: nil}
+	var mid, mid_backward *distanceNode
+
+	// a node reaches itself by the empty path, which no search step finds
+	if u == v {
+		lengthBestPath, midPathNode = 0, u
+	}
 
-	for len(Q) > 0 {
+	for Q.Len() > 0 {
 
-		// terminates when no shorter paths can be found
-		if mid.realDist+mid_backward.realDist >= lengthBestPath {
+		bestNode := Q.Pop()
+
+		// terminates when no shorter paths can be found: every remaining path
+		// runs through a frontier node, whose estimate is a lower bound on its
+		// length
+		if bestNode.dist >= lengthBestPath {
 			break
 		}
 
-		bestNode := heap.Pop(&Q).(*distanceNode)
-
 		if bestNode.direction {
 
 			/* Forward Search */
@@ -111,7 +70,7 @@ func (g *DirectedGraph) AStarBi(u, v *Node) ([]*Node, float64) {
 
 					// update shortest paths
 					if dist, ok := forwardDist[n]; !ok || acc_dist < dist {
-						heap.Push(&Q, &distanceNode{node: n, dist: acc_dist + Dist(n, v),
+						Q.Push(&distanceNode{node: n, dist: acc_dist + g.Dist(n, v),
 							realDist: acc_dist, direction: true})
 						forwardDist[n] = acc_dist
 						next[n] = mid.node
@@ -138,7 +97,7 @@ func (g *DirectedGraph) AStarBi(u, v *Node) ([]*Node, float64) {
 
 					// update shortest paths
 					if dist, ok := backwardDist[n]; !ok || acc_dist < dist {
-						heap.Push(&Q, &distanceNode{node: n, dist: acc_dist + Dist(n, u),
+						Q.Push(&distanceNode{node: n, dist: acc_dist + g.Dist(n, u),
 							realDist: acc_dist, direction: false})
 						backwardDist[n] = acc_dist
 						back[n] = mid_backward.node
@@ -165,29 +124,6 @@ func (g *DirectedGraph) AStarBi(u, v *Node) ([]*Node, float64) {
 		return nil, math.Inf(1)
 	}
 
-	path := []*Node{midPathNode}
-
-	n := next[midPathNode]
-
-	for n != u {
-		path = append(path, n)
-		n = next[n]
-	}
-
-	path = append(path, u)
-
-	// reverse the path
-	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
-		path[i], path[j] = path[j], path[i]
-	}
-
-	n = midPathNode
-	for n != v {
-		path = append(path, n)
-		n = back[n]
-	}
-
-	path = append(path, v)
-
-	return path, lengthBestPath
+	g.checkHeuristic("AStarBi", u, v, lengthBestPath)
+	return joinPaths(midPathNode, u, v, next, back), lengthBestPath
 }
@@ -0,0 +1,316 @@
+package graph
+
+import (
+	"math"
+)
+
+// witnessSettleLimit bounds the number of nodes settled by a witness search
+// during contraction. A search that gives up adds a shortcut that may not be
+// needed, which costs query time but never correctness.
+const witnessSettleLimit = 256
+
+// ContractionHierarchy answers shortest path queries by searching only
+// upwards in a hierarchy of nodes. Nodes are contracted one by one, from the
+// least to the most important, and every shortest path through a contracted
+// node is preserved by a shortcut between its neighbours. A query then runs
+// a bidirectional dijkstra search that only follows arcs to nodes contracted
+// later, which settles a small fraction of the graph.
+type ContractionHierarchy struct {
+	// Rank is the position of every node in the contraction order
+	Rank []int
+
+	// Up[n] holds the arcs from n to nodes of higher rank, and Down[n] the
+	// arcs to n from nodes of higher rank, both including shortcuts
+	Up, Down [][]CHArc
+}
+
+// CHArc is an arc of a contraction hierarchy: an edge of the graph, or a
+// shortcut standing for the path through the node it bypasses
+type CHArc struct {
+	// Node is the other end of the arc
+	Node   int
+	Weight float64
+
+	// Via is the node the shortcut bypasses, -1 for an edge of the graph
+	Via int
+}
+
+// chArc is an arc of the graph remaining during contraction
+type chArc struct {
+	weight float64
+	via    int
+}
+
+// contraction is the state of the graph while it is contracted
+type contraction struct {
+	g       *DirectedGraph
+	out, in []map[int]chArc
+
+	// deleted counts the contracted neighbours of every node
+	deleted []int
+}
+
+// NewContractionHierarchy contracts g into a hierarchy. Nodes are ordered by
+// edge difference, the number of shortcuts their contraction adds less the
+// arcs it removes, which is updated lazily as neighbours are contracted.
+// Preprocessing is costly on large graphs; the result can be persisted with
+// SaveArtifact.
+func (g *DirectedGraph) NewContractionHierarchy() *ContractionHierarchy {
+	n := len(g.Nodes)
+	c := &contraction{
+		g:       g,
+		out:     make([]map[int]chArc, n),
+		in:      make([]map[int]chArc, n),
+		deleted: make([]int, n),
+	}
+	for i := range c.out {
+		c.out[i] = make(map[int]chArc)
+		c.in[i] = make(map[int]chArc)
+	}
+
+	// keep the lightest of parallel edges, self loops are never on a
+	// shortest path
+	for _, u := range g.Nodes {
+		for _, e := range u.EdgeStart {
+			if e.To != u {
+				c.addArc(u.ID, e.To.ID, chArc{e.Weight, -1})
+			}
+		}
+	}
+
+	ch := &ContractionHierarchy{
+		Rank: make([]int, n),
+		Up:   make([][]CHArc, n),
+		Down: make([][]CHArc, n),
+	}
+
+	Q := newDistanceQueue()
+	for _, u := range g.Nodes {
+		Q.Push(&distanceNode{node: u, dist: c.priority(u.ID)})
+	}
+
+	for rank := 0; Q.Len() > 0; {
+		u := Q.Pop().node.ID
+
+		// the priority may have risen since u was queued, in which case it
+		// is queued again unless it is still the lowest
+		if p := c.priority(u); Q.Len() > 0 && p > Q.Peek().dist {
+			Q.Push(&distanceNode{node: g.Nodes[u], dist: p})
+			continue
+		}
+
+		c.contract(u, true)
+
+		for v, a := range c.out[u] {
+			ch.Up[u] = append(ch.Up[u], CHArc{v, a.weight, a.via})
+			delete(c.in[v], u)
+			c.deleted[v]++
+		}
+		for v, a := range c.in[u] {
+			ch.Down[u] = append(ch.Down[u], CHArc{v, a.weight, a.via})
+			delete(c.out[v], u)
+			c.deleted[v]++
+		}
+		c.out[u], c.in[u] = nil, nil
+
+		ch.Rank[u] = rank
+		rank++
+	}
+
+	return ch
+}
+
+// addArc adds an arc from u to v, unless one at most as heavy exists
+func (c *contraction) addArc(u, v int, a chArc) {
+	if old, ok := c.out[u][v]; ok && old.weight <= a.weight {
+		return
+	}
+	c.out[u][v] = a
+	c.in[v][u] = a
+}
+
+// priority returns the edge difference of u, plus its contracted neighbours
+// so that contraction spreads evenly over the graph
+func (c *contraction) priority(u int) float64 {
+	shortcuts := c.contract(u, false)
+	return float64(shortcuts - len(c.in[u]) - len(c.out[u]) + c.deleted[u])
+}
+
+// contract returns the number of shortcuts needed to preserve the shortest
+// paths through u once it is removed, adding them if add is set. A shortcut
+// from v to w is needed unless a witness search finds a path at most as
+// short that avoids u.
+func (c *contraction) contract(u int, add bool) int {
+	shortcuts := 0
+
+	for v, in := range c.in[u] {
+		limit := 0.0
+		for w, out := range c.out[u] {
+			if w != v {
+				limit = math.Max(limit, in.weight+out.weight)
+			}
+		}
+
+		dist := c.witnessSearch(v, u, limit)
+
+		for w, out := range c.out[u] {
+			if w == v {
+				continue
+			}
+			if d, ok := dist[w]; ok && d <= in.weight+out.weight {
+				continue
+			}
+
+			shortcuts++
+			if add {
+				c.addArc(v, w, chArc{in.weight + out.weight, u})
+			}
+		}
+	}
+
+	return shortcuts
+}
+
+// witnessSearch runs a dijkstra search from v in the remaining graph that
+// avoids u, until it has settled the successors of u, passed distance limit
+// or settled witnessSettleLimit nodes, and returns the distances it found
+func (c *contraction) witnessSearch(v, u int, limit float64) map[int]float64 {
+	dist := map[int]float64{v: 0}
+
+	targets := len(c.out[u])
+	if _, ok := c.out[u][v]; ok {
+		targets--
+	}
+
+	Q := newDistanceQueue()
+	Q.Push(&distanceNode{node: c.g.Nodes[v], dist: 0})
+
+	for settled := 0; Q.Len() > 0 && settled < witnessSettleLimit; settled++ {
+		mid := Q.Pop()
+		if mid.dist > dist[mid.node.ID] {
+			continue
+		}
+		if mid.dist > limit || targets == 0 {
+			break
+		}
+		if _, ok := c.out[u][mid.node.ID]; ok && mid.node.ID != v {
+			targets--
+		}
+
+		for w, a := range c.out[mid.node.ID] {
+			if w == u {
+				continue
+			}
+			if d, ok := dist[w]; !ok || mid.dist+a.weight < d {
+				dist[w] = mid.dist + a.weight
+				Q.Push(&distanceNode{node: c.g.Nodes[w], dist: dist[w]})
+			}
+		}
+	}
+
+	return dist
+}
+
+// ShortestPath returns a shortest path from u to v in g and its distance,
+// +Inf if there is no path. Shortcuts on the path found are unpacked into
+// the nodes they bypass. ch must have been computed for g.
+func (ch *ContractionHierarchy) ShortestPath(g *DirectedGraph, u, v *Node) ([]*Node, float64) {
+	forwardDist, forwardArc := ch.upwardSearch(g, u, ch.Up)
+	backwardDist, backwardArc := ch.upwardSearch(g, v, ch.Down)
+
+	// the searches meet at the top of a shortest path
+	var top *Node
+	lengthBestPath := math.Inf(1)
+	for n, d := range forwardDist {
+		if b, ok := backwardDist[n]; ok && d+b < lengthBestPath {
+			top, lengthBestPath = n, d+b
+		}
+	}
+	if top == nil {
+		return nil, math.Inf(1)
+	}
+
+	// collect the arcs from u up to top, which the forward search found
+	// from top down
+	var up []*Node
+	for n := top; n != u; n = g.Nodes[forwardArc[n].Node] {
+		up = append(up, n)
+	}
+
+	ids := []int{u.ID}
+	for i := len(up) - 1; i >= 0; i-- {
+		a := forwardArc[up[i]]
+		ids = ch.unpack(ids, a.Node, up[i].ID, a.Via)
+		ids = append(ids, up[i].ID)
+	}
+
+	for n := top; n != v; {
+		a := backwardArc[n]
+		ids = ch.unpack(ids, n.ID, a.Node, a.Via)
+		ids = append(ids, a.Node)
+		n = g.Nodes[a.Node]
+	}
+
+	path := make([]*Node, len(ids))
+	for i, id := range ids {
+		path[i] = g.Nodes[id]
+	}
+
+	return path, lengthBestPath
+}
+
+// upwardSearch runs a dijkstra search from s over the given arcs, Up for a
+// forward search and Down for a backward one. It returns the distances of
+// the nodes reached and, for each, the arc it was reached by, whose Node is
+// the previous node of the search.
+func (ch *ContractionHierarchy) upwardSearch(g *DirectedGraph, s *Node, arcs [][]CHArc) (map[*Node]float64, map[*Node]CHArc) {
+	dist := map[*Node]float64{s: 0}
+	prev := make(map[*Node]CHArc)
+
+	Q := newDistanceQueue()
+	Q.Push(&distanceNode{node: s, dist: 0})
+
+	for Q.Len() > 0 {
+		mid := Q.Pop()
+		if mid.dist > dist[mid.node] {
+			continue
+		}
+
+		for _, a := range arcs[mid.node.ID] {
+			n := g.Nodes[a.Node]
+			if d, ok := dist[n]; !ok || mid.dist+a.Weight < d {
+				dist[n] = mid.dist + a.Weight
+				prev[n] = CHArc{mid.node.ID, a.Weight, a.Via}
+				Q.Push(&distanceNode{node: n, dist: dist[n]})
+			}
+		}
+	}
+
+	return dist, prev
+}
+
+// unpack appends the nodes strictly between from and to on the arc between
+// them that bypasses via, -1 for an edge of the graph
+func (ch *ContractionHierarchy) unpack(ids []int, from, to, via int) []int {
+	if via < 0 {
+		return ids
+	}
+
+	// via was contracted before both ends, so the arc from from is among
+	// its Down arcs and the one to to among its Up arcs
+	for _, a := range ch.Down[via] {
+		if a.Node == from {
+			ids = ch.unpack(ids, from, via, a.Via)
+			break
+		}
+	}
+	ids = append(ids, via)
+	for _, a := range ch.Up[via] {
+		if a.Node == to {
+			ids = ch.unpack(ids, via, to, a.Via)
+			break
+		}
+	}
+
+	return ids
+}
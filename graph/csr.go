@@ -0,0 +1,212 @@
+package graph
+
+import (
+	"math"
+
+	"github.com/hanyangtay/go-datastructures/deque"
+)
+
+// Graph is a read-only view of a directed graph whose nodes are identified by
+// the integers 0 to Order()-1, matching Node IDs. Algorithms written against
+// it, such as DijkstraIDs and BFSLevels, run over any representation: a
+// DirectedGraph or a frozen CSRGraph.
+type Graph interface {
+	// Order returns the number of nodes
+	Order() int
+
+	// Successors calls fn for every edge starting at node id until fn
+	// returns false
+	Successors(id int, fn func(to int, weight float64) bool)
+
+	// Predecessors calls fn for every edge ending at node id until fn
+	// returns false
+	Predecessors(id int, fn func(from int, weight float64) bool)
+}
+
+// Order returns the number of nodes in the graph
+func (g *DirectedGraph) Order() int {
+	return len(g.Nodes)
+}
+
+// Successors calls fn for every edge starting at node id until fn returns
+// false
+func (g *DirectedGraph) Successors(id int, fn func(to int, weight float64) bool) {
+	for _, e := range g.Nodes[id].EdgeStart {
+		if !fn(e.To.ID, e.Weight) {
+			return
+		}
+	}
+}
+
+// Predecessors calls fn for every edge ending at node id until fn returns
+// false
+func (g *DirectedGraph) Predecessors(id int, fn func(from int, weight float64) bool) {
+	for _, e := range g.Nodes[id].EdgeEnd {
+		if !fn(e.From.ID, e.Weight) {
+			return
+		}
+	}
+}
+
+// CSRGraph is an immutable graph in compressed sparse row form: the edges
+// starting at node i are targets[offsets[i]:offsets[i+1]], with matching
+// weights, and likewise for the reverse adjacency. Storing adjacency in flat
+// slices takes a fraction of the memory of Node and Edge structs and keeps
+// the edges of a node contiguous for the cache.
+type CSRGraph struct {
+	X, Y []float64
+
+	offsets []int32
+	targets []int32
+	weights []float64
+
+	rOffsets []int32
+	sources  []int32
+	rWeights []float64
+}
+
+// Freeze returns a CSRGraph snapshot of g. Later changes to g are not
+// reflected in the snapshot.
+func (g *DirectedGraph) Freeze() *CSRGraph {
+	n := len(g.Nodes)
+	c := &CSRGraph{
+		X:        make([]float64, n),
+		Y:        make([]float64, n),
+		offsets:  make([]int32, n+1),
+		rOffsets: make([]int32, n+1),
+	}
+
+	for i, u := range g.Nodes {
+		c.X[i], c.Y[i] = u.X, u.Y
+		c.offsets[i+1] = c.offsets[i] + int32(len(u.EdgeStart))
+		c.rOffsets[i+1] = c.rOffsets[i] + int32(len(u.EdgeEnd))
+	}
+
+	c.targets = make([]int32, 0, c.offsets[n])
+	c.weights = make([]float64, 0, c.offsets[n])
+	c.sources = make([]int32, 0, c.rOffsets[n])
+	c.rWeights = make([]float64, 0, c.rOffsets[n])
+
+	for _, u := range g.Nodes {
+		for _, e := range u.EdgeStart {
+			c.targets = append(c.targets, int32(e.To.ID))
+			c.weights = append(c.weights, e.Weight)
+		}
+		for _, e := range u.EdgeEnd {
+			c.sources = append(c.sources, int32(e.From.ID))
+			c.rWeights = append(c.rWeights, e.Weight)
+		}
+	}
+
+	return c
+}
+
+// Order returns the number of nodes in the graph
+func (c *CSRGraph) Order() int {
+	return len(c.offsets) - 1
+}
+
+// Size returns the number of edges in the graph
+func (c *CSRGraph) Size() int {
+	return len(c.targets)
+}
+
+// Successors calls fn for every edge starting at node id until fn returns
+// false
+func (c *CSRGraph) Successors(id int, fn func(to int, weight float64) bool) {
+	for i := c.offsets[id]; i < c.offsets[id+1]; i++ {
+		if !fn(int(c.targets[i]), c.weights[i]) {
+			return
+		}
+	}
+}
+
+// Predecessors calls fn for every edge ending at node id until fn returns
+// false
+func (c *CSRGraph) Predecessors(id int, fn func(from int, weight float64) bool) {
+	for i := c.rOffsets[id]; i < c.rOffsets[id+1]; i++ {
+		if !fn(int(c.sources[i]), c.rWeights[i]) {
+			return
+		}
+	}
+}
+
+/* Algorithms over the Graph interface */
+
+// DijkstraIDs returns a shortest path between the nodes with IDs u and v in
+// g and its distance. The distance is +Inf if there is no path.
+func DijkstraIDs(g Graph, u, v int) ([]int, float64) {
+	dist := make([]float64, g.Order())
+	next := make([]int32, g.Order())
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		next[i] = -1
+	}
+	dist[u] = 0
+
+	Q := newIDHeap(g.Order())
+	Q.push(u, 0)
+
+	for Q.len() > 0 {
+		mid := Q.pop()
+
+		// terminates when final node is found
+		if mid == v {
+			break
+		}
+
+		g.Successors(mid, func(n int, w float64) bool {
+			// update shortest paths
+			if acc_dist := dist[mid] + w; acc_dist < dist[n] {
+				dist[n] = acc_dist
+				next[n] = int32(mid)
+				Q.push(n, acc_dist)
+			}
+			return true
+		})
+	}
+
+	// no path found
+	if math.IsInf(dist[v], 1) {
+		return nil, dist[v]
+	}
+
+	path := []int{v}
+	for n := v; n != u; {
+		n = int(next[n])
+		path = append(path, n)
+	}
+
+	// reverse the path
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, dist[v]
+}
+
+// BFSLevels returns the hop distance from the node with ID from to every node
+// of g, indexed by ID, with -1 for unreachable nodes.
+func BFSLevels(g Graph, from int) []int {
+	levels := make([]int, g.Order())
+	for i := range levels {
+		levels[i] = -1
+	}
+	levels[from] = 0
+
+	var queue deque.Deque[int]
+	queue.PushBack(from)
+	for queue.Len() > 0 {
+		u := queue.PopFront()
+
+		g.Successors(u, func(v int, _ float64) bool {
+			if levels[v] < 0 {
+				levels[v] = levels[u] + 1
+				queue.PushBack(v)
+			}
+			return true
+		})
+	}
+
+	return levels
+}
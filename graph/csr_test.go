@@ -0,0 +1,149 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// adjacency returns the edges of node id in view, forwards or backwards, as
+// sorted (node, weight) pairs
+func adjacency(view Graph, id int, forward bool) [][2]float64 {
+	var edges [][2]float64
+	fn := func(other int, w float64) bool {
+		edges = append(edges, [2]float64{float64(other), w})
+		return true
+	}
+	if forward {
+		view.Successors(id, fn)
+	} else {
+		view.Predecessors(id, fn)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i][0] < edges[j][0] || (edges[i][0] == edges[j][0] && edges[i][1] < edges[j][1])
+	})
+	return edges
+}
+
+// checkView fails t unless view has the nodes and edges of g
+func checkView(t *testing.T, g *DirectedGraph, view Graph) {
+	t.Helper()
+	if view.Order() != len(g.Nodes) {
+		t.Fatalf("got order %d, want %d", view.Order(), len(g.Nodes))
+	}
+	for _, n := range g.Nodes {
+		for _, forward := range []bool{true, false} {
+			got, want := adjacency(view, n.ID, forward), adjacency(g, n.ID, forward)
+			if len(got) != len(want) {
+				t.Fatalf("node %d: got %d edges, want %d", n.ID, len(got), len(want))
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Fatalf("node %d: got edge %v, want %v", n.ID, got[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	g := newRandomDirectedGraph(200, rand.New(rand.NewSource(1)))
+	c := g.Freeze()
+	checkView(t, g, c)
+
+	size := 0
+	for _, n := range g.Nodes {
+		size += len(n.EdgeStart)
+	}
+	if c.Size() != size {
+		t.Fatalf("got size %d, want %d", c.Size(), size)
+	}
+
+	// the snapshot is not affected by later changes
+	g.RemoveNode(g.Nodes[0])
+	if len(adjacency(c, 0, true)) == 0 {
+		t.Fatal("frozen graph lost the edges of a removed node")
+	}
+
+	calls := 0
+	c.Successors(1, func(int, float64) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Fatalf("Successors went on for %d calls after fn returned false", calls)
+	}
+}
+
+// checkIDPath fails t unless path is a path from u to v in view of length
+// dist, which is want
+func checkIDPath(t *testing.T, view Graph, u, v int, path []int, dist, want float64) {
+	t.Helper()
+	if math.IsInf(want, 1) {
+		if path != nil || !math.IsInf(dist, 1) {
+			t.Fatalf("%d -> %d: got path %v at %v, want none", u, v, path, dist)
+		}
+		return
+	}
+	if math.Abs(dist-want) > 1e-9 || len(path) == 0 || path[0] != u || path[len(path)-1] != v {
+		t.Fatalf("%d -> %d: got path %v at %v, want distance %v", u, v, path, dist, want)
+	}
+
+	length := 0.0
+	for i := 1; i < len(path); i++ {
+		w := math.Inf(1)
+		view.Successors(path[i-1], func(to int, weight float64) bool {
+			if to == path[i] {
+				w = math.Min(w, weight)
+			}
+			return true
+		})
+		length += w
+	}
+	if math.Abs(length-want) > 1e-9 {
+		t.Fatalf("%d -> %d: path has length %v, want %v", u, v, length, want)
+	}
+}
+
+func TestDijkstraIDs(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(200, rng)
+	g.AddNode(&Node{})
+	c := g.Freeze()
+
+	for i := 0; i < 10; i++ {
+		u := rng.Intn(len(g.Nodes))
+		dist := bellmanFord(g, g.Nodes[u])
+		for v := range g.Nodes {
+			for _, view := range []Graph{g, c} {
+				path, d := DijkstraIDs(view, u, v)
+				checkIDPath(t, view, u, v, path, d, dist[v])
+			}
+		}
+	}
+}
+
+func TestBFSLevels(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(200, rng)
+	g.AddNode(&Node{})
+	c := g.Freeze()
+
+	for i := 0; i < 10; i++ {
+		u := g.Nodes[rng.Intn(len(g.Nodes))]
+		hops, _ := g.BFSFrom(u)
+		for _, view := range []Graph{g, c} {
+			levels := BFSLevels(view, u.ID)
+			for _, v := range g.Nodes {
+				want, ok := hops[v]
+				if !ok {
+					want = -1
+				}
+				if levels[v.ID] != want {
+					t.Fatalf("%d -> %d: got level %d, want %d", u.ID, v.ID, levels[v.ID], want)
+				}
+			}
+		}
+	}
+}
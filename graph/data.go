@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"github.com/hanyangtay/go-datastructures/bitset"
+)
+
+// NodeData attaches a value of type T to nodes of a graph, stored by node
+// ID, such as the names or populations of the places nodes stand for. It is
+// the typed alternative to a payload field on Node: a graph may carry any
+// number of them, and lookups need no type assertions. Node IDs never
+// change, so the values stay attached as the graph is modified. The zero
+// value holds no values.
+type NodeData[T any] struct {
+	values []T
+	set    bitset.BitSet
+}
+
+// Get returns the value of n, and whether it has one
+func (d *NodeData[T]) Get(n *Node) (T, bool) {
+	var zero T
+	if n.ID < 0 || n.ID >= len(d.values) || !d.set.Test(n.ID) {
+		return zero, false
+	}
+	return d.values[n.ID], true
+}
+
+// Set sets the value of n
+func (d *NodeData[T]) Set(n *Node, v T) {
+	if n.ID >= len(d.values) {
+		d.values = append(d.values, make([]T, n.ID+1-len(d.values))...)
+		d.set.Grow(len(d.values))
+	}
+	d.values[n.ID] = v
+	d.set.Set(n.ID)
+}
+
+// Delete removes the value of n, if it has one
+func (d *NodeData[T]) Delete(n *Node) {
+	if n.ID < 0 || n.ID >= len(d.values) {
+		return
+	}
+	var zero T
+	d.values[n.ID] = zero
+	d.set.Clear(n.ID)
+}
+
+// EdgeData attaches a value of type T to edges of a graph, such as road
+// names or speed limits, the edge counterpart of NodeData. The zero value
+// holds no values.
+type EdgeData[T any] struct {
+	values map[*Edge]T
+}
+
+// Get returns the value of e, and whether it has one
+func (d *EdgeData[T]) Get(e *Edge) (T, bool) {
+	v, ok := d.values[e]
+	return v, ok
+}
+
+// Set sets the value of e
+func (d *EdgeData[T]) Set(e *Edge, v T) {
+	if d.values == nil {
+		d.values = make(map[*Edge]T)
+	}
+	d.values[e] = v
+}
+
+// Delete removes the value of e, if it has one. Values of edges removed from
+// the graph are kept until deleted.
+func (d *EdgeData[T]) Delete(e *Edge) { delete(d.values, e) }
@@ -0,0 +1,58 @@
+package graph
+
+import "testing"
+
+func TestNodeData(t *testing.T) {
+	g := NewGridGraph(3, 3, false, false)
+	var names NodeData[string]
+	if _, ok := names.Get(g.Nodes[0]); ok {
+		t.Fatal("zero NodeData has a value")
+	}
+
+	names.Set(g.Nodes[4], "centre")
+	names.Set(g.Nodes[8], "corner")
+	names.Set(g.Nodes[8], "far corner")
+	for i, n := range g.Nodes {
+		v, ok := names.Get(n)
+		want := map[int]string{4: "centre", 8: "far corner"}[i]
+		if ok != (want != "") || v != want {
+			t.Fatalf("node %d: got %q, %t, want %q", i, v, ok, want)
+		}
+	}
+
+	names.Delete(g.Nodes[4])
+	names.Delete(&Node{ID: 100})
+	if _, ok := names.Get(g.Nodes[4]); ok {
+		t.Fatal("deleted value is still set")
+	}
+	if _, ok := names.Get(&Node{ID: -1}); ok {
+		t.Fatal("node -1 has a value")
+	}
+}
+
+func TestEdgeData(t *testing.T) {
+	g := newGraph(3, [][2]int{{0, 1}, {1, 2}})
+	a, b := g.Nodes[0].EdgeStart[0], g.Nodes[1].EdgeStart[0]
+
+	var limits EdgeData[int]
+	if _, ok := limits.Get(a); ok {
+		t.Fatal("zero EdgeData has a value")
+	}
+	limits.Set(a, 50)
+	if v, ok := limits.Get(a); !ok || v != 50 {
+		t.Fatalf("got %d, %t, want 50", v, ok)
+	}
+	if _, ok := limits.Get(b); ok {
+		t.Fatal("edge without a value has one")
+	}
+
+	// values outlive the removal of their edge
+	g.RemoveDirectedEdge(a)
+	if _, ok := limits.Get(a); !ok {
+		t.Fatal("value of a removed edge was dropped")
+	}
+	limits.Delete(a)
+	if _, ok := limits.Get(a); ok {
+		t.Fatal("deleted value is still set")
+	}
+}
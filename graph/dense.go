@@ -0,0 +1,136 @@
+package graph
+
+import (
+	"github.com/hanyangtay/go-datastructures/bitset"
+)
+
+var _ Graph = (*DenseGraph)(nil)
+
+// DenseGraph is a directed graph stored as an adjacency matrix: the weight
+// of the edge from node i to node j, if there is one, is at i*n+j. Looking
+// up, adding and removing an edge take O(1), where a DirectedGraph scans
+// the edges of a node, at the cost of O(n²) memory, so it suits small dense
+// graphs. Iterating over the successors of a node takes O(n/64 + degree),
+// over its predecessors O(n). There is at most one edge from a node to
+// another, and none from a node to itself. Lookups of nodes outside the
+// graph find no edges.
+type DenseGraph struct {
+	n       int
+	weights []float64
+	present *bitset.BitSet
+	size    int
+}
+
+// NewDenseGraph returns a graph of n nodes, with IDs 0 to n-1, and no edges
+func NewDenseGraph(n int) *DenseGraph {
+	return &DenseGraph{
+		n:       n,
+		weights: make([]float64, n*n),
+		present: bitset.New(n * n),
+	}
+}
+
+// Dense returns a DenseGraph snapshot of g. Of several edges between the
+// same nodes only the lightest is kept.
+func (g *DirectedGraph) Dense() *DenseGraph {
+	d := NewDenseGraph(len(g.Nodes))
+	for _, n := range g.Nodes {
+		for _, e := range n.EdgeStart {
+			if w, ok := d.Weight(e.From.ID, e.To.ID); !ok || e.Weight < w {
+				d.SetEdge(e.From.ID, e.To.ID, e.Weight)
+			}
+		}
+	}
+	return d
+}
+
+// Order returns the number of nodes in the graph
+func (d *DenseGraph) Order() int { return d.n }
+
+// Size returns the number of edges in the graph
+func (d *DenseGraph) Size() int { return d.size }
+
+// index returns the position of the edge from node from to node to in the
+// matrix, or false if either node is not in the graph
+func (d *DenseGraph) index(from, to int) (int, bool) {
+	if from < 0 || from >= d.n || to < 0 || to >= d.n {
+		return 0, false
+	}
+	return from*d.n + to, true
+}
+
+// SetEdge adds an edge from node from to node to with weight w, or changes
+// the weight of the existing one. It returns ErrNodeNotFound if either node
+// is not in the graph and ErrSelfEdge if from equals to.
+func (d *DenseGraph) SetEdge(from, to int, w float64) error {
+	i, ok := d.index(from, to)
+	if !ok {
+		return ErrNodeNotFound
+	}
+	if from == to {
+		return ErrSelfEdge
+	}
+
+	if !d.present.TestAndSet(i) {
+		d.size++
+	}
+	d.weights[i] = w
+	return nil
+}
+
+// RemoveEdge removes the edge from node from to node to and reports whether
+// there was one
+func (d *DenseGraph) RemoveEdge(from, to int) bool {
+	i, ok := d.index(from, to)
+	if !ok || !d.present.Test(i) {
+		return false
+	}
+	d.present.Clear(i)
+	d.weights[i] = 0
+	d.size--
+	return true
+}
+
+// HasEdge reports whether there is an edge from node from to node to
+func (d *DenseGraph) HasEdge(from, to int) bool {
+	i, ok := d.index(from, to)
+	return ok && d.present.Test(i)
+}
+
+// Weight returns the weight of the edge from node from to node to, and
+// whether there is one
+func (d *DenseGraph) Weight(from, to int) (float64, bool) {
+	i, ok := d.index(from, to)
+	if !ok || !d.present.Test(i) {
+		return 0, false
+	}
+	return d.weights[i], true
+}
+
+// Successors calls fn for every edge starting at node id, in order of the
+// target IDs, until fn returns false
+func (d *DenseGraph) Successors(id int, fn func(to int, weight float64) bool) {
+	row, ok := d.index(id, 0)
+	if !ok {
+		return
+	}
+	for i, ok := d.present.NextSet(row); ok && i < row+d.n; i, ok = d.present.NextSet(i + 1) {
+		if !fn(i-row, d.weights[i]) {
+			return
+		}
+	}
+}
+
+// Predecessors calls fn for every edge ending at node id, in order of the
+// source IDs, until fn returns false
+func (d *DenseGraph) Predecessors(id int, fn func(from int, weight float64) bool) {
+	i, ok := d.index(0, id)
+	if !ok {
+		return
+	}
+	for from := 0; from < d.n; from, i = from+1, i+d.n {
+		if d.present.Test(i) && !fn(from, d.weights[i]) {
+			return
+		}
+	}
+}
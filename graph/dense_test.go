@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestDense(t *testing.T) {
+	g := NewGridGraph(6, 6, true, false)
+	d := g.Dense()
+	checkView(t, g, d)
+	if d.Size() != g.EdgeCount() {
+		t.Fatalf("got size %d, want %d", d.Size(), g.EdgeCount())
+	}
+
+	// DijkstraIDs gives the same distances on either view
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		u, v := rng.Intn(len(g.Nodes)), rng.Intn(len(g.Nodes))
+		_, want := DijkstraIDs(g, u, v)
+		path, got := DijkstraIDs(d, u, v)
+		checkIDPath(t, d, u, v, path, got, want)
+	}
+
+	d.SetEdge(0, 1, 5)
+	d.SetEdge(0, 35, 2)
+	if w, ok := d.Weight(0, 1); !ok || w != 5 {
+		t.Fatalf("got weight %v, %t, want 5", w, ok)
+	}
+	if !d.HasEdge(0, 35) || d.Size() != g.EdgeCount()+1 {
+		t.Fatalf("got size %d after adding an edge, want %d", d.Size(), g.EdgeCount()+1)
+	}
+	if !d.RemoveEdge(0, 35) || d.RemoveEdge(0, 35) || d.HasEdge(0, 35) {
+		t.Fatal("removed edge 0 -> 35 twice")
+	}
+	if _, ok := d.Weight(0, 35); ok || d.Size() != g.EdgeCount() {
+		t.Fatalf("got size %d after removing an edge, want %d", d.Size(), g.EdgeCount())
+	}
+
+	if err := d.SetEdge(3, 3, 1); !errors.Is(err, ErrSelfEdge) {
+		t.Fatalf("self edge: got error %v, want ErrSelfEdge", err)
+	}
+	if err := d.SetEdge(0, 36, 1); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("edge to node 36: got error %v, want ErrNodeNotFound", err)
+	}
+
+	// nodes outside the graph have no edges
+	if d.HasEdge(-1, 0) || d.RemoveEdge(0, 36) {
+		t.Fatal("found an edge of a node outside the graph")
+	}
+	if _, ok := d.Weight(36, 0); ok {
+		t.Fatal("found the weight of an edge from node 36")
+	}
+	d.Successors(36, func(int, float64) bool {
+		t.Fatal("found a successor of node 36")
+		return false
+	})
+	d.Predecessors(-1, func(int, float64) bool {
+		t.Fatal("found a predecessor of node -1")
+		return false
+	})
+}
@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// HeuristicCheck enables a debug mode for AStar and AStarBi that repeats a
+// sample of their queries with a plain dijkstra search and reports every
+// query where the A* distance is longer. Such a difference means the
+// heuristic overestimated somewhere, which A* cannot detect by itself.
+//
+// Checked queries cost an extra dijkstra search, so the check is meant for
+// tests and staging rather than production traffic.
+type HeuristicCheck struct {
+	// SampleRate is the fraction of queries that are checked, from 0 to 1
+	SampleRate float64
+
+	// Report is called for every violation found, and must not modify the
+	// graph. Queries are not checked if it is nil.
+	Report func(HeuristicViolation)
+}
+
+// HeuristicViolation describes a query for which A* returned a longer
+// distance than dijkstra.
+type HeuristicViolation struct {
+	Algorithm string // "AStar" or "AStarBi"
+	From, To  *Node
+	Got, Want float64 // distance returned by A* and by dijkstra
+
+	// Edge is the first edge on the shortest path that weighs less than the
+	// distance between its endpoints, which makes the heuristic inadmissible.
+	// It is nil if no such edge was found.
+	Edge   *Edge
+	Length float64 // distance between the endpoints of Edge
+}
+
+// Error describes the violation, so that it can be passed on as an error
+func (v HeuristicViolation) Error() string {
+	msg := fmt.Sprintf("graph: %s from node %d to node %d returned distance %v, shortest is %v",
+		v.Algorithm, v.From.ID, v.To.ID, v.Got, v.Want)
+	if v.Edge != nil {
+		msg += fmt.Sprintf(" (edge %v weighs %v, less than its length %v)",
+			v.Edge.ID, v.Edge.Weight, v.Length)
+	}
+	return msg
+}
+
+// checkHeuristic cross-checks a distance returned by the A* search named
+// algorithm against dijkstra, if the query is sampled by g.HeuristicCheck
+func (g *DirectedGraph) checkHeuristic(algorithm string, u, v *Node, got float64) {
+	c := g.HeuristicCheck
+	if c == nil || c.Report == nil || rand.Float64() >= c.SampleRate {
+		return
+	}
+
+	path, want, _ := g.shortestPath(u, v, searchOptions{})
+
+	// allow for rounding in the different order of additions
+	if got <= want+1e-9*math.Max(1, want) {
+		return
+	}
+
+	violation := HeuristicViolation{
+		Algorithm: algorithm,
+		From:      u,
+		To:        v,
+		Got:       got,
+		Want:      want,
+	}
+
+	// with a metric heuristic, a suboptimal result requires an edge on the
+	// shortest path that is shorter than the distance it covers
+	for i := 1; i < len(path) && violation.Edge == nil; i++ {
+		e := lightestEdge(path[i-1], path[i])
+		if length := g.Dist(e.From, e.To); e.Weight < length {
+			violation.Edge, violation.Length = e, length
+		}
+	}
+
+	c.Report(violation)
+}
+
+// InadmissibleEdges returns every edge which weighs less than the distance
+// between its endpoints in the graph's coordinate system. A* searches remain
+// exact only if there are none, so the result is worth checking whenever
+// weights are not plain lengths, e.g. travel times.
+func (g *DirectedGraph) InadmissibleEdges() []*Edge {
+	var edges []*Edge
+	for _, n := range g.Nodes {
+		for _, e := range n.EdgeStart {
+			if e.Weight < g.Dist(e.From, e.To) {
+				edges = append(edges, e)
+			}
+		}
+	}
+	return edges
+}
@@ -0,0 +1,47 @@
+package graph
+
+import "testing"
+
+func TestHeuristicCheck(t *testing.T) {
+	// the detour through node 2 is far shorter than its length, so A*
+	// settles node 1 before finding it
+	g := NewDirectedGraph()
+	for _, x := range []float64{0, 1, 10} {
+		g.AddNode(&Node{X: x})
+	}
+	for _, e := range []struct {
+		from, to int
+		weight   float64
+	}{{0, 1, 5}, {0, 2, 0.1}, {2, 1, 0.1}} {
+		g.AddDirectedEdge(&Edge{ID: [2]int{e.from, e.to}, From: g.Nodes[e.from], To: g.Nodes[e.to], Weight: e.weight})
+	}
+
+	if edges := g.InadmissibleEdges(); len(edges) != 2 || edges[0].ID != [2]int{0, 2} || edges[1].ID != [2]int{2, 1} {
+		t.Fatalf("got %d inadmissible edges, want 0 -> 2 and 2 -> 1", len(edges))
+	}
+
+	var violations []HeuristicViolation
+	g.HeuristicCheck = &HeuristicCheck{SampleRate: 0, Report: func(v HeuristicViolation) {
+		violations = append(violations, v)
+	}}
+	g.AStar(g.Nodes[0], g.Nodes[1])
+	if len(violations) != 0 {
+		t.Fatalf("got %d violations at sample rate 0", len(violations))
+	}
+
+	g.HeuristicCheck.SampleRate = 1
+	g.AStar(g.Nodes[0], g.Nodes[1])
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+	v := violations[0]
+	if v.Algorithm != "AStar" || v.Got != 5 || v.Want != 0.2 || v.Edge == nil || v.Edge.ID != [2]int{0, 2} || v.Length != 10 {
+		t.Fatalf("got violation %q", v.Error())
+	}
+
+	// admissible queries are not reported
+	g.AStar(g.Nodes[2], g.Nodes[1])
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1", len(violations))
+	}
+}
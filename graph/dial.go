@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+)
+
+// DialDijkstra returns a shortest path from u to v and the distance in a
+// graph whose edge weights are integers between 0 and maxWeight. Instead of a
+// heap, nodes are kept in a circular array of maxWeight+1 buckets indexed by
+// distance, so each queue operation is O(1).
+// Time complexity: O(|E| + |V| * maxWeight)
+// It returns ErrBadWeight if it meets an edge of any other weight, or panics
+// with it if g.Strict is set.
+func (g *DirectedGraph) DialDijkstra(u, v *Node, maxWeight int) ([]*Node, float64, error) {
+	if maxWeight < 0 {
+		err := fmt.Errorf("%w: maxWeight %d is negative", ErrBadWeight, maxWeight)
+		return nil, math.Inf(1), g.check(err)
+	}
+
+	forwardDist := map[*Node]int{u: 0}
+	next := make(map[*Node]*Node)
+
+	buckets := make([][]*Node, maxWeight+1)
+	buckets[0] = []*Node{u}
+	queued := 1
+
+	for d := 0; queued > 0; d++ {
+		b := d % len(buckets)
+
+		// the bucket may grow while it is processed by 0 weight edges
+		for len(buckets[b]) > 0 {
+			mid := buckets[b][len(buckets[b])-1]
+			buckets[b] = buckets[b][:len(buckets[b])-1]
+			queued--
+
+			// skip entries superseded by a shorter distance
+			if forwardDist[mid] != d {
+				continue
+			}
+
+			// terminates when final node is found
+			if mid == v {
+				return tracePath(next, u, v), float64(d), nil
+			}
+
+			for _, e := range mid.EdgeStart {
+				w := int(e.Weight)
+				if float64(w) != e.Weight || w < 0 || w > maxWeight {
+					err := fmt.Errorf("%w: edge %v has weight %v, not an integer between 0 and %d", ErrBadWeight, e.ID, e.Weight, maxWeight)
+					return nil, math.Inf(1), g.check(err)
+				}
+
+				n := e.To
+				acc_dist := d + w
+
+				// update shortest paths
+				if dist, ok := forwardDist[n]; !ok || acc_dist < dist {
+					forwardDist[n] = acc_dist
+					next[n] = mid
+
+					nb := acc_dist % len(buckets)
+					buckets[nb] = append(buckets[nb], n)
+					queued++
+				}
+			}
+		}
+	}
+
+	// no path found
+	return nil, math.Inf(1), nil
+}
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestDialDijkstra(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, maxWeight := range []int{1, 3, 10} {
+		g := newRandomIntGraph(200, maxWeight, rng)
+		for i := 0; i < 10; i++ {
+			u := g.Nodes[rng.Intn(len(g.Nodes))]
+			dist := bellmanFord(g, u)
+			for _, v := range g.Nodes {
+				path, d, err := g.DialDijkstra(u, v, maxWeight)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkPath(t, g, u, v, path, d, dist[v.ID])
+			}
+		}
+	}
+}
+
+func TestDialDijkstraBadWeight(t *testing.T) {
+	g := newGraph(2, [][2]int{{0, 1}})
+	g.Nodes[0].EdgeStart[0].Weight = 1.5
+
+	if _, _, err := g.DialDijkstra(g.Nodes[0], g.Nodes[1], 3); !errors.Is(err, ErrBadWeight) {
+		t.Fatalf("weight 1.5: got error %v, want ErrBadWeight", err)
+	}
+	if _, _, err := g.DialDijkstra(g.Nodes[0], g.Nodes[1], -1); !errors.Is(err, ErrBadWeight) {
+		t.Fatalf("maxWeight -1: got error %v, want ErrBadWeight", err)
+	}
+
+	g.Strict = true
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrBadWeight) {
+			t.Fatalf("strict graph panicked with %v, want ErrBadWeight", err)
+		}
+	}()
+	g.DialDijkstra(g.Nodes[0], g.Nodes[1], 3)
+}
@@ -0,0 +1,244 @@
+package graph
+
+import (
+	"math"
+
+	"github.com/hanyangtay/go-datastructures/indexedpq"
+)
+
+// DStarLite maintains a shortest path from a start node to a goal node while
+// edge weights change, e.g. under live traffic updates, using the D* Lite
+// algorithm. The search runs backwards from the goal, so after a weight
+// change only the distances the change invalidates are repaired, rather than
+// recomputing the route from scratch. The start may also move along the route
+// without losing any previous work.
+// Like AStar, it uses g.Dist to the start as heuristic.
+type DStarLite struct {
+	g           *DirectedGraph
+	start, goal *Node
+
+	// last is the start when km was last updated, km the accumulated
+	// heuristic offset that keeps old queue keys valid as the start moves
+	last *Node
+	km   float64
+
+	// dist is the current distance estimate to the goal, rhs its one step
+	// lookahead value; a node is consistent when both agree
+	dist, rhs map[*Node]float64
+	queue     *keyQueue
+}
+
+// NewDStarLite prepares an incremental search for a shortest path from start
+// to goal in g. The first call to Path computes the initial route.
+func (g *DirectedGraph) NewDStarLite(start, goal *Node) *DStarLite {
+	d := &DStarLite{
+		g:     g,
+		start: start,
+		goal:  goal,
+		last:  start,
+		dist:  make(map[*Node]float64),
+		rhs:   map[*Node]float64{goal: 0},
+		queue: newKeyQueue(),
+	}
+
+	d.queue.update(goal, d.key(goal))
+	return d
+}
+
+// Path brings the search up to date with all weight changes and moves so far
+// and returns the current shortest path from the start to the goal and its
+// distance. The distance is +Inf if there is no path.
+func (d *DStarLite) Path() ([]*Node, float64) {
+	d.computeShortestPath()
+
+	if math.IsInf(d.distOf(d.start), 1) {
+		return nil, math.Inf(1)
+	}
+
+	// walk greedily along the best successor of every node
+	path := []*Node{d.start}
+	seen := map[*Node]bool{d.start: true}
+
+	for n := d.start; n != d.goal; {
+		var best *Node
+		bestDist := math.Inf(1)
+
+		for _, e := range n.EdgeStart {
+			if dist := e.Weight + d.distOf(e.To); dist < bestDist && !seen[e.To] {
+				best, bestDist = e.To, dist
+			}
+		}
+
+		if best == nil {
+			return nil, math.Inf(1)
+		}
+
+		n = best
+		seen[n] = true
+		path = append(path, n)
+	}
+
+	return path, d.distOf(d.start)
+}
+
+// MoveTo moves the start of the search to n, typically the next node along
+// the current path once it has been reached.
+func (d *DStarLite) MoveTo(n *Node) {
+	d.start = n
+	d.km += d.g.Dist(d.last, d.start)
+	d.last = d.start
+}
+
+// UpdateWeight sets the weight of e to w and marks the affected distances for
+// repair; the next call to Path repairs them. Several changes may be made
+// between calls to Path.
+func (d *DStarLite) UpdateWeight(e *Edge, w float64) {
+	old := e.Weight
+	e.Weight = w
+
+	u, v := e.From, e.To
+	if u == d.goal {
+		return
+	}
+
+	if w < old {
+		d.rhs[u] = math.Min(d.rhsOf(u), w+d.distOf(v))
+	} else if d.rhsOf(u) == old+d.distOf(v) {
+		d.rhs[u] = d.bestSuccessor(u)
+	}
+
+	d.updateNode(u)
+}
+
+// distOf returns the distance estimate of n, +Inf if n was never reached
+func (d *DStarLite) distOf(n *Node) float64 {
+	if dist, ok := d.dist[n]; ok {
+		return dist
+	}
+	return math.Inf(1)
+}
+
+// rhsOf returns the lookahead value of n, +Inf if n was never reached
+func (d *DStarLite) rhsOf(n *Node) float64 {
+	if rhs, ok := d.rhs[n]; ok {
+		return rhs
+	}
+	return math.Inf(1)
+}
+
+// key returns the queue priority of n
+func (d *DStarLite) key(n *Node) dstarKey {
+	m := math.Min(d.distOf(n), d.rhsOf(n))
+	return dstarKey{m + d.g.Dist(d.start, n) + d.km, m}
+}
+
+// bestSuccessor returns the lowest distance to the goal through any of the
+// edges starting at n
+func (d *DStarLite) bestSuccessor(n *Node) float64 {
+	best := math.Inf(1)
+	for _, e := range n.EdgeStart {
+		best = math.Min(best, e.Weight+d.distOf(e.To))
+	}
+	return best
+}
+
+// updateNode queues n if it is inconsistent and dequeues it otherwise
+func (d *DStarLite) updateNode(n *Node) {
+	if d.distOf(n) != d.rhsOf(n) {
+		d.queue.update(n, d.key(n))
+	} else {
+		d.queue.remove(n)
+	}
+}
+
+// computeShortestPath processes inconsistent nodes until the start node is
+// consistent and no queued node could still lower its distance
+func (d *DStarLite) computeShortestPath() {
+	for d.queue.len() > 0 {
+		u, key := d.queue.top()
+		if !key.less(d.key(d.start)) && d.rhsOf(d.start) == d.distOf(d.start) {
+			break
+		}
+
+		if newKey := d.key(u); key.less(newKey) {
+			// the key is outdated since the start moved
+			d.queue.update(u, newKey)
+			continue
+		}
+
+		if gOld := d.distOf(u); gOld > d.rhsOf(u) {
+			// overconsistent: settle the lower distance
+			d.dist[u] = d.rhsOf(u)
+			d.queue.remove(u)
+
+			for _, e := range u.EdgeEnd {
+				if s := e.From; s != d.goal {
+					d.rhs[s] = math.Min(d.rhsOf(s), e.Weight+d.dist[u])
+					d.updateNode(s)
+				}
+			}
+		} else {
+			// underconsistent: invalidate the distance and the nodes that
+			// relied on it
+			d.dist[u] = math.Inf(1)
+
+			if u != d.goal && d.rhsOf(u) == gOld {
+				d.rhs[u] = d.bestSuccessor(u)
+			}
+			d.updateNode(u)
+
+			for _, e := range u.EdgeEnd {
+				if s := e.From; s != d.goal && d.rhsOf(s) == e.Weight+gOld {
+					d.rhs[s] = d.bestSuccessor(s)
+					d.updateNode(s)
+				}
+			}
+		}
+	}
+}
+
+// dstarKey is a D* Lite queue priority, compared lexicographically
+type dstarKey [2]float64
+
+func (k dstarKey) less(other dstarKey) bool {
+	return k[0] < other[0] || (k[0] == other[0] && k[1] < other[1])
+}
+
+// keyQueue is a priority queue of nodes that supports changing and removing
+// entries
+type keyQueue struct {
+	pq      *indexedpq.PriorityQueue[*Node, dstarKey]
+	handles map[*Node]*indexedpq.Handle[*Node, dstarKey]
+}
+
+func newKeyQueue() *keyQueue {
+	return &keyQueue{
+		pq:      indexedpq.New[*Node](dstarKey.less),
+		handles: make(map[*Node]*indexedpq.Handle[*Node, dstarKey]),
+	}
+}
+
+func (q *keyQueue) len() int { return q.pq.Len() }
+
+// top returns the queued node with the lowest key, and its key
+func (q *keyQueue) top() (*Node, dstarKey) {
+	h := q.pq.Peek()
+	return h.Value(), h.Priority()
+}
+
+// update queues n with the given key, or changes its key if already queued
+func (q *keyQueue) update(n *Node, key dstarKey) {
+	if h, ok := q.handles[n]; ok {
+		q.pq.Update(h, key)
+		return
+	}
+	q.handles[n] = q.pq.Push(n, key)
+}
+
+// remove dequeues n if it is queued
+func (q *keyQueue) remove(n *Node) {
+	if h, ok := q.handles[n]; ok {
+		q.pq.Remove(h)
+		delete(q.handles, n)
+	}
+}
@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestDStarLite changes edge weights and moves the start along the route,
+// and compares every replanned route with a search from scratch
+func TestDStarLite(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(300, rng)
+	start, goal := g.Nodes[0], g.Nodes[1]
+	d := g.NewDStarLite(start, goal)
+
+	for i := 0; i < 50; i++ {
+		path, dist := d.Path()
+		checkPath(t, g, start, goal, path, dist, bellmanFord(g, start)[goal.ID])
+		if path == nil || start == goal {
+			break
+		}
+
+		// weights stay at least the distance between their nodes, so the
+		// heuristic stays admissible
+		for j := 0; j < 10; j++ {
+			var e *Edge
+			if j < 3 && len(path) > 1 {
+				// hit the current route, so that it changes
+				k := rng.Intn(len(path) - 1)
+				e = lightestEdge(path[k], path[k+1])
+			} else if n := g.Nodes[rng.Intn(len(g.Nodes))]; len(n.EdgeStart) > 0 {
+				e = n.EdgeStart[rng.Intn(len(n.EdgeStart))]
+			} else {
+				continue
+			}
+			d.UpdateWeight(e, Dist(e.From, e.To)*(1+3*rng.Float64()))
+		}
+
+		if i%5 == 4 {
+			start = path[1]
+			d.MoveTo(start)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/hanyangtay/go-datastructures/internal/wire"
+)
+
+// ErrInvalidData is returned when decoding data that is not an encoded graph
+var ErrInvalidData = errors.New("graph: invalid encoding")
+
+// encodingVersion is the version of the layout written by MarshalBinary
+const encodingVersion = 1
+
+// graphPayload is the content of an encoded graph, with edges referring to
+// nodes by index
+type graphPayload struct {
+	Coordinates CoordinateSystem
+	Nodes       []nodePayload
+	Edges       []edgePayload
+}
+
+type nodePayload struct {
+	X, Y, Cost float64
+}
+
+type edgePayload struct {
+	From, To int
+	Weight   float64
+	Shape    []Point
+}
+
+// MarshalBinary encodes the coordinate system of the graph, its nodes with
+// their coordinates and costs and its edges with their weights and shapes.
+// Searches are configured by the decoding graph, so Heap, Metrics and the
+// like are not encoded.
+func (g *DirectedGraph) MarshalBinary() ([]byte, error) {
+	p := graphPayload{Coordinates: g.Coordinates, Nodes: make([]nodePayload, len(g.Nodes))}
+	for i, n := range g.Nodes {
+		p.Nodes[i] = nodePayload{n.X, n.Y, n.Cost}
+		for _, e := range n.EdgeStart {
+			p.Edges = append(p.Edges, edgePayload{e.From.ID, e.To.ID, e.Weight, e.Shape})
+		}
+	}
+	return wire.Marshal("graph.DirectedGraph", encodingVersion, &p)
+}
+
+// UnmarshalBinary replaces the nodes and edges of the graph with those
+// encoded by MarshalBinary, allocated as by NewNode and NewEdge. Edges keep
+// their order, so the decoded graph has the Fingerprint of the encoded one
+// and loads its artifacts. ErrInvalidData is returned if data is malformed.
+func (g *DirectedGraph) UnmarshalBinary(data []byte) error {
+	var p graphPayload
+	if err := wire.Unmarshal(data, "graph.DirectedGraph", encodingVersion, &p); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	// check every edge before the graph is cleared, so that NewEdge cannot
+	// fail and leave it half decoded
+	for _, e := range p.Edges {
+		switch {
+		case e.From < 0 || e.From >= len(p.Nodes) || e.To < 0 || e.To >= len(p.Nodes):
+			return fmt.Errorf("%w: edge %d->%d has an end outside the graph", ErrInvalidData, e.From, e.To)
+		case e.From == e.To:
+			return fmt.Errorf("%w: self edge at node %d", ErrInvalidData, e.From)
+		case math.IsNaN(e.Weight) || e.Weight < 0:
+			return fmt.Errorf("%w: edge %d->%d has invalid weight %v", ErrInvalidData, e.From, e.To, e.Weight)
+		}
+	}
+
+	g.Release()
+	g.Coordinates = p.Coordinates
+	for _, n := range p.Nodes {
+		g.NewNode(n.X, n.Y).Cost = n.Cost
+	}
+	for _, e := range p.Edges {
+		edge, err := g.NewEdge(g.Nodes[e.From], g.Nodes[e.To], e.Weight)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidData, err)
+		}
+		edge.Shape = e.Shape
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/hanyangtay/go-datastructures/internal/wire"
+)
+
+func TestEncoding(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(100, rng)
+	g.Coordinates = WGS84
+	g.Nodes[3].Cost = 2.5
+	g.Nodes[0].EdgeStart[0].Shape = []Point{{X: 1, Y: 2}, {X: 3, Y: 4}}
+
+	data, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewGridGraph(3, 3, false, false)
+	if err := h.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if errs := h.Validate(); errs != nil {
+		t.Fatal(errs)
+	}
+	if h.Coordinates != WGS84 || len(h.Nodes) != len(g.Nodes) || h.Nodes[3].Cost != 2.5 {
+		t.Fatalf("decoded %d nodes in coordinate system %d", len(h.Nodes), h.Coordinates)
+	}
+	if h.Fingerprint() != g.Fingerprint() {
+		t.Fatal("decoded graph has another fingerprint")
+	}
+	if shape := h.Nodes[0].EdgeStart[0].Shape; len(shape) != 2 || shape[1] != (Point{X: 3, Y: 4}) {
+		t.Fatalf("got shape %v", shape)
+	}
+	for _, n := range g.Nodes {
+		m := h.Nodes[n.ID]
+		if m.X != n.X || m.Y != n.Y || len(m.EdgeStart) != len(n.EdgeStart) {
+			t.Fatalf("node %d was not decoded", n.ID)
+		}
+		for i, e := range n.EdgeStart {
+			if f := m.EdgeStart[i]; f.ID != e.ID || f.Weight != e.Weight {
+				t.Fatalf("got edge %v of weight %v, want %v of weight %v", f.ID, f.Weight, e.ID, e.Weight)
+			}
+		}
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	g := newGraph(3, [][2]int{{0, 1}, {1, 2}})
+	data, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bad := map[string][]byte{
+		"empty":      nil,
+		"truncated":  data[:len(data)-3],
+		"headerless": data[len("GDSENC"):],
+	}
+	for name, edge := range map[string]edgePayload{
+		"edge outside the graph": {From: 0, To: 3, Weight: 1},
+		"self edge":              {From: 1, To: 1, Weight: 1},
+		"NaN weight":             {From: 0, To: 1, Weight: math.NaN()},
+	} {
+		p := graphPayload{Nodes: make([]nodePayload, 3), Edges: []edgePayload{edge}}
+		if bad[name], err = wire.Marshal("graph.DirectedGraph", encodingVersion, &p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, data := range bad {
+		h := newGraph(2, [][2]int{{0, 1}})
+		if err := h.UnmarshalBinary(data); !errors.Is(err, ErrInvalidData) {
+			t.Errorf("%s: got %v, want ErrInvalidData", name, err)
+		}
+		if len(h.Nodes) != 2 || h.EdgeCount() != 1 {
+			t.Errorf("%s: failed decoding changed the graph", name)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"math/rand"
+
+	"github.com/hanyangtay/go-datastructures/rtree"
+)
+
+// NewGridGraph builds a rows×cols grid graph. The node in row i and column j
+// has ID i*cols + j and coordinates X = j, Y = i.
+// Adjacent nodes are joined by a pair of opposing directed edges. Nodes are
+// 4-connected, or 8-connected if diagonal is set.
+// If weighted is set, edge weights are the Euclidean distance between the
+// endpoints, otherwise every edge has weight 1.
+func NewGridGraph(rows, cols int, diagonal, weighted bool) *DirectedGraph {
+	g := NewDirectedGraph()
+
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			g.AddNode(&Node{X: float64(j), Y: float64(i)})
+		}
+	}
+
+	// offsets of the neighbours each node links to; the opposite direction is
+	// added at the same time, so only half of the neighbourhood is needed
+	offsets := [][2]int{{0, 1}, {1, 0}}
+	if diagonal {
+		offsets = append(offsets, [2]int{1, 1}, [2]int{1, -1})
+	}
+
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			u := g.Nodes[i*cols+j]
+
+			for _, o := range offsets {
+				ni, nj := i+o[0], j+o[1]
+				if ni < 0 || ni >= rows || nj < 0 || nj >= cols {
+					continue
+				}
+
+				v := g.Nodes[ni*cols+nj]
+				w := 1.0
+				if weighted {
+					w = Dist(u, v)
+				}
+
+				addEdgePair(g, u, v, w)
+			}
+		}
+	}
+
+	return g
+}
+
+// addEdgePair joins u and v with directed edges of weight w in both directions
+func addEdgePair(g *DirectedGraph, u, v *Node, w float64) {
+	g.AddDirectedEdge(&Edge{ID: [2]int{u.ID, v.ID}, From: u, To: v, Weight: w})
+	g.AddDirectedEdge(&Edge{ID: [2]int{v.ID, u.ID}, From: v, To: u, Weight: w})
+}
+
+// nodePoint indexes a node by its coordinates in an R-tree
+type nodePoint struct {
+	rtree.RTreePoint
+	node *Node
+}
+
+// NewRandomGeometricGraph scatters n nodes uniformly in the rectangle
+// [0, width) × [0, height) and joins every pair of nodes within distance r of
+// each other by a pair of opposing directed edges weighted by their Euclidean
+// distance. Randomness is drawn from rng, or the math/rand default source if
+// rng is nil.
+func NewRandomGeometricGraph(n int, width, height, r float64, rng *rand.Rand) *DirectedGraph {
+	g := NewDirectedGraph()
+
+	float := rand.Float64
+	if rng != nil {
+		float = rng.Float64
+	}
+
+	tree := rtree.NewTree[*nodePoint](25, 50)
+	points := make([]*nodePoint, n)
+
+	for i := 0; i < n; i++ {
+		u := &Node{X: float() * width, Y: float() * height}
+		g.AddNode(u)
+
+		points[i] = &nodePoint{RTreePoint: rtree.RTreePoint{X: u.X, Y: u.Y}, node: u}
+		tree.Insert(points[i])
+	}
+
+	for _, p := range points {
+		u := p.node
+		bb := rtree.NewRect(&rtree.RTreePoint{X: u.X - r, Y: u.Y - r},
+			&rtree.RTreePoint{X: u.X + r, Y: u.Y + r})
+
+		for _, q := range tree.SearchIntersect(bb) {
+			v := q.node
+
+			// each pair is found from both ends, only link it once
+			if v.ID <= u.ID {
+				continue
+			}
+
+			if d := Dist(u, v); d <= r {
+				addEdgePair(g, u, v, d)
+			}
+		}
+	}
+
+	return g
+}
@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestGridGraph(t *testing.T) {
+	tests := []struct {
+		diagonal, weighted bool
+		edges              int
+	}{
+		{false, false, 2 * (3*3 + 4*2)},
+		{true, false, 2 * (3*3 + 4*2 + 2*3*2)},
+		{true, true, 2 * (3*3 + 4*2 + 2*3*2)},
+	}
+	for _, tt := range tests {
+		g := NewGridGraph(3, 4, tt.diagonal, tt.weighted)
+		if len(g.Nodes) != 12 {
+			t.Fatalf("got %d nodes, want 12", len(g.Nodes))
+		}
+
+		edges := 0
+		for _, u := range g.Nodes {
+			if u.X != float64(u.ID%4) || u.Y != float64(u.ID/4) {
+				t.Fatalf("node %d is at (%v, %v)", u.ID, u.X, u.Y)
+			}
+			for _, e := range u.EdgeStart {
+				edges++
+				want := 1.0
+				if tt.weighted {
+					want = Dist(e.From, e.To)
+				}
+				if e.Weight != want {
+					t.Fatalf("edge %v has weight %v, want %v", e.ID, e.Weight, want)
+				}
+				if d := Dist(e.From, e.To); d > 1.5 || (!tt.diagonal && d != 1) {
+					t.Fatalf("edge %v joins nodes %v apart", e.ID, d)
+				}
+				if _, ok := g.Weight(e.To, e.From); !ok {
+					t.Fatalf("edge %v has no reverse edge", e.ID)
+				}
+			}
+		}
+		if edges != tt.edges {
+			t.Fatalf("diagonal %t: got %d edges, want %d", tt.diagonal, edges, tt.edges)
+		}
+	}
+}
+
+func TestRandomGeometricGraph(t *testing.T) {
+	g := NewRandomGeometricGraph(300, 100, 50, 8, rand.New(rand.NewSource(1)))
+	if len(g.Nodes) != 300 {
+		t.Fatalf("got %d nodes, want 300", len(g.Nodes))
+	}
+
+	for _, u := range g.Nodes {
+		if u.X < 0 || u.X >= 100 || u.Y < 0 || u.Y >= 50 {
+			t.Fatalf("node %d at (%v, %v) lies outside the rectangle", u.ID, u.X, u.Y)
+		}
+		for _, v := range g.Nodes {
+			w, ok := g.Weight(u, v)
+			if d := Dist(u, v); ok != (u != v && d <= 8) {
+				t.Fatalf("%d -> %d: got edge %t for nodes %v apart", u.ID, v.ID, ok, d)
+			} else if ok && math.Abs(w-d) > 1e-12 {
+				t.Fatalf("%d -> %d: got weight %v, want %v", u.ID, v.ID, w, d)
+			}
+		}
+	}
+}
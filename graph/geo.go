@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"math"
+
+	"github.com/hanyangtay/go-datastructures/geom"
+)
+
+// CoordinateSystem selects how node coordinates are interpreted when
+// measuring distances between nodes.
+type CoordinateSystem int
+
+const (
+	// Planar treats X and Y as cartesian coordinates.
+	Planar CoordinateSystem = iota
+
+	// WGS84 treats X as longitude and Y as latitude in degrees, measuring
+	// great circle distances in metres.
+	WGS84
+)
+
+// EarthRadius is the mean radius of the earth in metres
+const EarthRadius = 6371008.8
+
+// Dist returns the distance between two nodes in the graph's coordinate
+// system.
+func (g *DirectedGraph) Dist(u, v *Node) float64 {
+	if g.Coordinates == WGS84 {
+		return Haversine(u, v)
+	}
+	return Dist(u, v)
+}
+
+// DistFromEdge returns the distance between a node and an edge in the graph's
+// coordinate system.
+func (g *DirectedGraph) DistFromEdge(n *Node, e *Edge) float64 {
+	if g.Coordinates == WGS84 {
+		return HaversineFromEdge(n, e)
+	}
+	return DistFromEdge(n, e)
+}
+
+// Haversine returns the great circle distance in metres between two nodes
+// whose coordinates are longitude (X) and latitude (Y) in degrees.
+func Haversine(u, v *Node) float64 {
+	lat1, lat2 := radians(u.Y), radians(v.Y)
+	dLat := lat2 - lat1
+	dLon := radians(v.X - u.X)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * EarthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// HaversineFromEdge returns the distance in metres between a node and an edge
+// with longitude (X) and latitude (Y) coordinates in degrees. The edge is
+// projected onto the plane tangent to the earth at n, which is accurate for
+// edges that are short compared to the radius of the earth.
+func HaversineFromEdge(n *Node, e *Edge) float64 {
+	scaleY := radians(EarthRadius)
+	scaleX := scaleY * math.Cos(radians(n.Y))
+
+	project := func(m *Node) *Node {
+		return &Node{X: (m.X - n.X) * scaleX, Y: (m.Y - n.Y) * scaleY}
+	}
+
+	local := &Edge{From: project(e.From), To: project(e.To)}
+	return DistFromEdge(&Node{}, local)
+}
+
+// radians converts degrees to radians
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// SimplifyShapes drops the shape points of every edge that lie within
+// tolerance of its simplified geometry, by the Douglas-Peucker algorithm, to
+// shrink the graph before rendering or storing it. Tolerance is measured in
+// coordinate units, i.e. degrees for WGS84 coordinates.
+func (g *DirectedGraph) SimplifyShapes(tolerance float64) {
+	for _, n := range g.Nodes {
+		for _, e := range n.EdgeStart {
+			if len(e.Shape) == 0 {
+				continue
+			}
+
+			line := make([]Point, 0, len(e.Shape)+2)
+			line = append(line, Point{X: e.From.X, Y: e.From.Y})
+			line = append(line, e.Shape...)
+			line = append(line, Point{X: e.To.X, Y: e.To.Y})
+
+			simple := geom.SimplifyDouglasPeucker(line, tolerance)
+			e.Shape = nil
+			if len(simple) > 2 {
+				e.Shape = simple[1 : len(simple)-1]
+			}
+		}
+	}
+}
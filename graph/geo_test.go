@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHaversine(t *testing.T) {
+	degree := EarthRadius * math.Pi / 180
+	tests := []struct {
+		u, v Node
+		want float64
+	}{
+		{Node{X: 0, Y: 0}, Node{X: 0, Y: 1}, degree},
+		{Node{X: 0, Y: 0}, Node{X: 1, Y: 0}, degree},
+		{Node{X: 10, Y: 60}, Node{X: 11, Y: 60}, 2 * EarthRadius * math.Asin(math.Cos(math.Pi/3)*math.Sin(math.Pi/360))},
+		{Node{X: 0, Y: 90}, Node{X: 123, Y: -90}, math.Pi * EarthRadius},
+		{Node{X: 179.5, Y: 0}, Node{X: -179.5, Y: 0}, degree},
+	}
+	for _, tt := range tests {
+		if d := Haversine(&tt.u, &tt.v); math.Abs(d-tt.want) > 1e-6*tt.want {
+			t.Errorf("Haversine((%v, %v), (%v, %v)) = %v, want %v", tt.u.X, tt.u.Y, tt.v.X, tt.v.Y, d, tt.want)
+		}
+	}
+}
+
+func TestHaversineFromEdge(t *testing.T) {
+	e := &Edge{From: &Node{X: 13.40, Y: 52.50}, To: &Node{X: 13.42, Y: 52.50}}
+	tests := []struct {
+		n, nearest Node
+	}{
+		{Node{X: 13.41, Y: 52.501}, Node{X: 13.41, Y: 52.50}},
+		{Node{X: 13.39, Y: 52.50}, Node{X: 13.40, Y: 52.50}},
+		{Node{X: 13.43, Y: 52.499}, Node{X: 13.42, Y: 52.50}},
+	}
+	for _, tt := range tests {
+		want := Haversine(&tt.n, &tt.nearest)
+		if d := HaversineFromEdge(&tt.n, e); math.Abs(d-want) > 1e-3*want {
+			t.Errorf("HaversineFromEdge((%v, %v)) = %v, want %v", tt.n.X, tt.n.Y, d, want)
+		}
+	}
+}
+
+func TestWGS84(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := NewDirectedGraph()
+	g.Coordinates = WGS84
+	for i := 0; i < 100; i++ {
+		g.AddNode(&Node{X: 2 + rng.Float64(), Y: 48 + rng.Float64()})
+	}
+	for _, u := range g.Nodes {
+		for j := 0; j < 4; j++ {
+			if v := g.Nodes[rng.Intn(len(g.Nodes))]; v != u {
+				g.AddDirectedEdge(&Edge{ID: [2]int{u.ID, v.ID}, From: u, To: v, Weight: Haversine(u, v)})
+			}
+		}
+	}
+
+	u := g.Nodes[0]
+	if g.Dist(u, g.Nodes[1]) != Haversine(u, g.Nodes[1]) {
+		t.Fatal("g.Dist does not measure great circle distances")
+	}
+	dist := bellmanFord(g, u)
+	for _, v := range g.Nodes {
+		path, d := g.AStar(u, v)
+		checkPath(t, g, u, v, path, d, dist[v.ID])
+	}
+
+	g.Coordinates = Planar
+	if g.Dist(u, g.Nodes[1]) != Dist(u, g.Nodes[1]) {
+		t.Fatal("g.Dist does not measure planar distances")
+	}
+}
+
+func TestSimplifyShapes(t *testing.T) {
+	g := newGraph(3, [][2]int{{0, 1}, {1, 2}})
+	straight, bent := g.Nodes[0].EdgeStart[0], g.Nodes[1].EdgeStart[0]
+	straight.Shape = []Point{{X: 0.25, Y: 0.01}, {X: 0.5, Y: -0.01}, {X: 0.75, Y: 0}}
+	bent.Shape = []Point{{X: 1.25, Y: 0.51}, {X: 1.5, Y: 1}, {X: 1.75, Y: 0.49}}
+
+	g.SimplifyShapes(0.1)
+	if len(straight.Shape) != 0 {
+		t.Fatalf("straight edge kept %d shape points", len(straight.Shape))
+	}
+	if len(bent.Shape) != 1 || bent.Shape[0] != (Point{X: 1.5, Y: 1}) {
+		t.Fatalf("got shape %v for bent edge, want its peak", bent.Shape)
+	}
+}
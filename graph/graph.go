@@ -1,13 +1,41 @@
 /*
 Package graph implements a directed graph with node and edge structure
-Note that edge query is inefficient if graph has a high degree.
+Note that edge query is inefficient if graph has a high degree; DenseGraph
+looks edges up in constant time. NodeData and EdgeData attach typed values
+to nodes and edges.
 */
 
 package graph
 
 import (
+	"errors"
 	"math"
-	"time"
+	"sync"
+
+	"github.com/hanyangtay/go-datastructures/geom"
+	"github.com/hanyangtay/go-datastructures/internal/arena"
+	"github.com/hanyangtay/go-datastructures/metrics"
+)
+
+var (
+	// ErrNodeNotFound is returned when adding an edge to a node that is not
+	// in the graph
+	ErrNodeNotFound = errors.New("graph: node does not exist")
+
+	// ErrSelfEdge is returned when adding an edge from a node to itself
+	ErrSelfEdge = errors.New("graph: self edge")
+
+	// ErrBadWeight is returned by searches restricted to certain edge
+	// weights, such as ZeroOneBFS, when they meet an edge of another weight
+	ErrBadWeight = errors.New("graph: edge weight out of range")
+
+	// ErrBadPartCount is returned by Partition for a part count below one
+	ErrBadPartCount = errors.New("graph: number of parts must be positive")
+
+	// ErrInconsistent is returned, in builds with the datastructures_debug
+	// tag, by a mutation that leaves the edge lists of the nodes it touches
+	// out of step, e.g. an edge added twice or changed while in the graph
+	ErrInconsistent = errors.New("graph: graph is inconsistent")
 )
 
 type Node struct {
@@ -16,16 +44,55 @@ type Node struct {
 	Y         float64
 	EdgeEnd   []*Edge
 	EdgeStart []*Edge
+
+	// Cost is incurred by paths passing through the node, e.g. an
+	// intersection delay. Only node weighted searches take it into account.
+	Cost float64
 }
 
 type Edge struct {
 	ID       [2]int
 	From, To *Node
 	Weight   float64
+
+	// Shape holds the intermediate points of the edge's geometry between
+	// From and To, e.g. the curvature of a road. Empty for a straight edge.
+	Shape []Point
 }
 
+// Point is a location given by its coordinates
+type Point = geom.Point
+
 type DirectedGraph struct {
 	Nodes []*Node
+
+	// Coordinates selects how node coordinates are interpreted by g.Dist,
+	// g.DistFromEdge and the A* heuristics
+	Coordinates CoordinateSystem
+
+	// HeuristicCheck, if set, cross-checks sampled A* queries against dijkstra
+	HeuristicCheck *HeuristicCheck
+
+	// Heap selects the priority queue of the shortest path searches, and
+	// HeapArity the number of children per node of a DaryHeap
+	Heap      HeapKind
+	HeapArity int
+
+	// Metrics, if set, receives the number of searches and the nodes
+	// expanded and queued by each of Dijkstra, AStar and their single
+	// direction variants, including DijkstraInto and AStarInto
+	Metrics metrics.Metrics
+
+	// Strict makes AddDirectedEdge, ZeroOneBFS, DialDijkstra and Partition
+	// panic on invalid input instead of returning an error, for callers that
+	// treat one as a programming error
+	Strict bool
+
+	// nodes and edges allocate those created by NewNode and NewEdge
+	nodes arena.Arena[Node]
+	edges arena.Arena[Edge]
+
+	scratch sync.Pool // search state of DijkstraInto and AStarInto
 }
 
 // NewDirectedGraph initialises an empty graph
@@ -37,13 +104,13 @@ func NewDirectedGraph() *DirectedGraph {
 
 // HasNode checks if node exists in a graph
 func (g *DirectedGraph) HasNode(n *Node) bool {
-	return n.ID < len(g.Nodes)
+	return n.ID >= 0 && n.ID < len(g.Nodes)
 }
 
 // Node returns the corresponding node, given an id
 // otherwise returns a nil pointer
 func (g *DirectedGraph) Node(id int) *Node {
-	if len(g.Nodes) > id {
+	if id >= 0 && len(g.Nodes) > id {
 		return g.Nodes[id]
 	}
 
@@ -51,7 +118,7 @@ func (g *DirectedGraph) Node(id int) *Node {
 }
 
 // HasEdge checks if edge exists in a graph
-func (g *DirectedGraph) Edge(id [2]int) bool {
+func (g *DirectedGraph) HasEdge(id [2]int) bool {
 	for _, e := range g.Nodes[id[0]].EdgeStart {
 		if e.ID == id {
 			return true
@@ -73,71 +140,130 @@ func (g *DirectedGraph) Edge(id [2]int) *Edge {
 	return nil
 }
 
-// AddNode adds node n to the graph
+// AddNode adds node n to the graph. Debug builds panic with ErrInconsistent
+// if n has edges to nodes outside the graph.
 func (g *DirectedGraph) AddNode(n *Node) {
 	n.ID = len(g.Nodes)
 	g.Nodes = append(g.Nodes, n)
+	if err := g.checkEdgeNodes(n, n); err != nil {
+		panic(err)
+	}
+}
+
+// NewNode adds a node at x, y to the graph and returns it. Unlike nodes
+// passed to AddNode, it is allocated together with others in a slab, which
+// for graphs of millions of nodes saves the garbage collector much of its
+// work.
+func (g *DirectedGraph) NewNode(x, y float64) *Node {
+	n := g.nodes.Alloc()
+	n.X, n.Y = x, y
+	g.AddNode(n)
+	return n
+}
+
+// NewEdge adds an edge of the given weight from node from to node to and
+// returns it, allocated in a slab like the nodes of NewNode. It fails as
+// AddDirectedEdge does.
+func (g *DirectedGraph) NewEdge(from, to *Node, weight float64) (*Edge, error) {
+	e := g.edges.Alloc()
+	*e = Edge{From: from, To: to, Weight: weight}
+	if err := g.AddDirectedEdge(e); err != nil {
+		g.edges.Free(e)
+		return nil, err
+	}
+	e.ID = [2]int{from.ID, to.ID}
+	return e, nil
+}
+
+// Release removes all nodes and edges from the graph at once, leaving
+// those allocated by NewNode and NewEdge to the garbage collector once they
+// are no longer referenced
+func (g *DirectedGraph) Release() {
+	g.Nodes = make([]*Node, 0)
+	g.nodes.Release()
+	g.edges.Release()
 }
 
 // RemoveNode removes n from the graph, as well as any edges attached to it.
 // If the node is not in the graph it is a no-op.
-func (g *DirectedGraph) RemoveNode(n graph.Node) {
+func (g *DirectedGraph) RemoveNode(n *Node) {
 	if !g.HasNode(n) {
 		return
 	}
 
-	for _, e := range n.EdgeStart {
+	// removal modifies the edge lists, so iterate over copies
+	for _, e := range append([]*Edge(nil), n.EdgeStart...) {
 		g.RemoveDirectedEdge(e)
 	}
 
-	for _, e := range n.EdgeEnd {
+	for _, e := range append([]*Edge(nil), n.EdgeEnd...) {
 		g.RemoveDirectedEdge(e)
 	}
 }
 
-// AddDirectedEdge adds directed edge e to the graph
-func (g *DirectedGraph) AddDirectedEdge(e *Edge) {
+// AddDirectedEdge adds directed edge e to the graph. It returns
+// ErrNodeNotFound if either end is not in the graph and ErrSelfEdge if both
+// ends are the same node, or panics with the error if g.Strict is set. Debug
+// builds check e and the edges of both ends for ErrInconsistent, and leave e
+// out of the graph if the check fails.
+func (g *DirectedGraph) AddDirectedEdge(e *Edge) error {
 	from, to := e.From, e.To
 
-	if from.ID == to.ID {
-		panic("Self edge detected.")
-	}
-
-	if !g.HasNode(from) {
-		panic("Unable to add edge. Node does not exist. ")
-		return
+	var err error
+	switch {
+	case from == nil || to == nil || !g.HasNode(from) || !g.HasNode(to):
+		err = ErrNodeNotFound
+	case from.ID == to.ID:
+		err = ErrSelfEdge
+	default:
+		if err = g.checkNewEdge(e); err != nil {
+			break
+		}
+		from.EdgeStart = append(from.EdgeStart, e)
+		to.EdgeEnd = append(to.EdgeEnd, e)
+		if err = g.checkEdgeNodes(from, to); err != nil {
+			// leave the graph as it was, so that the caller may discard e
+			from.EdgeStart = from.EdgeStart[:len(from.EdgeStart)-1]
+			to.EdgeEnd = to.EdgeEnd[:len(to.EdgeEnd)-1]
+		}
 	}
+	return g.check(err)
+}
 
-	if !g.HasNode(to) {
-		panic("Unable to add edge. Node does not exist. ")
-		return
+// check panics with err if it is set and the graph is strict, and returns it
+func (g *DirectedGraph) check(err error) error {
+	if err != nil && g.Strict {
+		panic(err)
 	}
-
-	g.Nodes[e.From.ID].EdgeStart = append(g.Nodes[e.From.ID].EdgeStart, e)
-	g.Nodes[e.To.ID].EdgeEnd = append(g.Nodes[e.To.ID].EdgeEnd, e)
+	return err
 }
 
 // RemoveEdge removes e from the graph, leaving the terminal nodes.
-// If the edge does not exist, it is a no-op.
-func (g *DirectedGraph) RemoveDirectedEdge(e graph.Edge) {
+// If the edge does not exist, it is a no-op. Debug builds panic with
+// ErrInconsistent if the edges of its ends are out of step afterwards.
+func (g *DirectedGraph) RemoveDirectedEdge(e *Edge) {
 	from, to := e.From, e.To
 	if !g.HasNode(from) || !g.HasNode(to) {
 		return
 	}
 
 	for i, n := range from.EdgeStart {
-		if n == to {
-			from.EdgeStart = append(from.EdgeStart[:i], from.EdgeStart[i:]...)
+		if n == e {
+			from.EdgeStart = append(from.EdgeStart[:i], from.EdgeStart[i+1:]...)
 			break
 		}
 	}
 
 	for i, n := range to.EdgeEnd {
-		if n == from {
-			to.EdgeEnd = append(to.EdgeEnd[:i], to.EdgeEnd[i:]...)
+		if n == e {
+			to.EdgeEnd = append(to.EdgeEnd[:i], to.EdgeEnd[i+1:]...)
 			break
 		}
 	}
+
+	if err := g.checkEdgeNodes(from, to); err != nil {
+		panic(err)
+	}
 }
 
 // Weight returns weight of directed edge from u to v
@@ -158,6 +284,27 @@ func (g *DirectedGraph) Weight(u, v *Node) (w float64, ok bool) {
 	return 0, false
 }
 
+// NeighborsFrom calls fn with the end node of every edge starting at u, and
+// the edge itself, until fn returns false. Unlike collecting the neighbours
+// into a slice, it does not allocate.
+func (g *DirectedGraph) NeighborsFrom(u *Node, fn func(v *Node, e *Edge) bool) {
+	for _, e := range u.EdgeStart {
+		if !fn(e.To, e) {
+			return
+		}
+	}
+}
+
+// NeighborsTo calls fn with the start node of every edge ending at u, and the
+// edge itself, until fn returns false.
+func (g *DirectedGraph) NeighborsTo(u *Node, fn func(v *Node, e *Edge) bool) {
+	for _, e := range u.EdgeEnd {
+		if !fn(e.From, e) {
+			return
+		}
+	}
+}
+
 // Dist returns the Euclidean distance between two nodes.
 func Dist(u, v *Node) float64 {
 	return math.Sqrt(SquaredDist(u, v))
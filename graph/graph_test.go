@@ -0,0 +1,110 @@
+package graph
+
+import "testing"
+
+func TestNeighbors(t *testing.T) {
+	g := newGraph(4, [][2]int{{0, 1}, {0, 2}, {0, 3}, {1, 0}, {2, 0}})
+
+	var from []*Node
+	g.NeighborsFrom(g.Nodes[0], func(v *Node, e *Edge) bool {
+		if e.From != g.Nodes[0] || e.To != v {
+			t.Fatalf("got edge %v for neighbour %d", e.ID, v.ID)
+		}
+		from = append(from, v)
+		return true
+	})
+	if len(from) != 3 || from[0] != g.Nodes[1] || from[1] != g.Nodes[2] || from[2] != g.Nodes[3] {
+		t.Fatalf("got %d neighbours from node 0, want 1, 2 and 3", len(from))
+	}
+
+	var to []*Node
+	g.NeighborsTo(g.Nodes[0], func(v *Node, e *Edge) bool {
+		if e.To != g.Nodes[0] || e.From != v {
+			t.Fatalf("got edge %v for neighbour %d", e.ID, v.ID)
+		}
+		to = append(to, v)
+		return true
+	})
+	if len(to) != 2 || to[0] != g.Nodes[1] || to[1] != g.Nodes[2] {
+		t.Fatalf("got %d neighbours to node 0, want 1 and 2", len(to))
+	}
+
+	// returning false stops the iteration
+	calls := 0
+	g.NeighborsFrom(g.Nodes[0], func(*Node, *Edge) bool {
+		calls++
+		return false
+	})
+	g.NeighborsTo(g.Nodes[0], func(*Node, *Edge) bool {
+		calls++
+		return false
+	})
+	if calls != 2 {
+		t.Fatalf("got %d calls, want 2", calls)
+	}
+}
+
+func TestAddDirectedEdge(t *testing.T) {
+	g := newGraph(2, nil)
+	other := &Node{ID: 5}
+
+	for _, tt := range []struct {
+		from, to *Node
+		want     error
+	}{
+		{g.Nodes[0], g.Nodes[1], nil},
+		{g.Nodes[0], other, ErrNodeNotFound},
+		{nil, g.Nodes[1], ErrNodeNotFound},
+		{g.Nodes[1], g.Nodes[1], ErrSelfEdge},
+	} {
+		if err := g.AddDirectedEdge(&Edge{From: tt.from, To: tt.to, Weight: 1}); err != tt.want {
+			t.Fatalf("got error %v, want %v", err, tt.want)
+		}
+	}
+	if g.EdgeCount() != 1 {
+		t.Fatalf("got %d edges, want 1", g.EdgeCount())
+	}
+
+	g.Strict = true
+	defer func() {
+		if r := recover(); r != ErrSelfEdge {
+			t.Fatalf("strict graph panicked with %v, want ErrSelfEdge", r)
+		}
+	}()
+	g.AddDirectedEdge(&Edge{From: g.Nodes[0], To: g.Nodes[0]})
+}
+
+func TestNewNodeEdge(t *testing.T) {
+	g := NewDirectedGraph()
+	for round := 0; round < 2; round++ {
+		for i := 0; i < 100; i++ {
+			if n := g.NewNode(float64(i), 1); n.ID != i || n.X != float64(i) || n.Y != 1 {
+				t.Fatalf("got node %d at (%v, %v), want %d at (%d, 1)", n.ID, n.X, n.Y, i, i)
+			}
+		}
+		for i := 1; i < 100; i++ {
+			e, err := g.NewEdge(g.Nodes[i-1], g.Nodes[i], 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.ID != [2]int{i - 1, i} {
+				t.Fatalf("got edge %v, want %v", e.ID, [2]int{i - 1, i})
+			}
+		}
+		if e, err := g.NewEdge(g.Nodes[0], g.Nodes[0], 1); e != nil || err != ErrSelfEdge {
+			t.Fatalf("self edge: got %v, %v, want ErrSelfEdge", e, err)
+		}
+		if _, d := g.Dijkstra(g.Nodes[0], g.Nodes[99]); d != 198 || g.EdgeCount() != 99 {
+			t.Fatalf("got %d edges and distance %v, want 99 and 198", g.EdgeCount(), d)
+		}
+
+		kept := g.Nodes[50]
+		g.Release()
+		if len(g.Nodes) != 0 {
+			t.Fatalf("got %d nodes after Release", len(g.Nodes))
+		}
+		if kept.X != 50 || len(kept.EdgeStart) != 1 {
+			t.Fatal("node held by the caller changed after Release")
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/hanyangtay/go-datastructures/fibheap"
+	"github.com/hanyangtay/go-datastructures/internal/debug"
+	"github.com/hanyangtay/go-datastructures/pairingheap"
+)
+
+// HeapKind selects the priority queue used by the single direction shortest
+// path searches: Dijkstra, AStar and their variants. All kinds return the
+// same distances; they differ in speed depending on the graph.
+type HeapKind int
+
+const (
+	// BinaryHeap is an array based binary heap, the default
+	BinaryHeap HeapKind = iota
+
+	// PairingHeap is a pairing heap, whose decrease-key is cheaper, which
+	// pays off on dense graphs where distances are lowered often
+	PairingHeap
+
+	// FibonacciHeap is a Fibonacci heap, with the best asymptotic bounds
+	// but high constant factors
+	FibonacciHeap
+
+	// DaryHeap is an array based heap in which every node has
+	// DirectedGraph.HeapArity children. Arities of 4 or 8 are often faster
+	// than a binary heap, as the heap is shallower and children share
+	// cache lines.
+	DaryHeap
+)
+
+// defaultHeapArity is the arity of DaryHeap if HeapArity is not set
+const defaultHeapArity = 4
+
+// nodeQueue is a priority queue of nodes in which pushing a node that is
+// already queued lowers its priority (decrease-key)
+type nodeQueue interface {
+	len() int
+	push(n *Node, p float64)
+	pop() *Node
+}
+
+// newNodeQueue returns an empty queue of the kind selected by g.Heap
+func (g *DirectedGraph) newNodeQueue() nodeQueue {
+	less := func(a, b float64) bool { return a < b }
+
+	switch g.Heap {
+	case PairingHeap:
+		return newHandleQueue[*pairingheap.Handle[*Node, float64]](pairingheap.New[*Node](less))
+	case FibonacciHeap:
+		return newHandleQueue[*fibheap.Handle[*Node, float64]](fibheap.New[*Node](less))
+	case DaryHeap:
+		if g.HeapArity < 2 {
+			return newNodeHeap(defaultHeapArity)
+		}
+		return newNodeHeap(g.HeapArity)
+	default:
+		return newNodeHeap(2)
+	}
+}
+
+// addressableHeap is a heap of nodes whose entries are addressed by handles
+// of type H
+type addressableHeap[H any] interface {
+	Len() int
+	Push(n *Node, p float64) H
+	Pop() H
+	DecreaseKey(h H, p float64)
+}
+
+// handleQueue is a nodeQueue backed by an addressable heap, which keeps the
+// handle of every queued node for decrease-key
+type handleQueue[H interface{ Value() *Node }] struct {
+	heap    addressableHeap[H]
+	handles map[*Node]H
+}
+
+func newHandleQueue[H interface{ Value() *Node }](heap addressableHeap[H]) *handleQueue[H] {
+	return &handleQueue[H]{heap: heap, handles: make(map[*Node]H)}
+}
+
+func (q *handleQueue[H]) len() int { return q.heap.Len() }
+
+func (q *handleQueue[H]) push(n *Node, p float64) {
+	if h, ok := q.handles[n]; ok {
+		q.heap.DecreaseKey(h, p)
+		return
+	}
+	q.handles[n] = q.heap.Push(n, p)
+}
+
+func (q *handleQueue[H]) pop() *Node {
+	n := q.heap.Pop().Value()
+	delete(q.handles, n)
+	return n
+}
+
+// checkHeap panics with an error wrapping ErrInconsistent, in debug builds,
+// unless prio is in the order of a heap with the given arity. A NaN, from an
+// edge weight or a heuristic, is the usual cause, as it compares false with
+// everything.
+func checkHeap(prio []float64, arity int) {
+	if !debug.Enabled {
+		return
+	}
+	for i := 1; i < len(prio); i++ {
+		if parent := (i - 1) / arity; !(prio[parent] <= prio[i]) {
+			panic(fmt.Errorf("%w: heap entry %d of priority %v is out of order with its parent %d of priority %v",
+				ErrInconsistent, i, prio[i], parent, prio[parent]))
+		}
+	}
+}
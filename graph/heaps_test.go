@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// heaps lists every kind of queue, with the default and another arity for
+// DaryHeap
+var heaps = []struct {
+	kind  HeapKind
+	arity int
+}{{BinaryHeap, 0}, {PairingHeap, 0}, {FibonacciHeap, 0}, {DaryHeap, 0}, {DaryHeap, 3}}
+
+func TestHeapKinds(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(200, rng)
+
+	for _, h := range heaps {
+		g.Heap, g.HeapArity = h.kind, h.arity
+		for i := 0; i < 5; i++ {
+			u := g.Nodes[rng.Intn(len(g.Nodes))]
+			dist := bellmanFord(g, u)
+			for _, v := range g.Nodes {
+				path, d := g.Dijkstra(u, v)
+				checkPath(t, g, u, v, path, d, dist[v.ID])
+				path, d = g.AStar(u, v)
+				checkPath(t, g, u, v, path, d, dist[v.ID])
+			}
+		}
+	}
+}
+
+// TestNodeQueue checks every kind of queue against a map of the queued
+// nodes, lowering the priority of queued nodes as the searches do
+func TestNodeQueue(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	nodes := make([]*Node, 50)
+	for i := range nodes {
+		nodes[i] = &Node{ID: i}
+	}
+
+	for _, h := range heaps {
+		g := &DirectedGraph{Heap: h.kind, HeapArity: h.arity}
+		q := g.newNodeQueue()
+		queued := make(map[*Node]float64)
+		for i := 0; i < 5000; i++ {
+			if rng.Intn(3) > 0 || len(queued) == 0 {
+				n, p := nodes[rng.Intn(len(nodes))], rng.Float64()
+				if old, ok := queued[n]; ok && old < p {
+					continue
+				}
+				q.push(n, p)
+				queued[n] = p
+			} else {
+				n := q.pop()
+				if _, ok := queued[n]; !ok {
+					t.Fatalf("kind %d, arity %d: popped node %d, which is not queued", h.kind, h.arity, n.ID)
+				}
+				for m, p := range queued {
+					if p < queued[n] {
+						t.Fatalf("kind %d, arity %d: popped node %d at %v before node %d at %v", h.kind, h.arity, n.ID, queued[n], m.ID, p)
+					}
+				}
+				delete(queued, n)
+			}
+
+			if q.len() != len(queued) {
+				t.Fatalf("kind %d, arity %d: got length %d, want %d", h.kind, h.arity, q.len(), len(queued))
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package graph
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+)
+
+// hubEntry is the distance between a node and one of its hubs
+type hubEntry struct {
+	hub  int32 // rank of the hub node
+	dist float64
+}
+
+// HubLabels is an exact distance oracle. Every node stores the distances from
+// a set of hub nodes that reach it (in labels) and to a set of hubs it
+// reaches (out labels), chosen so that every shortest path passes through a
+// hub common to the out label of its start and the in label of its end. A
+// distance query then merges two short sorted labels instead of searching
+// the graph.
+//
+// Labels are built by pruned landmark labeling: nodes are processed from the
+// highest degree down, each running a forward and a backward dijkstra search
+// that is pruned wherever the labels built so far already give the distance.
+type HubLabels struct {
+	in, out [][]hubEntry
+}
+
+// NewHubLabels computes hub labels for g. Preprocessing runs two pruned
+// searches per node, so it is costly on large graphs; the result can be
+// persisted with SaveArtifact.
+func NewHubLabels(g Graph) *HubLabels {
+	n := g.Order()
+	h := &HubLabels{
+		in:  make([][]hubEntry, n),
+		out: make([][]hubEntry, n),
+	}
+
+	// rank nodes by degree, highest first
+	degree := make([]int, n)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+		g.Successors(i, func(int, float64) bool { degree[i]++; return true })
+		g.Predecessors(i, func(int, float64) bool { degree[i]++; return true })
+	}
+	sort.SliceStable(order, func(i, j int) bool { return degree[order[i]] > degree[order[j]] })
+
+	dist := make([]float64, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+
+	// every search empties the queue, so it is shared between them
+	Q := newIDHeap(n)
+
+	for rank, v := range order {
+		h.prunedSearch(g, Q, v, int32(rank), dist, true)
+		h.prunedSearch(g, Q, v, int32(rank), dist, false)
+	}
+
+	return h
+}
+
+// prunedSearch runs a dijkstra search from v, forwards or backwards, adding v
+// with the given rank as hub to the in (forwards) or out (backwards) labels of
+// every node it settles. Nodes whose distance is already covered by existing
+// labels are not labelled or expanded. dist must be all +Inf and is restored
+// before returning.
+func (h *HubLabels) prunedSearch(g Graph, Q *idHeap, v int, rank int32, dist []float64, forward bool) {
+	touched := []int{v}
+	dist[v] = 0
+	Q.push(v, 0)
+
+	for Q.len() > 0 {
+		u := Q.pop()
+		d := dist[u]
+
+		if forward {
+			if distance(h.out[v], h.in[u]) <= d {
+				continue
+			}
+			h.in[u] = append(h.in[u], hubEntry{rank, d})
+		} else {
+			if distance(h.out[u], h.in[v]) <= d {
+				continue
+			}
+			h.out[u] = append(h.out[u], hubEntry{rank, d})
+		}
+
+		relax := func(n int, w float64) bool {
+			if acc_dist := d + w; acc_dist < dist[n] {
+				if math.IsInf(dist[n], 1) {
+					touched = append(touched, n)
+				}
+				dist[n] = acc_dist
+				Q.push(n, acc_dist)
+			}
+			return true
+		}
+
+		if forward {
+			g.Successors(u, relax)
+		} else {
+			g.Predecessors(u, relax)
+		}
+	}
+
+	for _, n := range touched {
+		dist[n] = math.Inf(1)
+	}
+}
+
+// Distance returns the shortest path distance from the node with ID u to the
+// node with ID v, or +Inf if there is no path.
+func (h *HubLabels) Distance(u, v int) float64 {
+	return distance(h.out[u], h.in[v])
+}
+
+// LabelSize returns the average number of hubs per label, which determines
+// both memory use and query time.
+func (h *HubLabels) LabelSize() float64 {
+	if len(h.in) == 0 {
+		return 0
+	}
+
+	total := 0
+	for i := range h.in {
+		total += len(h.in[i]) + len(h.out[i])
+	}
+	return float64(total) / float64(2*len(h.in))
+}
+
+// distance merges an out label and an in label, both sorted by hub rank, and
+// returns the lowest distance through a common hub
+func distance(out, in []hubEntry) float64 {
+	best := math.Inf(1)
+
+	for i, j := 0, 0; i < len(out) && j < len(in); {
+		switch {
+		case out[i].hub < in[j].hub:
+			i++
+		case out[i].hub > in[j].hub:
+			j++
+		default:
+			if d := out[i].dist + in[j].dist; d < best {
+				best = d
+			}
+			i++
+			j++
+		}
+	}
+
+	return best
+}
+
+// MarshalBinary encodes the labels, so that they can be stored with
+// SaveArtifact.
+func (h *HubLabels) MarshalBinary() ([]byte, error) {
+	buf := binary.AppendUvarint(nil, uint64(len(h.in)))
+
+	for _, labels := range [][][]hubEntry{h.in, h.out} {
+		for _, label := range labels {
+			buf = binary.AppendUvarint(buf, uint64(len(label)))
+			for _, e := range label {
+				buf = binary.AppendUvarint(buf, uint64(e.hub))
+				buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(e.dist))
+			}
+		}
+	}
+
+	return buf, nil
+}
+
+// UnmarshalBinary decodes labels encoded by MarshalBinary.
+func (h *HubLabels) UnmarshalBinary(data []byte) error {
+	errCorrupt := errors.New("graph: corrupt hub labels")
+
+	next := func() (uint64, bool) {
+		x, k := binary.Uvarint(data)
+		if k <= 0 {
+			return 0, false
+		}
+		data = data[k:]
+		return x, true
+	}
+
+	n, ok := next()
+	if !ok || n > uint64(len(data)) {
+		return errCorrupt
+	}
+
+	h.in = make([][]hubEntry, n)
+	h.out = make([][]hubEntry, n)
+
+	for _, labels := range [][][]hubEntry{h.in, h.out} {
+		for i := range labels {
+			size, ok := next()
+			if !ok || size > uint64(len(data)) {
+				return errCorrupt
+			}
+
+			label := make([]hubEntry, size)
+			for j := range label {
+				hub, ok := next()
+				if !ok || len(data) < 8 {
+					return errCorrupt
+				}
+				label[j] = hubEntry{int32(hub), math.Float64frombits(binary.LittleEndian.Uint64(data))}
+				data = data[8:]
+			}
+			labels[i] = label
+		}
+	}
+
+	if len(data) != 0 {
+		return errCorrupt
+	}
+	return nil
+}
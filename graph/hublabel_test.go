@@ -0,0 +1,42 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHubLabels(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(200, rng)
+	g.AddNode(&Node{})
+	h := NewHubLabels(g.Freeze())
+	if h.LabelSize() <= 0 {
+		t.Fatalf("got label size %v", h.LabelSize())
+	}
+
+	data, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded HubLabels
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("decoded truncated labels")
+	}
+
+	for i := 0; i < 10; i++ {
+		u := g.Nodes[rng.Intn(len(g.Nodes))]
+		dist := bellmanFord(g, u)
+		for _, v := range g.Nodes {
+			for _, labels := range []*HubLabels{h, &decoded} {
+				got, want := labels.Distance(u.ID, v.ID), dist[v.ID]
+				if got != want && math.Abs(got-want) > 1e-9 {
+					t.Fatalf("Distance(%d, %d) = %v, want %v", u.ID, v.ID, got, want)
+				}
+			}
+		}
+	}
+}
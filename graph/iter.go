@@ -0,0 +1,86 @@
+package graph
+
+// NodeIterator streams the nodes of a graph in ID order:
+//
+//	for it := g.NodesIter(); it.Next(); {
+//		n := it.Node()
+//		...
+//	}
+type NodeIterator struct {
+	g   *DirectedGraph
+	i   int
+	cur *Node
+}
+
+// NodesIter returns an iterator over the nodes of the graph
+func (g *DirectedGraph) NodesIter() *NodeIterator {
+	return &NodeIterator{g: g}
+}
+
+// Next advances to the next node, returning false once there are none left
+func (it *NodeIterator) Next() bool {
+	if it.i >= len(it.g.Nodes) {
+		it.cur = nil
+		return false
+	}
+
+	it.cur = it.g.Nodes[it.i]
+	it.i++
+	return true
+}
+
+// Node returns the current node
+func (it *NodeIterator) Node() *Node {
+	return it.cur
+}
+
+// EdgeIterator streams the edges of a graph, grouped by the node they start
+// from in ID order:
+//
+//	for it := g.EdgesIter(); it.Next(); {
+//		e := it.Edge()
+//		...
+//	}
+type EdgeIterator struct {
+	g    *DirectedGraph
+	node int // node whose edges are being visited
+	i    int // index of the next edge of node
+	cur  *Edge
+}
+
+// EdgesIter returns an iterator over the edges of the graph
+func (g *DirectedGraph) EdgesIter() *EdgeIterator {
+	return &EdgeIterator{g: g}
+}
+
+// Next advances to the next edge, returning false once there are none left
+func (it *EdgeIterator) Next() bool {
+	for it.node < len(it.g.Nodes) {
+		edges := it.g.Nodes[it.node].EdgeStart
+		if it.i < len(edges) {
+			it.cur = edges[it.i]
+			it.i++
+			return true
+		}
+
+		it.node++
+		it.i = 0
+	}
+
+	it.cur = nil
+	return false
+}
+
+// Edge returns the current edge
+func (it *EdgeIterator) Edge() *Edge {
+	return it.cur
+}
+
+// EdgeCount returns the number of edges in the graph
+func (g *DirectedGraph) EdgeCount() int {
+	count := 0
+	for _, n := range g.Nodes {
+		count += len(n.EdgeStart)
+	}
+	return count
+}
@@ -0,0 +1,34 @@
+package graph
+
+import "testing"
+
+func TestIterators(t *testing.T) {
+	g := NewGridGraph(4, 3, false, false)
+
+	i := 0
+	for it := g.NodesIter(); it.Next(); i++ {
+		if it.Node() != g.Nodes[i] {
+			t.Fatalf("node %d out of order", i)
+		}
+	}
+	if i != len(g.Nodes) {
+		t.Fatalf("iterated over %d nodes, want %d", i, len(g.Nodes))
+	}
+
+	var edges []*Edge
+	for it := g.EdgesIter(); it.Next(); {
+		e := it.Edge()
+		if n := len(edges); n > 0 && e.From.ID < edges[n-1].From.ID {
+			t.Fatalf("edge from %d after an edge from %d", e.From.ID, edges[n-1].From.ID)
+		}
+		edges = append(edges, e)
+	}
+	if want := 2 * (3*3 + 4*2); len(edges) != want || g.EdgeCount() != want {
+		t.Fatalf("iterated over %d edges and counted %d, want %d", len(edges), g.EdgeCount(), want)
+	}
+
+	empty := NewDirectedGraph()
+	if empty.NodesIter().Next() || empty.EdgesIter().Next() || empty.EdgeCount() != 0 {
+		t.Fatal("empty graph has nodes or edges")
+	}
+}
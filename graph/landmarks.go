@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"math"
+)
+
+// Landmarks holds the distances between every node and a few landmark nodes,
+// from which the ALT variant of A* (A*, landmarks, triangle inequality)
+// derives a lower bound on the distance left to the target. Unlike the
+// euclidean heuristic of AStar, the bound needs no coordinates and stays
+// tight on graphs whose weights are not distances, e.g. travel times.
+type Landmarks struct {
+	// IDs are the landmark nodes
+	IDs []int
+
+	// From[i][n] is the distance from landmark i to node n, and To[i][n]
+	// the distance from node n to landmark i, +Inf if there is no path
+	From, To [][]float64
+}
+
+// NewLandmarks selects up to k landmarks in g and computes their distances
+// to and from every node. Each landmark is the node furthest from those
+// already selected, or one they do not reach, which spreads them towards the
+// edges of the graph where they give the best bounds. Preprocessing runs two
+// dijkstra searches per landmark; the result can be persisted with
+// SaveArtifact.
+func (g *DirectedGraph) NewLandmarks(k int) *Landmarks {
+	l := &Landmarks{}
+	if len(g.Nodes) == 0 {
+		return l
+	}
+
+	// closest holds the distance of every node from the nearest landmark,
+	// starting from an arbitrary node, which is not itself a landmark
+	closest := g.distancesFrom(g.Nodes[0], false)
+
+	for len(l.IDs) < k {
+		next, furthest := -1, 0.0
+		for id, d := range closest {
+			if d > furthest {
+				next, furthest = id, d
+			}
+		}
+		if next < 0 {
+			// no node is left at a positive distance
+			break
+		}
+
+		from := g.distancesFrom(g.Nodes[next], false)
+		l.IDs = append(l.IDs, next)
+		l.From = append(l.From, from)
+		l.To = append(l.To, g.distancesFrom(g.Nodes[next], true))
+
+		for id, d := range from {
+			closest[id] = math.Min(closest[id], d)
+		}
+	}
+
+	return l
+}
+
+// Heuristic returns a lower bound on the distance from n to target, which
+// can be passed to WithHeuristic. The bound is the largest one given by the
+// triangle inequality over the landmarks, and is +Inf if some landmark
+// proves there is no path.
+func (l *Landmarks) Heuristic(n, target *Node) float64 {
+	best := 0.0
+
+	for i := range l.IDs {
+		// d(L, target) <= d(L, n) + d(n, target)
+		if b := l.From[i][target.ID] - l.From[i][n.ID]; b > best {
+			best = b
+		}
+		// d(n, L) <= d(n, target) + d(target, L)
+		if b := l.To[i][n.ID] - l.To[i][target.ID]; b > best {
+			best = b
+		}
+	}
+
+	return best
+}
+
+// ShortestPath returns a shortest path from u to v in g and its distance,
+// +Inf if there is no path, using an A* search guided by l.Heuristic. l must
+// have been computed for g.
+func (l *Landmarks) ShortestPath(g *DirectedGraph, u, v *Node) ([]*Node, float64) {
+	path, dist, _ := g.shortestPath(u, v, searchOptions{
+		heuristic: func(n *Node) float64 { return l.Heuristic(n, v) },
+	})
+	return path, dist
+}
+
+// distancesFrom returns the distance from src to every node of g, indexed by
+// node ID, or the distance from every node to src if reverse is set. Nodes
+// with no path are at +Inf.
+func (g *DirectedGraph) distancesFrom(src *Node, reverse bool) []float64 {
+	dist := make([]float64, len(g.Nodes))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	dist[src.ID] = 0
+
+	Q := newDistanceQueue()
+	Q.Push(&distanceNode{node: src, dist: 0})
+
+	for Q.Len() > 0 {
+		mid := Q.Pop()
+		if mid.dist > dist[mid.node.ID] {
+			// superseded by a shorter distance
+			continue
+		}
+
+		edges := mid.node.EdgeStart
+		if reverse {
+			edges = mid.node.EdgeEnd
+		}
+
+		for _, e := range edges {
+			n := e.To
+			if reverse {
+				n = e.From
+			}
+
+			if acc_dist := mid.dist + e.Weight; acc_dist < dist[n.ID] {
+				dist[n.ID] = acc_dist
+				Q.Push(&distanceNode{node: n, dist: acc_dist})
+			}
+		}
+	}
+
+	return dist
+}
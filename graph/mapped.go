@@ -0,0 +1,211 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// Layout of a frozen graph file, all values little endian:
+//
+//	magic    [8]byte   "GDSCSR\x00\x01"
+//	n, m     uint64    number of nodes and edges
+//	X, Y     [n]float64
+//	offsets  [n+1]uint32, targets [m]uint32, weights [m]float64
+//	rOffsets [n+1]uint32, sources [m]uint32, rWeights [m]float64
+//
+// The sections match the fields of CSRGraph, so a mapped file can be queried
+// in place without decoding it first.
+const csrMagic = "GDSCSR\x00\x01"
+
+// csrHeaderSize is the size of the magic and counts preceding the sections
+const csrHeaderSize = 8 + 2*8
+
+// ErrBadGraphFile is returned when opening a file that is not a valid frozen
+// graph file.
+var ErrBadGraphFile = errors.New("graph: not a valid frozen graph file")
+
+// WriteTo writes c to w in the frozen graph file layout read by OpenMapped.
+func (c *CSRGraph) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var written int64
+	var buf [8]byte
+
+	put := func(b []byte) {
+		n, _ := bw.Write(b)
+		written += int64(n)
+	}
+	put64 := func(x uint64) {
+		binary.LittleEndian.PutUint64(buf[:], x)
+		put(buf[:8])
+	}
+	put32 := func(x uint32) {
+		binary.LittleEndian.PutUint32(buf[:], x)
+		put(buf[:4])
+	}
+	putFloats := func(xs []float64) {
+		for _, x := range xs {
+			put64(math.Float64bits(x))
+		}
+	}
+	putInts := func(xs []int32) {
+		for _, x := range xs {
+			put32(uint32(x))
+		}
+	}
+
+	put([]byte(csrMagic))
+	put64(uint64(c.Order()))
+	put64(uint64(c.Size()))
+	putFloats(c.X)
+	putFloats(c.Y)
+	putInts(c.offsets)
+	putInts(c.targets)
+	putFloats(c.weights)
+	putInts(c.rOffsets)
+	putInts(c.sources)
+	putFloats(c.rWeights)
+
+	return written, bw.Flush()
+}
+
+// MappedGraph is a read-only graph backed by a frozen graph file mapped into
+// memory. Queries read the file contents in place, so nothing is decoded,
+// and processes mapping the same file share a single copy of it through the
+// page cache. Opening a graph validates its index in a single pass over the
+// offsets and node IDs, in O(n+m) time. A MappedGraph implements Graph.
+type MappedGraph struct {
+	data  []byte
+	n, m  int
+	unmap func() error
+
+	// byte offsets of the sections within data
+	x, y, offsets, targets, weights, rOffsets, sources, rWeights int
+}
+
+// OpenMapped maps the frozen graph file at path, as written by
+// CSRGraph.WriteTo, into memory. The graph must be closed once it is no
+// longer used.
+func OpenMapped(path string) (*MappedGraph, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := newMappedGraph(data)
+	if err != nil {
+		unmap()
+		return nil, err
+	}
+
+	g.unmap = unmap
+	return g, nil
+}
+
+// newMappedGraph checks the layout of data and locates its sections
+func newMappedGraph(data []byte) (*MappedGraph, error) {
+	if len(data) < csrHeaderSize || string(data[:8]) != csrMagic {
+		return nil, ErrBadGraphFile
+	}
+
+	n64 := binary.LittleEndian.Uint64(data[8:])
+	m64 := binary.LittleEndian.Uint64(data[16:])
+	if n64 >= math.MaxInt32 || m64 >= math.MaxInt32 {
+		return nil, ErrBadGraphFile
+	}
+
+	g := &MappedGraph{data: data, n: int(n64), m: int(m64)}
+	n, m := g.n, g.m
+
+	g.x = csrHeaderSize
+	g.y = g.x + 8*n
+	g.offsets = g.y + 8*n
+	g.targets = g.offsets + 4*(n+1)
+	g.weights = g.targets + 4*m
+	g.rOffsets = g.weights + 8*m
+	g.sources = g.rOffsets + 4*(n+1)
+	g.rWeights = g.sources + 4*m
+
+	if len(data) != g.rWeights+8*m {
+		return nil, ErrBadGraphFile
+	}
+
+	if !g.validIndex(g.offsets, g.targets) || !g.validIndex(g.rOffsets, g.sources) {
+		return nil, ErrBadGraphFile
+	}
+
+	return g, nil
+}
+
+// validIndex reports whether the offsets section at byte base offsets runs
+// from 0 to m without decreasing, and every node ID in the section at byte
+// base ids is below n, so that queries stay within the file
+func (g *MappedGraph) validIndex(offsets, ids int) bool {
+	if g.offset(offsets, 0) != 0 || g.offset(offsets, g.n) != g.m {
+		return false
+	}
+	for i := 0; i < g.n; i++ {
+		if g.offset(offsets, i) > g.offset(offsets, i+1) {
+			return false
+		}
+	}
+	for i := 0; i < g.m; i++ {
+		if g.offset(ids, i) >= g.n {
+			return false
+		}
+	}
+	return true
+}
+
+// Close unmaps the file. The graph must not be used afterwards.
+func (g *MappedGraph) Close() error {
+	g.data = nil
+	return g.unmap()
+}
+
+// Order returns the number of nodes in the graph
+func (g *MappedGraph) Order() int {
+	return g.n
+}
+
+// Size returns the number of edges in the graph
+func (g *MappedGraph) Size() int {
+	return g.m
+}
+
+// Coordinates returns the coordinates of node id
+func (g *MappedGraph) Coordinates(id int) (x, y float64) {
+	return g.float(g.x, id), g.float(g.y, id)
+}
+
+// Successors calls fn for every edge starting at node id until fn returns
+// false
+func (g *MappedGraph) Successors(id int, fn func(to int, weight float64) bool) {
+	for i := g.offset(g.offsets, id); i < g.offset(g.offsets, id+1); i++ {
+		if !fn(g.offset(g.targets, i), g.float(g.weights, i)) {
+			return
+		}
+	}
+}
+
+// Predecessors calls fn for every edge ending at node id until fn returns
+// false
+func (g *MappedGraph) Predecessors(id int, fn func(from int, weight float64) bool) {
+	for i := g.offset(g.rOffsets, id); i < g.offset(g.rOffsets, id+1); i++ {
+		if !fn(g.offset(g.sources, i), g.float(g.rWeights, i)) {
+			return
+		}
+	}
+}
+
+// offset returns entry i of the uint32 section starting at byte base
+func (g *MappedGraph) offset(base, i int) int {
+	return int(binary.LittleEndian.Uint32(g.data[base+4*i:]))
+}
+
+// float returns entry i of the float64 section starting at byte base
+func (g *MappedGraph) float(base, i int) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(g.data[base+8*i:]))
+}
@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMapped(t *testing.T) {
+	g := newRandomDirectedGraph(200, rand.New(rand.NewSource(1)))
+	path := filepath.Join(t.TempDir(), "graph.csr")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Freeze().WriteTo(f); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := OpenMapped(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	checkView(t, g, m)
+	for _, n := range g.Nodes {
+		if x, y := m.Coordinates(n.ID); x != n.X || y != n.Y {
+			t.Fatalf("node %d: got coordinates (%v, %v), want (%v, %v)", n.ID, x, y, n.X, n.Y)
+		}
+	}
+	if _, d := DijkstraIDs(m, 0, 1); d != bellmanFord(g, g.Nodes[0])[1] {
+		t.Fatalf("got distance %v on the mapped graph", d)
+	}
+}
+
+func TestMappedInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	newGraph(3, [][2]int{{0, 1}, {1, 2}}).Freeze().WriteTo(&buf)
+	data := buf.Bytes()
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[csrHeaderSize+6*8+4] = 200 // second offset of the forward index
+
+	for name, bad := range map[string][]byte{
+		"empty":     nil,
+		"magic":     append([]byte("GDSCSR\x00\x02"), data[8:]...),
+		"truncated": data[:len(data)-1],
+		"offsets":   corrupt,
+	} {
+		if _, err := newMappedGraph(bad); !errors.Is(err, ErrBadGraphFile) {
+			t.Errorf("%s: got %v, want ErrBadGraphFile", name, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "bad.csr")
+	if err := os.WriteFile(path, data[:10], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenMapped(path); !errors.Is(err, ErrBadGraphFile) {
+		t.Fatalf("got %v, want ErrBadGraphFile", err)
+	}
+}
@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"math"
+)
+
+// Merge returns a new graph combining the nodes and edges of a and b, e.g.
+// two adjacent regional extracts. matchNodes returns a key identifying each
+// node, such as an external ID or its rounded coordinates; nodes with equal
+// keys become a single node of the merged graph, and edges are remapped onto
+// the merged nodes. Nodes whose key is nil are never merged. If matchNodes is
+// nil, the graphs are simply placed side by side.
+//
+// The nodes of a keep their IDs if none of them share a key. Merged nodes
+// take their coordinates and cost from the first node with the key. Edges of
+// b joining nodes which are already joined in the same direction are
+// dropped, as are edges whose endpoints were merged into one node.
+// The input graphs are not modified.
+func Merge(a, b *DirectedGraph, matchNodes func(n *Node) interface{}) *DirectedGraph {
+	g := NewDirectedGraph()
+	g.Coordinates = a.Coordinates
+
+	byKey := make(map[interface{}]*Node)
+	mapped := make(map[*Node]*Node, len(a.Nodes)+len(b.Nodes))
+
+	for _, src := range []*DirectedGraph{a, b} {
+		for _, n := range src.Nodes {
+			var key interface{}
+			if matchNodes != nil {
+				key = matchNodes(n)
+			}
+
+			if m, ok := byKey[key]; ok && key != nil {
+				mapped[n] = m
+				continue
+			}
+
+			m := &Node{X: n.X, Y: n.Y, Cost: n.Cost}
+			g.AddNode(m)
+			mapped[n] = m
+
+			if key != nil {
+				byKey[key] = m
+			}
+		}
+	}
+
+	for _, src := range []*DirectedGraph{a, b} {
+		for _, n := range src.Nodes {
+			for _, e := range n.EdgeStart {
+				from, to := mapped[e.From], mapped[e.To]
+
+				// endpoints collapsed into one node
+				if from == to {
+					continue
+				}
+
+				if src == b && lightestEdge(from, to) != nil {
+					continue
+				}
+
+				g.AddDirectedEdge(&Edge{
+					ID:     [2]int{from.ID, to.ID},
+					From:   from,
+					To:     to,
+					Weight: e.Weight,
+					Shape:  append([]Point(nil), e.Shape...),
+				})
+			}
+		}
+	}
+
+	return g
+}
+
+// MatchByCoordinates returns a node matcher for Merge that identifies nodes
+// by their coordinates rounded to multiples of precision. Nodes closer than
+// precision may still fall either side of a rounding boundary and stay
+// separate.
+func MatchByCoordinates(precision float64) func(n *Node) interface{} {
+	return func(n *Node) interface{} {
+		return [2]float64{
+			math.Round(n.X / precision),
+			math.Round(n.Y / precision),
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package graph
+
+import "testing"
+
+func TestMerge(t *testing.T) {
+	// two 3x3 grids sharing the column at X = 2
+	a := NewGridGraph(3, 3, false, false)
+	b := NewGridGraph(3, 3, false, false)
+	for _, n := range b.Nodes {
+		n.X += 2
+	}
+	for _, e := range b.Nodes[0].EdgeStart {
+		e.Weight = 5
+	}
+
+	g := Merge(a, b, MatchByCoordinates(0.01))
+	if len(g.Nodes) != 15 {
+		t.Fatalf("got %d nodes, want 15", len(g.Nodes))
+	}
+	for i, n := range a.Nodes {
+		if g.Nodes[i].X != n.X || g.Nodes[i].Y != n.Y {
+			t.Fatalf("node %d moved to (%v, %v)", i, g.Nodes[i].X, g.Nodes[i].Y)
+		}
+	}
+
+	// each grid has 24 edges, and the 4 along the shared column are only
+	// kept from a
+	edges := 0
+	for _, n := range g.Nodes {
+		edges += len(n.EdgeStart)
+	}
+	if edges != 44 {
+		t.Fatalf("got %d edges, want 44", edges)
+	}
+	if w, ok := g.Weight(g.Nodes[2], g.Nodes[5]); !ok || w != 1 {
+		t.Fatalf("got weight %v, %t along the shared column, want 1", w, ok)
+	}
+	if _, d := g.Dijkstra(g.Nodes[0], g.Nodes[len(g.Nodes)-1]); d != 6 {
+		t.Fatalf("got distance %v across the merged grids, want 6", d)
+	}
+
+	// a and b are not modified
+	if len(a.Nodes) != 9 || len(b.Nodes) != 9 || len(a.Nodes[2].EdgeStart) != 2 {
+		t.Fatal("Merge modified its inputs")
+	}
+
+	g = Merge(a, b, nil)
+	if len(g.Nodes) != 18 {
+		t.Fatalf("got %d nodes side by side, want 18", len(g.Nodes))
+	}
+	if _, d := g.Dijkstra(g.Nodes[0], g.Nodes[9]); d <= 6 {
+		t.Fatalf("found a path of %v between graphs placed side by side", d)
+	}
+}
+
+func TestMergeCollapsed(t *testing.T) {
+	a := newGraph(2, [][2]int{{0, 1}, {1, 0}})
+	a.Nodes[1].X = 0.001
+
+	g := Merge(a, NewDirectedGraph(), MatchByCoordinates(0.1))
+	if len(g.Nodes) != 1 || len(g.Nodes[0].EdgeStart) != 0 {
+		t.Fatalf("got %d nodes and %d edges, want the edge between merged nodes dropped", len(g.Nodes), len(g.Nodes[0].EdgeStart))
+	}
+}
@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"context"
+)
+
+// Option configures a search run by ShortestPath. Options combine freely,
+// where the named variants such as AStarAvoid or DijkstraCtx each cover one
+// combination.
+type Option func(o *searchOptions)
+
+// WithContext makes the search give up once ctx is cancelled or its
+// deadline passes, returning ctx.Err()
+func WithContext(ctx context.Context) Option {
+	return func(o *searchOptions) { o.ctx = ctx }
+}
+
+// WithHeuristic turns the search into A*, guided by h, which estimates the
+// distance from n to the target. It must never overestimate it for the path
+// found to be a shortest one. The heuristic of AStar is g.Dist.
+func WithHeuristic(h func(n, target *Node) float64) Option {
+	return func(o *searchOptions) { o.targetHeuristic = h }
+}
+
+// WithNodeCosts adds the Cost of every intermediate node on the path to the
+// distance, and those of the source and target too if endpoints is set, as
+// DijkstraNodeWeighted does
+func WithNodeCosts(endpoints bool) Option {
+	return func(o *searchOptions) {
+		o.nodeCosts = true
+		o.endpointCosts = endpoints
+	}
+}
+
+// WithAvoid excludes the nodes and edges listed in avoid from the search, as
+// DijkstraAvoid does
+func WithAvoid(avoid Avoid) Option {
+	return func(o *searchOptions) { o.avoid = &avoid }
+}
+
+// WithWeight makes the search weigh every edge by weight(e) instead of
+// e.Weight, e.g. travel times computed from lengths and speed limits. The
+// weights must not be negative.
+func WithWeight(weight func(e *Edge) float64) Option {
+	return func(o *searchOptions) { o.weight = weight }
+}
+
+// WithCutoff stops the search at distance cutoff: paths that are longer are
+// not explored, and if the target is further away none is returned. A
+// cutoff bounds the time spent on far or unreachable targets.
+func WithCutoff(cutoff float64) Option {
+	return func(o *searchOptions) {
+		o.limited = true
+		o.cutoff = cutoff
+	}
+}
+
+// ShortestPath returns a shortest path from u to v and its distance, using
+// a dijkstra search configured by opts. The distance is +Inf if there is no
+// path. The error is only set by a context given WithContext, and the
+// search queue is the one selected by g.Heap.
+func (g *DirectedGraph) ShortestPath(u, v *Node, opts ...Option) ([]*Node, float64, error) {
+	var o searchOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if h := o.targetHeuristic; h != nil {
+		o.heuristic = func(n *Node) float64 { return h(n, v) }
+	}
+	return g.shortestPath(u, v, o)
+}
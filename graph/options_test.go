@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestShortestPathOptions(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(200, rng)
+	for _, n := range g.Nodes {
+		n.Cost = rng.Float64()
+	}
+	avoid := Avoid{Nodes: map[*Node]bool{}, Edges: map[*Edge]bool{}}
+	for i := 0; i < 20; i++ {
+		n := g.Nodes[rng.Intn(len(g.Nodes))]
+		avoid.Nodes[n] = true
+		if len(n.EdgeStart) > 0 {
+			avoid.Edges[n.EdgeStart[0]] = true
+		}
+	}
+	double := func(e *Edge) float64 { return 2 * e.Weight }
+
+	for i := 0; i < 5; i++ {
+		u := g.Nodes[rng.Intn(len(g.Nodes))]
+		dist := bellmanFord(g, u)
+		for _, v := range g.Nodes {
+			path, d, err := g.ShortestPath(u, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPath(t, g, u, v, path, d, dist[v.ID])
+
+			path, d, _ = g.ShortestPath(u, v, WithHeuristic(g.Dist))
+			checkPath(t, g, u, v, path, d, dist[v.ID])
+
+			if _, d, _ = g.ShortestPath(u, v, WithWeight(double)); d != 2*dist[v.ID] && math.Abs(d-2*dist[v.ID]) > 1e-9 {
+				t.Fatalf("%d -> %d: got doubled distance %v, want %v", u.ID, v.ID, d, 2*dist[v.ID])
+			}
+
+			// below the cutoff the search is unchanged, above it finds nothing
+			path, d, _ = g.ShortestPath(u, v, WithCutoff(50))
+			if want := dist[v.ID]; want <= 50 {
+				checkPath(t, g, u, v, path, d, want)
+			} else if path != nil || !math.IsInf(d, 1) {
+				t.Fatalf("%d -> %d: got distance %v beyond cutoff 50", u.ID, v.ID, d)
+			}
+
+			// options combine with the named variants they generalise
+			_, want := g.DijkstraNodeWeighted(u, v, true)
+			if _, d, _ = g.ShortestPath(u, v, WithNodeCosts(true), WithHeuristic(g.Dist)); d != want && math.Abs(d-want) > 1e-9 {
+				t.Fatalf("%d -> %d: got distance %v with node costs, want %v", u.ID, v.ID, d, want)
+			}
+			_, want = g.DijkstraAvoid(u, v, avoid)
+			if _, d, _ = g.ShortestPath(u, v, WithAvoid(avoid), WithWeight(double)); d != 2*want && math.Abs(d-2*want) > 1e-9 {
+				t.Fatalf("%d -> %d: got doubled distance %v avoiding nodes, want %v", u.ID, v.ID, d, 2*want)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := g.ShortestPath(g.Nodes[0], g.Nodes[1], WithContext(ctx)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+}
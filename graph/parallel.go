@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// frontierChunk is the number of frontier nodes a worker claims at a time
+const frontierChunk = 64
+
+// ParallelBFS computes the hop distance from `from` to every node with a
+// level-synchronous breadth first search spread over the given number of
+// goroutines. Workers claim nodes through a shared atomic visited bitset and
+// collect the next level in their own frontiers, which are joined between
+// levels. The result is indexed by node ID, with -1 for unreachable nodes.
+// If workers < 1, runtime.GOMAXPROCS(0) workers are used.
+func (g *DirectedGraph) ParallelBFS(from *Node, workers int) []int {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	levels := make([]int, len(g.Nodes))
+	for i := range levels {
+		levels[i] = -1
+	}
+
+	visited := make([]uint64, (len(g.Nodes)+63)/64)
+	claim := func(id int) bool {
+		word, bit := &visited[id/64], uint64(1)<<uint(id%64)
+		for {
+			old := atomic.LoadUint64(word)
+			if old&bit != 0 {
+				return false
+			}
+			if atomic.CompareAndSwapUint64(word, old, old|bit) {
+				return true
+			}
+		}
+	}
+
+	claim(from.ID)
+	levels[from.ID] = 0
+	frontier := []*Node{from}
+	next := make([][]*Node, workers)
+
+	for level := 1; len(frontier) > 0; level++ {
+		var wg sync.WaitGroup
+		var cursor int64
+
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				local := next[w][:0]
+
+				for {
+					start := int(atomic.AddInt64(&cursor, frontierChunk)) - frontierChunk
+					if start >= len(frontier) {
+						break
+					}
+					end := start + frontierChunk
+					if end > len(frontier) {
+						end = len(frontier)
+					}
+
+					for _, u := range frontier[start:end] {
+						for _, e := range u.EdgeStart {
+							if claim(e.To.ID) {
+								levels[e.To.ID] = level
+								local = append(local, e.To)
+							}
+						}
+					}
+				}
+
+				next[w] = local
+			}(w)
+		}
+		wg.Wait()
+
+		// join the per-worker frontiers into the next level
+		frontier = frontier[:0]
+		for _, local := range next {
+			frontier = append(frontier, local...)
+		}
+	}
+
+	return levels
+}
@@ -0,0 +1,29 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParallelBFS(t *testing.T) {
+	g := NewRandomGeometricGraph(2000, 100, 100, 3, rand.New(rand.NewSource(1)))
+	// add a one-way edge, so that edge directions matter
+	g.AddDirectedEdge(&Edge{ID: [2]int{0, 1}, From: g.Nodes[0], To: g.Nodes[1], Weight: 1})
+
+	hops, _ := g.BFSFrom(g.Nodes[0])
+	for _, workers := range []int{0, 1, 3, 8} {
+		levels := g.ParallelBFS(g.Nodes[0], workers)
+		if len(levels) != len(g.Nodes) {
+			t.Fatalf("got %d levels, want %d", len(levels), len(g.Nodes))
+		}
+		for _, n := range g.Nodes {
+			want, ok := hops[n]
+			if !ok {
+				want = -1
+			}
+			if levels[n.ID] != want {
+				t.Fatalf("%d workers: node %d at level %d, want %d", workers, n.ID, levels[n.ID], want)
+			}
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package graph
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hanyangtay/go-datastructures/pq"
+)
+
+// maxRefinePasses bounds the number of refinement passes per bisection
+const maxRefinePasses = 8
+
+// Partition splits the nodes of g into k parts of nearly equal size, joined
+// by as few edges as possible, and returns the part of every node by ID.
+// Edges are counted in either direction and regardless of weight.
+//
+// The graph is bisected recursively: each set of nodes is split across its
+// principal axis (inertial bisection), which yields compact parts for graphs
+// embedded in the plane, and the split is then refined by Kernighan-Lin
+// swaps that reduce the number of edges cut. Part sizes differ by at most
+// one node per level of recursion.
+//
+// It returns ErrBadPartCount if k is less than one, or panics with it if
+// g.Strict is set.
+func (g *DirectedGraph) Partition(k int) ([]int, error) {
+	if k < 1 {
+		return nil, g.check(ErrBadPartCount)
+	}
+
+	parts := make([]int, len(g.Nodes))
+	ids := make([]int, len(g.Nodes))
+	for i := range ids {
+		ids[i] = i
+	}
+
+	// side of every node in the current bisection, -1 outside of it
+	side := make([]int8, len(g.Nodes))
+	for i := range side {
+		side[i] = -1
+	}
+
+	g.bisect(ids, 0, k, parts, side)
+	return parts, nil
+}
+
+// EdgeCut returns the number of edges joining nodes in different parts of a
+// partition, as returned by Partition.
+func (g *DirectedGraph) EdgeCut(parts []int) int {
+	cut := 0
+	for _, n := range g.Nodes {
+		for _, e := range n.EdgeStart {
+			if parts[e.From.ID] != parts[e.To.ID] {
+				cut++
+			}
+		}
+	}
+	return cut
+}
+
+// bisect assigns the nodes in ids to the k parts numbered from first,
+// splitting them in proportion to the number of parts on either side
+func (g *DirectedGraph) bisect(ids []int, first, k int, parts []int, side []int8) {
+	if k == 1 || len(ids) <= 1 {
+		for _, id := range ids {
+			parts[id] = first
+		}
+		return
+	}
+
+	left := k / 2
+	split := len(ids) * left / k
+
+	g.sortByPrincipalAxis(ids)
+	g.refine(ids, split, side)
+
+	g.bisect(ids[:split], first, left, parts, side)
+	g.bisect(ids[split:], first+left, k-left, parts, side)
+}
+
+// sortByPrincipalAxis sorts the nodes in ids by their projection onto the
+// axis along which their coordinates spread the most
+func (g *DirectedGraph) sortByPrincipalAxis(ids []int) {
+	var mx, my float64
+	for _, id := range ids {
+		mx += g.Nodes[id].X
+		my += g.Nodes[id].Y
+	}
+	mx /= float64(len(ids))
+	my /= float64(len(ids))
+
+	var sxx, syy, sxy float64
+	for _, id := range ids {
+		dx, dy := g.Nodes[id].X-mx, g.Nodes[id].Y-my
+		sxx += dx * dx
+		syy += dy * dy
+		sxy += dx * dy
+	}
+
+	// direction of the largest eigenvector of the covariance matrix
+	angle := math.Atan2(2*sxy, sxx-syy) / 2
+	ax, ay := math.Cos(angle), math.Sin(angle)
+
+	proj := make(map[int]float64, len(ids))
+	for _, id := range ids {
+		proj[id] = g.Nodes[id].X*ax + g.Nodes[id].Y*ay
+	}
+	sort.SliceStable(ids, func(i, j int) bool { return proj[ids[i]] < proj[ids[j]] })
+}
+
+// gainEntry is a candidate move in a refinement pass. Entries are not updated
+// when a gain changes; outdated ones are skipped when popped.
+type gainEntry struct {
+	id   int
+	gain int
+}
+
+// refine improves the bisection of ids into ids[:split] and ids[split:],
+// and reorders ids accordingly. Each pass swaps pairs of nodes between the
+// halves in order of gain, the reduction in edges cut, even if a swap makes
+// the cut worse, then keeps the best prefix of swaps found. Edges leaving
+// ids are ignored. side is restored to -1 for all of ids before returning.
+func (g *DirectedGraph) refine(ids []int, split int, side []int8) {
+	for i, id := range ids {
+		if i < split {
+			side[id] = 0
+		} else {
+			side[id] = 1
+		}
+	}
+
+	gain := make(map[int]int, len(ids))
+
+	// each edge adds to the gain of its endpoints if it crosses the cut,
+	// and subtracts from it otherwise
+	forEdge := func(id int, fn func(other int)) {
+		n := g.Nodes[id]
+		for _, e := range n.EdgeStart {
+			if side[e.To.ID] >= 0 {
+				fn(e.To.ID)
+			}
+		}
+		for _, e := range n.EdgeEnd {
+			if side[e.From.ID] >= 0 {
+				fn(e.From.ID)
+			}
+		}
+	}
+
+	for pass := 0; pass < maxRefinePasses; pass++ {
+		byGain := func(a, b gainEntry) bool { return a.gain > b.gain }
+		queues := [2]*pq.PriorityQueue[gainEntry]{pq.New(byGain), pq.New(byGain)}
+		for _, id := range ids {
+			gain[id] = 0
+			forEdge(id, func(other int) {
+				if side[other] != side[id] {
+					gain[id]++
+				} else {
+					gain[id]--
+				}
+			})
+			queues[side[id]].Push(gainEntry{id, gain[id]})
+		}
+
+		locked := make(map[int]bool)
+		var moves []int
+		total, best, bestLen := 0, 0, 0
+
+		move := func(from int8) bool {
+			q := queues[from]
+			for q.Len() > 0 {
+				e := q.Pop()
+				if locked[e.id] || side[e.id] != from || e.gain != gain[e.id] {
+					continue
+				}
+
+				id := e.id
+				locked[id] = true
+				total += gain[id]
+				side[id] = 1 - from
+				gain[id] = -gain[id]
+				moves = append(moves, id)
+
+				forEdge(id, func(other int) {
+					if locked[other] {
+						return
+					}
+					// the edge now crosses the cut iff it did not before
+					if side[other] == from {
+						gain[other] += 2
+					} else {
+						gain[other] -= 2
+					}
+					queues[side[other]].Push(gainEntry{other, gain[other]})
+				})
+				return true
+			}
+			return false
+		}
+
+		for move(0) && move(1) {
+			if total > best {
+				best, bestLen = total, len(moves)
+			}
+		}
+
+		// undo the moves after the best balanced prefix
+		for _, id := range moves[bestLen:] {
+			side[id] = 1 - side[id]
+		}
+
+		if best == 0 {
+			break
+		}
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool { return side[ids[i]] < side[ids[j]] })
+	for _, id := range ids {
+		side[id] = -1
+	}
+}
@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPartition(t *testing.T) {
+	g := NewGridGraph(16, 8, false, false)
+
+	for _, tt := range []struct {
+		k, maxCut int
+	}{
+		// a straight cut across the grid crosses 8 edges each way
+		{1, 0}, {2, 16}, {4, 48}, {5, 80},
+	} {
+		parts, err := g.Partition(tt.k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes := make([]int, tt.k)
+		for _, p := range parts {
+			sizes[p]++
+		}
+		for p, size := range sizes {
+			if size < len(g.Nodes)/tt.k-2 || size > len(g.Nodes)/tt.k+2 {
+				t.Fatalf("k = %d: part %d has %d nodes", tt.k, p, size)
+			}
+		}
+		if cut := g.EdgeCut(parts); cut > tt.maxCut {
+			t.Fatalf("k = %d: got %d edges cut, want at most %d", tt.k, cut, tt.maxCut)
+		}
+	}
+
+	if _, err := g.Partition(0); !errors.Is(err, ErrBadPartCount) {
+		t.Fatalf("k = 0: got error %v, want ErrBadPartCount", err)
+	}
+
+	g.Strict = true
+	defer func() {
+		if r := recover(); r != ErrBadPartCount {
+			t.Fatalf("strict graph panicked with %v, want ErrBadPartCount", r)
+		}
+	}()
+	g.Partition(0)
+}
@@ -0,0 +1,200 @@
+package graph
+
+import (
+	"fmt"
+)
+
+// Path is a walk through a graph, stored as its sequence of nodes together
+// with the edges joining them: Edges[i] leads from Nodes[i] to Nodes[i+1].
+// A path of n > 0 nodes has n-1 edges, the empty path has neither.
+type Path struct {
+	Nodes []*Node
+	Edges []*Edge
+}
+
+// NewPath builds a Path from a sequence of nodes, such as the ones returned by
+// the shortest path searches. Where consecutive nodes are joined by more than
+// one edge, the lightest one is chosen.
+func NewPath(nodes []*Node) (*Path, error) {
+	p := &Path{Nodes: nodes}
+
+	if len(nodes) > 0 {
+		p.Edges = make([]*Edge, 0, len(nodes)-1)
+	}
+
+	for i := 1; i < len(nodes); i++ {
+		e := lightestEdge(nodes[i-1], nodes[i])
+		if e == nil {
+			return nil, fmt.Errorf("graph: no edge from node %d to node %d", nodes[i-1].ID, nodes[i].ID)
+		}
+		p.Edges = append(p.Edges, e)
+	}
+
+	return p, nil
+}
+
+// lightestEdge returns the edge from u to v with the least weight, or nil
+func lightestEdge(u, v *Node) *Edge {
+	var best *Edge
+	for _, e := range u.EdgeStart {
+		if e.To == v && (best == nil || e.Weight < best.Weight) {
+			best = e
+		}
+	}
+	return best
+}
+
+// Len returns the number of edges in the path
+func (p *Path) Len() int {
+	return len(p.Edges)
+}
+
+// Cost returns the total weight of the edges in the path
+func (p *Path) Cost() float64 {
+	cost := 0.0
+	for _, e := range p.Edges {
+		cost += e.Weight
+	}
+	return cost
+}
+
+// NodeCost returns the cost of the path including the Cost of its
+// intermediate nodes, as minimised by the node weighted searches. If endpoints
+// is set, the costs of the first and last node are included as well.
+func (p *Path) NodeCost(endpoints bool) float64 {
+	cost := p.Cost()
+
+	for i, n := range p.Nodes {
+		if endpoints || (i > 0 && i < len(p.Nodes)-1) {
+			cost += n.Cost
+		}
+	}
+
+	return cost
+}
+
+// Geometry returns the polyline the path follows: its first node, then for
+// every edge its shape points followed by its end node.
+func (p *Path) Geometry() []Point {
+	if len(p.Nodes) == 0 {
+		return nil
+	}
+
+	first := p.Nodes[0]
+	points := []Point{{X: first.X, Y: first.Y}}
+
+	for _, e := range p.Edges {
+		points = append(points, e.Shape...)
+		points = append(points, Point{X: e.To.X, Y: e.To.Y})
+	}
+
+	return points
+}
+
+// Validate checks that the path is a valid walk through g: its nodes belong to
+// g, and every edge is registered in g and joins consecutive nodes.
+func (p *Path) Validate(g *DirectedGraph) error {
+	if len(p.Nodes) == 0 {
+		if len(p.Edges) != 0 {
+			return fmt.Errorf("graph: path has %d edges but no nodes", len(p.Edges))
+		}
+		return nil
+	}
+
+	if len(p.Edges) != len(p.Nodes)-1 {
+		return fmt.Errorf("graph: path has %d nodes but %d edges", len(p.Nodes), len(p.Edges))
+	}
+
+	for _, n := range p.Nodes {
+		if g.Node(n.ID) != n {
+			return fmt.Errorf("graph: path node %d is not in the graph", n.ID)
+		}
+	}
+
+	for i, e := range p.Edges {
+		if e.From != p.Nodes[i] || e.To != p.Nodes[i+1] {
+			return fmt.Errorf("graph: path edge %v does not join nodes %d and %d",
+				e.ID, p.Nodes[i].ID, p.Nodes[i+1].ID)
+		}
+
+		registered := false
+		for _, e2 := range e.From.EdgeStart {
+			if e2 == e {
+				registered = true
+				break
+			}
+		}
+		if !registered {
+			return fmt.Errorf("graph: path edge %v is not in the graph", e.ID)
+		}
+	}
+
+	return nil
+}
+
+// Concat returns the path p followed by other. other must start at the node
+// where p ends; either path may be empty.
+func (p *Path) Concat(other *Path) (*Path, error) {
+	if len(p.Nodes) == 0 {
+		return other.copy(), nil
+	}
+	if len(other.Nodes) == 0 {
+		return p.copy(), nil
+	}
+
+	last, first := p.Nodes[len(p.Nodes)-1], other.Nodes[0]
+	if last != first {
+		return nil, fmt.Errorf("graph: cannot join path ending at node %d to path starting at node %d",
+			last.ID, first.ID)
+	}
+
+	q := &Path{
+		Nodes: make([]*Node, 0, len(p.Nodes)+len(other.Nodes)-1),
+		Edges: make([]*Edge, 0, len(p.Edges)+len(other.Edges)),
+	}
+	q.Nodes = append(append(q.Nodes, p.Nodes...), other.Nodes[1:]...)
+	q.Edges = append(append(q.Edges, p.Edges...), other.Edges...)
+
+	return q, nil
+}
+
+// Truncate returns the longest prefix of p whose cost does not exceed maxCost.
+func (p *Path) Truncate(maxCost float64) *Path {
+	if len(p.Nodes) == 0 || maxCost < 0 {
+		return &Path{}
+	}
+
+	cost, n := 0.0, 0
+	for _, e := range p.Edges {
+		if cost+e.Weight > maxCost {
+			break
+		}
+		cost += e.Weight
+		n++
+	}
+
+	return &Path{
+		Nodes: append([]*Node(nil), p.Nodes[:n+1]...),
+		Edges: append([]*Edge(nil), p.Edges[:n]...),
+	}
+}
+
+// Reverse returns the path walked from its last node back to its first, using
+// the lightest opposing edge for every step. It fails if some edge of p has
+// no counterpart in the opposite direction.
+func (p *Path) Reverse() (*Path, error) {
+	nodes := make([]*Node, len(p.Nodes))
+	for i, n := range p.Nodes {
+		nodes[len(nodes)-1-i] = n
+	}
+
+	return NewPath(nodes)
+}
+
+// copy returns a copy of p that shares no slices with it
+func (p *Path) copy() *Path {
+	return &Path{
+		Nodes: append([]*Node(nil), p.Nodes...),
+		Edges: append([]*Edge(nil), p.Edges...),
+	}
+}
@@ -0,0 +1,101 @@
+package graph
+
+import "testing"
+
+func TestPath(t *testing.T) {
+	g := newGraph(4, [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 2}, {2, 1}})
+	g.AddDirectedEdge(&Edge{ID: [2]int{0, 1}, From: g.Nodes[0], To: g.Nodes[1], Weight: 0.5})
+
+	p, err := NewPath(g.Nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Len() != 3 || p.Cost() != 2.5 {
+		t.Fatalf("got %d edges of cost %v, want 3 of cost 2.5", p.Len(), p.Cost())
+	}
+	if err := p.Validate(g); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewPath([]*Node{g.Nodes[0], g.Nodes[2]}); err == nil {
+		t.Fatal("built a path over a missing edge")
+	}
+	if _, err := p.Reverse(); err == nil {
+		t.Fatal("reversed a path over a one-way edge")
+	}
+
+	tail, _ := NewPath([]*Node{g.Nodes[3], g.Nodes[2], g.Nodes[1]})
+	q, err := p.Concat(tail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Len() != 5 || q.Cost() != 4.5 || q.Validate(g) != nil {
+		t.Fatalf("got joined path of %d edges and cost %v, want 5 and 4.5", q.Len(), q.Cost())
+	}
+	if _, err := tail.Concat(p); err == nil {
+		t.Fatal("joined paths that do not meet")
+	}
+	if r, err := tail.Reverse(); err != nil || r.Nodes[0] != g.Nodes[1] || r.Cost() != 2 {
+		t.Fatalf("Reverse = %v, %v", r, err)
+	}
+
+	for _, tt := range []struct {
+		max   float64
+		edges int
+	}{{-1, -1}, {0, 0}, {0.4, 0}, {0.5, 1}, {2, 2}, {10, 3}} {
+		r := p.Truncate(tt.max)
+		if tt.edges < 0 {
+			if len(r.Nodes) != 0 {
+				t.Fatalf("Truncate(%v) kept %d nodes", tt.max, len(r.Nodes))
+			}
+			continue
+		}
+		if r.Len() != tt.edges || len(r.Nodes) != tt.edges+1 || r.Validate(g) != nil {
+			t.Fatalf("Truncate(%v) kept %d edges, want %d", tt.max, r.Len(), tt.edges)
+		}
+	}
+}
+
+func TestPathValidate(t *testing.T) {
+	g := newGraph(3, [][2]int{{0, 1}, {1, 2}})
+	p, _ := NewPath(g.Nodes)
+
+	other := newGraph(3, [][2]int{{0, 1}, {1, 2}})
+	if p.Validate(other) == nil {
+		t.Fatal("path validated against another graph")
+	}
+
+	g.RemoveDirectedEdge(p.Edges[1])
+	if p.Validate(g) == nil {
+		t.Fatal("path over a removed edge validated")
+	}
+
+	bad := &Path{Nodes: g.Nodes[:2]}
+	if bad.Validate(g) == nil {
+		t.Fatal("path without edges validated")
+	}
+	if err := (&Path{}).Validate(g); err != nil {
+		t.Fatalf("empty path: %v", err)
+	}
+}
+
+func TestPathGeometry(t *testing.T) {
+	g := newGraph(3, [][2]int{{0, 1}, {1, 2}})
+	g.Nodes[0].EdgeStart[0].Shape = []Point{{X: 0.5, Y: 1}, {X: 0.5, Y: 0}}
+	p, _ := NewPath(g.Nodes)
+
+	want := []Point{{X: 0, Y: 0}, {X: 0.5, Y: 1}, {X: 0.5, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}
+	got := p.Geometry()
+	if len(got) != len(want) {
+		t.Fatalf("got %d points, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got point %d at %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if points := (&Path{}).Geometry(); points != nil {
+		t.Fatalf("empty path has geometry %v", points)
+	}
+}
@@ -0,0 +1,396 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// maxExactMatching is the largest number of odd degree nodes for which
+// ChinesePostmanUndirected pairs them up optimally
+const maxExactMatching = 20
+
+// ErrNotSymmetric is returned by operations that read the graph as undirected,
+// with a pair of opposite edges for every link, if an edge has no reverse
+var ErrNotSymmetric = errors.New("graph: edge has no reverse edge")
+
+// ChinesePostman returns a shortest closed walk from start that traverses
+// every edge of g at least once, in its direction, e.g. a street sweeping
+// route. The walk repeats shortest paths from nodes with more incoming than
+// outgoing edges to nodes with more outgoing ones, chosen by a min cost flow
+// so that every node is balanced, and is then laid out by Hierholzer's
+// algorithm. If start is nil, the walk starts at the first node with an edge.
+//
+// ErrNotConnected is returned unless every edge can be reached from start
+// and start from every edge, i.e. all edges lie in the strongly connected
+// component of start.
+func (g *DirectedGraph) ChinesePostman(start *Node) (*Path, error) {
+	start, err := g.postmanStart(start)
+	if start == nil || err != nil {
+		return &Path{}, err
+	}
+
+	var surplus, deficit []*Node
+	var supply, demand []int
+	for _, n := range g.Nodes {
+		switch d := len(n.EdgeEnd) - len(n.EdgeStart); {
+		case d > 0:
+			surplus = append(surplus, n)
+			supply = append(supply, d)
+		case d < 0:
+			deficit = append(deficit, n)
+			demand = append(demand, -d)
+		}
+	}
+
+	Q := newIDHeap(len(g.Nodes))
+	via := make([][]*Edge, len(surplus))
+	cost := make([][]float64, len(surplus))
+	for i, s := range surplus {
+		var dist []float64
+		dist, via[i] = g.dijkstraTree(s, Q, false)
+
+		cost[i] = make([]float64, len(deficit))
+		for j, t := range deficit {
+			cost[i][j] = dist[t.ID]
+		}
+	}
+
+	out := make([][]eulerArc, len(g.Nodes))
+	streets := 0
+	addArc := func(e *Edge) {
+		out[e.From.ID] = append(out[e.From.ID], eulerArc{e, streets})
+		streets++
+	}
+
+	for _, n := range g.Nodes {
+		for _, e := range n.EdgeStart {
+			addArc(e)
+		}
+	}
+
+	flow := minCostTransport(supply, demand, cost)
+	for i, s := range surplus {
+		for j, t := range deficit {
+			for k := 0; k < flow[i][j]; k++ {
+				for n := t; n != s; n = via[i][n.ID].From {
+					addArc(via[i][n.ID])
+				}
+			}
+		}
+	}
+
+	return eulerCircuit(start, out, streets), nil
+}
+
+// ChinesePostmanUndirected is the undirected variant of ChinesePostman, for
+// graphs whose links are stored as a pair of opposite edges, as built by
+// NewGridGraph. Each pair is traversed at least once in either direction.
+// Opposite edges are assumed to weigh the same.
+//
+// Nodes of odd degree are paired up at minimum total distance and the
+// shortest paths between pairs repeated. The pairing is optimal for up to 20
+// odd nodes; beyond that it is greedy, so the walk may not be shortest.
+// ErrNotSymmetric is returned if an edge has no reverse, ErrNotConnected if
+// not all edges are connected to start.
+func (g *DirectedGraph) ChinesePostmanUndirected(start *Node) (*Path, error) {
+	// pair every edge with a reverse edge
+	partner := make(map[*Edge]*Edge)
+	for _, n := range g.Nodes {
+		for _, e := range n.EdgeStart {
+			if partner[e] != nil {
+				continue
+			}
+
+			for _, r := range e.To.EdgeStart {
+				if r.To == n && partner[r] == nil {
+					partner[e], partner[r] = r, e
+					break
+				}
+			}
+
+			if partner[e] == nil {
+				return nil, ErrNotSymmetric
+			}
+		}
+	}
+
+	start, err := g.postmanStart(start)
+	if start == nil || err != nil {
+		return &Path{}, err
+	}
+
+	out := make([][]eulerArc, len(g.Nodes))
+	streets := 0
+	addStreet := func(e *Edge) {
+		r := partner[e]
+		out[e.From.ID] = append(out[e.From.ID], eulerArc{e, streets})
+		out[r.From.ID] = append(out[r.From.ID], eulerArc{r, streets})
+		streets++
+	}
+
+	var odd []*Node
+	for _, n := range g.Nodes {
+		for _, e := range n.EdgeStart {
+			if e.From.ID < e.To.ID {
+				addStreet(e)
+			}
+		}
+		if len(n.EdgeStart)%2 == 1 {
+			odd = append(odd, n)
+		}
+	}
+
+	Q := newIDHeap(len(g.Nodes))
+	via := make([][]*Edge, len(odd))
+	cost := make([][]float64, len(odd))
+	for i, s := range odd {
+		var dist []float64
+		dist, via[i] = g.dijkstraTree(s, Q, false)
+
+		cost[i] = make([]float64, len(odd))
+		for j, t := range odd {
+			cost[i][j] = dist[t.ID]
+		}
+	}
+
+	for i, j := range minMatching(cost) {
+		if i < j {
+			for n := odd[j]; n != odd[i]; n = via[i][n.ID].From {
+				addStreet(via[i][n.ID])
+			}
+		}
+	}
+
+	return eulerCircuit(start, out, streets), nil
+}
+
+// postmanStart checks that all edges lie in the strongly connected component
+// of start and returns it, or the first node with an edge if start is nil.
+// It returns nil if the graph has no edges and no start was given.
+func (g *DirectedGraph) postmanStart(start *Node) (*Node, error) {
+	if start == nil {
+		for _, n := range g.Nodes {
+			if len(n.EdgeStart) > 0 {
+				start = n
+				break
+			}
+		}
+		if start == nil {
+			return nil, nil
+		}
+	}
+
+	comp, _ := g.stronglyConnected()
+	for _, n := range g.Nodes {
+		if len(n.EdgeStart)+len(n.EdgeEnd) > 0 && comp[n.ID] != comp[start.ID] {
+			return nil, ErrNotConnected
+		}
+	}
+
+	return start, nil
+}
+
+// eulerArc is an edge that may be traversed as part of an Euler circuit.
+// Arcs with the same street number are alternatives, only one of which is
+// traversed, such as the two directions of an undirected link.
+type eulerArc struct {
+	edge   *Edge
+	street int
+}
+
+// eulerCircuit returns a closed walk from start that traverses every street
+// once, given the arcs leaving every node by ID, using Hierholzer's
+// algorithm. Every street must be reachable from start, and every node must
+// be left as often as it is entered.
+func eulerCircuit(start *Node, out [][]eulerArc, streets int) *Path {
+	used := make([]bool, streets)
+	next := make([]int, len(out))
+
+	// the walk is built backwards while the stack unwinds
+	stack := []*Node{start}
+	var taken, edges []*Edge
+	var nodes []*Node
+
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+
+		for next[v.ID] < len(out[v.ID]) && used[out[v.ID][next[v.ID]].street] {
+			next[v.ID]++
+		}
+
+		if next[v.ID] < len(out[v.ID]) {
+			arc := out[v.ID][next[v.ID]]
+			used[arc.street] = true
+			stack = append(stack, arc.edge.To)
+			taken = append(taken, arc.edge)
+			continue
+		}
+
+		stack = stack[:len(stack)-1]
+		nodes = append(nodes, v)
+		if len(taken) > 0 {
+			edges = append(edges, taken[len(taken)-1])
+			taken = taken[:len(taken)-1]
+		}
+	}
+
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+	for i, j := 0, len(edges)-1; i < j; i, j = i+1, j-1 {
+		edges[i], edges[j] = edges[j], edges[i]
+	}
+
+	return &Path{Nodes: nodes, Edges: edges}
+}
+
+// minCostTransport returns how many units to ship from every source to every
+// sink so that all supplies and demands are met, at minimum total cost, where
+// cost[i][j] is the cost per unit from source i to sink j. Total supply and
+// demand must be equal. Flow is augmented along shortest paths of the
+// residual network, which are found with Bellman-Ford since cancelling a
+// shipment has negative cost.
+func minCostTransport(supply, demand []int, cost [][]float64) [][]int {
+	m, k := len(supply), len(demand)
+
+	flow := make([][]int, m)
+	for i := range flow {
+		flow[i] = make([]int, k)
+	}
+	sent := make([]int, m)
+	received := make([]int, k)
+
+	// residual network nodes: sources 0..m-1, sinks m..m+k-1
+	dist := make([]float64, m+k)
+	prev := make([]int, m+k)
+
+	for {
+		for i := range dist {
+			dist[i] = math.Inf(1)
+			prev[i] = -1
+			if i < m && sent[i] < supply[i] {
+				dist[i] = 0
+			}
+		}
+
+		for round := 0; round < m+k; round++ {
+			changed := false
+			for i := 0; i < m; i++ {
+				for j := 0; j < k; j++ {
+					// ship more from i to j
+					if d := dist[i] + cost[i][j]; d < dist[m+j] {
+						dist[m+j], prev[m+j] = d, i
+						changed = true
+					}
+					// ship less from i to j
+					if d := dist[m+j] - cost[i][j]; flow[i][j] > 0 && d < dist[i] {
+						dist[i], prev[i] = d, m+j
+						changed = true
+					}
+				}
+			}
+			if !changed {
+				break
+			}
+		}
+
+		sink := -1
+		for j := 0; j < k; j++ {
+			if received[j] < demand[j] && (sink < 0 || dist[m+j] < dist[m+sink]) {
+				sink = j
+			}
+		}
+		if sink < 0 || math.IsInf(dist[m+sink], 1) {
+			return flow
+		}
+
+		// find the bottleneck along the path back to a source with supply
+		amount := demand[sink] - received[sink]
+		n := m + sink
+		for prev[n] >= 0 {
+			if n < m && flow[n][prev[n]-m] < amount {
+				amount = flow[n][prev[n]-m]
+			}
+			n = prev[n]
+		}
+		if left := supply[n] - sent[n]; left < amount {
+			amount = left
+		}
+
+		received[sink] += amount
+		sent[n] += amount
+		for n := m + sink; prev[n] >= 0; n = prev[n] {
+			if n >= m {
+				flow[prev[n]][n-m] += amount
+			} else {
+				flow[n][prev[n]-m] -= amount
+			}
+		}
+	}
+}
+
+// minMatching pairs up an even number of nodes at minimum total cost, given
+// the cost of pairing every two of them, and returns the mate of each. Up to
+// maxExactMatching nodes the matching is found by dynamic programming over
+// subsets; beyond that the cheapest remaining pair is matched first.
+func minMatching(cost [][]float64) []int {
+	k := len(cost)
+	mate := make([]int, k)
+
+	if k > maxExactMatching {
+		type pair struct{ i, j int }
+		pairs := make([]pair, 0, k*(k-1)/2)
+		for i := 0; i < k; i++ {
+			for j := i + 1; j < k; j++ {
+				pairs = append(pairs, pair{i, j})
+			}
+		}
+		sort.SliceStable(pairs, func(a, b int) bool {
+			return cost[pairs[a].i][pairs[a].j] < cost[pairs[b].i][pairs[b].j]
+		})
+
+		for i := range mate {
+			mate[i] = -1
+		}
+		for _, p := range pairs {
+			if mate[p.i] < 0 && mate[p.j] < 0 {
+				mate[p.i], mate[p.j] = p.j, p.i
+			}
+		}
+		return mate
+	}
+
+	// best[mask] is the cost of matching the nodes not in mask, choice[mask]
+	// the mate of the lowest of them in that matching
+	full := 1<<k - 1
+	best := make([]float64, 1<<k)
+	choice := make([]int8, 1<<k)
+
+	for mask := full - 1; mask >= 0; mask-- {
+		best[mask] = math.Inf(1)
+		if bits.OnesCount(uint(mask))%2 == 1 {
+			continue
+		}
+
+		i := bits.TrailingZeros(uint(^mask))
+		for j := i + 1; j < k; j++ {
+			if mask&(1<<j) != 0 {
+				continue
+			}
+			if c := cost[i][j] + best[mask|1<<i|1<<j]; c < best[mask] {
+				best[mask], choice[mask] = c, int8(j)
+			}
+		}
+	}
+
+	for mask := 0; mask != full; {
+		i := bits.TrailingZeros(uint(^mask))
+		j := int(choice[mask])
+		mate[i], mate[j] = j, i
+		mask |= 1<<i | 1<<j
+	}
+
+	return mate
+}
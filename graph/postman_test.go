@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+// checkWalk fails t unless walk is a closed walk of g from start of the given
+// cost, which traverses every link of g, in either direction if undirected
+func checkWalk(t *testing.T, g *DirectedGraph, start *Node, walk *Path, cost float64, undirected bool) {
+	t.Helper()
+
+	if err := walk.Validate(g); err != nil {
+		t.Fatal(err)
+	}
+	if walk.Nodes[0] != start || walk.Nodes[len(walk.Nodes)-1] != start {
+		t.Fatalf("walk from %d to %d, want a closed walk from %d", walk.Nodes[0].ID, walk.Nodes[len(walk.Nodes)-1].ID, start.ID)
+	}
+	if walk.Cost() != cost {
+		t.Fatalf("got cost %v, want %v", walk.Cost(), cost)
+	}
+
+	traversed := make(map[[2]int]bool)
+	for _, e := range walk.Edges {
+		traversed[e.ID] = true
+		if undirected {
+			traversed[[2]int{e.ID[1], e.ID[0]}] = true
+		}
+	}
+	for _, n := range g.Nodes {
+		for _, e := range n.EdgeStart {
+			if !traversed[e.ID] {
+				t.Fatalf("edge %v was not traversed", e.ID)
+			}
+		}
+	}
+}
+
+func TestChinesePostman(t *testing.T) {
+	// the chord 0 -> 2 forces the walk round 2 -> 3 -> 0 twice
+	g := newGraph(4, [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}, {0, 2}})
+	walk, err := g.ChinesePostman(g.Nodes[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkWalk(t, g, g.Nodes[1], walk, 7, false)
+
+	if walk, err = g.ChinesePostman(nil); err != nil {
+		t.Fatal(err)
+	}
+	checkWalk(t, g, g.Nodes[0], walk, 7, false)
+
+	g.AddNode(&Node{})
+	g.AddDirectedEdge(&Edge{ID: [2]int{4, 0}, From: g.Nodes[4], To: g.Nodes[0], Weight: 1})
+	if _, err := g.ChinesePostman(g.Nodes[0]); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("got error %v, want ErrNotConnected", err)
+	}
+}
+
+func TestChinesePostmanUndirected(t *testing.T) {
+	// the four nodes of odd degree are paired up at a distance of 2 each
+	g := NewGridGraph(3, 3, false, false)
+	walk, err := g.ChinesePostmanUndirected(g.Nodes[4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkWalk(t, g, g.Nodes[4], walk, 16, true)
+
+	h := newGraph(3, [][2]int{{0, 1}, {1, 0}, {1, 2}})
+	if _, err := h.ChinesePostmanUndirected(nil); !errors.Is(err, ErrNotSymmetric) {
+		t.Fatalf("got error %v, want ErrNotSymmetric", err)
+	}
+}
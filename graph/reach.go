@@ -0,0 +1,269 @@
+package graph
+
+import (
+	"math/rand"
+)
+
+// ReachabilityIndex answers whether one node can reach another in a fixed
+// graph. Strongly connected components are contracted into a DAG, whose
+// components are labelled with intervals from several randomised depth first
+// traversals (GRAIL labelling). If v is reachable from u, the interval of v
+// is nested in that of u in every traversal, so most negative queries are
+// answered by comparing a few labels, and most positive ones by the tree
+// intervals of the first traversal. The remaining queries fall back to a
+// depth first search of the DAG that is pruned by the same labels.
+//
+// The index reflects the graph at the time it was built.
+type ReachabilityIndex struct {
+	comp []int32 // component of each node, in reverse topological order
+
+	children [][]int32 // DAG of components
+
+	// lo and hi hold the interval of every component for each traversal,
+	// pre the discovery order of the first traversal
+	traversals int
+	lo, hi     []int32
+	pre        []int32
+}
+
+// NewReachabilityIndex builds a reachability index over g using the given
+// number of traversals. More traversals cost memory and build time but prune
+// more queries; 2 to 5 work well for most graphs.
+func (g *DirectedGraph) NewReachabilityIndex(traversals int) *ReachabilityIndex {
+	if traversals < 1 {
+		traversals = 1
+	}
+
+	comp, count := g.stronglyConnected()
+
+	// contract components into a DAG, without duplicate edges
+	children := make([][]int32, count)
+	last := make([]int32, count)
+	for i := range last {
+		last[i] = -1
+	}
+	for _, n := range g.Nodes {
+		from := comp[n.ID]
+		for _, e := range n.EdgeStart {
+			to := comp[e.To.ID]
+			if to != from && last[to] != from {
+				last[to] = from
+				children[from] = append(children[from], to)
+			}
+		}
+	}
+
+	idx := &ReachabilityIndex{
+		comp:       comp,
+		children:   children,
+		traversals: traversals,
+		lo:         make([]int32, traversals*count),
+		hi:         make([]int32, traversals*count),
+		pre:        make([]int32, count),
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for t := 0; t < traversals; t++ {
+		idx.label(t, rng)
+	}
+
+	return idx
+}
+
+// CanReach reports whether there is a path from u to v.
+func (idx *ReachabilityIndex) CanReach(u, v *Node) bool {
+	cu, cv := idx.comp[u.ID], idx.comp[v.ID]
+	if cu == cv {
+		return true
+	}
+
+	// components can only reach those later in topological order
+	if cu < cv || !idx.contains(cu, cv) {
+		return false
+	}
+
+	// descendants in the first search tree are reachable
+	if idx.pre[cu] <= idx.pre[cv] && idx.hi[cv] <= idx.hi[cu] {
+		return true
+	}
+
+	// search the DAG, skipping components whose labels rule out v
+	visited := map[int32]bool{cu: true}
+	stack := []int32{cu}
+
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, child := range idx.children[c] {
+			if child == cv {
+				return true
+			}
+			if !visited[child] && child > cv && idx.contains(child, cv) {
+				visited[child] = true
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	return false
+}
+
+// contains reports whether the intervals of component c contain those of d
+// in every traversal
+func (idx *ReachabilityIndex) contains(c, d int32) bool {
+	count := int32(len(idx.children))
+	for t := int32(0); t < int32(idx.traversals); t++ {
+		i, j := t*count+c, t*count+d
+		if idx.lo[i] > idx.lo[j] || idx.hi[j] > idx.hi[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// label runs traversal t over the DAG, visiting children in random order,
+// and records the interval of every component: its post-order rank, and the
+// lowest rank among its descendants. The first traversal also records the
+// discovery order in idx.pre, which the later ones must leave as it is, as
+// CanReach pairs it with the post-order ranks of the first.
+func (idx *ReachabilityIndex) label(t int, rng *rand.Rand) {
+	count := len(idx.children)
+	lo, hi := idx.lo[t*count:(t+1)*count], idx.hi[t*count:(t+1)*count]
+
+	for _, children := range idx.children {
+		rng.Shuffle(len(children), func(i, j int) {
+			children[i], children[j] = children[j], children[i]
+		})
+	}
+
+	visited := make([]bool, count)
+	var post, pre int32
+	discover := func(c int32) {
+		visited[c] = true
+		if t == 0 {
+			idx.pre[c] = pre
+			pre++
+		}
+	}
+
+	type frame struct {
+		comp int32
+		next int
+	}
+
+	for _, root := range rng.Perm(count) {
+		if visited[root] {
+			continue
+		}
+
+		discover(int32(root))
+		stack := []frame{{comp: int32(root)}}
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			c := top.comp
+
+			if top.next < len(idx.children[c]) {
+				child := idx.children[c][top.next]
+				top.next++
+
+				if !visited[child] {
+					discover(child)
+					stack = append(stack, frame{comp: child})
+				}
+				continue
+			}
+
+			// all children are labelled, finish c
+			hi[c] = post
+			lo[c] = post
+			post++
+			for _, child := range idx.children[c] {
+				if lo[child] < lo[c] {
+					lo[c] = lo[child]
+				}
+			}
+
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// stronglyConnected finds the strongly connected components of g with an
+// iterative version of Tarjan's algorithm. It returns the component of every
+// node by ID and the number of components. Components are numbered in
+// reverse topological order: edges between components always lead from a
+// higher to a lower number.
+func (g *DirectedGraph) stronglyConnected() ([]int32, int) {
+	n := len(g.Nodes)
+	index := make([]int32, n)
+	low := make([]int32, n)
+	comp := make([]int32, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = -1
+	}
+
+	var stack []int32
+	var frames []dfsFrame
+	var counter int32
+	count := 0
+
+	visit := func(u *Node) {
+		index[u.ID], low[u.ID] = counter, counter
+		counter++
+		stack = append(stack, int32(u.ID))
+		onStack[u.ID] = true
+		frames = append(frames, dfsFrame{node: u})
+	}
+
+	for _, root := range g.Nodes {
+		if index[root.ID] >= 0 {
+			continue
+		}
+		visit(root)
+
+		for len(frames) > 0 {
+			top := &frames[len(frames)-1]
+			u := top.node
+
+			if top.next < len(u.EdgeStart) {
+				w := u.EdgeStart[top.next].To
+				top.next++
+
+				if index[w.ID] < 0 {
+					visit(w)
+				} else if onStack[w.ID] && index[w.ID] < low[u.ID] {
+					low[u.ID] = index[w.ID]
+				}
+				continue
+			}
+
+			frames = frames[:len(frames)-1]
+
+			// u is the root of a component, pop its members
+			if low[u.ID] == index[u.ID] {
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					comp[w] = int32(count)
+					if w == int32(u.ID) {
+						break
+					}
+				}
+				count++
+			}
+
+			if len(frames) > 0 {
+				parent := frames[len(frames)-1].node
+				if low[u.ID] < low[parent.ID] {
+					low[parent.ID] = low[u.ID]
+				}
+			}
+		}
+	}
+
+	return comp, count
+}
@@ -0,0 +1,34 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestReachabilityIndex(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// a sparse random graph has both cycles and nodes that cannot reach
+	// each other
+	var edges [][2]int
+	for i := 0; i < 250; i++ {
+		u, v := rng.Intn(200), rng.Intn(200)
+		if u != v {
+			edges = append(edges, [2]int{u, v})
+		}
+	}
+	g := newGraph(200, edges)
+
+	for _, traversals := range []int{1, 3} {
+		idx := g.NewReachabilityIndex(traversals)
+		for _, u := range g.Nodes {
+			dist := bellmanFord(g, u)
+			for _, v := range g.Nodes {
+				if got, want := idx.CanReach(u, v), !math.IsInf(dist[v.ID], 1); got != want {
+					t.Fatalf("%d traversals: CanReach(%d, %d) = %t, want %t", traversals, u.ID, v.ID, got, want)
+				}
+			}
+		}
+	}
+}
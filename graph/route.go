@@ -0,0 +1,186 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+)
+
+// maxExactWaypoints is the largest number of reorderable waypoints for which
+// RouteVia tries every order
+const maxExactWaypoints = 7
+
+// RouteVia returns a single path from the first to the last of the given
+// points that passes through all of them, stitched together from shortest
+// paths between consecutive waypoints.
+// If reorder is set, the intermediate waypoints may be visited in any order
+// and RouteVia picks a cheap one: the best order when there are at most 7 of
+// them, otherwise a nearest neighbour tour improved by 2-opt.
+func (g *DirectedGraph) RouteVia(points []*Node, reorder bool) (*Path, error) {
+	if len(points) == 0 {
+		return &Path{}, nil
+	}
+
+	legs := make(map[[2]*Node]*Path)
+	leg := func(u, v *Node) (*Path, error) {
+		if p, ok := legs[[2]*Node{u, v}]; ok {
+			return p, nil
+		}
+
+		nodes, dist := g.Dijkstra(u, v)
+		if math.IsInf(dist, 1) {
+			return nil, fmt.Errorf("graph: no route from node %d to node %d", u.ID, v.ID)
+		}
+
+		p, err := NewPath(nodes)
+		if err != nil {
+			return nil, err
+		}
+
+		legs[[2]*Node{u, v}] = p
+		return p, nil
+	}
+
+	order := points
+	if reorder && len(points) > 3 {
+		// cost of travelling between every pair of waypoints
+		cost := make([][]float64, len(points))
+		for i, u := range points {
+			cost[i] = make([]float64, len(points))
+			for j, v := range points {
+				if i == j {
+					continue
+				}
+				p, err := leg(u, v)
+				if err != nil {
+					cost[i][j] = math.Inf(1)
+					continue
+				}
+				cost[i][j] = p.Cost()
+			}
+		}
+
+		var idx []int
+		if len(points)-2 <= maxExactWaypoints {
+			idx = bestOrder(cost)
+		} else {
+			idx = twoOpt(cost, nearestNeighbourOrder(cost))
+		}
+
+		order = make([]*Node, len(idx))
+		for i, j := range idx {
+			order[i] = points[j]
+		}
+	}
+
+	route := &Path{Nodes: []*Node{order[0]}}
+	for i := 1; i < len(order); i++ {
+		p, err := leg(order[i-1], order[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if route, err = route.Concat(p); err != nil {
+			return nil, err
+		}
+	}
+
+	return route, nil
+}
+
+// tourCost returns the cost of visiting waypoints in the given order
+func tourCost(cost [][]float64, order []int) float64 {
+	total := 0.0
+	for i := 1; i < len(order); i++ {
+		total += cost[order[i-1]][order[i]]
+	}
+	return total
+}
+
+// bestOrder tries every order of the intermediate waypoints, keeping the
+// first and last fixed, and returns the cheapest
+func bestOrder(cost [][]float64) []int {
+	n := len(cost)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	best := append([]int(nil), order...)
+	bestCost := tourCost(cost, order)
+
+	// permute order[lo:n-1] in place
+	var permute func(lo int)
+	permute = func(lo int) {
+		if lo == n-1 {
+			if c := tourCost(cost, order); c < bestCost {
+				bestCost = c
+				copy(best, order)
+			}
+			return
+		}
+
+		for i := lo; i < n-1; i++ {
+			order[lo], order[i] = order[i], order[lo]
+			permute(lo + 1)
+			order[lo], order[i] = order[i], order[lo]
+		}
+	}
+	permute(1)
+
+	return best
+}
+
+// nearestNeighbourOrder builds a tour from the first waypoint by always
+// moving to the closest unvisited intermediate waypoint, ending at the last
+func nearestNeighbourOrder(cost [][]float64) []int {
+	n := len(cost)
+	visited := make([]bool, n)
+	order := []int{0}
+	visited[0], visited[n-1] = true, true
+
+	for len(order) < n-1 {
+		cur, next := order[len(order)-1], -1
+		for j := 1; j < n-1; j++ {
+			if !visited[j] && (next < 0 || cost[cur][j] < cost[cur][next]) {
+				next = j
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+	}
+
+	return append(order, n-1)
+}
+
+// twoOpt improves a tour by reversing segments of intermediate waypoints
+// while that lowers its cost
+func twoOpt(cost [][]float64, order []int) []int {
+	bestCost := tourCost(cost, order)
+
+	for improved := true; improved; {
+		improved = false
+
+		for i := 1; i < len(order)-2; i++ {
+			for j := i + 1; j < len(order)-1; j++ {
+				reverseInts(order[i : j+1])
+
+				// costs may be asymmetric, so the whole tour is recomputed
+				if c := tourCost(cost, order); c < bestCost {
+					bestCost = c
+					improved = true
+				} else {
+					reverseInts(order[i : j+1])
+				}
+			}
+		}
+	}
+
+	return order
+}
+
+// reverseInts reverses s in place
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// checkRoute fails t unless route is a valid path of g from the first to the
+// last of points that passes through all of them
+func checkRoute(t *testing.T, g *DirectedGraph, points []*Node, route *Path) {
+	t.Helper()
+	if err := route.Validate(g); err != nil {
+		t.Fatal(err)
+	}
+	if route.Nodes[0] != points[0] || route.Nodes[len(route.Nodes)-1] != points[len(points)-1] {
+		t.Fatalf("route runs from %d to %d, want %d to %d", route.Nodes[0].ID, route.Nodes[len(route.Nodes)-1].ID,
+			points[0].ID, points[len(points)-1].ID)
+	}
+	passed := make(map[*Node]bool)
+	for _, n := range route.Nodes {
+		passed[n] = true
+	}
+	for _, n := range points {
+		if !passed[n] {
+			t.Fatalf("route does not pass node %d", n.ID)
+		}
+	}
+}
+
+// permutations calls fn with every permutation of a
+func permutations(a []int, k int, fn func([]int)) {
+	if k == len(a) {
+		fn(a)
+		return
+	}
+	for i := k; i < len(a); i++ {
+		a[k], a[i] = a[i], a[k]
+		permutations(a, k+1, fn)
+		a[k], a[i] = a[i], a[k]
+	}
+}
+
+func TestRouteVia(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(200, rng)
+
+	points := make([]*Node, 7)
+	for i := range points {
+		points[i] = g.Nodes[rng.Intn(len(g.Nodes))]
+	}
+	dist := make([][]float64, len(points))
+	for i, u := range points {
+		all := bellmanFord(g, u)
+		for _, v := range points {
+			dist[i] = append(dist[i], all[v.ID])
+		}
+	}
+
+	route, err := g.RouteVia(points, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoute(t, g, points, route)
+	want := 0.0
+	for i := 1; i < len(points); i++ {
+		want += dist[i-1][i]
+	}
+	if math.Abs(route.Cost()-want) > 1e-9 {
+		t.Fatalf("got cost %v, want %v", route.Cost(), want)
+	}
+
+	// the best order of the intermediate points
+	best := math.Inf(1)
+	permutations([]int{1, 2, 3, 4, 5}, 0, func(order []int) {
+		cost, last := 0.0, 0
+		for _, i := range append(order, 6) {
+			cost += dist[last][i]
+			last = i
+		}
+		best = math.Min(best, cost)
+	})
+
+	route, err = g.RouteVia(points, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoute(t, g, points, route)
+	if math.Abs(route.Cost()-best) > 1e-9 {
+		t.Fatalf("got reordered cost %v, want %v", route.Cost(), best)
+	}
+
+	// too many points to try every order
+	points = make([]*Node, 15)
+	for i := range points {
+		points[i] = g.Nodes[rng.Intn(len(g.Nodes))]
+	}
+	route, err = g.RouteVia(points, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoute(t, g, points, route)
+}
+
+func TestRouteViaUnreachable(t *testing.T) {
+	g := newGraph(3, [][2]int{{0, 1}, {1, 0}})
+	if _, err := g.RouteVia(g.Nodes, false); err == nil {
+		t.Fatal("routed to an unreachable node")
+	}
+	if route, err := g.RouteVia(nil, true); err != nil || len(route.Nodes) != 0 {
+		t.Fatalf("RouteVia of no points = %v, %v, want an empty path", route, err)
+	}
+}
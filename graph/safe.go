@@ -0,0 +1,217 @@
+package graph
+
+import (
+	"sync"
+)
+
+// SafeGraph wraps a DirectedGraph for concurrent use. Structural mutations
+// take an exclusive lock, while lookups and algorithm runs share a read lock,
+// so queries may run in parallel with each other but never observe a
+// half-applied change.
+//
+// Read-only: HasNode, Node, HasEdge, Edge, Weight and all searches and
+// traversals. Traversal callbacks run under the read lock and must not
+// modify the graph.
+//
+// Mutating: AddNode, RemoveNode, AddDirectedEdge, RemoveDirectedEdge and
+// Update.
+type SafeGraph struct {
+	mu sync.RWMutex
+	g  *DirectedGraph
+}
+
+// Safe returns a concurrency-safe wrapper around g. Once wrapped, g must only
+// be accessed through the wrapper.
+func Safe(g *DirectedGraph) *SafeGraph {
+	return &SafeGraph{g: g}
+}
+
+// Update runs fn with exclusive access to the underlying graph, for batches
+// of mutations that should become visible to queries all at once.
+func (s *SafeGraph) Update(fn func(g *DirectedGraph)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.g)
+}
+
+// View runs fn with shared read access to the underlying graph. fn must not
+// modify the graph.
+func (s *SafeGraph) View(fn func(g *DirectedGraph)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(s.g)
+}
+
+/* Mutations */
+
+// AddNode adds node n to the graph
+func (s *SafeGraph) AddNode(n *Node) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.g.AddNode(n)
+}
+
+// RemoveNode removes n from the graph, as well as any edges attached to it.
+func (s *SafeGraph) RemoveNode(n *Node) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.g.RemoveNode(n)
+}
+
+// AddDirectedEdge adds directed edge e to the graph, returning an error as
+// DirectedGraph.AddDirectedEdge does
+func (s *SafeGraph) AddDirectedEdge(e *Edge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.g.AddDirectedEdge(e)
+}
+
+// RemoveDirectedEdge removes e from the graph, leaving the terminal nodes.
+func (s *SafeGraph) RemoveDirectedEdge(e *Edge) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.g.RemoveDirectedEdge(e)
+}
+
+/* Lookups */
+
+// HasNode checks if node exists in the graph
+func (s *SafeGraph) HasNode(n *Node) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.HasNode(n)
+}
+
+// Node returns the node with the given id, or nil
+func (s *SafeGraph) Node(id int) *Node {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.Node(id)
+}
+
+// HasEdge checks if edge exists in the graph
+func (s *SafeGraph) HasEdge(id [2]int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.HasEdge(id)
+}
+
+// Edge returns the edge with the given id, or nil
+func (s *SafeGraph) Edge(id [2]int) *Edge {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.Edge(id)
+}
+
+// Weight returns weight of directed edge from u to v
+func (s *SafeGraph) Weight(u, v *Node) (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.Weight(u, v)
+}
+
+/* Searches */
+
+// Dijkstra returns a shortest path from u to v and its distance.
+func (s *SafeGraph) Dijkstra(u, v *Node) ([]*Node, float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.Dijkstra(u, v)
+}
+
+// DijkstraBi returns a shortest path from u to v and its distance, using
+// bidirectional dijkstra.
+func (s *SafeGraph) DijkstraBi(u, v *Node) ([]*Node, float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.DijkstraBi(u, v)
+}
+
+// AStar returns a shortest path from u to v and its distance.
+func (s *SafeGraph) AStar(u, v *Node) ([]*Node, float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.AStar(u, v)
+}
+
+// AStarBi returns a shortest path from u to v and its distance, using
+// bidirectional A*.
+func (s *SafeGraph) AStarBi(u, v *Node) ([]*Node, float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.AStarBi(u, v)
+}
+
+// DijkstraInto returns a shortest path from u to v, appended to buf[:0],
+// and its distance.
+func (s *SafeGraph) DijkstraInto(u, v *Node, buf []*Node) ([]*Node, float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.DijkstraInto(u, v, buf)
+}
+
+// AStarInto returns a shortest path from u to v, appended to buf[:0], and
+// its distance.
+func (s *SafeGraph) AStarInto(u, v *Node, buf []*Node) ([]*Node, float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.AStarInto(u, v, buf)
+}
+
+// ShortestPath returns a shortest path from u to v and its distance, using
+// a search configured by opts.
+func (s *SafeGraph) ShortestPath(u, v *Node, opts ...Option) ([]*Node, float64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.ShortestPath(u, v, opts...)
+}
+
+// BreadthFirstSearch traverses the graph via breadth first search.
+func (s *SafeGraph) BreadthFirstSearch(from *Node, visit func(u, v *Node) VisitAction) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.g.BreadthFirstSearch(from, visit)
+}
+
+// DepthFirstSearch traverses the graph via depth first search.
+func (s *SafeGraph) DepthFirstSearch(from *Node, visit func(u, v *Node) VisitAction) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.g.DepthFirstSearch(from, visit)
+}
+
+// BFSFrom returns hop distances and predecessors of nodes reached from u.
+func (s *SafeGraph) BFSFrom(u *Node) (map[*Node]int, map[*Node]*Node) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.BFSFrom(u)
+}
+
+// DepthFirstSearchOrder traverses the graph depth first with pre and post
+// order callbacks, returning discovery and finish times.
+func (s *SafeGraph) DepthFirstSearchOrder(from *Node, pre, post func(n *Node)) DFSTimes {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.DepthFirstSearchOrder(from, pre, post)
+}
+
+// DepthLimitedSearch traverses paths of at most depth edges from `from`.
+func (s *SafeGraph) DepthLimitedSearch(from *Node, depth int, visit func(u, v *Node) VisitAction) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.DepthLimitedSearch(from, depth, visit)
+}
+
+// IDDFS finds a path with the fewest edges from `from` to `to`.
+func (s *SafeGraph) IDDFS(from, to *Node) ([]*Node, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.IDDFS(from, to)
+}
+
+// ParallelBFS computes hop distances from `from` using several goroutines.
+func (s *SafeGraph) ParallelBFS(from *Node, workers int) []int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.g.ParallelBFS(from, workers)
+}
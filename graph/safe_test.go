@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestSafeGraph runs searches while another goroutine adds and removes a
+// shortcut, so that the race detector catches unguarded accesses and every
+// search sees the graph either with or without it
+func TestSafeGraph(t *testing.T) {
+	g := NewGridGraph(10, 10, false, false)
+	s := Safe(g)
+	from, to := s.Node(0), s.Node(99)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			e := &Edge{ID: [2]int{0, 99}, From: from, To: to, Weight: 1}
+			s.AddDirectedEdge(e)
+			s.RemoveDirectedEdge(e)
+		}
+		s.Update(func(g *DirectedGraph) {
+			g.AddDirectedEdge(&Edge{ID: [2]int{0, 99}, From: from, To: to, Weight: 1})
+		})
+	}()
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, d := s.Dijkstra(from, to); d != 1 && d != 18 {
+					t.Errorf("got distance %v, want 1 or 18", d)
+					return
+				}
+				if levels := s.ParallelBFS(from, 2); levels[99] != 1 && levels[99] != 18 {
+					t.Errorf("got level %d, want 1 or 18", levels[99])
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !s.HasEdge([2]int{0, 99}) {
+		t.Fatal("edge added by Update is missing")
+	}
+	s.View(func(g *DirectedGraph) {
+		if w, ok := g.Weight(from, to); !ok || w != 1 {
+			t.Fatalf("got weight %v, %t, want 1, true", w, ok)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"math"
+)
+
+// searchScratch is the state of a search by node ID, kept between queries
+// so that DijkstraInto and AStarInto do not allocate
+type searchScratch struct {
+	dist    []float64
+	next    []*Node
+	touched []int32 // IDs whose dist and next were set
+	Q       *idHeap
+}
+
+// getScratch returns scratch space sized for the nodes of g, from the pool
+// of g if one was put back
+func (g *DirectedGraph) getScratch() *searchScratch {
+	s, _ := g.scratch.Get().(*searchScratch)
+	if s == nil {
+		s = &searchScratch{Q: newIDHeap(0)}
+	}
+
+	for len(s.dist) < len(g.Nodes) {
+		s.dist = append(s.dist, math.Inf(1))
+		s.next = append(s.next, nil)
+		s.Q.pos = append(s.Q.pos, -1)
+	}
+	return s
+}
+
+// putScratch resets the state set by a search and returns s to the pool
+func (g *DirectedGraph) putScratch(s *searchScratch) {
+	for _, id := range s.touched {
+		s.dist[id] = math.Inf(1)
+		s.next[id] = nil
+	}
+	for _, id := range s.Q.keys {
+		s.Q.pos[id] = -1
+	}
+	s.touched = s.touched[:0]
+	s.Q.keys, s.Q.prio = s.Q.keys[:0], s.Q.prio[:0]
+	g.scratch.Put(s)
+}
+
+// DijkstraInto is a variant of Dijkstra that appends the path to buf[:0]
+// and keeps its search state between queries, indexed by node ID, instead
+// of in maps, so that a caller reusing the path does not allocate. The path
+// is empty if there is none.
+func (g *DirectedGraph) DijkstraInto(u, v *Node, buf []*Node) ([]*Node, float64) {
+	return g.searchInto(u, v, nil, buf)
+}
+
+// AStarInto is the A* counterpart of DijkstraInto, with the heuristic of
+// AStar.
+func (g *DirectedGraph) AStarInto(u, v *Node, buf []*Node) ([]*Node, float64) {
+	path, dist := g.searchInto(u, v, func(n *Node) float64 { return g.Dist(n, v) }, buf)
+	g.checkHeuristic("AStar", u, v, dist)
+	return path, dist
+}
+
+// searchInto searches for a shortest path from u to v, guided by h if set,
+// and appends it to buf[:0]
+func (g *DirectedGraph) searchInto(u, v *Node, h func(n *Node) float64, buf []*Node) ([]*Node, float64) {
+	s := g.getScratch()
+	defer g.putScratch(s)
+
+	set := func(n *Node, dist float64, prev *Node) {
+		if math.IsInf(s.dist[n.ID], 1) {
+			s.touched = append(s.touched, int32(n.ID))
+		}
+		s.dist[n.ID], s.next[n.ID] = dist, prev
+	}
+
+	set(u, 0, nil)
+	s.Q.push(u.ID, 0)
+
+	expanded, queued := 0, 1
+	defer func() { g.reportSearch(expanded, queued) }()
+
+	for s.Q.len() > 0 {
+		mid := g.Nodes[s.Q.pop()]
+		expanded++
+
+		// terminates when final node is found
+		if mid == v {
+			break
+		}
+
+		for _, e := range mid.EdgeStart {
+			n := e.To
+
+			// update shortest paths
+			if acc_dist := s.dist[mid.ID] + e.Weight; acc_dist < s.dist[n.ID] {
+				set(n, acc_dist, mid)
+				p := acc_dist
+				if h != nil {
+					p += h(n)
+				}
+				s.Q.push(n.ID, p)
+				queued = max(queued, s.Q.len())
+			}
+		}
+	}
+
+	// no path found
+	dist := s.dist[v.ID]
+	if math.IsInf(dist, 1) {
+		return buf[:0], dist
+	}
+
+	path := buf[:0]
+	for n := v; n != nil; n = s.next[n.ID] {
+		path = append(path, n)
+	}
+
+	// reverse the path
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, dist
+}
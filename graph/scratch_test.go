@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestInto(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(200, rng)
+	g.AddNode(&Node{X: 50, Y: 50})
+
+	var buf []*Node
+	for i := 0; i < 10; i++ {
+		u := g.Nodes[rng.Intn(len(g.Nodes))]
+		dist := bellmanFord(g, u)
+		for _, v := range g.Nodes {
+			var d float64
+			buf, d = g.DijkstraInto(u, v, buf)
+			checkIDPath(t, g, u.ID, v.ID, ids(buf), d, dist[v.ID])
+			buf, d = g.AStarInto(u, v, buf)
+			checkIDPath(t, g, u.ID, v.ID, ids(buf), d, dist[v.ID])
+		}
+	}
+
+	// a buffer large enough is reused
+	buf = make([]*Node, 0, len(g.Nodes))
+	if path, _ := g.DijkstraInto(g.Nodes[0], g.Nodes[1], buf); len(path) > 0 && &path[:1][0] != &buf[:1][0] {
+		t.Fatal("path was not appended to the buffer")
+	}
+}
+
+// ids returns the IDs of nodes, or nil for an empty path
+func ids(nodes []*Node) []int {
+	if len(nodes) == 0 {
+		return nil
+	}
+	id := make([]int, len(nodes))
+	for i, n := range nodes {
+		id[i] = n.ID
+	}
+	return id
+}
+
+// BenchmarkDijkstraInto reports the allocations of a query with a warm buffer
+// and pool, which should be none. It is not a test, as the race detector
+// makes sync.Pool drop the search state at random.
+func BenchmarkDijkstraInto(b *testing.B) {
+	g := NewGridGraph(100, 100, false, false)
+	buf, _ := g.DijkstraInto(g.Nodes[0], g.Nodes[9999], nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, _ = g.DijkstraInto(g.Nodes[0], g.Nodes[9999], buf)
+	}
+}
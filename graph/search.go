@@ -0,0 +1,334 @@
+package graph
+
+import (
+	"context"
+	"math"
+
+	"github.com/hanyangtay/go-datastructures/metrics"
+)
+
+// ctxCheckInterval is the number of queue pops between context checks
+const ctxCheckInterval = 1024
+
+// searchOptions configures a single direction shortest path search
+type searchOptions struct {
+	ctx context.Context
+
+	// heuristic estimates the remaining distance from n to the target,
+	// nil for a plain dijkstra search
+	heuristic func(n *Node) float64
+
+	// targetHeuristic is the heuristic given to ShortestPath, from which
+	// heuristic is set once the target is known
+	targetHeuristic func(n, target *Node) float64
+
+	// nodeCosts adds the Cost of every node passed through to the distance,
+	// endpointCosts also adds those of the source and target
+	nodeCosts     bool
+	endpointCosts bool
+
+	// avoid excludes nodes and edges from the search
+	avoid *Avoid
+
+	// weight replaces the weights of edges, nil to use Edge.Weight
+	weight func(e *Edge) float64
+
+	// limited stops the search at paths longer than cutoff
+	limited bool
+	cutoff  float64
+}
+
+// Avoid lists nodes and edges a search must not use, e.g. road closures,
+// without removing them from the graph. Either map may be nil.
+type Avoid struct {
+	Nodes map[*Node]bool
+	Edges map[*Edge]bool
+}
+
+// excludes reports whether a search may not traverse e
+func (a *Avoid) excludes(e *Edge) bool {
+	return a != nil && (a.Edges[e] || a.Nodes[e.To])
+}
+
+// DijkstraCtx is a variant of Dijkstra that gives up once ctx is cancelled
+// or its deadline passes, returning ctx.Err() with no path.
+func (g *DirectedGraph) DijkstraCtx(ctx context.Context, u, v *Node) ([]*Node, float64, error) {
+	return g.shortestPath(u, v, searchOptions{ctx: ctx})
+}
+
+// AStarCtx is a variant of AStar that gives up once ctx is cancelled or its
+// deadline passes, returning ctx.Err() with no path.
+func (g *DirectedGraph) AStarCtx(ctx context.Context, u, v *Node) ([]*Node, float64, error) {
+	return g.shortestPath(u, v, searchOptions{
+		ctx:       ctx,
+		heuristic: func(n *Node) float64 { return g.Dist(n, v) },
+	})
+}
+
+// DijkstraNodeWeighted returns a shortest path from u to v and its distance,
+// where the distance includes the Cost of every intermediate node on the path.
+// If endpoints is set, the costs of u and v are included as well.
+func (g *DirectedGraph) DijkstraNodeWeighted(u, v *Node, endpoints bool) ([]*Node, float64) {
+	path, dist, _ := g.shortestPath(u, v, searchOptions{nodeCosts: true, endpointCosts: endpoints})
+	return path, dist
+}
+
+// AStarNodeWeighted is the A* counterpart of DijkstraNodeWeighted. Node costs
+// only add to distances, so the heuristic of AStar stays admissible.
+func (g *DirectedGraph) AStarNodeWeighted(u, v *Node, endpoints bool) ([]*Node, float64) {
+	path, dist, _ := g.shortestPath(u, v, searchOptions{
+		heuristic:     func(n *Node) float64 { return g.Dist(n, v) },
+		nodeCosts:     true,
+		endpointCosts: endpoints,
+	})
+	return path, dist
+}
+
+// DijkstraAvoid returns a shortest path from u to v and its distance that
+// does not use any of the nodes or edges listed in avoid. Listing u has no
+// effect, since every path starts there.
+func (g *DirectedGraph) DijkstraAvoid(u, v *Node, avoid Avoid) ([]*Node, float64) {
+	path, dist, _ := g.shortestPath(u, v, searchOptions{avoid: &avoid})
+	return path, dist
+}
+
+// AStarAvoid is the A* counterpart of DijkstraAvoid.
+func (g *DirectedGraph) AStarAvoid(u, v *Node, avoid Avoid) ([]*Node, float64) {
+	path, dist, _ := g.shortestPath(u, v, searchOptions{
+		heuristic: func(n *Node) float64 { return g.Dist(n, v) },
+		avoid:     &avoid,
+	})
+	return path, dist
+}
+
+// shortestPath searches for a shortest path from u to v, guided by the
+// heuristic in opts if one is set. The distance is +Inf if there is no path.
+func (g *DirectedGraph) shortestPath(u, v *Node, opts searchOptions) ([]*Node, float64, error) {
+	h := opts.heuristic
+	if h == nil {
+		h = func(*Node) float64 { return 0 }
+	}
+
+	start := 0.0
+	if opts.endpointCosts {
+		start = u.Cost
+	}
+
+	forwardDist := map[*Node]float64{u: start}
+	next := make(map[*Node]*Node)
+
+	// each node is queued at most once, its priority lowered as shorter
+	// distances are found
+	Q := g.newNodeQueue()
+	Q.push(u, start+h(u))
+
+	expanded, queued := 0, 1
+	defer func() { g.reportSearch(expanded, queued) }()
+
+	for pops := 0; Q.len() > 0; pops++ {
+		if opts.ctx != nil && pops%ctxCheckInterval == 0 {
+			if err := opts.ctx.Err(); err != nil {
+				return nil, math.Inf(1), err
+			}
+		}
+
+		mid := Q.pop()
+		expanded++
+
+		// terminates when final node is found
+		if mid == v {
+			return tracePath(next, u, v), forwardDist[v], nil
+		}
+
+		for _, e := range mid.EdgeStart {
+			if opts.avoid.excludes(e) {
+				continue
+			}
+
+			n := e.To
+
+			w := e.Weight
+			if opts.weight != nil {
+				w = opts.weight(e)
+			}
+
+			// total distance travelled so far
+			acc_dist := forwardDist[mid] + w
+			if opts.nodeCosts && (n != v || opts.endpointCosts) {
+				acc_dist += n.Cost
+			}
+			if opts.limited && acc_dist > opts.cutoff {
+				continue
+			}
+
+			// update shortest paths
+			if dist, ok := forwardDist[n]; !ok || acc_dist < dist {
+				Q.push(n, acc_dist+h(n))
+				queued = max(queued, Q.len())
+				forwardDist[n] = acc_dist
+				next[n] = mid
+			}
+		}
+	}
+
+	// no path found
+	return nil, math.Inf(1), nil
+}
+
+// reportSearch reports a search that expanded the given number of nodes,
+// with at most queued nodes in its queue at once, to g.Metrics
+func (g *DirectedGraph) reportSearch(expanded, queued int) {
+	m := metrics.Or(g.Metrics)
+	m.Count("graph_searches_total", 1)
+	m.Observe("graph_nodes_expanded", float64(expanded))
+	m.Observe("graph_queue_size", float64(queued))
+}
+
+// tracePath follows the predecessors in next from v back to u and returns
+// the path from u to v
+func tracePath(next map[*Node]*Node, u, v *Node) []*Node {
+	n := v
+	path := []*Node{v}
+
+	for n != u {
+		n = next[n]
+		path = append(path, n)
+	}
+
+	// reverse the path
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+// keyedHeap is an array based min-heap of keys ordered by priority, in which
+// every entry has up to arity children. Pushing a key that is already queued
+// changes its priority in place (decrease-key), so the heap never holds more
+// than one entry per key. The index of every queued key is kept in pos, a map
+// for nodes and a slice for node IDs.
+//
+// The searches use it rather than indexedpq: it needs no handle allocated
+// per entry, supports DaryHeap, and a heap of IDs keeps its index in a flat
+// slice.
+type keyedHeap[K any, S heapIndex[K]] struct {
+	keys  []K
+	prio  []float64
+	pos   S
+	arity int
+}
+
+// heapIndex records the index of every key queued in a keyedHeap
+type heapIndex[K any] interface {
+	get(k K) (int, bool)
+	set(k K, i int)
+	remove(k K)
+}
+
+// nodeHeap is a keyedHeap of nodes, and idHeap a binary keyedHeap of node
+// IDs below the length of its pos
+type (
+	nodeHeap = keyedHeap[*Node, nodeIndex]
+	idHeap   = keyedHeap[int, idIndex]
+)
+
+func newNodeHeap(arity int) *nodeHeap {
+	return &nodeHeap{pos: make(nodeIndex), arity: arity}
+}
+
+func newIDHeap(n int) *idHeap {
+	pos := make(idIndex, n)
+	for i := range pos {
+		pos[i] = -1
+	}
+	return &idHeap{pos: pos, arity: 2}
+}
+
+type nodeIndex map[*Node]int
+
+func (x nodeIndex) get(n *Node) (int, bool) { i, ok := x[n]; return i, ok }
+func (x nodeIndex) set(n *Node, i int)      { x[n] = i }
+func (x nodeIndex) remove(n *Node)          { delete(x, n) }
+
+// idIndex holds the index of every ID, or -1 if it is not queued
+type idIndex []int32
+
+func (x idIndex) get(id int) (int, bool) { return int(x[id]), x[id] >= 0 }
+func (x idIndex) set(id int, i int)      { x[id] = int32(i) }
+func (x idIndex) remove(id int)          { x[id] = -1 }
+
+func (q *keyedHeap[K, S]) len() int { return len(q.keys) }
+
+// push queues k with priority p, or moves it to p if it is already queued
+func (q *keyedHeap[K, S]) push(k K, p float64) {
+	i, ok := q.pos.get(k)
+	if !ok {
+		i = len(q.keys)
+		q.keys = append(q.keys, k)
+		q.prio = append(q.prio, p)
+		q.pos.set(k, i)
+	}
+
+	old := q.prio[i]
+	q.prio[i] = p
+
+	if ok && p > old {
+		q.down(i)
+	} else {
+		q.up(i)
+	}
+	checkHeap(q.prio, q.arity)
+}
+
+// pop removes and returns the key with the lowest priority
+func (q *keyedHeap[K, S]) pop() K {
+	k := q.keys[0]
+	last := len(q.keys) - 1
+
+	q.swap(0, last)
+	q.keys, q.prio = q.keys[:last], q.prio[:last]
+	q.pos.remove(k)
+
+	if last > 0 {
+		q.down(0)
+	}
+	checkHeap(q.prio, q.arity)
+
+	return k
+}
+
+func (q *keyedHeap[K, S]) swap(i, j int) {
+	q.keys[i], q.keys[j] = q.keys[j], q.keys[i]
+	q.prio[i], q.prio[j] = q.prio[j], q.prio[i]
+	q.pos.set(q.keys[i], i)
+	q.pos.set(q.keys[j], j)
+}
+
+func (q *keyedHeap[K, S]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / q.arity
+		if q.prio[parent] <= q.prio[i] {
+			break
+		}
+		q.swap(i, parent)
+		i = parent
+	}
+}
+
+func (q *keyedHeap[K, S]) down(i int) {
+	for {
+		least := i
+		first := q.arity*i + 1
+		for c := first; c < first+q.arity && c < len(q.keys); c++ {
+			if q.prio[c] < q.prio[least] {
+				least = c
+			}
+		}
+		if least == i {
+			return
+		}
+		q.swap(i, least)
+		i = least
+	}
+}
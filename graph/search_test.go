@@ -0,0 +1,301 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// newRandomDirectedGraph returns a random geometric graph with extra one-way
+// edges, so that distances are not symmetric. Edges are no shorter than the
+// distance between their nodes, so that A* finds shortest paths.
+func newRandomDirectedGraph(n int, rng *rand.Rand) *DirectedGraph {
+	g := NewRandomGeometricGraph(n, 100, 100, 12, rng)
+	for i := 0; i < n; i++ {
+		u, v := g.Nodes[rng.Intn(n)], g.Nodes[rng.Intn(n)]
+		if u != v {
+			g.AddDirectedEdge(&Edge{ID: [2]int{u.ID, v.ID}, From: u, To: v, Weight: Dist(u, v) * (1 + rng.Float64())})
+		}
+	}
+	return g
+}
+
+// bellmanFord returns the distances from u to every node of g, by node ID
+func bellmanFord(g *DirectedGraph, u *Node) []float64 {
+	dist := make([]float64, len(g.Nodes))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	dist[u.ID] = 0
+
+	for changed := true; changed; {
+		changed = false
+		for _, n := range g.Nodes {
+			for _, e := range n.EdgeStart {
+				if d := dist[n.ID] + e.Weight; d < dist[e.To.ID] {
+					dist[e.To.ID] = d
+					changed = true
+				}
+			}
+		}
+	}
+	return dist
+}
+
+// checkPath fails t unless path is a path from u to v in g of length dist,
+// which is want
+func checkPath(t *testing.T, g *DirectedGraph, u, v *Node, path []*Node, dist, want float64) {
+	t.Helper()
+
+	if math.IsInf(want, 1) {
+		if path != nil || !math.IsInf(dist, 1) {
+			t.Fatalf("%d -> %d: got path %v at %v, want none", u.ID, v.ID, path, dist)
+		}
+		return
+	}
+	if math.Abs(dist-want) > 1e-9 {
+		t.Fatalf("%d -> %d: got distance %v, want %v", u.ID, v.ID, dist, want)
+	}
+	if len(path) == 0 || path[0] != u || path[len(path)-1] != v {
+		t.Fatalf("%d -> %d: path %v does not join the nodes", u.ID, v.ID, path)
+	}
+
+	length := 0.0
+	for i := 1; i < len(path); i++ {
+		w := math.Inf(1)
+		for _, e := range path[i-1].EdgeStart {
+			if e.To == path[i] {
+				w = math.Min(w, e.Weight)
+			}
+		}
+		length += w
+	}
+	if math.Abs(length-want) > 1e-9 {
+		t.Fatalf("%d -> %d: path has length %v, want %v", u.ID, v.ID, length, want)
+	}
+}
+
+func TestShortestPaths(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(200, rng)
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		u := g.Nodes[rng.Intn(len(g.Nodes))]
+		dist := bellmanFord(g, u)
+		for _, v := range g.Nodes {
+			want := dist[v.ID]
+
+			path, d := g.Dijkstra(u, v)
+			checkPath(t, g, u, v, path, d, want)
+			path, d = g.AStar(u, v)
+			checkPath(t, g, u, v, path, d, want)
+
+			path, d, err := g.DijkstraCtx(ctx, u, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPath(t, g, u, v, path, d, want)
+			path, d, err = g.AStarCtx(ctx, u, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPath(t, g, u, v, path, d, want)
+		}
+	}
+}
+
+// TestBidirectional checks DijkstraBi and AStarBi, whose stopping rules
+// depend on both directions of the search
+func TestBidirectional(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(200, rng)
+	g.AddNode(&Node{X: 50, Y: 50})
+
+	for i := 0; i < 10; i++ {
+		u := g.Nodes[rng.Intn(len(g.Nodes))]
+		dist := bellmanFord(g, u)
+		for _, v := range g.Nodes {
+			path, d := g.DijkstraBi(u, v)
+			checkPath(t, g, u, v, path, d, dist[v.ID])
+			path, d = g.AStarBi(u, v)
+			checkPath(t, g, u, v, path, d, dist[v.ID])
+		}
+	}
+}
+
+func TestSearchCancelled(t *testing.T) {
+	g := NewGridGraph(10, 10, false, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if path, d, err := g.DijkstraCtx(ctx, g.Nodes[0], g.Nodes[99]); !errors.Is(err, context.Canceled) || path != nil || !math.IsInf(d, 1) {
+		t.Fatalf("DijkstraCtx = %v, %v, %v, want no path and context.Canceled", path, d, err)
+	}
+	if path, d, err := g.AStarCtx(ctx, g.Nodes[0], g.Nodes[99]); !errors.Is(err, context.Canceled) || path != nil || !math.IsInf(d, 1) {
+		t.Fatalf("AStarCtx = %v, %v, %v, want no path and context.Canceled", path, d, err)
+	}
+}
+
+func TestNodeWeighted(t *testing.T) {
+	g := newRandomDirectedGraph(200, rand.New(rand.NewSource(1)))
+
+	// h is a copy of g with the node costs added to the edges leading to
+	// them
+	h := newRandomDirectedGraph(200, rand.New(rand.NewSource(1)))
+	rng := rand.New(rand.NewSource(2))
+	for i, n := range g.Nodes {
+		n.Cost = rng.Float64() * 20
+		for _, e := range h.Nodes[i].EdgeEnd {
+			e.Weight += n.Cost
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		u := g.Nodes[rng.Intn(len(g.Nodes))]
+		dist := bellmanFord(h, h.Nodes[u.ID])
+		for _, v := range g.Nodes {
+			for _, endpoints := range []bool{false, true} {
+				want := dist[v.ID]
+				if endpoints {
+					want += u.Cost
+				} else if u != v {
+					want -= v.Cost
+				}
+
+				path, d := g.DijkstraNodeWeighted(u, v, endpoints)
+				if math.Abs(d-want) > 1e-9 {
+					t.Fatalf("%d -> %d: got distance %v, want %v", u.ID, v.ID, d, want)
+				}
+				if p, err := NewPath(path); err != nil || math.Abs(p.NodeCost(endpoints)-want) > 1e-9 {
+					t.Fatalf("%d -> %d: path %v does not cost %v", u.ID, v.ID, path, want)
+				}
+
+				if _, d := g.AStarNodeWeighted(u, v, endpoints); math.Abs(d-want) > 1e-9 {
+					t.Fatalf("%d -> %d: got A* distance %v, want %v", u.ID, v.ID, d, want)
+				}
+			}
+		}
+	}
+}
+
+func TestAvoid(t *testing.T) {
+	g := newRandomDirectedGraph(200, rand.New(rand.NewSource(1)))
+
+	// h is a copy of g without the avoided nodes and edges
+	h := newRandomDirectedGraph(200, rand.New(rand.NewSource(1)))
+	twin := make(map[*Edge]*Edge)
+	for i, n := range g.Nodes {
+		for j, e := range n.EdgeStart {
+			twin[e] = h.Nodes[i].EdgeStart[j]
+		}
+	}
+
+	rng := rand.New(rand.NewSource(2))
+	avoid := Avoid{Nodes: make(map[*Node]bool), Edges: make(map[*Edge]bool)}
+	for i := 0; i < 100; i++ {
+		if n := g.Nodes[rng.Intn(len(g.Nodes))]; len(n.EdgeStart) > 0 {
+			e := n.EdgeStart[rng.Intn(len(n.EdgeStart))]
+			avoid.Edges[e] = true
+			h.RemoveDirectedEdge(twin[e])
+		}
+	}
+	for i := 0; i < 20; i++ {
+		n := g.Nodes[rng.Intn(len(g.Nodes))]
+		avoid.Nodes[n] = true
+		h.RemoveNode(h.Nodes[n.ID])
+	}
+
+	for i := 0; i < 10; i++ {
+		u := g.Nodes[rng.Intn(len(g.Nodes))]
+		if avoid.Nodes[u] {
+			continue
+		}
+		dist := bellmanFord(h, h.Nodes[u.ID])
+		for _, v := range g.Nodes {
+			path, d := g.DijkstraAvoid(u, v, avoid)
+			checkPath(t, g, u, v, path, d, dist[v.ID])
+			path, d = g.AStarAvoid(u, v, avoid)
+			checkPath(t, g, u, v, path, d, dist[v.ID])
+
+			for j := 1; j < len(path); j++ {
+				if n := path[j]; avoid.Nodes[n] {
+					t.Fatalf("%d -> %d: path %v passes avoided node %d", u.ID, v.ID, path, n.ID)
+				}
+			}
+		}
+	}
+}
+
+// TestNodeHeap checks the decrease-key heap of the searches against a map of
+// the queued priorities
+func TestNodeHeap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	nodes := make([]*Node, 50)
+	for i := range nodes {
+		nodes[i] = &Node{ID: i}
+	}
+
+	for arity := 2; arity <= 4; arity++ {
+		q := newNodeHeap(arity)
+		queued := make(map[*Node]float64)
+		for i := 0; i < 5000; i++ {
+			if rng.Intn(3) > 0 || len(queued) == 0 {
+				n, p := nodes[rng.Intn(len(nodes))], rng.Float64()
+				q.push(n, p)
+				queued[n] = p
+			} else {
+				n := q.pop()
+				for m, p := range queued {
+					if p < queued[n] {
+						t.Fatalf("arity %d: popped node %d at %v before node %d at %v", arity, n.ID, queued[n], m.ID, p)
+					}
+				}
+				if _, ok := queued[n]; !ok {
+					t.Fatalf("arity %d: popped node %d, which is not queued", arity, n.ID)
+				}
+				delete(queued, n)
+			}
+
+			if q.len() != len(queued) {
+				t.Fatalf("arity %d: got length %d, want %d", arity, q.len(), len(queued))
+			}
+		}
+	}
+}
+
+// recorder keeps the metrics it receives
+type recorder struct {
+	counts       map[string]int64
+	observations map[string][]float64
+}
+
+func (r *recorder) Count(name string, delta int64) { r.counts[name] += delta }
+
+func (r *recorder) Observe(name string, value float64) {
+	r.observations[name] = append(r.observations[name], value)
+}
+
+func TestSearchMetrics(t *testing.T) {
+	// on a line every node is expanded, with one queued at a time
+	g := newGraph(5, [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}})
+	r := &recorder{counts: map[string]int64{}, observations: map[string][]float64{}}
+	g.Metrics = r
+
+	g.Dijkstra(g.Nodes[0], g.Nodes[4])
+	g.AStar(g.Nodes[2], g.Nodes[4])
+	if got := r.counts["graph_searches_total"]; got != 2 {
+		t.Fatalf("got %d searches, want 2", got)
+	}
+	for name, want := range map[string][]float64{
+		"graph_nodes_expanded": {5, 3},
+		"graph_queue_size":     {1, 1},
+	} {
+		got := r.observations[name]
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("got %s %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/hanyangtay/go-datastructures/dsu"
+)
+
+// ErrNotConnected is returned when nodes that must be connected are not
+var ErrNotConnected = errors.New("graph: nodes are not connected")
+
+// SteinerTree returns a tree of edges connecting all terminals, and its total
+// weight, which is at most twice that of the lightest such tree. Edges are
+// treated as undirected links, so the tree may use an edge against its
+// direction; of two opposite edges only the lighter one is ever used.
+//
+// The tree is built by the algorithm of Kou, Markowsky and Berman: a minimum
+// spanning tree of the terminals under shortest path distances is expanded
+// into the underlying paths, the minimum spanning tree of those edges taken
+// again, and non-terminal leaves are pruned. This runs one dijkstra search
+// per terminal. ErrNotConnected is returned if some terminals cannot be
+// linked.
+func (g *DirectedGraph) SteinerTree(terminals []*Node) ([]*Edge, float64, error) {
+	isTerminal := make(map[*Node]bool, len(terminals))
+	var unique []*Node
+	for _, t := range terminals {
+		if !isTerminal[t] {
+			isTerminal[t] = true
+			unique = append(unique, t)
+		}
+	}
+	terminals = unique
+
+	if len(terminals) < 2 {
+		return nil, 0, nil
+	}
+
+	// shortest paths from every terminal, ignoring edge directions
+	dist := make([][]float64, len(terminals))
+	via := make([][]*Edge, len(terminals))
+	Q := newIDHeap(len(g.Nodes))
+	for i, t := range terminals {
+		dist[i], via[i] = g.dijkstraTree(t, Q, true)
+	}
+
+	// prim's algorithm over the metric closure of the terminals
+	inTree := make([]bool, len(terminals))
+	best := make([]float64, len(terminals))
+	parent := make([]int, len(terminals))
+	for i := range best {
+		best[i] = math.Inf(1)
+	}
+	best[0] = 0
+
+	links := make(map[[2]int]*Edge)
+	for range terminals {
+		next := -1
+		for i := range terminals {
+			if !inTree[i] && (next < 0 || best[i] < best[next]) {
+				next = i
+			}
+		}
+		if math.IsInf(best[next], 1) {
+			return nil, 0, ErrNotConnected
+		}
+		inTree[next] = true
+
+		// expand the closure edge into the path it stands for
+		if next != 0 {
+			from := parent[next]
+			for n := terminals[next]; n != terminals[from]; {
+				e := via[from][n.ID]
+				addLink(links, e)
+				if e.To == n {
+					n = e.From
+				} else {
+					n = e.To
+				}
+			}
+		}
+
+		for i, t := range terminals {
+			if d := dist[next][t.ID]; !inTree[i] && d < best[i] {
+				best[i] = d
+				parent[i] = next
+			}
+		}
+	}
+
+	// kruskal's algorithm over the union of the paths, which may share
+	// nodes and so contain cycles
+	edges := make([]*Edge, 0, len(links))
+	for _, e := range links {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Weight != edges[j].Weight {
+			return edges[i].Weight < edges[j].Weight
+		}
+		return edges[i].ID[0] < edges[j].ID[0] ||
+			edges[i].ID[0] == edges[j].ID[0] && edges[i].ID[1] < edges[j].ID[1]
+	})
+
+	sets := dsu.New(len(g.Nodes))
+	tree := edges[:0]
+	degree := make(map[*Node]int)
+	for _, e := range edges {
+		if !sets.Union(e.From.ID, e.To.ID) {
+			continue
+		}
+		tree = append(tree, e)
+		degree[e.From]++
+		degree[e.To]++
+	}
+
+	// prune leaves that are not terminals, which can be left over when
+	// paths overlap
+	removed := make(map[*Edge]bool)
+	for pruned := true; pruned; {
+		pruned = false
+		for _, e := range tree {
+			if removed[e] {
+				continue
+			}
+			for _, n := range []*Node{e.From, e.To} {
+				if degree[n] == 1 && !isTerminal[n] {
+					removed[e] = true
+					degree[e.From]--
+					degree[e.To]--
+					pruned = true
+					break
+				}
+			}
+		}
+	}
+
+	result := make([]*Edge, 0, len(tree)-len(removed))
+	cost := 0.0
+	for _, e := range tree {
+		if !removed[e] {
+			result = append(result, e)
+			cost += e.Weight
+		}
+	}
+
+	return result, cost, nil
+}
+
+// addLink adds e to links, keyed by its unordered endpoints, unless a
+// lighter edge between the same nodes is already present
+func addLink(links map[[2]int]*Edge, e *Edge) {
+	key := [2]int{e.From.ID, e.To.ID}
+	if key[0] > key[1] {
+		key[0], key[1] = key[1], key[0]
+	}
+	if other, ok := links[key]; !ok || e.Weight < other.Weight {
+		links[key] = e
+	}
+}
+
+// dijkstraTree returns the distances from u to all nodes by ID, and the edge
+// through which each node was reached. If undirected is set, edges are also
+// followed against their direction. Q must be empty.
+func (g *DirectedGraph) dijkstraTree(u *Node, Q *idHeap, undirected bool) ([]float64, []*Edge) {
+	dist := make([]float64, len(g.Nodes))
+	via := make([]*Edge, len(g.Nodes))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+
+	dist[u.ID] = 0
+	Q.push(u.ID, 0)
+
+	for Q.len() > 0 {
+		mid := g.Nodes[Q.pop()]
+
+		relax := func(n *Node, e *Edge) bool {
+			if acc_dist := dist[mid.ID] + e.Weight; acc_dist < dist[n.ID] {
+				dist[n.ID] = acc_dist
+				via[n.ID] = e
+				Q.push(n.ID, acc_dist)
+			}
+			return true
+		}
+		g.NeighborsFrom(mid, relax)
+		if undirected {
+			g.NeighborsTo(mid, relax)
+		}
+	}
+
+	return dist, via
+}
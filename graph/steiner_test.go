@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+// checkTree fails t unless edges form a tree of weight w that connects the
+// terminals
+func checkTree(t *testing.T, edges []*Edge, w float64, terminals []*Node) {
+	t.Helper()
+
+	root := make(map[*Node]*Node)
+	find := func(n *Node) *Node {
+		for root[n] != nil {
+			n = root[n]
+		}
+		return n
+	}
+
+	total := 0.0
+	for _, e := range edges {
+		a, b := find(e.From), find(e.To)
+		if a == b {
+			t.Fatalf("edge %v closes a cycle", e.ID)
+		}
+		root[a] = b
+		total += e.Weight
+	}
+	if total != w {
+		t.Fatalf("got weight %v, edges weigh %v", w, total)
+	}
+	for _, n := range terminals {
+		if find(n) != find(terminals[0]) {
+			t.Fatalf("terminal %d is not connected to %d", n.ID, terminals[0].ID)
+		}
+	}
+}
+
+func TestSteinerTree(t *testing.T) {
+	g := NewGridGraph(5, 5, false, false)
+
+	// the lightest tree joining the corners weighs 12
+	corners := []*Node{g.Nodes[0], g.Nodes[4], g.Nodes[20], g.Nodes[24]}
+	edges, w, err := g.SteinerTree(corners)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTree(t, edges, w, corners)
+	if w < 12 || w > 24 {
+		t.Fatalf("got weight %v, want 12 to 24", w)
+	}
+
+	// terminals along a shortest path are joined by that path
+	line := []*Node{g.Nodes[4], g.Nodes[0], g.Nodes[2], g.Nodes[0]}
+	if edges, w, err = g.SteinerTree(line); err != nil || w != 4 {
+		t.Fatalf("got weight %v and error %v, want 4", w, err)
+	}
+	checkTree(t, edges, w, line)
+
+	if edges, w, err = g.SteinerTree(g.Nodes[:1]); edges != nil || w != 0 || err != nil {
+		t.Fatalf("single terminal: got %d edges of weight %v and error %v", len(edges), w, err)
+	}
+
+	// edges are followed against their direction
+	h := newGraph(4, [][2]int{{0, 1}, {2, 1}, {3, 2}})
+	if edges, w, err = h.SteinerTree([]*Node{h.Nodes[0], h.Nodes[3]}); err != nil || w != 3 {
+		t.Fatalf("got weight %v and error %v, want 3", w, err)
+	}
+
+	h.AddNode(&Node{})
+	if _, _, err = h.SteinerTree([]*Node{h.Nodes[0], h.Nodes[4]}); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("got error %v, want ErrNotConnected", err)
+	}
+}
@@ -1,56 +1,282 @@
 package graph
 
-// BreadthFirstSearch traverses the graph via breadth first search.
-func (g *DirectedGraph) BreadthFirstSearch(from *Node, visit func(u, v *Node)) {
+import (
+	"github.com/hanyangtay/go-datastructures/bitset"
+	"github.com/hanyangtay/go-datastructures/deque"
+)
 
-	visited := make([]int, len(g.Nodes))
-	visited[from.ID] = true
-	queue := []*Node{from}
+// VisitAction is returned by traversal callbacks to control the search.
+type VisitAction int
 
-	for len(queue) > 0 {
-		u := queue[0]
-		queue = queue[1:]
+const (
+	// Continue expands the visited node as usual.
+	Continue VisitAction = iota
+	// Skip does not expand the visited node, so nodes beyond it are only
+	// reached through other paths.
+	Skip
+	// Stop terminates the entire search.
+	Stop
+)
 
-		for _, v := range g.FindNeighboursFrom(u) {
-			if visited[v.ID] {
-				continue
-			}
+// BreadthFirstSearch traverses the graph via breadth first search.
+// visit is called for every edge (u, v) leading to an unvisited node v, and
+// its result decides whether v is expanded or the search stops.
+func (g *DirectedGraph) BreadthFirstSearch(from *Node, visit func(u, v *Node) VisitAction) {
 
-			//process vertex u, v
-			if visit != nil {
-				visit(u, v)
-			}
+	visited := bitset.New(len(g.Nodes))
+	visited.Set(from.ID)
+	var queue deque.Deque[*Node]
+	queue.PushBack(from)
 
-			visited[v.ID] = true
-			queue = append(queue, v)
+	for queue.Len() > 0 {
+		u := queue.PopFront()
+
+		if !g.expand(u, visited, visit, queue.PushBack) {
+			return
 		}
 	}
-
 }
 
 // DepthFirstSearch traverses the graph via depth first search.
-func (g *DirectedGraph) DepthFirstSearch(from *Node, visit func(u, v *Node)) {
+// visit is called for every edge (u, v) leading to an unvisited node v, and
+// its result decides whether v is expanded or the search stops.
+func (g *DirectedGraph) DepthFirstSearch(from *Node, visit func(u, v *Node) VisitAction) {
 
-	visited := make([]int, len(g.Nodes))
-	visited[from.ID] = true
+	visited := bitset.New(len(g.Nodes))
+	visited.Set(from.ID)
 	stack := []*Node{from}
+	push := func(v *Node) { stack = append(stack, v) }
 
 	for len(stack) > 0 {
 		u := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
 
-		for _, v := range g.FindNeighboursFrom(u) {
-			if visited[v.ID] {
+		if !g.expand(u, visited, visit, push) {
+			return
+		}
+	}
+}
+
+// expand visits the unvisited neighbours of u, handing those to be expanded
+// to push. It returns false if visit stopped the search.
+func (g *DirectedGraph) expand(u *Node, visited *bitset.BitSet, visit func(u, v *Node) VisitAction, push func(v *Node)) bool {
+	stopped := false
+
+	g.NeighborsFrom(u, func(v *Node, _ *Edge) bool {
+		if visited.TestAndSet(v.ID) {
+			return true
+		}
+
+		//process vertex u, v
+		action := Continue
+		if visit != nil {
+			action = visit(u, v)
+		}
+
+		switch action {
+		case Stop:
+			stopped = true
+			return false
+		case Skip:
+			return true
+		}
+
+		push(v)
+		return true
+	})
+
+	return !stopped
+}
+
+// BFSFrom runs a breadth first search from u and returns the hop distance
+// and predecessor of every reached node. Following prev from any node leads
+// back to u along a path with the fewest edges; u itself has no predecessor.
+func (g *DirectedGraph) BFSFrom(u *Node) (hops map[*Node]int, prev map[*Node]*Node) {
+	hops = map[*Node]int{u: 0}
+	prev = make(map[*Node]*Node)
+
+	g.BreadthFirstSearch(u, func(from, to *Node) VisitAction {
+		hops[to] = hops[from] + 1
+		prev[to] = from
+		return Continue
+	})
+
+	return hops, prev
+}
+
+// DFSTimes records the discovery and finish time of every node reached by a
+// depth first search. All timestamps come from a single clock, so the
+// interval [Discovered, Finished] of a node nests inside those of its
+// ancestors in the search tree.
+type DFSTimes struct {
+	Discovered map[*Node]int
+	Finished   map[*Node]int
+}
+
+// dfsFrame is a node on the explicit DFS stack and the next edge to explore
+type dfsFrame struct {
+	node *Node
+	next int
+}
+
+// DepthFirstSearchOrder traverses the graph via depth first search, calling
+// pre when a node is discovered and post once all of its descendants are
+// finished. Either callback may be nil. If from is nil, every node of the
+// graph is covered, starting a new search tree at each unvisited node in ID
+// order. The search uses an explicit stack, so deep graphs cannot overflow
+// the goroutine stack.
+func (g *DirectedGraph) DepthFirstSearchOrder(from *Node, pre, post func(n *Node)) DFSTimes {
+	times := DFSTimes{
+		Discovered: make(map[*Node]int),
+		Finished:   make(map[*Node]int),
+	}
+	clock := 0
+
+	discover := func(n *Node) {
+		times.Discovered[n] = clock
+		clock++
+		if pre != nil {
+			pre(n)
+		}
+	}
+
+	search := func(root *Node) {
+		discover(root)
+		stack := []dfsFrame{{node: root}}
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+
+			// all edges explored, finish the node
+			if top.next == len(top.node.EdgeStart) {
+				times.Finished[top.node] = clock
+				clock++
+				if post != nil {
+					post(top.node)
+				}
+				stack = stack[:len(stack)-1]
 				continue
 			}
 
-			//process vertex u, v
-			if visit != nil {
-				visit(u, v)
+			v := top.node.EdgeStart[top.next].To
+			top.next++
+
+			if _, ok := times.Discovered[v]; !ok {
+				discover(v)
+				stack = append(stack, dfsFrame{node: v})
+			}
+		}
+	}
+
+	if from != nil {
+		search(from)
+		return times
+	}
+
+	for _, n := range g.Nodes {
+		if _, ok := times.Discovered[n]; !ok {
+			search(n)
+		}
+	}
+
+	return times
+}
+
+// DepthLimitedSearch traverses the graph depth first from `from`, following
+// paths of at most depth edges. visit is called for every edge (u, v) that
+// extends the current path, and its result decides whether v is expanded or
+// the search stops.
+// Only the current path is remembered rather than a visited set, so memory
+// grows with depth instead of the size of the graph, at the cost of visiting
+// nodes once for every path leading to them. Returns true if the depth limit
+// cut off any path that could have been extended further.
+func (g *DirectedGraph) DepthLimitedSearch(from *Node, depth int, visit func(u, v *Node) VisitAction) bool {
+	_, cutoff := g.depthLimitedSearch(from, depth, visit)
+	return cutoff
+}
+
+// IDDFS finds a path from `from` to `to` with the fewest edges by running depth
+// limited searches of increasing depth. It reports false if to is unreachable.
+func (g *DirectedGraph) IDDFS(from, to *Node) ([]*Node, bool) {
+	if from == to {
+		return []*Node{from}, true
+	}
+
+	found := func(u, v *Node) VisitAction {
+		if v == to {
+			return Stop
+		}
+		return Continue
+	}
+
+	for depth := 1; ; depth++ {
+		path, cutoff := g.depthLimitedSearch(from, depth, found)
+		if path != nil {
+			return path, true
+		}
+
+		// every path was explored in full, a deeper search finds nothing new
+		if !cutoff {
+			return nil, false
+		}
+	}
+}
+
+// depthLimitedSearch implements DepthLimitedSearch. If visit stops the
+// search, it also returns the path from `from` to the node being visited.
+func (g *DirectedGraph) depthLimitedSearch(from *Node, depth int, visit func(u, v *Node) VisitAction) ([]*Node, bool) {
+	cutoff := false
+	onPath := map[*Node]bool{from: true}
+	stack := []dfsFrame{{node: from}}
+
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		u := top.node
+
+		// backtrack at the depth limit or once all edges are explored, noting
+		// whether the path could have gone further
+		if len(stack)-1 == depth || top.next == len(u.EdgeStart) {
+			if len(stack)-1 == depth {
+				for _, e := range u.EdgeStart {
+					if !onPath[e.To] {
+						cutoff = true
+						break
+					}
+				}
 			}
 
-			visited[v.ID] = true
-			stack = append(stack, v)
+			delete(onPath, u)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		v := u.EdgeStart[top.next].To
+		top.next++
+
+		// avoid cycles along the current path
+		if onPath[v] {
+			continue
 		}
+
+		action := Continue
+		if visit != nil {
+			action = visit(u, v)
+		}
+
+		switch action {
+		case Stop:
+			path := make([]*Node, 0, len(stack)+1)
+			for _, f := range stack {
+				path = append(path, f.node)
+			}
+			return append(path, v), cutoff
+		case Skip:
+			continue
+		}
+
+		onPath[v] = true
+		stack = append(stack, dfsFrame{node: v})
 	}
+
+	return nil, cutoff
 }
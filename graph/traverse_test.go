@@ -0,0 +1,222 @@
+package graph
+
+import (
+	"math"
+	"testing"
+)
+
+// newGraph returns a graph of n nodes joined by the given edges, each of
+// weight 1
+func newGraph(n int, edges [][2]int) *DirectedGraph {
+	g := NewDirectedGraph()
+	for i := 0; i < n; i++ {
+		g.AddNode(&Node{X: float64(i)})
+	}
+	for _, id := range edges {
+		g.AddDirectedEdge(&Edge{ID: id, From: g.Nodes[id[0]], To: g.Nodes[id[1]], Weight: 1})
+	}
+	return g
+}
+
+// hops returns the number of edges between two nodes of a 4-connected grid
+// graph
+func hops(u, v *Node) int {
+	return int(math.Abs(u.X-v.X) + math.Abs(u.Y-v.Y))
+}
+
+func TestBreadthFirstSearch(t *testing.T) {
+	g := NewGridGraph(5, 5, false, false)
+	from := g.Nodes[12]
+
+	visited := map[*Node]bool{from: true}
+	last := 0
+	g.BreadthFirstSearch(from, func(u, v *Node) VisitAction {
+		if visited[v] {
+			t.Fatalf("visited %d twice", v.ID)
+		}
+		visited[v] = true
+		if h := hops(from, v); h < last || h != hops(from, u)+1 {
+			t.Fatalf("visited %d at %d hops after a node at %d hops", v.ID, h, last)
+		} else {
+			last = h
+		}
+		return Continue
+	})
+	if len(visited) != 25 {
+		t.Fatalf("visited %d nodes, want 25", len(visited))
+	}
+
+	// skipping the middle column cuts the left of the grid off
+	visited = map[*Node]bool{}
+	g.BreadthFirstSearch(g.Nodes[0], func(u, v *Node) VisitAction {
+		visited[v] = true
+		if v.X == 2 {
+			return Skip
+		}
+		return Continue
+	})
+	for _, n := range g.Nodes {
+		if visited[n] != (n.X <= 2 && n != g.Nodes[0]) {
+			t.Fatalf("got visited %t for node %d", visited[n], n.ID)
+		}
+	}
+
+	calls := 0
+	g.BreadthFirstSearch(from, func(u, v *Node) VisitAction {
+		calls++
+		return Stop
+	})
+	if calls != 1 {
+		t.Fatalf("search went on for %d calls after Stop", calls)
+	}
+}
+
+func TestDepthFirstSearch(t *testing.T) {
+	g := newGraph(6, [][2]int{{0, 1}, {1, 2}, {2, 0}, {0, 3}, {3, 4}, {5, 0}})
+
+	visited := map[*Node]bool{}
+	g.DepthFirstSearch(g.Nodes[0], func(u, v *Node) VisitAction {
+		if visited[v] {
+			t.Fatalf("visited %d twice", v.ID)
+		}
+		if !hasEdge(g, u, v) {
+			t.Fatalf("visited %d from %d without an edge", v.ID, u.ID)
+		}
+		visited[v] = true
+		return Continue
+	})
+	for _, id := range []int{1, 2, 3, 4} {
+		if !visited[g.Nodes[id]] {
+			t.Fatalf("node %d was not visited", id)
+		}
+	}
+	if visited[g.Nodes[5]] {
+		t.Fatal("visited node 5, which is unreachable")
+	}
+
+	visited = map[*Node]bool{}
+	g.DepthFirstSearch(g.Nodes[0], func(u, v *Node) VisitAction {
+		visited[v] = true
+		if v.ID == 3 {
+			return Skip
+		}
+		return Continue
+	})
+	if visited[g.Nodes[4]] {
+		t.Fatal("visited node 4 beyond the skipped node 3")
+	}
+}
+
+func TestBFSFrom(t *testing.T) {
+	g := NewGridGraph(4, 6, false, false)
+	from := g.Nodes[8]
+	dist, prev := g.BFSFrom(from)
+
+	if len(dist) != 24 {
+		t.Fatalf("reached %d nodes, want 24", len(dist))
+	}
+	for _, n := range g.Nodes {
+		if dist[n] != hops(from, n) {
+			t.Fatalf("node %d is %d hops away, want %d", n.ID, dist[n], hops(from, n))
+		}
+		if p, ok := prev[n]; n == from && ok {
+			t.Fatal("the start node has a predecessor")
+		} else if n != from && (!ok || dist[p] != dist[n]-1 || !hasEdge(g, p, n)) {
+			t.Fatalf("node %d has predecessor %v", n.ID, p)
+		}
+	}
+
+	g = newGraph(3, [][2]int{{1, 0}})
+	if dist, _ := g.BFSFrom(g.Nodes[0]); len(dist) != 1 {
+		t.Fatalf("reached %d nodes over no edges", len(dist))
+	}
+}
+
+func TestDepthFirstSearchOrder(t *testing.T) {
+	g := newGraph(7, [][2]int{{0, 1}, {1, 2}, {2, 0}, {0, 3}, {3, 4}, {5, 6}, {6, 4}})
+
+	var pre, post []int
+	times := g.DepthFirstSearchOrder(nil,
+		func(n *Node) { pre = append(pre, n.ID) },
+		func(n *Node) { post = append(post, n.ID) })
+
+	if len(pre) != 7 || len(post) != 7 {
+		t.Fatalf("got %d discoveries and %d finishes, want 7", len(pre), len(post))
+	}
+	for i := 1; i < 7; i++ {
+		if times.Discovered[g.Nodes[pre[i]]] <= times.Discovered[g.Nodes[pre[i-1]]] {
+			t.Fatal("pre-order does not follow discovery times")
+		}
+		if times.Finished[g.Nodes[post[i]]] <= times.Finished[g.Nodes[post[i-1]]] {
+			t.Fatal("post-order does not follow finish times")
+		}
+	}
+
+	// the interval of an edge's target either nests in that of its source
+	// or, for edges back or across the search tree, ends before it
+	for _, u := range g.Nodes {
+		du, fu := times.Discovered[u], times.Finished[u]
+		if du >= fu {
+			t.Fatalf("node %d finished at %d before its discovery at %d", u.ID, fu, du)
+		}
+		for _, e := range u.EdgeStart {
+			dv, fv := times.Discovered[e.To], times.Finished[e.To]
+			if (dv > du && fv > fu) || (dv < du && fv > du && fv < fu) {
+				t.Fatalf("edge %v: intervals [%d, %d] and [%d, %d] overlap", e.ID, du, fu, dv, fv)
+			}
+		}
+	}
+
+	times = g.DepthFirstSearchOrder(g.Nodes[3], nil, nil)
+	if len(times.Discovered) != 2 || len(times.Finished) != 2 {
+		t.Fatalf("reached %d nodes from node 3, want 2", len(times.Discovered))
+	}
+}
+
+func TestIDDFS(t *testing.T) {
+	g := NewGridGraph(4, 5, false, false)
+	from := g.Nodes[0]
+	for _, to := range g.Nodes {
+		path, ok := g.IDDFS(from, to)
+		if !ok || len(path)-1 != hops(from, to) {
+			t.Fatalf("0 -> %d: got %d edges, want %d", to.ID, len(path)-1, hops(from, to))
+		}
+		for i := 1; i < len(path); i++ {
+			if !hasEdge(g, path[i-1], path[i]) {
+				t.Fatalf("0 -> %d: path %v has no edge %d -> %d", to.ID, path, path[i-1].ID, path[i].ID)
+			}
+		}
+	}
+
+	g = newGraph(4, [][2]int{{0, 1}, {1, 2}, {2, 0}, {3, 0}})
+	if path, ok := g.IDDFS(g.Nodes[0], g.Nodes[3]); ok || path != nil {
+		t.Fatalf("found path %v to an unreachable node", path)
+	}
+}
+
+func TestDepthLimitedSearch(t *testing.T) {
+	g := newGraph(4, [][2]int{{0, 1}, {1, 2}, {2, 3}})
+
+	depth := 0
+	visit := func(u, v *Node) VisitAction {
+		if v.ID > depth {
+			depth = v.ID
+		}
+		return Continue
+	}
+	if !g.DepthLimitedSearch(g.Nodes[0], 2, visit) {
+		t.Fatal("no cutoff reported for a path longer than the limit")
+	}
+	if depth != 2 {
+		t.Fatalf("reached depth %d, want 2", depth)
+	}
+	if g.DepthLimitedSearch(g.Nodes[0], 3, visit) {
+		t.Fatal("cutoff reported for a search reaching every path end")
+	}
+}
+
+// hasEdge reports whether g has an edge from u to v
+func hasEdge(g *DirectedGraph, u, v *Node) bool {
+	_, ok := g.Weight(u, v)
+	return ok
+}
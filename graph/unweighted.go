@@ -0,0 +1,89 @@
+package graph
+
+// BidirectionalBFS returns a path from u to v with the fewest edges and its
+// number of edges, ignoring edge weights. Breadth first searches run from
+// both ends, always expanding the smaller frontier by a full level, until
+// they meet. The length is -1 if there is no path.
+func (g *DirectedGraph) BidirectionalBFS(u, v *Node) ([]*Node, int) {
+	if u == v {
+		return []*Node{u}, 0
+	}
+
+	forwardHops := map[*Node]int{u: 0}
+	backwardHops := map[*Node]int{v: 0}
+	next := make(map[*Node]*Node)
+	back := make(map[*Node]*Node)
+
+	forward, backward := []*Node{u}, []*Node{v}
+
+	for len(forward) > 0 && len(backward) > 0 {
+		var mid *Node
+
+		if len(forward) <= len(backward) {
+			forward, mid = expandLevel(forward, forwardHops, backwardHops, next, true)
+		} else {
+			backward, mid = expandLevel(backward, backwardHops, forwardHops, back, false)
+		}
+
+		if mid != nil {
+			return joinPaths(mid, u, v, next, back), forwardHops[mid] + backwardHops[mid]
+		}
+	}
+
+	// no path found
+	return nil, -1
+}
+
+// expandLevel expands every node of frontier by one edge, forwards along
+// EdgeStart or backwards along EdgeEnd, recording hops and predecessors. It
+// returns the next frontier and the meeting node with the fewest total hops,
+// if the search reached any node already seen from the other end.
+func expandLevel(frontier []*Node, hops, otherHops map[*Node]int,
+	prev map[*Node]*Node, forward bool) ([]*Node, *Node) {
+
+	var level []*Node
+	var mid *Node
+	best := -1
+
+	for _, n := range frontier {
+		edges := n.EdgeStart
+		if !forward {
+			edges = n.EdgeEnd
+		}
+
+		for _, e := range edges {
+			m := e.To
+			if !forward {
+				m = e.From
+			}
+
+			if _, ok := hops[m]; ok {
+				continue
+			}
+
+			hops[m] = hops[n] + 1
+			prev[m] = n
+			level = append(level, m)
+
+			if h, ok := otherHops[m]; ok && (best < 0 || hops[m]+h < best) {
+				best = hops[m] + h
+				mid = m
+			}
+		}
+	}
+
+	return level, mid
+}
+
+// joinPaths returns the path from u to v through mid, following next from mid
+// back to u and back from mid on to v
+func joinPaths(mid, u, v *Node, next, back map[*Node]*Node) []*Node {
+	path := tracePath(next, u, mid)
+
+	for n := mid; n != v; {
+		n = back[n]
+		path = append(path, n)
+	}
+
+	return path
+}
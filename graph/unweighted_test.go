@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBidirectionalBFS(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomDirectedGraph(300, rng)
+	g.AddNode(&Node{})
+
+	for i := 0; i < 20; i++ {
+		u := g.Nodes[rng.Intn(len(g.Nodes))]
+		hops, _ := g.BFSFrom(u)
+		for _, v := range g.Nodes {
+			path, n := g.BidirectionalBFS(u, v)
+			want, ok := hops[v]
+			if !ok {
+				if path != nil || n != -1 {
+					t.Fatalf("%d -> %d: got path %v of %d edges, want none", u.ID, v.ID, path, n)
+				}
+				continue
+			}
+
+			if n != want || len(path) != n+1 || path[0] != u || path[n] != v {
+				t.Fatalf("%d -> %d: got path %v of %d edges, want %d edges", u.ID, v.ID, path, n, want)
+			}
+			for j := 1; j < len(path); j++ {
+				if _, ok := g.Weight(path[j-1], path[j]); !ok {
+					t.Fatalf("%d -> %d: path %v has no edge %d -> %d", u.ID, v.ID, path, path[j-1].ID, path[j].ID)
+				}
+			}
+		}
+	}
+}
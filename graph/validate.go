@@ -0,0 +1,209 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/hanyangtay/go-datastructures/internal/debug"
+)
+
+// Validate checks the graph for structural problems that would otherwise only
+// surface as wrong results deep inside a query, and returns one error per
+// problem found:
+//
+//   - nodes whose ID does not match their index in Nodes
+//   - edges with an endpoint that is missing or not part of the graph
+//   - edges whose ID does not match the IDs of their endpoints
+//   - edges registered in EdgeStart of their start node but not in EdgeEnd
+//     of their end node, or the other way round
+//   - edges with a NaN or negative weight
+//   - nil nodes and edges
+//
+// A nil result means the graph is consistent.
+func (g *DirectedGraph) Validate() []error {
+	var errs []error
+	report := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("graph: "+format, args...))
+	}
+
+	inGraph := func(n *Node) bool {
+		return g.contains(n)
+	}
+
+	starts := make(map[*Edge]bool)
+	ends := make(map[*Edge]bool)
+
+	for i, n := range g.Nodes {
+		if n == nil {
+			report("node at index %d is nil", i)
+			continue
+		}
+		if n.ID != i {
+			report("node at index %d has ID %d", i, n.ID)
+		}
+
+		for _, e := range n.EdgeStart {
+			if e == nil {
+				report("node %d has a nil edge in EdgeStart", i)
+				continue
+			}
+			starts[e] = true
+			if e.From != n {
+				report("edge %v is registered as starting at node %d", e.ID, i)
+			}
+		}
+		for _, e := range n.EdgeEnd {
+			if e == nil {
+				report("node %d has a nil edge in EdgeEnd", i)
+				continue
+			}
+			ends[e] = true
+			if e.To != n {
+				report("edge %v is registered as ending at node %d", e.ID, i)
+			}
+		}
+	}
+
+	check := func(e *Edge) {
+		if !inGraph(e.From) || !inGraph(e.To) {
+			report("edge %v has an endpoint outside the graph", e.ID)
+			return
+		}
+
+		if e.ID != [2]int{e.From.ID, e.To.ID} {
+			report("edge %v joins node %d to node %d", e.ID, e.From.ID, e.To.ID)
+		}
+
+		if math.IsNaN(e.Weight) || e.Weight < 0 {
+			report("edge %v has invalid weight %v", e.ID, e.Weight)
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if n == nil {
+			continue
+		}
+
+		for _, e := range n.EdgeStart {
+			if e == nil {
+				continue
+			}
+			check(e)
+			if !ends[e] {
+				report("edge %v is missing from EdgeEnd of its end node", e.ID)
+			}
+		}
+
+		// edges known only from the end node are checked here
+		for _, e := range n.EdgeEnd {
+			if e != nil && !starts[e] {
+				check(e)
+				report("edge %v is missing from EdgeStart of its start node", e.ID)
+			}
+		}
+	}
+
+	return errs
+}
+
+// checkEdgeNodes checks the edges of from and to, the nodes a mutation
+// touched, in debug builds. It is the part of Validate that concerns them,
+// so that it takes time in their degree rather than the size of the graph.
+func (g *DirectedGraph) checkEdgeNodes(from, to *Node) error {
+	if !debug.Enabled {
+		return nil
+	}
+	if err := g.checkNode(from); err != nil {
+		return err
+	}
+	return g.checkNode(to)
+}
+
+// checkNewEdge checks, in debug builds, that e has a valid weight and is not
+// in the graph yet, before AddDirectedEdge links it
+func (g *DirectedGraph) checkNewEdge(e *Edge) error {
+	if !debug.Enabled {
+		return nil
+	}
+	switch {
+	case math.IsNaN(e.Weight) || e.Weight < 0:
+		return fmt.Errorf("%w: edge %s has invalid weight %v", ErrInconsistent, edgeName(e), e.Weight)
+	case count(e.From.EdgeStart, e) > 0 || count(e.To.EdgeEnd, e) > 0:
+		return fmt.Errorf("%w: edge %s is already in the graph", ErrInconsistent, edgeName(e))
+	}
+	return nil
+}
+
+// checkNode returns an error wrapping ErrInconsistent that describes the
+// first problem with n or its edges, or nil
+func (g *DirectedGraph) checkNode(n *Node) error {
+	report := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: node %d: %s", ErrInconsistent, n.ID, fmt.Sprintf(format, args...))
+	}
+
+	if !g.contains(n) {
+		return report("is not the node of its ID")
+	}
+
+	for _, e := range n.EdgeStart {
+		switch {
+		case e == nil:
+			return report("has a nil edge in EdgeStart")
+		case e.From != n:
+			return report("has edge %s in EdgeStart", edgeName(e))
+		case count(n.EdgeStart, e) > 1:
+			return report("has edge %s in EdgeStart more than once", edgeName(e))
+		case !g.contains(e.To):
+			return report("has edge %s to a node outside the graph", edgeName(e))
+		case count(e.To.EdgeEnd, e) != 1:
+			return report("has edge %s in EdgeStart, which is missing from EdgeEnd of its end node", edgeName(e))
+		case math.IsNaN(e.Weight) || e.Weight < 0:
+			return report("has edge %s of invalid weight %v", edgeName(e), e.Weight)
+		}
+	}
+
+	for _, e := range n.EdgeEnd {
+		switch {
+		case e == nil:
+			return report("has a nil edge in EdgeEnd")
+		case e.To != n:
+			return report("has edge %s in EdgeEnd", edgeName(e))
+		case count(n.EdgeEnd, e) > 1:
+			return report("has edge %s in EdgeEnd more than once", edgeName(e))
+		case !g.contains(e.From):
+			return report("has edge %s from a node outside the graph", edgeName(e))
+		case count(e.From.EdgeStart, e) != 1:
+			return report("has edge %s in EdgeEnd, which is missing from EdgeStart of its start node", edgeName(e))
+		}
+	}
+
+	return nil
+}
+
+// contains reports whether n is the node of its ID in g
+func (g *DirectedGraph) contains(n *Node) bool {
+	return n != nil && g.HasNode(n) && g.Nodes[n.ID] == n
+}
+
+// count returns the number of times e occurs in edges
+func count(edges []*Edge, e *Edge) int {
+	k := 0
+	for _, e2 := range edges {
+		if e2 == e {
+			k++
+		}
+	}
+	return k
+}
+
+// edgeName describes e by the IDs of its ends, as "from->to"
+func edgeName(e *Edge) string {
+	id := func(n *Node) string {
+		if n == nil {
+			return "nil"
+		}
+		return strconv.Itoa(n.ID)
+	}
+	return id(e.From) + "->" + id(e.To)
+}
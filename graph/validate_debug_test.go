@@ -0,0 +1,50 @@
+//go:build datastructures_debug
+
+package graph
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDebugValidates(t *testing.T) {
+	g := newGraph(3, [][2]int{{0, 1}, {1, 2}})
+	e := g.Nodes[0].EdgeStart[0]
+
+	if err := g.AddDirectedEdge(e); !errors.Is(err, ErrInconsistent) {
+		t.Fatalf("adding edge %v twice returned %v, want ErrInconsistent", e.ID, err)
+	}
+	bad := &Edge{ID: [2]int{2, 0}, From: g.Nodes[2], To: g.Nodes[0], Weight: math.NaN()}
+	if err := g.AddDirectedEdge(bad); !errors.Is(err, ErrInconsistent) {
+		t.Fatalf("adding an edge of weight NaN returned %v, want ErrInconsistent", err)
+	}
+	if g.EdgeCount() != 2 || len(g.Nodes[0].EdgeEnd) != 0 {
+		t.Fatal("failed additions changed the graph")
+	}
+
+	// an edge changed while in the graph fails the next mutation of its ends
+	g.Nodes[1].EdgeStart[0].Weight = -1
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrInconsistent) {
+			t.Fatalf("RemoveDirectedEdge panicked with %v, want ErrInconsistent", err)
+		}
+	}()
+	g.RemoveDirectedEdge(e)
+}
+
+func TestDebugHeap(t *testing.T) {
+	// a NaN heuristic leaves the queue out of order
+	g := NewGridGraph(5, 5, false, false)
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrInconsistent) {
+			t.Fatalf("search panicked with %v, want ErrInconsistent", err)
+		}
+	}()
+	g.ShortestPath(g.Nodes[0], g.Nodes[24], WithHeuristic(func(n, _ *Node) float64 {
+		if n.ID%3 == 0 {
+			return math.NaN()
+		}
+		return 0
+	}))
+}
@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hanyangtay/go-datastructures/deque"
+)
+
+// ZeroOneBFS returns a shortest path from u to v and the distance in a graph
+// whose edge weights are all 0 or 1. Nodes reached through a 0 weight edge go
+// to the front of a deque and the rest to the back, which keeps the deque
+// ordered by distance without a heap. Time complexity: O(|V| + |E|)
+// It returns ErrBadWeight if it meets an edge of any other weight, or panics
+// with it if g.Strict is set.
+func (g *DirectedGraph) ZeroOneBFS(u, v *Node) ([]*Node, float64, error) {
+	forwardDist := map[*Node]float64{u: 0}
+	next := make(map[*Node]*Node)
+	settled := make(map[*Node]bool)
+
+	var Q deque.Deque[*Node]
+	Q.PushBack(u)
+
+	for Q.Len() > 0 {
+		mid := Q.PopFront()
+
+		// a node may be queued twice if its distance dropped, skip repeats
+		if settled[mid] {
+			continue
+		}
+		settled[mid] = true
+
+		// terminates when final node is found
+		if mid == v {
+			return tracePath(next, u, v), forwardDist[v], nil
+		}
+
+		for _, e := range mid.EdgeStart {
+			if e.Weight != 0 && e.Weight != 1 {
+				err := fmt.Errorf("%w: edge %v has weight %v, not 0 or 1", ErrBadWeight, e.ID, e.Weight)
+				return nil, math.Inf(1), g.check(err)
+			}
+
+			n := e.To
+			acc_dist := forwardDist[mid] + e.Weight
+
+			// update shortest paths
+			if dist, ok := forwardDist[n]; !ok || acc_dist < dist {
+				forwardDist[n] = acc_dist
+				next[n] = mid
+
+				if e.Weight == 0 {
+					Q.PushFront(n)
+				} else {
+					Q.PushBack(n)
+				}
+			}
+		}
+	}
+
+	// no path found
+	return nil, math.Inf(1), nil
+}
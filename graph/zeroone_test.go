@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// newRandomIntGraph returns a graph of n nodes with 4n random edges of
+// integer weights from 0 to maxWeight
+func newRandomIntGraph(n, maxWeight int, rng *rand.Rand) *DirectedGraph {
+	g := NewDirectedGraph()
+	for i := 0; i < n; i++ {
+		g.AddNode(&Node{X: float64(i)})
+	}
+	for i := 0; i < 4*n; i++ {
+		u, v := g.Nodes[rng.Intn(n)], g.Nodes[rng.Intn(n)]
+		if u != v {
+			g.AddDirectedEdge(&Edge{ID: [2]int{u.ID, v.ID}, From: u, To: v, Weight: float64(rng.Intn(maxWeight + 1))})
+		}
+	}
+	return g
+}
+
+func TestZeroOneBFS(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := newRandomIntGraph(200, 1, rng)
+
+	for i := 0; i < 20; i++ {
+		u := g.Nodes[rng.Intn(len(g.Nodes))]
+		dist := bellmanFord(g, u)
+		for _, v := range g.Nodes {
+			path, d, err := g.ZeroOneBFS(u, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPath(t, g, u, v, path, d, dist[v.ID])
+		}
+	}
+}
+
+func TestZeroOneBFSBadWeight(t *testing.T) {
+	g := newGraph(2, [][2]int{{0, 1}})
+	g.Nodes[0].EdgeStart[0].Weight = 2
+
+	if _, _, err := g.ZeroOneBFS(g.Nodes[0], g.Nodes[1]); !errors.Is(err, ErrBadWeight) {
+		t.Fatalf("weight 2: got error %v, want ErrBadWeight", err)
+	}
+
+	g.Strict = true
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrBadWeight) {
+			t.Fatalf("strict graph panicked with %v, want ErrBadWeight", err)
+		}
+	}()
+	g.ZeroOneBFS(g.Nodes[0], g.Nodes[1])
+}
@@ -0,0 +1,289 @@
+// Package hamt provides a generic persistent hash map, an immutable map whose
+// updates return new versions sharing most of their memory with the old ones.
+package hamt
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+const (
+	levelBits = 5
+	levelMask = 1<<levelBits - 1
+
+	// maxShift is the shift beyond which hashes are exhausted and keys with
+	// equal hashes are kept in a collision list
+	maxShift = 64 - 64%levelBits
+)
+
+type leaf[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+}
+
+// slot is either a leaf or a child node
+type slot[K comparable, V any] struct {
+	leaf  *leaf[K, V]
+	child *node[K, V]
+}
+
+// node holds a slot for every set bit of bitmap, in order, each standing for
+// five bits of the hash at its depth. At maxShift, nodes hold a list of
+// leaves with the same hash instead. Nodes are never modified once they are
+// part of a map.
+type node[K comparable, V any] struct {
+	bitmap     uint32
+	slots      []slot[K, V]
+	collisions []*leaf[K, V]
+}
+
+// Map is an immutable hash map stored as a hash array mapped trie: a trie
+// over the bits of key hashes, five at a time, in which every node stores
+// only its present children, located by a bitmap. Lookups and updates take
+// O(log32 n), and an update copies only the nodes on the path to its key,
+// sharing all others with the original, which makes snapshots free.
+//
+// Maps are safe for concurrent use since they never change.
+type Map[K comparable, V any] struct {
+	root   *node[K, V]
+	length int
+	hash   func(K) uint64
+}
+
+// New returns an empty map hashing keys with hash/maphash, with a random
+// seed shared by all maps derived from it
+func New[K comparable, V any]() *Map[K, V] {
+	seed := maphash.MakeSeed()
+	return NewWithHasher[K, V](func(key K) uint64 { return maphash.Comparable(seed, key) })
+}
+
+// NewWithHasher returns an empty map hashing keys with hash, which must
+// return equal hashes for equal keys
+func NewWithHasher[K comparable, V any](hash func(K) uint64) *Map[K, V] {
+	return &Map[K, V]{root: &node[K, V]{}, hash: hash}
+}
+
+// Len returns the number of entries
+func (m *Map[K, V]) Len() int { return m.length }
+
+// position returns the bit for hash at shift, and the index of its slot in n
+func position[K comparable, V any](n *node[K, V], hash uint64, shift uint) (uint32, int) {
+	bit := uint32(1) << (hash >> shift & levelMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// Get returns the value stored under key, and whether it was found.
+// Time complexity: O(log32 n)
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	hash := m.hash(key)
+	n := m.root
+	for shift := uint(0); ; shift += levelBits {
+		if shift >= maxShift {
+			for _, l := range n.collisions {
+				if l.key == key {
+					return l.value, true
+				}
+			}
+			break
+		}
+
+		bit, i := position(n, hash, shift)
+		if n.bitmap&bit == 0 {
+			break
+		}
+		if s := n.slots[i]; s.leaf != nil {
+			if s.leaf.key == key {
+				return s.leaf.value, true
+			}
+			break
+		}
+		n = n.slots[i].child
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set returns a map with value stored under key, replacing any previous
+// value.
+// Time complexity: O(log32 n)
+func (m *Map[K, V]) Set(key K, value V) *Map[K, V] {
+	l := &leaf[K, V]{hash: m.hash(key), key: key, value: value}
+	root, added := insert(m.root, 0, l)
+
+	w := *m
+	w.root = root
+	if added {
+		w.length++
+	}
+	return &w
+}
+
+// insert returns a copy of n with l stored, and whether its key is new
+func insert[K comparable, V any](n *node[K, V], shift uint, l *leaf[K, V]) (*node[K, V], bool) {
+	if shift >= maxShift {
+		collisions := append([]*leaf[K, V](nil), n.collisions...)
+		for i, c := range collisions {
+			if c.key == l.key {
+				collisions[i] = l
+				return &node[K, V]{collisions: collisions}, false
+			}
+		}
+		return &node[K, V]{collisions: append(collisions, l)}, true
+	}
+
+	bit, i := position(n, l.hash, shift)
+	if n.bitmap&bit == 0 {
+		slots := make([]slot[K, V], len(n.slots)+1)
+		copy(slots, n.slots[:i])
+		slots[i] = slot[K, V]{leaf: l}
+		copy(slots[i+1:], n.slots[i:])
+		return &node[K, V]{bitmap: n.bitmap | bit, slots: slots}, true
+	}
+
+	slots := append([]slot[K, V](nil), n.slots...)
+	w := &node[K, V]{bitmap: n.bitmap, slots: slots}
+
+	s := slots[i]
+	switch {
+	case s.child != nil:
+		child, added := insert(s.child, shift+levelBits, l)
+		slots[i] = slot[K, V]{child: child}
+		return w, added
+	case s.leaf.key == l.key:
+		slots[i] = slot[K, V]{leaf: l}
+		return w, false
+	}
+
+	slots[i] = slot[K, V]{child: pair(shift+levelBits, s.leaf, l)}
+	return w, true
+}
+
+// pair returns a node at shift holding the leaves a and b, with distinct
+// keys whose hashes agree below shift
+func pair[K comparable, V any](shift uint, a, b *leaf[K, V]) *node[K, V] {
+	if shift >= maxShift {
+		return &node[K, V]{collisions: []*leaf[K, V]{a, b}}
+	}
+
+	i, j := a.hash>>shift&levelMask, b.hash>>shift&levelMask
+	if i == j {
+		return &node[K, V]{
+			bitmap: 1 << i,
+			slots:  []slot[K, V]{{child: pair(shift+levelBits, a, b)}},
+		}
+	}
+	if i > j {
+		a, b = b, a
+	}
+	return &node[K, V]{
+		bitmap: 1<<i | 1<<j,
+		slots:  []slot[K, V]{{leaf: a}, {leaf: b}},
+	}
+}
+
+// Delete returns a map without key, or m itself if key is not present.
+// Time complexity: O(log32 n)
+func (m *Map[K, V]) Delete(key K) *Map[K, V] {
+	root, removed := remove(m.root, 0, m.hash(key), key)
+	if !removed {
+		return m
+	}
+
+	w := *m
+	w.root, w.length = root, m.length-1
+	if root == nil {
+		w.root = &node[K, V]{}
+	}
+	return &w
+}
+
+// remove returns a copy of n without key, or nil if n becomes empty, and
+// whether key was present
+func remove[K comparable, V any](n *node[K, V], shift uint, hash uint64, key K) (*node[K, V], bool) {
+	if shift >= maxShift {
+		for i, c := range n.collisions {
+			if c.key == key {
+				if len(n.collisions) == 1 {
+					return nil, true
+				}
+				collisions := make([]*leaf[K, V], 0, len(n.collisions)-1)
+				collisions = append(append(collisions, n.collisions[:i]...), n.collisions[i+1:]...)
+				return &node[K, V]{collisions: collisions}, true
+			}
+		}
+		return n, false
+	}
+
+	bit, i := position(n, hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	var replacement slot[K, V]
+	if s := n.slots[i]; s.leaf != nil {
+		if s.leaf.key != key {
+			return n, false
+		}
+	} else {
+		child, removed := remove(s.child, shift+levelBits, hash, key)
+		if !removed {
+			return n, false
+		}
+		if child != nil {
+			replacement = slot[K, V]{child: child}
+			// inline a child left with a single entry
+			if l := single(child); l != nil {
+				replacement = slot[K, V]{leaf: l}
+			}
+		}
+	}
+
+	if replacement.leaf != nil || replacement.child != nil {
+		slots := append([]slot[K, V](nil), n.slots...)
+		slots[i] = replacement
+		return &node[K, V]{bitmap: n.bitmap, slots: slots}, true
+	}
+
+	if len(n.slots) == 1 {
+		return nil, true
+	}
+	slots := make([]slot[K, V], 0, len(n.slots)-1)
+	slots = append(append(slots, n.slots[:i]...), n.slots[i+1:]...)
+	return &node[K, V]{bitmap: n.bitmap &^ bit, slots: slots}, true
+}
+
+// single returns the only entry of n, or nil if it has more or holds a child
+func single[K comparable, V any](n *node[K, V]) *leaf[K, V] {
+	if len(n.collisions) == 1 {
+		return n.collisions[0]
+	}
+	if len(n.slots) == 1 && n.slots[0].leaf != nil {
+		return n.slots[0].leaf
+	}
+	return nil
+}
+
+// Each calls fn for every entry in an unspecified order until fn returns
+// false
+func (m *Map[K, V]) Each(fn func(key K, value V) bool) {
+	each(m.root, fn)
+}
+
+func each[K comparable, V any](n *node[K, V], fn func(key K, value V) bool) bool {
+	for _, l := range n.collisions {
+		if !fn(l.key, l.value) {
+			return false
+		}
+	}
+	for _, s := range n.slots {
+		if s.leaf != nil && !fn(s.leaf.key, s.leaf.value) {
+			return false
+		}
+		if s.child != nil && !each(s.child, fn) {
+			return false
+		}
+	}
+	return true
+}
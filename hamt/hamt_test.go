@@ -0,0 +1,79 @@
+package hamt
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkMap fails unless m holds exactly the entries of want
+func checkMap(t *testing.T, m *Map[int, int], want map[int]int) {
+	t.Helper()
+	if m.Len() != len(want) {
+		t.Fatalf("got length %d, want %d", m.Len(), len(want))
+	}
+	for key, w := range want {
+		if v, ok := m.Get(key); !ok || v != w {
+			t.Fatalf("Get(%d) = %d, %v, want %d", key, v, ok, w)
+		}
+	}
+	n := 0
+	m.Each(func(key, value int) bool {
+		if want[key] != value {
+			t.Fatalf("Each visited %d=%d, want %d", key, value, want[key])
+		}
+		n++
+		return true
+	})
+	if n != len(want) {
+		t.Fatalf("Each visited %d entries, want %d", n, len(want))
+	}
+}
+
+// TestPersistence checks that updates leave all earlier versions unchanged,
+// with a default hasher and with one colliding on every key
+func TestPersistence(t *testing.T) {
+	for name, m := range map[string]*Map[int, int]{
+		"maphash":   New[int, int](),
+		"colliding": NewWithHasher[int, int](func(key int) uint64 { return uint64(key % 4) }),
+		"shared":    NewWithHasher[int, int](func(key int) uint64 { return uint64(key%8) << 59 }),
+	} {
+		rng := rand.New(rand.NewSource(1))
+		versions := []*Map[int, int]{m}
+		models := []map[int]int{{}}
+
+		for i := 0; i < 2000; i++ {
+			j := rng.Intn(len(versions))
+			v, model := versions[j], make(map[int]int)
+			for key, value := range models[j] {
+				model[key] = value
+			}
+
+			key := rng.Intn(300)
+			if rng.Intn(3) == 0 {
+				v = v.Delete(key)
+				delete(model, key)
+			} else {
+				v = v.Set(key, i)
+				model[key] = i
+			}
+			versions = append(versions, v)
+			models = append(models, model)
+		}
+
+		t.Run(name, func(t *testing.T) {
+			for j := range versions {
+				checkMap(t, versions[j], models[j])
+			}
+		})
+	}
+}
+
+func TestDeleteMissing(t *testing.T) {
+	m := New[string, int]().Set("a", 1)
+	if m.Delete("b") != m {
+		t.Fatal("deleting a missing key returned a new map")
+	}
+	if _, ok := m.Delete("a").Get("a"); ok {
+		t.Fatal("deleted key still found")
+	}
+}
@@ -0,0 +1,126 @@
+// Package hilbert maps between points of a square grid and their positions
+// along a Hilbert curve, which visits every cell once and keeps cells that
+// are close on the curve close in the plane.
+package hilbert
+
+import (
+	"math"
+	"sort"
+)
+
+// MaxOrder is the greatest order of a curve, whose grid is 2^32 cells wide
+const MaxOrder = 32
+
+func checkOrder(order uint) {
+	if order < 1 || order > MaxOrder {
+		panic("hilbert: order out of range")
+	}
+}
+
+// Encode returns the position along the Hilbert curve of the given order of
+// the cell at x, y in a grid 2^order cells wide. It panics if order is not
+// between 1 and MaxOrder, or if x or y does not fit the grid.
+func Encode(order uint, x, y uint32) uint64 {
+	checkOrder(order)
+	if order < 32 && (x>>order != 0 || y>>order != 0) {
+		panic("hilbert: coordinates out of range")
+	}
+
+	var d uint64
+	for s := uint32(1) << (order - 1); s > 0; s >>= 1 {
+		var rx, ry uint32
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+		d += uint64(s) * uint64(s) * uint64(3*rx^ry)
+		x, y = rotate(s, x, y, rx, ry)
+	}
+	return d
+}
+
+// Decode returns the cell at position d along the Hilbert curve of the
+// given order. It panics if order is not between 1 and MaxOrder, or if d is
+// beyond the end of the curve.
+func Decode(order uint, d uint64) (x, y uint32) {
+	checkOrder(order)
+	if order < 32 && d>>(2*order) != 0 {
+		panic("hilbert: position out of range")
+	}
+
+	for s := uint32(1); ; s <<= 1 {
+		rx := uint32(d>>1) & 1
+		ry := (uint32(d) ^ rx) & 1
+		x, y = rotate(s, x, y, rx, ry)
+		x += s * rx
+		y += s * ry
+		d >>= 2
+		if s == 1<<(order-1) {
+			return x, y
+		}
+	}
+}
+
+// rotate turns the quadrant of size s selected by rx, ry so that the curve
+// enters and leaves it at the right corners. Coordinates are taken modulo s.
+func rotate(s, x, y, rx, ry uint32) (uint32, uint32) {
+	if ry == 0 {
+		if rx == 1 {
+			x = s - 1 - x&(s-1)
+			y = s - 1 - y&(s-1)
+		}
+		x, y = y, x
+	}
+	return x & (s - 1), y & (s - 1)
+}
+
+// Sort orders items along the Hilbert curve of the given order laid over the
+// bounding box of their points, as returned by point. Sorting spatial
+// objects this way before bulk loading an index, or before processing them
+// in turn, keeps neighbours together in memory. It panics if order is not
+// between 1 and MaxOrder.
+func Sort[T any](items []T, order uint, point func(item T) (x, y float64)) {
+	checkOrder(order)
+	if len(items) < 2 {
+		return
+	}
+
+	xs := make([]float64, len(items))
+	ys := make([]float64, len(items))
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i, item := range items {
+		x, y := point(item)
+		xs[i], ys[i] = x, y
+		minX, maxX = min(minX, x), max(maxX, x)
+		minY, maxY = min(minY, y), max(maxY, y)
+	}
+
+	cells := math.Ldexp(1, int(order))
+	scale := func(v, lo, hi float64) uint32 {
+		if hi <= lo {
+			return 0
+		}
+		return uint32(min((v-lo)/(hi-lo)*cells, cells-1))
+	}
+
+	keys := make([]uint64, len(items))
+	for i := range items {
+		keys[i] = Encode(order, scale(xs[i], minX, maxX), scale(ys[i], minY, maxY))
+	}
+	sort.Sort(byKey[T]{items, keys})
+}
+
+type byKey[T any] struct {
+	items []T
+	keys  []uint64
+}
+
+func (s byKey[T]) Len() int           { return len(s.items) }
+func (s byKey[T]) Less(i, j int) bool { return s.keys[i] < s.keys[j] }
+func (s byKey[T]) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
@@ -0,0 +1,84 @@
+package hilbert
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestCurve checks that the curve of small orders visits every cell once,
+// moving to an adjacent cell at every step
+func TestCurve(t *testing.T) {
+	for order := uint(1); order <= 6; order++ {
+		n := uint64(1) << (2 * order)
+		seen := make(map[[2]uint32]bool)
+		px, py := Decode(order, 0)
+		if px != 0 || py != 0 {
+			t.Fatalf("order %d: curve starts at %d, %d, want 0, 0", order, px, py)
+		}
+
+		for d := uint64(0); d < n; d++ {
+			x, y := Decode(order, d)
+			if seen[[2]uint32{x, y}] {
+				t.Fatalf("order %d: cell %d, %d visited twice", order, x, y)
+			}
+			seen[[2]uint32{x, y}] = true
+			if e := Encode(order, x, y); e != d {
+				t.Fatalf("order %d: Encode(%d, %d) = %d, want %d", order, x, y, e, d)
+			}
+			if d > 0 && absDiff(x, px)+absDiff(y, py) != 1 {
+				t.Fatalf("order %d: step %d jumps from %d, %d to %d, %d", order, d, px, py, x, y)
+			}
+			px, py = x, y
+		}
+	}
+}
+
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func TestLargeOrders(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, order := range []uint{16, 31, MaxOrder} {
+		for i := 0; i < 1000; i++ {
+			x, y := rng.Uint32(), rng.Uint32()
+			if order < 32 {
+				x, y = x>>(32-order), y>>(32-order)
+			}
+			if dx, dy := Decode(order, Encode(order, x, y)); dx != x || dy != y {
+				t.Fatalf("order %d: %d, %d decodes to %d, %d", order, x, y, dx, dy)
+			}
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	type point struct{ x, y float64 }
+	var points []point
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			points = append(points, point{float64(x) * 10, float64(y) * 10})
+		}
+	}
+	rand.New(rand.NewSource(1)).Shuffle(len(points), func(i, j int) { points[i], points[j] = points[j], points[i] })
+
+	Sort(points, 3, func(p point) (float64, float64) { return p.x, p.y })
+	for d, p := range points {
+		x, y := Decode(3, uint64(d))
+		if p.x != float64(x)*10 || p.y != float64(y)*10 {
+			t.Fatalf("point %d is %v, want %d, %d", d, p, x*10, y*10)
+		}
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Encode of x = 4 on a curve of order 2 did not panic")
+		}
+	}()
+	Encode(2, 4, 0)
+}
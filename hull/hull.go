@@ -0,0 +1,151 @@
+// Package hull computes convex hulls of points in the plane, and measures
+// them with rotating calipers.
+package hull
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hanyangtay/go-datastructures/geom"
+	"github.com/hanyangtay/go-datastructures/rtree"
+)
+
+type point = rtree.RTreePoint
+
+// cross returns the cross product of b-a and c-a, positive if a, b, c turn
+// counterclockwise
+func cross(a, b, c point) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+func dot(ax, ay, bx, by float64) float64 { return ax*bx + ay*by }
+
+// ConvexHull returns the vertices of the convex hull of points,
+// counterclockwise from the lowest leftmost one, without collinear points
+// along its edges, using Andrew's monotone chain algorithm. The hull of
+// fewer than three distinct points, or of collinear ones, holds the
+// extreme points only. Time complexity: O(n log n)
+func ConvexHull(points []rtree.RTreePoint) []rtree.RTreePoint {
+	sorted := append([]point(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].X < sorted[j].X || sorted[i].X == sorted[j].X && sorted[i].Y < sorted[j].Y
+	})
+
+	unique := sorted[:0]
+	for _, p := range sorted {
+		if len(unique) == 0 || unique[len(unique)-1] != p {
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+
+	// lower chain left to right, then upper chain right to left
+	hull := make([]point, 0, 2*len(unique))
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for k := range unique {
+			p := unique[k]
+			if pass == 1 {
+				p = unique[len(unique)-1-k]
+			}
+			for len(hull) >= start+2 && geom.Orient(geom.Point(hull[len(hull)-2]), geom.Point(hull[len(hull)-1]), geom.Point(p)) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		// the last point of a chain starts the other
+		hull = hull[:len(hull)-1]
+	}
+	return hull
+}
+
+// Diameter returns the two points farthest apart, and their distance,
+// found by rotating calipers around the convex hull. It returns false if
+// points is empty. Time complexity: O(n log n)
+func Diameter(points []rtree.RTreePoint) (a, b rtree.RTreePoint, dist float64, ok bool) {
+	h := ConvexHull(points)
+	switch len(h) {
+	case 0:
+		return a, b, 0, false
+	case 1:
+		return h[0], h[0], 0, true
+	}
+
+	best := -1.0
+	n := len(h)
+	for i, j := 0, 1; i < n; i++ {
+		next := (i + 1) % n
+		// advance to the point farthest from the edge from i
+		for cross(h[i], h[next], h[(j+1)%n]) > cross(h[i], h[next], h[j]) {
+			j = (j + 1) % n
+		}
+		for _, k := range [2]int{i, next} {
+			if d := squaredDist(h[k], h[j]); d > best {
+				best, a, b = d, h[k], h[j]
+			}
+		}
+	}
+	return a, b, math.Sqrt(best), true
+}
+
+// MinAreaRect returns the corners of the rectangle of least area enclosing
+// points, counterclockwise, and its area. One side of the rectangle lies on
+// an edge of the convex hull, so rotating calipers find it by trying every
+// edge. It returns false if points is empty. Time complexity: O(n log n)
+func MinAreaRect(points []rtree.RTreePoint) (corners [4]rtree.RTreePoint, area float64, ok bool) {
+	h := ConvexHull(points)
+	n := len(h)
+	switch n {
+	case 0:
+		return corners, 0, false
+	case 1:
+		return [4]point{h[0], h[0], h[0], h[0]}, 0, true
+	case 2:
+		return [4]point{h[0], h[1], h[1], h[0]}, 0, true
+	}
+
+	area = math.Inf(1)
+	right, top, left := 1, 1, 1
+	for i := 0; i < n; i++ {
+		next := (i + 1) % n
+		length := math.Hypot(h[next].X-h[i].X, h[next].Y-h[i].Y)
+		ux, uy := (h[next].X-h[i].X)/length, (h[next].Y-h[i].Y)/length
+
+		// project onto the edge direction u and its left normal (-uy, ux),
+		// relative to h[i]
+		along := func(k int) float64 { return dot(h[k].X-h[i].X, h[k].Y-h[i].Y, ux, uy) }
+		above := func(k int) float64 { return dot(h[k].X-h[i].X, h[k].Y-h[i].Y, -uy, ux) }
+
+		for along((right+1)%n) > along(right) {
+			right = (right + 1) % n
+		}
+		if i == 0 {
+			top = right
+		}
+		for above((top+1)%n) > above(top) {
+			top = (top + 1) % n
+		}
+		if i == 0 {
+			left = top
+		}
+		for along((left+1)%n) < along(left) {
+			left = (left + 1) % n
+		}
+
+		lo, hi, height := along(left), along(right), above(top)
+		if a := (hi - lo) * height; a < area {
+			area = a
+			at := func(s, t float64) point {
+				return point{X: h[i].X + s*ux - t*uy, Y: h[i].Y + s*uy + t*ux}
+			}
+			corners = [4]point{at(lo, 0), at(hi, 0), at(hi, height), at(lo, height)}
+		}
+	}
+	return corners, area, true
+}
+
+func squaredDist(a, b point) float64 {
+	return (a.X-b.X)*(a.X-b.X) + (a.Y-b.Y)*(a.Y-b.Y)
+}
@@ -0,0 +1,99 @@
+package hull
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/hanyangtay/go-datastructures/rtree"
+)
+
+func randomPoints(rng *rand.Rand, n int) []rtree.RTreePoint {
+	points := make([]rtree.RTreePoint, n)
+	for i := range points {
+		points[i] = rtree.RTreePoint{X: float64(rng.Intn(100)), Y: float64(rng.Intn(100))}
+	}
+	return points
+}
+
+func TestConvexHull(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		points := randomPoints(rng, 3+rng.Intn(200))
+		h := ConvexHull(points)
+
+		for k := range h {
+			a, b, c := h[k], h[(k+1)%len(h)], h[(k+2)%len(h)]
+			if cross(a, b, c) <= 0 {
+				t.Fatalf("hull turns clockwise or straight at %v", b)
+			}
+			for _, p := range points {
+				if cross(a, b, p) < 0 {
+					t.Fatalf("%v lies outside the hull edge from %v to %v", p, a, b)
+				}
+			}
+		}
+		for _, p := range h[1:] {
+			if p.X < h[0].X || p.X == h[0].X && p.Y < h[0].Y {
+				t.Fatalf("hull starts at %v, not at its lowest leftmost point", h[0])
+			}
+		}
+	}
+
+	line := []rtree.RTreePoint{{X: 2, Y: 2}, {X: 0, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 0}}
+	if h := ConvexHull(line); len(h) != 2 || h[0] != line[1] || h[1] != line[0] {
+		t.Fatalf("hull of collinear points is %v, want the two ends", h)
+	}
+}
+
+func TestDiameter(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		points := randomPoints(rng, 1+rng.Intn(100))
+		want := 0.0
+		for _, p := range points {
+			for _, q := range points {
+				want = math.Max(want, math.Hypot(p.X-q.X, p.Y-q.Y))
+			}
+		}
+
+		a, b, d, ok := Diameter(points)
+		if !ok || math.Abs(d-want) > 1e-9 || math.Abs(math.Hypot(a.X-b.X, a.Y-b.Y)-d) > 1e-9 {
+			t.Fatalf("got diameter %v between %v and %v, want %v", d, a, b, want)
+		}
+	}
+
+	if _, _, _, ok := Diameter(nil); ok {
+		t.Fatal("got a diameter of no points")
+	}
+}
+
+func TestMinAreaRect(t *testing.T) {
+	// the corners of a rectangle of 3 by 4 turned by 30 degrees, and points
+	// inside it
+	sin, cos := math.Sin(math.Pi/6), math.Cos(math.Pi/6)
+	rotate := func(x, y float64) rtree.RTreePoint {
+		return rtree.RTreePoint{X: x*cos - y*sin, Y: x*sin + y*cos}
+	}
+	points := []rtree.RTreePoint{rotate(0, 0), rotate(3, 0), rotate(3, 4), rotate(0, 4)}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		points = append(points, rotate(rng.Float64()*3, rng.Float64()*4))
+	}
+
+	corners, area, ok := MinAreaRect(points)
+	if !ok || math.Abs(area-12) > 1e-9 {
+		t.Fatalf("got area %v, want 12", area)
+	}
+	for _, p := range points {
+		for k := range corners {
+			if cross(corners[k], corners[(k+1)%4], p) < -1e-9 {
+				t.Fatalf("%v lies outside the rectangle %v", p, corners)
+			}
+		}
+	}
+
+	if _, area, ok := MinAreaRect(points[:2]); !ok || area != 0 {
+		t.Fatalf("got area %v for two points, want 0", area)
+	}
+}
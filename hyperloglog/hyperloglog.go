@@ -0,0 +1,212 @@
+// Package hyperloglog provides HyperLogLog sketches, which estimate the
+// number of distinct items in a stream in a small, fixed amount of memory.
+package hyperloglog
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+
+	"github.com/hanyangtay/go-datastructures/internal/wire"
+)
+
+// MinPrecision and MaxPrecision bound the precision of a sketch
+const (
+	MinPrecision = 4
+	MaxPrecision = 18
+)
+
+// encodingVersion is the version of the layout written by MarshalBinary
+const encodingVersion = 1
+
+// ErrPrecisionMismatch is returned when merging sketches of different
+// precision
+var ErrPrecisionMismatch = errors.New("hyperloglog: sketches differ in precision")
+
+// ErrInvalidData is returned when decoding data that is not an encoded sketch
+var ErrInvalidData = errors.New("hyperloglog: invalid encoding")
+
+// Sketch estimates the number of distinct items added to it. Every item is
+// hashed to 64 bits: the first p bits select one of m = 2^p registers, which
+// keeps the greatest number of leading zeros seen in the remaining bits,
+// plus one. The standard error of the estimate is about 1.04/sqrt(m), e.g.
+// 0.8% for precision 14, using m bytes.
+//
+// As in HyperLogLog++, hashes have 64 bits, so that no correction for large
+// cardinalities is needed. Instead of the empirical bias correction of
+// HyperLogLog++ for small cardinalities, estimates use the improved
+// estimator of Ertl, which models the register distribution exactly and is
+// unbiased over the whole range without lookup tables.
+type Sketch struct {
+	p         uint8
+	registers []uint8
+}
+
+// New returns an empty sketch with 2^precision registers. It panics unless
+// precision is between MinPrecision and MaxPrecision.
+func New(precision uint8) *Sketch {
+	if precision < MinPrecision || precision > MaxPrecision {
+		panic("hyperloglog: precision out of range")
+	}
+	return &Sketch{p: precision, registers: make([]uint8, 1<<precision)}
+}
+
+// Precision returns the number of bits selecting a register
+func (s *Sketch) Precision() uint8 { return s.p }
+
+// hash returns a 64 bit hash of data: FNV-1a, with the finalizer of
+// MurmurHash3 so that all bits depend on all input bytes
+func hash(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// Add adds data to the sketch
+func (s *Sketch) Add(data []byte) { s.AddHash(hash(data)) }
+
+// AddString adds str to the sketch
+func (s *Sketch) AddString(str string) { s.Add([]byte(str)) }
+
+// AddHash adds an item by its 64 bit hash, for items hashed by the caller.
+// The hash bits must be uniformly distributed.
+func (s *Sketch) AddHash(h uint64) {
+	i := h >> (64 - s.p)
+	// a sentinel bit caps the rank at 64-p+1
+	rank := uint8(bits.LeadingZeros64(h<<s.p|1<<(s.p-1))) + 1
+	if rank > s.registers[i] {
+		s.registers[i] = rank
+	}
+}
+
+// Estimate returns the estimated number of distinct items added
+func (s *Sketch) Estimate() uint64 {
+	m := float64(len(s.registers))
+	q := 64 - int(s.p)
+
+	counts := make([]int, q+2)
+	for _, r := range s.registers {
+		counts[r]++
+	}
+
+	z := m * tau(1-float64(counts[q+1])/m)
+	for k := q; k >= 1; k-- {
+		z = 0.5 * (z + float64(counts[k]))
+	}
+	z += m * sigma(float64(counts[0])/m)
+
+	return uint64(math.Round(m * m / (2 * math.Ln2 * z)))
+}
+
+// sigma and tau are the series of Ertl's estimator for the registers that
+// are still zero and those that are saturated
+func sigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+	y, z := 1.0, x
+	for {
+		x *= x
+		prev := z
+		z += x * y
+		y += y
+		if z == prev {
+			return z
+		}
+	}
+}
+
+func tau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		prev := z
+		y *= 0.5
+		z -= (1 - x) * (1 - x) * y
+		if z == prev {
+			return z / 3
+		}
+	}
+}
+
+// Merge adds every item of other to s, as if they had been added to s
+// directly. ErrPrecisionMismatch is returned if the precisions differ.
+func (s *Sketch) Merge(other *Sketch) error {
+	if s.p != other.p {
+		return ErrPrecisionMismatch
+	}
+	for i, r := range other.registers {
+		if r > s.registers[i] {
+			s.registers[i] = r
+		}
+	}
+	return nil
+}
+
+// Reset empties the sketch
+func (s *Sketch) Reset() { clear(s.registers) }
+
+// MarshalBinary encodes the sketch in the standard dense representation, six
+// bits per register packed from the least significant bit of each byte,
+// preceded by the precision and the header common to the encodings of this
+// module
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	header := wire.Append(nil, "hyperloglog.Sketch", encodingVersion)
+	data := make([]byte, len(header)+1+(len(s.registers)*6+7)/8)
+	n := copy(data, header)
+	data[n] = s.p
+
+	packed := data[n+1:]
+	for i, r := range s.registers {
+		bit := i * 6
+		packed[bit/8] |= r << (bit % 8)
+		if bit%8 > 2 {
+			packed[1+bit/8] |= r >> (8 - bit%8)
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the sketch with one encoded by MarshalBinary.
+// ErrInvalidData is returned if data is malformed.
+func (s *Sketch) UnmarshalBinary(data []byte) error {
+	data, err := wire.Parse(data, "hyperloglog.Sketch", encodingVersion)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	if len(data) < 1 || data[0] < MinPrecision || data[0] > MaxPrecision {
+		return ErrInvalidData
+	}
+	p := data[0]
+	registers := make([]uint8, 1<<p)
+	packed := data[1:]
+	if len(packed) != (len(registers)*6+7)/8 {
+		return ErrInvalidData
+	}
+
+	for i := range registers {
+		bit := i * 6
+		r := packed[bit/8] >> (bit % 8)
+		if bit%8 > 2 {
+			r |= packed[1+bit/8] << (8 - bit%8)
+		}
+		registers[i] = r & 0x3f
+		if int(registers[i]) > 64-int(p)+1 {
+			return ErrInvalidData
+		}
+	}
+
+	s.p, s.registers = p, registers
+	return nil
+}
@@ -0,0 +1,93 @@
+package hyperloglog
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/hanyangtay/go-datastructures/internal/wire"
+)
+
+func TestEstimate(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 1000, 100000} {
+		s := New(14)
+		for i := 0; i < n; i++ {
+			s.AddString(fmt.Sprint("item", i))
+			// duplicates do not count
+			s.AddString(fmt.Sprint("item", i/2))
+		}
+
+		// five standard errors of 0.8%
+		got := float64(s.Estimate())
+		if math.Abs(got-float64(n)) > 0.04*float64(n)+1 {
+			t.Errorf("estimated %v distinct items, want %d", got, n)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, b := New(12), New(12)
+	for i := 0; i < 20000; i++ {
+		a.AddString(fmt.Sprint(i))
+		b.AddString(fmt.Sprint(i + 10000))
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := float64(a.Estimate()); math.Abs(got-30000) > 0.08*30000 {
+		t.Fatalf("estimated %v distinct items in the union, want 30000", got)
+	}
+
+	if err := a.Merge(New(13)); !errors.Is(err, ErrPrecisionMismatch) {
+		t.Fatalf("got %v for sketches of different precision, want ErrPrecisionMismatch", err)
+	}
+
+	a.Reset()
+	if a.Estimate() != 0 {
+		t.Fatalf("estimated %d items after Reset", a.Estimate())
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	for _, p := range []uint8{MinPrecision, 11, MaxPrecision} {
+		s := New(p)
+		for i := 0; i < 5000; i++ {
+			s.AddString(fmt.Sprint(i))
+		}
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var d Sketch
+		if err := d.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if d.Precision() != p || d.Estimate() != s.Estimate() {
+			t.Fatalf("decoded precision %d and estimate %d, want %d and %d",
+				d.Precision(), d.Estimate(), p, s.Estimate())
+		}
+
+		header := wire.Append(nil, "hyperloglog.Sketch", encodingVersion)
+		for name, bad := range map[string][]byte{
+			"truncated":     data[:len(data)-1],
+			"headerless":    data[len(header):],
+			"newer version": append(wire.Append(nil, "hyperloglog.Sketch", encodingVersion+1), data[len(header):]...),
+			"other kind":    append(wire.Append(nil, "bloom.Filter", encodingVersion), data[len(header):]...),
+		} {
+			if err := d.UnmarshalBinary(bad); !errors.Is(err, ErrInvalidData) {
+				t.Fatalf("precision %d, %s: got %v, want ErrInvalidData", p, name, err)
+			}
+		}
+	}
+}
+
+func TestBadPrecision(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("precision 3 did not panic")
+		}
+	}()
+	New(MinPrecision - 1)
+}
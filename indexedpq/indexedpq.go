@@ -0,0 +1,135 @@
+// Package indexedpq provides a generic priority queue whose entries can be
+// reprioritised and removed after they are pushed.
+package indexedpq
+
+// Handle refers to an entry of a PriorityQueue. It stays valid, and keeps
+// reporting its value and priority, after the entry leaves the queue.
+type Handle[T, P any] struct {
+	value    T
+	priority P
+	index    int // position in the heap, -1 once removed
+}
+
+// Value returns the value of the entry
+func (h *Handle[T, P]) Value() T { return h.value }
+
+// Priority returns the current priority of the entry
+func (h *Handle[T, P]) Priority() P { return h.priority }
+
+// PriorityQueue is a binary min heap of values with priorities ordered by a
+// less function. Push returns a handle through which the entry can later be
+// updated or removed in O(log n), as needed for a queue whose keys are
+// reprioritised in both directions, like that of D* Lite, or for cancelling
+// events in a simulation. Every entry is a separate allocation; for plain
+// decrease-key over dense integer keys, as in dijkstra, an array indexed by
+// key is cheaper, which is why package graph keeps its own heap for that.
+type PriorityQueue[T, P any] struct {
+	items []*Handle[T, P]
+	less  func(a, b P) bool
+}
+
+// New returns an empty queue ordered by less
+func New[T, P any](less func(a, b P) bool) *PriorityQueue[T, P] {
+	return &PriorityQueue[T, P]{less: less}
+}
+
+// Len returns the number of entries in the queue
+func (q *PriorityQueue[T, P]) Len() int { return len(q.items) }
+
+// Push adds value with the given priority and returns its handle.
+// Time complexity: O(log n)
+func (q *PriorityQueue[T, P]) Push(value T, priority P) *Handle[T, P] {
+	h := &Handle[T, P]{value: value, priority: priority, index: len(q.items)}
+	q.items = append(q.items, h)
+	q.up(h.index)
+	return h
+}
+
+// Peek returns the handle of the entry with the least priority without
+// removing it. It panics if the queue is empty.
+func (q *PriorityQueue[T, P]) Peek() *Handle[T, P] {
+	return q.items[0]
+}
+
+// Pop removes the entry with the least priority and returns its handle. It
+// panics if the queue is empty. Time complexity: O(log n)
+func (q *PriorityQueue[T, P]) Pop() *Handle[T, P] {
+	h := q.items[0]
+	q.Remove(h)
+	return h
+}
+
+// Contains reports whether the entry of h is still queued in q
+func (q *PriorityQueue[T, P]) Contains(h *Handle[T, P]) bool {
+	return h.index >= 0 && h.index < len(q.items) && q.items[h.index] == h
+}
+
+// Update changes the priority of the entry of h. It panics if the entry is
+// not queued in q. Time complexity: O(log n)
+func (q *PriorityQueue[T, P]) Update(h *Handle[T, P], priority P) {
+	if !q.Contains(h) {
+		panic("indexedpq: handle is not queued")
+	}
+
+	h.priority = priority
+	if !q.up(h.index) {
+		q.down(h.index)
+	}
+}
+
+// Remove takes the entry of h out of the queue. It panics if the entry is not
+// queued in q. Time complexity: O(log n)
+func (q *PriorityQueue[T, P]) Remove(h *Handle[T, P]) {
+	if !q.Contains(h) {
+		panic("indexedpq: handle is not queued")
+	}
+
+	i, last := h.index, len(q.items)-1
+	q.swap(i, last)
+	q.items[last] = nil
+	q.items = q.items[:last]
+	h.index = -1
+
+	if i < last && !q.up(i) {
+		q.down(i)
+	}
+}
+
+func (q *PriorityQueue[T, P]) swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+// up moves the entry at i towards the root and reports whether it moved
+func (q *PriorityQueue[T, P]) up(i int) bool {
+	moved := false
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.items[i].priority, q.items[parent].priority) {
+			break
+		}
+		q.swap(i, parent)
+		i = parent
+		moved = true
+	}
+	return moved
+}
+
+func (q *PriorityQueue[T, P]) down(i int) {
+	n := len(q.items)
+	for {
+		least := i
+		if l := 2*i + 1; l < n && q.less(q.items[l].priority, q.items[least].priority) {
+			least = l
+		}
+		if r := 2*i + 2; r < n && q.less(q.items[r].priority, q.items[least].priority) {
+			least = r
+		}
+		if least == i {
+			return
+		}
+		q.swap(i, least)
+		i = least
+	}
+}
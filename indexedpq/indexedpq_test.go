@@ -0,0 +1,87 @@
+package indexedpq
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func less(a, b int) bool { return a < b }
+
+// TestRandomOperations checks the queue against a map of the queued entries
+// under a random mix of pushes, updates, removals and pops
+func TestRandomOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	q := New[int](less)
+	queued := make(map[*Handle[int, int]]bool)
+
+	for i := 0; i < 5000; i++ {
+		var some *Handle[int, int]
+		for h := range queued {
+			some = h
+			break
+		}
+
+		switch op := rng.Intn(4); {
+		case op == 0 || some == nil:
+			queued[q.Push(i, rng.Intn(1000))] = true
+		case op == 1:
+			p := rng.Intn(1000)
+			q.Update(some, p)
+			if some.Priority() != p || !q.Contains(some) {
+				t.Fatalf("entry %d lost by Update", some.Value())
+			}
+		case op == 2:
+			q.Remove(some)
+			delete(queued, some)
+			if q.Contains(some) {
+				t.Fatalf("entry %d still queued after Remove", some.Value())
+			}
+		default:
+			min := some.Priority()
+			for h := range queued {
+				if h.Priority() < min {
+					min = h.Priority()
+				}
+			}
+			h := q.Pop()
+			if h.Priority() != min || !queued[h] {
+				t.Fatalf("popped priority %d, want %d", h.Priority(), min)
+			}
+			delete(queued, h)
+		}
+
+		if q.Len() != len(queued) {
+			t.Fatalf("got length %d, want %d", q.Len(), len(queued))
+		}
+	}
+}
+
+func TestHandleOutlivesEntry(t *testing.T) {
+	q := New[string](less)
+	a := q.Push("a", 2)
+	b := q.Push("b", 1)
+
+	if h := q.Peek(); h != b {
+		t.Fatalf("peeked %q, want b", h.Value())
+	}
+	q.Update(a, 0)
+	if h := q.Pop(); h != a || h.Value() != "a" || h.Priority() != 0 {
+		t.Fatalf("popped %q at %d, want a at 0", h.Value(), h.Priority())
+	}
+	if q.Contains(a) {
+		t.Fatal("popped entry is still queued")
+	}
+
+	other := New[string](less)
+	c := other.Push("c", 0)
+	if q.Contains(c) {
+		t.Fatal("queue contains the entry of another queue")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("updating a popped entry did not panic")
+		}
+	}()
+	q.Update(a, 5)
+}
@@ -0,0 +1,61 @@
+// Package arena provides a slab allocator for the nodes of pointer-heavy
+// structures.
+package arena
+
+const (
+	minSlab = 16
+	maxSlab = 4096
+)
+
+// Arena allocates values of type T from slabs, arrays of values allocated
+// together, instead of one by one. A structure of millions of small nodes
+// then consists of thousands of objects for the garbage collector to track
+// and the allocator to size, and Release drops them all at once. Slabs start
+// small and double in size up to 4096 values, so small structures waste
+// little memory.
+//
+// Values stay valid for as long as they are referenced: a slab is only
+// collected once none of its values is reachable, after Release or
+// otherwise. Values given to Free are reused by later allocations, so they
+// must no longer be referenced. The zero value is ready to use.
+type Arena[T any] struct {
+	slab []T
+	free []*T
+	live int
+}
+
+// Alloc returns a pointer to a zero value. Time complexity: O(1) amortized
+func (a *Arena[T]) Alloc() *T {
+	a.live++
+	if n := len(a.free); n > 0 {
+		p := a.free[n-1]
+		a.free[n-1] = nil
+		a.free = a.free[:n-1]
+		return p
+	}
+
+	if len(a.slab) == cap(a.slab) {
+		size := min(max(2*cap(a.slab), minSlab), maxSlab)
+		a.slab = make([]T, 0, size)
+	}
+	a.slab = a.slab[:len(a.slab)+1]
+	return &a.slab[len(a.slab)-1]
+}
+
+// Free zeroes the value p points to and makes it available to Alloc. p
+// must have been returned by Alloc and not be referenced anymore.
+func (a *Arena[T]) Free(p *T) {
+	var zero T
+	*p = zero
+	a.free = append(a.free, p)
+	a.live--
+}
+
+// Len returns the number of values allocated and not freed
+func (a *Arena[T]) Len() int { return a.live }
+
+// Release forgets all values, leaving their slabs to the garbage collector
+// once the values are no longer referenced
+func (a *Arena[T]) Release() {
+	*a = Arena[T]{}
+}
@@ -0,0 +1,57 @@
+package arena
+
+import "testing"
+
+type node struct {
+	value int
+	next  *node
+}
+
+func TestAlloc(t *testing.T) {
+	var a Arena[node]
+	var list *node
+	for i := 0; i < 10000; i++ {
+		n := a.Alloc()
+		if n.value != 0 || n.next != nil {
+			t.Fatalf("allocation %d is not zeroed", i)
+		}
+		n.value, n.next = i, list
+		list = n
+	}
+	if a.Len() != 10000 {
+		t.Fatalf("got length %d, want 10000", a.Len())
+	}
+
+	// values must not share memory
+	for i, n := 9999, list; n != nil; i, n = i-1, n.next {
+		if n.value != i {
+			t.Fatalf("got value %d, want %d", n.value, i)
+		}
+	}
+}
+
+func TestFree(t *testing.T) {
+	var a Arena[node]
+	p := a.Alloc()
+	p.value = 7
+	a.Free(p)
+	if a.Len() != 0 {
+		t.Fatalf("got length %d after Free, want 0", a.Len())
+	}
+
+	q := a.Alloc()
+	if q != p {
+		t.Fatal("Alloc did not reuse the freed value")
+	}
+	if q.value != 0 {
+		t.Fatalf("reused value holds %d, want 0", q.value)
+	}
+
+	a.Release()
+	if a.Len() != 0 {
+		t.Fatalf("got length %d after Release, want 0", a.Len())
+	}
+	if r := a.Alloc(); r == q {
+		t.Fatal("Alloc reused a value after Release")
+	}
+}
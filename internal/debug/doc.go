@@ -0,0 +1,8 @@
+// Package debug switches on the invariant checks of rtree and graph, which
+// verify the structures after every mutation. Build with
+//
+//	go build -tags datastructures_debug
+//
+// or pass the tag to go test to make corruption fail at the operation that
+// causes it, rather than as wrong query results later on.
+package debug
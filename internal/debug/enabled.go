@@ -0,0 +1,6 @@
+//go:build datastructures_debug
+
+package debug
+
+// Enabled reports whether the invariant checks run
+const Enabled = true
@@ -0,0 +1,79 @@
+// Package wire implements the header shared by the binary encodings of this
+// module. Every MarshalBinary output starts with it, naming the structure
+// encoded and the version of its layout, so that data is never decoded as
+// another structure or by code that does not know its layout:
+//
+//	magic    [6]byte  "GDSENC"
+//	kind     string   length as uvarint followed by the bytes, e.g. "cache.LRU"
+//	version  uvarint  version of the layout of the payload
+//
+// Structures holding values of type parameters encode the payload with
+// encoding/gob, so their keys and values must be encodable by it.
+package wire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+)
+
+// magic identifies the encodings of this module
+const magic = "GDSENC"
+
+// Append appends the header of an encoding of kind in the given layout
+// version to buf
+func Append(buf []byte, kind string, version uint64) []byte {
+	buf = append(buf, magic...)
+	buf = binary.AppendUvarint(buf, uint64(len(kind)))
+	buf = append(buf, kind...)
+	return binary.AppendUvarint(buf, version)
+}
+
+// Parse returns the payload following the header of data, which must be an
+// encoding of kind in the given layout version
+func Parse(data []byte, kind string, version uint64) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(magic)) {
+		return nil, errors.New("missing header")
+	}
+	data = data[len(magic):]
+
+	n, k := binary.Uvarint(data)
+	if k <= 0 || n > uint64(len(data)-k) {
+		return nil, errors.New("truncated header")
+	}
+	if got := string(data[k : k+int(n)]); got != kind {
+		return nil, fmt.Errorf("data encodes %s, not %s", got, kind)
+	}
+	data = data[k+int(n):]
+
+	v, k := binary.Uvarint(data)
+	if k <= 0 {
+		return nil, errors.New("truncated header")
+	}
+	if v != version {
+		return nil, fmt.Errorf("unsupported %s layout version %d", kind, v)
+	}
+	return data[k:], nil
+}
+
+// Marshal returns the header of kind in the given layout version followed
+// by the gob encoding of v
+func Marshal(kind string, version uint64, v interface{}) ([]byte, error) {
+	buf := bytes.NewBuffer(Append(nil, kind, version))
+	if err := gob.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes data encoded by Marshal with the same kind and version
+// into v, which must be a pointer
+func Unmarshal(data []byte, kind string, version uint64, v interface{}) error {
+	payload, err := Parse(data, kind, version)
+	if err != nil {
+		return err
+	}
+	return gob.NewDecoder(bytes.NewReader(payload)).Decode(v)
+}
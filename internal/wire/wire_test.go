@@ -0,0 +1,58 @@
+package wire
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	data := append(Append(nil, "pkg.Kind", 3), "payload"...)
+	payload, err := Parse(data, "pkg.Kind", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(payload) != "payload" {
+		t.Fatalf("got payload %q, want %q", payload, "payload")
+	}
+
+	for _, tt := range []struct {
+		name    string
+		data    []byte
+		kind    string
+		version uint64
+		want    string
+	}{
+		{"missing header", []byte("payload"), "pkg.Kind", 3, "missing header"},
+		{"truncated kind", data[:len(magic)+4], "pkg.Kind", 3, "truncated header"},
+		{"truncated version", data[:len(magic)+1+len("pkg.Kind")], "pkg.Kind", 3, "truncated header"},
+		{"other kind", data, "pkg.Other", 3, "data encodes pkg.Kind, not pkg.Other"},
+		{"other version", data, "pkg.Kind", 4, "unsupported pkg.Kind layout version 3"},
+	} {
+		if _, err := Parse(tt.data, tt.kind, tt.version); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	type payload struct {
+		Keys   []string
+		Values map[string]int
+	}
+	in := payload{Keys: []string{"a", "b"}, Values: map[string]int{"a": 1, "b": 2}}
+	data, err := Marshal("test.Payload", 1, &in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out payload
+	if err := Unmarshal(data, "test.Payload", 1, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Keys) != 2 || out.Keys[1] != "b" || out.Values["b"] != 2 {
+		t.Fatalf("decoded %+v, want %+v", out, in)
+	}
+	if err := Unmarshal(data, "test.Other", 1, &out); err == nil {
+		t.Fatal("decoded data of another kind")
+	}
+}
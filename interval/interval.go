@@ -0,0 +1,232 @@
+// Package interval provides a generic interval tree for stabbing and overlap
+// queries over one-dimensional closed intervals, and a set of disjoint
+// ranges that are merged and split as points are added and removed.
+package interval
+
+// Entry is an interval stored in a tree, along with its value. It identifies
+// the interval for Delete, since a tree may hold equal intervals.
+type Entry[T, V any] struct {
+	Lo, Hi T
+	Value  V
+	seq    uint64 // insertion order, to order equal intervals
+}
+
+// node is a tree node, ordered by the low end of its interval. It is
+// augmented with the greatest high end in its subtree, which allows to skip
+// subtrees that end before a query starts.
+type node[T, V any] struct {
+	entry       *Entry[T, V]
+	max         T
+	height      int
+	left, right *node[T, V]
+}
+
+func height[T, V any](n *node[T, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// Tree is a set of closed intervals [Lo, Hi] with associated values, with
+// endpoints ordered by a less function. It is an AVL tree ordered by low
+// ends, in which every node also records the greatest high end below it, so
+// that queries take O(log n + k) time for k results.
+type Tree[T, V any] struct {
+	root   *node[T, V]
+	length int
+	seq    uint64
+	less   func(a, b T) bool
+}
+
+// New returns an empty tree ordered by less
+func New[T, V any](less func(a, b T) bool) *Tree[T, V] {
+	return &Tree[T, V]{less: less}
+}
+
+// Len returns the number of intervals
+func (t *Tree[T, V]) Len() int { return t.length }
+
+// before orders entries by low end, then high end, then insertion
+func (t *Tree[T, V]) before(a, b *Entry[T, V]) bool {
+	switch {
+	case t.less(a.Lo, b.Lo):
+		return true
+	case t.less(b.Lo, a.Lo):
+		return false
+	case t.less(a.Hi, b.Hi):
+		return true
+	case t.less(b.Hi, a.Hi):
+		return false
+	}
+	return a.seq < b.seq
+}
+
+// Insert adds the interval [lo, hi] with value and returns its entry. Equal
+// intervals are kept separately. It panics if hi is less than lo.
+// Time complexity: O(log n)
+func (t *Tree[T, V]) Insert(lo, hi T, value V) *Entry[T, V] {
+	if t.less(hi, lo) {
+		panic("interval: Insert of an interval ending before it starts")
+	}
+
+	t.seq++
+	e := &Entry[T, V]{Lo: lo, Hi: hi, Value: value, seq: t.seq}
+	t.root = t.insert(t.root, e)
+	t.length++
+	return e
+}
+
+func (t *Tree[T, V]) insert(n *node[T, V], e *Entry[T, V]) *node[T, V] {
+	if n == nil {
+		return &node[T, V]{entry: e, max: e.Hi, height: 1}
+	}
+
+	if t.before(e, n.entry) {
+		n.left = t.insert(n.left, e)
+	} else {
+		n.right = t.insert(n.right, e)
+	}
+	return t.rebalance(n)
+}
+
+// Delete removes the interval of entry e, as returned by Insert, and reports
+// whether it was present.
+// Time complexity: O(log n)
+func (t *Tree[T, V]) Delete(e *Entry[T, V]) bool {
+	length := t.length
+	t.root = t.delete(t.root, e)
+	return t.length < length
+}
+
+func (t *Tree[T, V]) delete(n *node[T, V], e *Entry[T, V]) *node[T, V] {
+	if n == nil {
+		return nil
+	}
+
+	switch {
+	case n.entry == e:
+		t.length--
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+
+		// replace n by its successor
+		var succ *node[T, V]
+		n.right, succ = t.deleteMin(n.right)
+		succ.left, succ.right = n.left, n.right
+		n = succ
+	case t.before(e, n.entry):
+		n.left = t.delete(n.left, e)
+	default:
+		n.right = t.delete(n.right, e)
+	}
+
+	return t.rebalance(n)
+}
+
+// deleteMin detaches the least node below n and returns the remaining tree
+// and the detached node
+func (t *Tree[T, V]) deleteMin(n *node[T, V]) (*node[T, V], *node[T, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+
+	var min *node[T, V]
+	n.left, min = t.deleteMin(n.left)
+	return t.rebalance(n), min
+}
+
+// update recomputes the height and the greatest high end of n
+func (t *Tree[T, V]) update(n *node[T, V]) {
+	n.height = 1 + max(height(n.left), height(n.right))
+	n.max = n.entry.Hi
+	if n.left != nil && t.less(n.max, n.left.max) {
+		n.max = n.left.max
+	}
+	if n.right != nil && t.less(n.max, n.right.max) {
+		n.max = n.right.max
+	}
+}
+
+// rebalance restores the height invariant at n, whose subtrees are balanced
+// and differ in height by at most two, and returns the new subtree root
+func (t *Tree[T, V]) rebalance(n *node[T, V]) *node[T, V] {
+	t.update(n)
+
+	switch b := height(n.left) - height(n.right); {
+	case b > 1:
+		if height(n.left.left) < height(n.left.right) {
+			n.left = t.rotateLeft(n.left)
+		}
+		return t.rotateRight(n)
+	case b < -1:
+		if height(n.right.right) < height(n.right.left) {
+			n.right = t.rotateRight(n.right)
+		}
+		return t.rotateLeft(n)
+	}
+
+	return n
+}
+
+func (t *Tree[T, V]) rotateLeft(n *node[T, V]) *node[T, V] {
+	r := n.right
+	n.right, r.left = r.left, n
+	t.update(n)
+	t.update(r)
+	return r
+}
+
+func (t *Tree[T, V]) rotateRight(n *node[T, V]) *node[T, V] {
+	l := n.left
+	n.left, l.right = l.right, n
+	t.update(n)
+	t.update(l)
+	return l
+}
+
+// Stab calls fn for every interval containing point, in order of their low
+// ends, until fn returns false.
+// Time complexity: O(log n + k) for k intervals found
+func (t *Tree[T, V]) Stab(point T, fn func(e *Entry[T, V]) bool) {
+	t.Overlap(point, point, fn)
+}
+
+// Overlap calls fn for every interval sharing at least one point with
+// [lo, hi], in order of their low ends, until fn returns false.
+// Time complexity: O(log n + k) for k intervals found
+func (t *Tree[T, V]) Overlap(lo, hi T, fn func(e *Entry[T, V]) bool) {
+	var visit func(n *node[T, V]) bool
+	visit = func(n *node[T, V]) bool {
+		// every interval below n ends before lo
+		if n == nil || t.less(n.max, lo) {
+			return true
+		}
+		if !visit(n.left) {
+			return false
+		}
+		// n and every interval to its right start after hi
+		if t.less(hi, n.entry.Lo) {
+			return true
+		}
+		if !t.less(n.entry.Hi, lo) && !fn(n.entry) {
+			return false
+		}
+		return visit(n.right)
+	}
+	visit(t.root)
+}
+
+// Ascend calls fn for every interval in order of their low ends until fn
+// returns false
+func (t *Tree[T, V]) Ascend(fn func(e *Entry[T, V]) bool) {
+	var walk func(n *node[T, V]) bool
+	walk = func(n *node[T, V]) bool {
+		return n == nil || walk(n.left) && fn(n.entry) && walk(n.right)
+	}
+	walk(t.root)
+}
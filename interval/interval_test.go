@@ -0,0 +1,92 @@
+package interval
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func less(a, b int) bool { return a < b }
+
+// TestQueries compares stabbing and overlap queries with a scan over all
+// intervals, while intervals are inserted and deleted at random
+func TestQueries(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := New[int, int](less)
+	var live []*Entry[int, int]
+
+	for i := 0; i < 2000; i++ {
+		if len(live) > 0 && rng.Intn(3) == 0 {
+			j := rng.Intn(len(live))
+			if !tree.Delete(live[j]) {
+				t.Fatalf("Delete of [%d, %d] failed", live[j].Lo, live[j].Hi)
+			}
+			live = append(live[:j], live[j+1:]...)
+		} else {
+			lo := rng.Intn(1000)
+			live = append(live, tree.Insert(lo, lo+rng.Intn(50), i))
+		}
+		if tree.Len() != len(live) {
+			t.Fatalf("got length %d, want %d", tree.Len(), len(live))
+		}
+	}
+
+	for q := 0; q < 200; q++ {
+		lo := rng.Intn(1100) - 50
+		hi := lo + rng.Intn(30)
+
+		want := 0
+		for _, e := range live {
+			if e.Lo <= hi && lo <= e.Hi {
+				want++
+			}
+		}
+		got, prev := 0, -1
+		tree.Overlap(lo, hi, func(e *Entry[int, int]) bool {
+			if e.Hi < lo || hi < e.Lo {
+				t.Fatalf("[%d, %d] does not overlap [%d, %d]", e.Lo, e.Hi, lo, hi)
+			}
+			if e.Lo < prev {
+				t.Fatalf("Overlap out of order: %d after %d", e.Lo, prev)
+			}
+			got, prev = got+1, e.Lo
+			return true
+		})
+		if got != want {
+			t.Fatalf("Overlap(%d, %d) found %d intervals, want %d", lo, hi, got, want)
+		}
+
+		want = 0
+		for _, e := range live {
+			if e.Lo <= lo && lo <= e.Hi {
+				want++
+			}
+		}
+		got = 0
+		tree.Stab(lo, func(e *Entry[int, int]) bool {
+			got++
+			return true
+		})
+		if got != want {
+			t.Fatalf("Stab(%d) found %d intervals, want %d", lo, got, want)
+		}
+	}
+}
+
+func TestEqualIntervals(t *testing.T) {
+	tree := New[int, string](less)
+	a := tree.Insert(1, 5, "a")
+	tree.Insert(1, 5, "b")
+
+	if !tree.Delete(a) || tree.Delete(a) {
+		t.Fatal("Delete did not remove the given entry exactly once")
+	}
+	tree.Stab(3, func(e *Entry[int, string]) bool {
+		if e.Value != "b" {
+			t.Fatalf("found deleted entry %q", e.Value)
+		}
+		return true
+	})
+	if tree.Len() != 1 {
+		t.Fatalf("got length %d, want 1", tree.Len())
+	}
+}
@@ -0,0 +1,148 @@
+package interval
+
+import (
+	"github.com/hanyangtay/go-datastructures/avl"
+)
+
+// Set is a set of points stored as disjoint half-open ranges [lo, hi), with
+// endpoints ordered by a less function, such as the free slots of a
+// calendar or allocated blocks of IP addresses. Adding a range merges it
+// with the ranges it overlaps or touches, and removing one splits the range
+// it cuts, so the set always holds the fewest ranges covering its points.
+// Ranges are half-open so that adjacent ones, like [1, 3) and [3, 5), can be
+// joined. Updates take O((k+1) log n) time for k ranges merged or removed.
+type Set[T any] struct {
+	ranges *avl.Tree[T, T] // high end by low end
+	less   func(a, b T) bool
+}
+
+// NewSet returns an empty set ordered by less
+func NewSet[T any](less func(a, b T) bool) *Set[T] {
+	return &Set[T]{ranges: avl.New[T, T](less), less: less}
+}
+
+// Len returns the number of disjoint ranges
+func (s *Set[T]) Len() int { return s.ranges.Len() }
+
+// Add adds the points of [lo, hi) to the set. Empty ranges, where lo is not
+// less than hi, are ignored.
+func (s *Set[T]) Add(lo, hi T) {
+	if !s.less(lo, hi) {
+		return
+	}
+
+	// join a range starting before lo that reaches it
+	if l, h, ok := s.ranges.Floor(lo); ok && !s.less(h, lo) {
+		s.ranges.Delete(l)
+		lo = l
+		if s.less(hi, h) {
+			hi = h
+		}
+	}
+
+	// join the ranges starting from lo up to and including hi
+	for {
+		l, h, ok := s.ranges.Ceiling(lo)
+		if !ok || s.less(hi, l) {
+			break
+		}
+		s.ranges.Delete(l)
+		if s.less(hi, h) {
+			hi = h
+		}
+	}
+
+	s.ranges.Put(lo, hi)
+}
+
+// Remove removes the points of [lo, hi) from the set, splitting a range
+// that extends beyond both ends. Empty ranges are ignored.
+func (s *Set[T]) Remove(lo, hi T) {
+	if !s.less(lo, hi) {
+		return
+	}
+
+	// cut a range starting before lo that extends past it
+	if l, h, ok := s.ranges.Floor(lo); ok && s.less(l, lo) && s.less(lo, h) {
+		s.ranges.Put(l, lo)
+		if s.less(hi, h) {
+			s.ranges.Put(hi, h)
+			return
+		}
+	}
+
+	// drop the ranges starting in [lo, hi), keeping the end of the last one
+	// if it extends past hi
+	for {
+		l, h, ok := s.ranges.Ceiling(lo)
+		if !ok || !s.less(l, hi) {
+			break
+		}
+		s.ranges.Delete(l)
+		if s.less(hi, h) {
+			s.ranges.Put(hi, h)
+			break
+		}
+	}
+}
+
+// Contains reports whether x is in the set. Time complexity: O(log n)
+func (s *Set[T]) Contains(x T) bool {
+	_, h, ok := s.ranges.Floor(x)
+	return ok && s.less(x, h)
+}
+
+// Covers reports whether all points of [lo, hi) are in the set, which holds
+// for empty ranges. Time complexity: O(log n)
+func (s *Set[T]) Covers(lo, hi T) bool {
+	if !s.less(lo, hi) {
+		return true
+	}
+	_, h, ok := s.ranges.Floor(lo)
+	return ok && s.less(lo, h) && !s.less(h, hi)
+}
+
+// Overlaps reports whether some point of [lo, hi) is in the set.
+// Time complexity: O(log n)
+func (s *Set[T]) Overlaps(lo, hi T) bool {
+	if !s.less(lo, hi) {
+		return false
+	}
+	if s.Contains(lo) {
+		return true
+	}
+	l, _, ok := s.ranges.Ceiling(lo)
+	return ok && s.less(l, hi)
+}
+
+// Ascend calls fn for every range in order until fn returns false
+func (s *Set[T]) Ascend(fn func(lo, hi T) bool) {
+	s.ranges.Ascend(fn)
+}
+
+// Gaps calls fn in order for every maximal range of [lo, hi) that holds no
+// point of the set, until fn returns false. Time complexity: O(log n + k)
+// for k gaps
+func (s *Set[T]) Gaps(lo, hi T, fn func(lo, hi T) bool) {
+	if !s.less(lo, hi) {
+		return
+	}
+
+	// start after a range covering lo
+	if _, h, ok := s.ranges.Floor(lo); ok && s.less(lo, h) {
+		lo = h
+	}
+
+	stopped := false
+	s.ranges.Range(lo, hi, func(l, h T) bool {
+		if s.less(lo, l) && !fn(lo, l) {
+			stopped = true
+			return false
+		}
+		lo = h
+		return true
+	})
+	if !stopped && s.less(lo, hi) {
+		fn(lo, hi)
+	}
+}
@@ -0,0 +1,100 @@
+package interval
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestSet applies random additions and removals to a set and to an array of
+// points, and checks that the set holds the fewest ranges covering them
+func TestSet(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := NewSet[int](less)
+	var in [200]bool
+
+	for i := 0; i < 1000; i++ {
+		lo := rng.Intn(200)
+		hi := lo + rng.Intn(20)
+		if hi > 200 {
+			hi = 200
+		}
+
+		add := rng.Intn(2) == 0
+		if add {
+			s.Add(lo, hi)
+		} else {
+			s.Remove(lo, hi)
+		}
+		for x := lo; x < hi; x++ {
+			in[x] = add
+		}
+
+		// the ranges of the set are the maximal runs of points
+		var runs [][2]int
+		for x := 0; x < 200; x++ {
+			if in[x] && (x == 0 || !in[x-1]) {
+				runs = append(runs, [2]int{x, x})
+			}
+			if in[x] {
+				runs[len(runs)-1][1] = x + 1
+			}
+		}
+
+		var got [][2]int
+		s.Ascend(func(lo, hi int) bool {
+			got = append(got, [2]int{lo, hi})
+			return true
+		})
+		if len(got) != len(runs) || s.Len() != len(runs) {
+			t.Fatalf("got ranges %v, want %v", got, runs)
+		}
+		for j := range runs {
+			if got[j] != runs[j] {
+				t.Fatalf("got ranges %v, want %v", got, runs)
+			}
+		}
+	}
+
+	for x := 0; x < 200; x++ {
+		if s.Contains(x) != in[x] {
+			t.Fatalf("Contains(%d) = %v, want %v", x, !in[x], in[x])
+		}
+	}
+	for lo := 0; lo+5 <= 200; lo += 7 {
+		hi := lo + 5
+		covers, overlaps := true, false
+		for x := lo; x < hi; x++ {
+			covers = covers && in[x]
+			overlaps = overlaps || in[x]
+		}
+		if s.Covers(lo, hi) != covers || s.Overlaps(lo, hi) != overlaps {
+			t.Fatalf("[%d, %d): got Covers %v and Overlaps %v, want %v and %v",
+				lo, hi, s.Covers(lo, hi), s.Overlaps(lo, hi), covers, overlaps)
+		}
+	}
+}
+
+func TestGaps(t *testing.T) {
+	s := NewSet[int](less)
+	s.Add(10, 20)
+	s.Add(30, 40)
+	s.Add(20, 25) // joins [10, 20)
+
+	var gaps [][2]int
+	s.Gaps(0, 50, func(lo, hi int) bool {
+		gaps = append(gaps, [2]int{lo, hi})
+		return true
+	})
+	want := [][2]int{{0, 10}, {25, 30}, {40, 50}}
+	if len(gaps) != len(want) {
+		t.Fatalf("got gaps %v, want %v", gaps, want)
+	}
+	for i := range want {
+		if gaps[i] != want[i] {
+			t.Fatalf("got gaps %v, want %v", gaps, want)
+		}
+	}
+	if s.Len() != 2 {
+		t.Fatalf("got %d ranges, want 2", s.Len())
+	}
+}
@@ -0,0 +1,301 @@
+// Package kdtree provides a k-d tree for nearest neighbour and range queries
+// over points in a few dimensions.
+package kdtree
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hanyangtay/go-datastructures/topk"
+)
+
+// Point is a position given by its coordinates
+type Point []float64
+
+// Entry is a point with an associated value
+type Entry[V any] struct {
+	Point Point
+	Value V
+}
+
+// Neighbor is an entry returned by KNN, with its distance to the query point
+type Neighbor[V any] struct {
+	Entry[V]
+	Dist float64
+}
+
+// node splits space along the axis at its depth modulo the number of
+// dimensions: points of its left subtree are not greater than its own along
+// that axis, points of its right subtree not less
+type node[V any] struct {
+	Entry[V]
+	left, right *node[V]
+}
+
+// Tree is a k-d tree: a binary tree that partitions space by cycling through
+// the axes, for point data in low dimensions, up to about 16. Unlike an
+// R-tree it stores the points themselves rather than bounding boxes, so it
+// is smaller and faster to build, but it holds points only.
+//
+// Build returns a balanced tree, giving O(log n) nearest neighbour queries
+// for well spread points. Insert and Delete do not rebalance; a tree that
+// changed a lot should be rebuilt. Points must not be modified once added.
+type Tree[V any] struct {
+	root   *node[V]
+	dims   int
+	length int
+}
+
+// New returns an empty tree for points of dims dimensions. It panics if dims
+// is not positive.
+func New[V any](dims int) *Tree[V] {
+	if dims < 1 {
+		panic("kdtree: number of dimensions must be positive")
+	}
+	return &Tree[V]{dims: dims}
+}
+
+// Build returns a balanced tree of entries, split at the median along every
+// axis. The entries slice is reordered. It panics if a point does not have
+// dims dimensions. Time complexity: O(n log² n)
+func Build[V any](dims int, entries []Entry[V]) *Tree[V] {
+	t := New[V](dims)
+	for _, e := range entries {
+		t.check(e.Point)
+	}
+	t.root = build(entries, 0, dims)
+	t.length = len(entries)
+	return t
+}
+
+func build[V any](entries []Entry[V], axis, dims int) *node[V] {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Point[axis] < entries[j].Point[axis] })
+	mid := len(entries) / 2
+	next := (axis + 1) % dims
+	return &node[V]{
+		Entry: entries[mid],
+		left:  build(entries[:mid], next, dims),
+		right: build(entries[mid+1:], next, dims),
+	}
+}
+
+func (t *Tree[V]) check(p Point) {
+	if len(p) != t.dims {
+		panic("kdtree: point has wrong number of dimensions")
+	}
+}
+
+// Len returns the number of entries
+func (t *Tree[V]) Len() int { return t.length }
+
+// Dims returns the number of dimensions of the points
+func (t *Tree[V]) Dims() int { return t.dims }
+
+// Rebuild rebalances the tree. Time complexity: O(n log² n)
+func (t *Tree[V]) Rebuild() {
+	entries := make([]Entry[V], 0, t.length)
+	t.Each(func(p Point, v V) bool {
+		entries = append(entries, Entry[V]{p, v})
+		return true
+	})
+	t.root = build(entries, 0, t.dims)
+}
+
+// Insert adds value at p, keeping any other entries at the same point. It
+// panics if p does not have Dims dimensions.
+// Time complexity: O(depth)
+func (t *Tree[V]) Insert(p Point, value V) {
+	t.check(p)
+
+	link := &t.root
+	for axis := 0; *link != nil; axis = (axis + 1) % t.dims {
+		if n := *link; p[axis] < n.Point[axis] {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+	}
+	*link = &node[V]{Entry: Entry[V]{p, value}}
+	t.length++
+}
+
+// Delete removes an entry at p and reports whether there was one.
+// Time complexity: O(depth) on average
+func (t *Tree[V]) Delete(p Point) bool {
+	t.check(p)
+
+	link, axis := t.find(&t.root, 0, p)
+	if link == nil {
+		return false
+	}
+	t.remove(link, axis)
+	t.length--
+	return true
+}
+
+// find returns the link to a node below *link at p, and its axis, or nil.
+// Both subtrees are searched on a tie since either may hold equal points.
+func (t *Tree[V]) find(link **node[V], axis int, p Point) (**node[V], int) {
+	n := *link
+	if n == nil {
+		return nil, 0
+	}
+	if equal(n.Point, p) {
+		return link, axis
+	}
+
+	next := (axis + 1) % t.dims
+	if p[axis] <= n.Point[axis] {
+		if l, a := t.find(&n.left, next, p); l != nil {
+			return l, a
+		}
+	}
+	if p[axis] >= n.Point[axis] {
+		return t.find(&n.right, next, p)
+	}
+	return nil, 0
+}
+
+// remove deletes the node at *link, which splits along axis, by replacing it
+// with the least node along that axis from its right subtree, or from its
+// left subtree which then becomes the right one
+func (t *Tree[V]) remove(link **node[V], axis int) {
+	n := *link
+	if n.left == nil && n.right == nil {
+		*link = nil
+		return
+	}
+
+	if n.right == nil {
+		n.left, n.right = nil, n.left
+	}
+	next := (axis + 1) % t.dims
+	min, minAxis := t.min(&n.right, next, axis)
+	n.Entry = (*min).Entry
+	t.remove(min, minAxis)
+}
+
+// min returns the link to the node below *link, which splits along axis,
+// with the least coordinate along target, and the axis of that node
+func (t *Tree[V]) min(link **node[V], axis, target int) (**node[V], int) {
+	best, bestAxis := link, axis
+	next := (axis + 1) % t.dims
+	consider := func(l **node[V]) {
+		if *l == nil {
+			return
+		}
+		if m, a := t.min(l, next, target); (*m).Point[target] < (*best).Point[target] {
+			best, bestAxis = m, a
+		}
+	}
+
+	consider(&(*link).left)
+	if axis != target {
+		consider(&(*link).right)
+	}
+	return best, bestAxis
+}
+
+// KNN returns the k entries nearest to p by euclidean distance, nearest
+// first. It panics if p does not have Dims dimensions.
+func (t *Tree[V]) KNN(p Point, k int) []Neighbor[V] {
+	t.check(p)
+	if k < 1 {
+		return nil
+	}
+
+	// the best candidates by squared distance
+	best := topk.New(k, func(a, b Neighbor[V]) bool { return a.Dist > b.Dist })
+
+	var search func(n *node[V], axis int)
+	search = func(n *node[V], axis int) {
+		if n == nil {
+			return
+		}
+
+		best.Add(Neighbor[V]{n.Entry, squaredDist(n.Point, p)})
+
+		diff := p[axis] - n.Point[axis]
+		near, far := n.left, n.right
+		if diff >= 0 {
+			near, far = far, near
+		}
+
+		next := (axis + 1) % t.dims
+		search(near, next)
+		if !best.Full() || diff*diff < best.Min().Dist {
+			search(far, next)
+		}
+	}
+	search(t.root, 0)
+
+	result := best.Result()
+	for i := range result {
+		result[i].Dist = math.Sqrt(result[i].Dist)
+	}
+	return result
+}
+
+// Range calls fn for every entry inside the box from lo to hi, including its
+// boundary, until fn returns false. It panics if lo or hi do not have Dims
+// dimensions.
+func (t *Tree[V]) Range(lo, hi Point, fn func(p Point, value V) bool) {
+	t.check(lo)
+	t.check(hi)
+
+	var walk func(n *node[V], axis int) bool
+	walk = func(n *node[V], axis int) bool {
+		if n == nil {
+			return true
+		}
+
+		inside := true
+		for i, x := range n.Point {
+			if x < lo[i] || x > hi[i] {
+				inside = false
+				break
+			}
+		}
+		if inside && !fn(n.Point, n.Value) {
+			return false
+		}
+
+		next := (axis + 1) % t.dims
+		if lo[axis] <= n.Point[axis] && !walk(n.left, next) {
+			return false
+		}
+		return hi[axis] < n.Point[axis] || walk(n.right, next)
+	}
+	walk(t.root, 0)
+}
+
+// Each calls fn for every entry in an unspecified order until fn returns
+// false
+func (t *Tree[V]) Each(fn func(p Point, value V) bool) {
+	var walk func(n *node[V]) bool
+	walk = func(n *node[V]) bool {
+		return n == nil || fn(n.Point, n.Value) && walk(n.left) && walk(n.right)
+	}
+	walk(t.root)
+}
+
+func squaredDist(a, b Point) float64 {
+	d := 0.0
+	for i := range a {
+		d += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return d
+}
+
+func equal(a, b Point) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package kdtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func randomPoint(rng *rand.Rand, dims int) Point {
+	p := make(Point, dims)
+	for i := range p {
+		p[i] = float64(rng.Intn(100))
+	}
+	return p
+}
+
+func dist(a, b Point) float64 { return math.Sqrt(squaredDist(a, b)) }
+
+// checkKNN compares KNN against the distances of all points to q
+func checkKNN(t *testing.T, tr *Tree[int], q Point, k int) {
+	t.Helper()
+	var want []float64
+	tr.Each(func(p Point, _ int) bool {
+		want = append(want, dist(p, q))
+		return true
+	})
+	sort.Float64s(want)
+	want = want[:min(k, len(want))]
+
+	got := tr.KNN(q, k)
+	if len(got) != len(want) {
+		t.Fatalf("KNN(%v, %d) returned %d entries, want %d", q, k, len(got), len(want))
+	}
+	for i, n := range got {
+		if n.Dist != want[i] || dist(n.Point, q) != n.Dist {
+			t.Fatalf("KNN(%v, %d)[%d] at distance %v, want %v", q, k, i, n.Dist, want[i])
+		}
+	}
+}
+
+func TestKNN(t *testing.T) {
+	for _, dims := range []int{1, 2, 3, 5} {
+		rng := rand.New(rand.NewSource(int64(dims)))
+		var entries []Entry[int]
+		for i := 0; i < 500; i++ {
+			entries = append(entries, Entry[int]{randomPoint(rng, dims), i})
+		}
+
+		built, inserted := Build(dims, entries), New[int](dims)
+		for _, e := range entries {
+			inserted.Insert(e.Point, e.Value)
+		}
+		for i := 0; i < 50; i++ {
+			q := randomPoint(rng, dims)
+			for _, k := range []int{1, 5, 600} {
+				checkKNN(t, built, q, k)
+				checkKNN(t, inserted, q, k)
+			}
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tr := New[int](2)
+	var points []Point
+	for i := 0; i < 300; i++ {
+		p := randomPoint(rng, 2)
+		tr.Insert(p, i)
+		points = append(points, p)
+	}
+
+	for i, p := range points[:200] {
+		if !tr.Delete(p) {
+			t.Fatalf("Delete(%v) found nothing", p)
+		}
+		if tr.Len() != 299-i {
+			t.Fatalf("got length %d, want %d", tr.Len(), 299-i)
+		}
+		if i%20 == 0 {
+			checkKNN(t, tr, randomPoint(rng, 2), 10)
+		}
+	}
+	if tr.Delete(Point{-1, -1}) {
+		t.Fatal("deleted a point that was never inserted")
+	}
+
+	tr.Rebuild()
+	if tr.Len() != 100 {
+		t.Fatalf("got length %d after Rebuild, want 100", tr.Len())
+	}
+	checkKNN(t, tr, Point{50, 50}, 20)
+}
+
+func TestRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var entries []Entry[int]
+	for i := 0; i < 1000; i++ {
+		entries = append(entries, Entry[int]{randomPoint(rng, 3), i})
+	}
+	tr := Build(3, append([]Entry[int](nil), entries...))
+
+	for i := 0; i < 50; i++ {
+		lo, hi := randomPoint(rng, 3), randomPoint(rng, 3)
+		for j := range lo {
+			lo[j], hi[j] = min(lo[j], hi[j]), max(lo[j], hi[j])
+		}
+
+		want := make(map[int]bool)
+		for _, e := range entries {
+			if e.Point[0] >= lo[0] && e.Point[0] <= hi[0] && e.Point[1] >= lo[1] && e.Point[1] <= hi[1] && e.Point[2] >= lo[2] && e.Point[2] <= hi[2] {
+				want[e.Value] = true
+			}
+		}
+		n := 0
+		tr.Range(lo, hi, func(p Point, v int) bool {
+			if !want[v] {
+				t.Fatalf("Range(%v, %v) returned %v outside", lo, hi, p)
+			}
+			n++
+			return true
+		})
+		if n != len(want) {
+			t.Fatalf("Range(%v, %v) returned %d entries, want %d", lo, hi, n, len(want))
+		}
+	}
+}
+
+func TestWrongDimensions(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("inserting a 3 dimensional point into a 2 dimensional tree did not panic")
+		}
+	}()
+	New[int](2).Insert(Point{1, 2, 3}, 0)
+}
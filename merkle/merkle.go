@@ -0,0 +1,181 @@
+// Package merkle provides a Merkle tree, a binary tree of hashes over a list
+// of leaves, whose root commits to all of them: any single leaf can be shown
+// to be part of the list by a proof of O(log n) hashes.
+package merkle
+
+import (
+	"bytes"
+	"hash"
+)
+
+// Leaves and inner nodes are hashed with different prefixes, as in RFC 6962,
+// so an inner node cannot be passed off as a leaf
+const (
+	leafPrefix = 0
+	nodePrefix = 1
+)
+
+// Tree is a Merkle tree over a list of leaves, with a configurable hash
+// function. A node with no sibling, at the end of an odd level, is carried
+// up unchanged rather than paired with a copy of itself, which would give a
+// list the same root as the list with its last leaf repeated. Leaves can be appended and replaced in O(log n) hashes. A Tree is not safe
+// for concurrent use.
+type Tree struct {
+	h hash.Hash
+
+	// levels[0] holds the leaf hashes, and levels[i+1][j] the hash of
+	// levels[i][2j] and levels[i][2j+1]; the last level holds the root
+	levels [][][]byte
+}
+
+// New returns a tree over leaves using the hash function returned by
+// newHash, such as sha256.New
+func New(newHash func() hash.Hash, leaves [][]byte) *Tree {
+	t := &Tree{h: newHash()}
+
+	level := make([][]byte, len(leaves))
+	for i, data := range leaves {
+		level[i] = leafHash(t.h, data)
+	}
+	t.levels = [][][]byte{level}
+
+	for len(level) > 1 {
+		next := make([][]byte, (len(level)+1)/2)
+		for j := range next {
+			next[j] = t.parent(level, j)
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+
+	return t
+}
+
+// Len returns the number of leaves
+func (t *Tree) Len() int { return len(t.levels[0]) }
+
+// Root returns the root hash. The root of an empty tree is the hash of no
+// data.
+func (t *Tree) Root() []byte {
+	if t.Len() == 0 {
+		t.h.Reset()
+		return t.h.Sum(nil)
+	}
+	return bytes.Clone(t.levels[len(t.levels)-1][0])
+}
+
+// Leaf returns the hash of leaf i. It panics if i is out of range.
+func (t *Tree) Leaf(i int) []byte {
+	t.check(i)
+	return bytes.Clone(t.levels[0][i])
+}
+
+func (t *Tree) check(i int) {
+	if i < 0 || i >= t.Len() {
+		panic("merkle: index out of range")
+	}
+}
+
+// parent returns the hash of node j of the level above level
+func (t *Tree) parent(level [][]byte, j int) []byte {
+	if 2*j+1 == len(level) {
+		return level[2*j]
+	}
+	return nodeHash(t.h, level[2*j], level[2*j+1])
+}
+
+// Update replaces leaf i by data and rehashes the path to the root. It
+// panics if i is out of range. Time complexity: O(log n)
+func (t *Tree) Update(i int, data []byte) {
+	t.check(i)
+	t.levels[0][i] = leafHash(t.h, data)
+	t.rehash(i)
+}
+
+// Append adds data as the last leaf. Time complexity: O(log n) amortized
+func (t *Tree) Append(data []byte) {
+	t.levels[0] = append(t.levels[0], leafHash(t.h, data))
+	t.rehash(t.Len() - 1)
+}
+
+// rehash recomputes the ancestors of leaf i, growing levels as needed
+func (t *Tree) rehash(i int) {
+	for k := 0; len(t.levels[k]) > 1; k++ {
+		if k+1 == len(t.levels) {
+			t.levels = append(t.levels, nil)
+		}
+		i /= 2
+		if i == len(t.levels[k+1]) {
+			t.levels[k+1] = append(t.levels[k+1], nil)
+		}
+		t.levels[k+1][i] = t.parent(t.levels[k], i)
+	}
+}
+
+// Proof shows that a leaf is part of a tree: it holds the hashes of the
+// siblings along the path from the leaf to the root, bottom up, leaving out
+// nodes that have no sibling
+type Proof struct {
+	Index  int // position of the leaf
+	Size   int // number of leaves in the tree
+	Hashes [][]byte
+}
+
+// Proof returns the proof that leaf i is part of t. It panics if i is out of
+// range. Time complexity: O(log n)
+func (t *Tree) Proof(i int) Proof {
+	t.check(i)
+
+	p := Proof{Index: i, Size: t.Len()}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		if sibling := i ^ 1; sibling < len(level) {
+			p.Hashes = append(p.Hashes, bytes.Clone(level[sibling]))
+		}
+		i /= 2
+	}
+	return p
+}
+
+// Verify reports whether proof shows that data is a leaf of the tree with
+// the given root, hashed by the hash function returned by newHash.
+// Time complexity: O(log n)
+func Verify(newHash func() hash.Hash, root, data []byte, proof Proof) bool {
+	if proof.Index < 0 || proof.Index >= proof.Size {
+		return false
+	}
+
+	h := newHash()
+	sum := leafHash(h, data)
+	hashes := proof.Hashes
+	for i, n := proof.Index, proof.Size; n > 1; i, n = i/2, (n+1)/2 {
+		if i%2 == 0 && i+1 == n {
+			continue // carried up without a sibling
+		}
+		if len(hashes) == 0 {
+			return false
+		}
+		if i%2 == 0 {
+			sum = nodeHash(h, sum, hashes[0])
+		} else {
+			sum = nodeHash(h, hashes[0], sum)
+		}
+		hashes = hashes[1:]
+	}
+
+	return len(hashes) == 0 && bytes.Equal(sum, root)
+}
+
+func leafHash(h hash.Hash, data []byte) []byte {
+	h.Reset()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func nodeHash(h hash.Hash, left, right []byte) []byte {
+	h.Reset()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
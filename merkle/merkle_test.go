@@ -0,0 +1,72 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+func leaves(n int) [][]byte {
+	var data [][]byte
+	for i := 0; i < n; i++ {
+		data = append(data, []byte(fmt.Sprint("leaf", i)))
+	}
+	return data
+}
+
+// TestProofs checks that every leaf of trees of many sizes has a valid
+// proof, and that proofs do not verify other data or positions
+func TestProofs(t *testing.T) {
+	for n := 1; n <= 33; n++ {
+		data := leaves(n)
+		tr := New(sha256.New, data)
+		root := tr.Root()
+
+		for i := range data {
+			p := tr.Proof(i)
+			if !Verify(sha256.New, root, data[i], p) {
+				t.Fatalf("n=%d: proof of leaf %d does not verify", n, i)
+			}
+			if Verify(sha256.New, root, []byte("forged"), p) {
+				t.Fatalf("n=%d: proof of leaf %d verifies other data", n, i)
+			}
+			if n > 1 {
+				p.Index = (i + 1) % n
+				if Verify(sha256.New, root, data[i], p) {
+					t.Fatalf("n=%d: proof of leaf %d verifies at another index", n, i)
+				}
+			}
+		}
+	}
+}
+
+func TestAppendUpdate(t *testing.T) {
+	data := leaves(20)
+	tr := New(sha256.New, nil)
+	for i, d := range data {
+		tr.Append(d)
+		if want := New(sha256.New, data[:i+1]).Root(); !bytes.Equal(tr.Root(), want) {
+			t.Fatalf("root after %d appends differs from a tree built at once", i+1)
+		}
+	}
+
+	old := tr.Root()
+	tr.Update(7, []byte("changed"))
+	data[7] = []byte("changed")
+	if bytes.Equal(tr.Root(), old) || !bytes.Equal(tr.Root(), New(sha256.New, data).Root()) {
+		t.Fatal("root after Update differs from a tree built at once")
+	}
+	if !bytes.Equal(tr.Leaf(7), New(sha256.New, data[7:8]).Root()) {
+		t.Fatal("leaf hash differs from the root of a tree of that leaf")
+	}
+}
+
+// TestDuplicatedLast checks that repeating the last leaf of an odd list
+// changes the root
+func TestDuplicatedLast(t *testing.T) {
+	data := leaves(3)
+	if bytes.Equal(New(sha256.New, data).Root(), New(sha256.New, append(data, data[2])).Root()) {
+		t.Fatal("a list and the list with its last leaf repeated have the same root")
+	}
+}
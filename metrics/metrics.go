@@ -0,0 +1,40 @@
+// Package metrics defines the instrumentation hooks that rtree and graph
+// report to. Package registry collects them for expvar and Prometheus.
+//
+// Metrics reported:
+//
+//	rtree_inserts_total        counter    objects inserted
+//	rtree_deletes_total        counter    objects deleted
+//	rtree_queries_total        counter    SearchIntersect and KNN queries
+//	rtree_nodes_visited        histogram  tree nodes visited per query
+//	graph_searches_total       counter    single direction shortest path searches
+//	graph_nodes_expanded       histogram  nodes expanded per search
+//	graph_queue_size           histogram  largest queue length per search
+package metrics
+
+// Metrics receives measurements. Implementations must be safe for
+// concurrent use, as searches of a shared graph may run in parallel.
+type Metrics interface {
+	// Count adds delta to the counter name
+	Count(name string, delta int64)
+
+	// Observe records value in the histogram name
+	Observe(name string, value float64)
+}
+
+// Discard is a Metrics that drops all measurements, the default of the
+// instrumented types
+var Discard Metrics = discard{}
+
+type discard struct{}
+
+func (discard) Count(string, int64)     {}
+func (discard) Observe(string, float64) {}
+
+// Or returns m, or Discard if m is nil
+func Or(m Metrics) Metrics {
+	if m == nil {
+		return Discard
+	}
+	return m
+}
@@ -0,0 +1,22 @@
+package metrics
+
+import "testing"
+
+type counter map[string]int64
+
+func (c counter) Count(name string, delta int64)     { c[name] += delta }
+func (c counter) Observe(name string, value float64) {}
+
+func TestOr(t *testing.T) {
+	if Or(nil) != Discard {
+		t.Fatal("Or(nil) is not Discard")
+	}
+	Or(nil).Count("a", 1)
+	Or(nil).Observe("a", 1)
+
+	c := counter{}
+	Or(c).Count("a", 2)
+	if c["a"] != 2 {
+		t.Fatalf("got count %d, want 2", c["a"])
+	}
+}
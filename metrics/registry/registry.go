@@ -0,0 +1,145 @@
+// Package registry provides a metrics.Metrics that keeps measurements in
+// memory and serves them to expvar and Prometheus.
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hanyangtay/go-datastructures/metrics"
+)
+
+var _ metrics.Metrics = (*Registry)(nil)
+
+// numBuckets is the number of finite histogram buckets, with upper bounds
+// 1, 2, 4 and so on up to 2^(numBuckets-1)
+const numBuckets = 21
+
+type histogram struct {
+	buckets [numBuckets + 1]uint64 // the last one is unbounded
+	count   uint64
+	sum     float64
+}
+
+func (h *histogram) observe(v float64) {
+	i := 0
+	for i < numBuckets && v > math.Ldexp(1, i) {
+		i++
+	}
+	h.buckets[i]++
+	h.count++
+	h.sum += v
+}
+
+// Registry is a Metrics that keeps counters and histograms in memory. It is
+// an expvar.Var, so expvar.Publish("name", r) serves it as JSON on
+// /debug/vars, and an http.Handler serving the Prometheus text format.
+// Histograms have buckets with upper bounds of the powers of two from 1 to
+// 2^20. The zero value is ready to use.
+type Registry struct {
+	mu         sync.Mutex
+	counters   map[string]int64
+	histograms map[string]*histogram
+}
+
+// New returns an empty registry
+func New() *Registry {
+	return &Registry{}
+}
+
+// Count adds delta to the counter name
+func (r *Registry) Count(name string, delta int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counters == nil {
+		r.counters = make(map[string]int64)
+	}
+	r.counters[name] += delta
+}
+
+// Observe records value in the histogram name
+func (r *Registry) Observe(name string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.histograms == nil {
+		r.histograms = make(map[string]*histogram)
+	}
+	h := r.histograms[name]
+	if h == nil {
+		h = &histogram{}
+		r.histograms[name] = h
+	}
+	h.observe(value)
+}
+
+// Counter returns the value of the counter name
+func (r *Registry) Counter(name string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counters[name]
+}
+
+// String returns the counters, and the count and sum of every histogram, as
+// a JSON object, as expvar.Var requires
+func (r *Registry) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vars := make(map[string]interface{}, len(r.counters)+len(r.histograms))
+	for name, n := range r.counters {
+		vars[name] = n
+	}
+	for name, h := range r.histograms {
+		vars[name] = map[string]interface{}{"count": h.count, "sum": h.sum}
+	}
+	data, _ := json.Marshal(vars)
+	return string(data)
+}
+
+// WriteTo writes all metrics to w in the Prometheus text format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	var b strings.Builder
+	for _, name := range sortedKeys(r.counters) {
+		fmt.Fprintf(&b, "# TYPE %s counter\n%s %d\n", name, name, r.counters[name])
+	}
+	for _, name := range sortedKeys(r.histograms) {
+		h := r.histograms[name]
+		fmt.Fprintf(&b, "# TYPE %s histogram\n", name)
+		var cumulative uint64
+		for i, n := range h.buckets[:numBuckets] {
+			cumulative += n
+			le := strconv.FormatFloat(math.Ldexp(1, i), 'f', -1, 64)
+			fmt.Fprintf(&b, "%s_bucket{le=\"%s\"} %d\n", name, le, cumulative)
+		}
+		fmt.Fprintf(&b, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+		fmt.Fprintf(&b, "%s_sum %v\n%s_count %d\n", name, h.sum, name, h.count)
+	}
+	r.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP writes all metrics in the Prometheus text format, for use as
+// the handler of a /metrics endpoint
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.WriteTo(w)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
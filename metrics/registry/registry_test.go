@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCounters(t *testing.T) {
+	var r Registry
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.Count("a", 1)
+				r.Observe("h", float64(j))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := r.Counter("a"); got != 800 {
+		t.Fatalf("got counter %d, want 800", got)
+	}
+	if got := r.Counter("b"); got != 0 {
+		t.Fatalf("got counter %d for an unknown name, want 0", got)
+	}
+}
+
+func TestString(t *testing.T) {
+	r := New()
+	r.Count("a", 3)
+	r.Observe("h", 2)
+	r.Observe("h", 5)
+
+	var vars struct {
+		A int64 `json:"a"`
+		H struct {
+			Count uint64  `json:"count"`
+			Sum   float64 `json:"sum"`
+		} `json:"h"`
+	}
+	if err := json.Unmarshal([]byte(r.String()), &vars); err != nil {
+		t.Fatal(err)
+	}
+	if vars.A != 3 || vars.H.Count != 2 || vars.H.Sum != 7 {
+		t.Fatalf("got %s", r.String())
+	}
+}
+
+func TestPrometheus(t *testing.T) {
+	r := New()
+	r.Count("a_total", 3)
+	for _, v := range []float64{0.5, 1, 3, 1e9} {
+		r.Observe("h", v)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, line := range []string{
+		"# TYPE a_total counter",
+		"a_total 3",
+		"# TYPE h histogram",
+		`h_bucket{le="1"} 2`,
+		`h_bucket{le="2"} 2`,
+		`h_bucket{le="4"} 3`,
+		`h_bucket{le="1048576"} 3`,
+		`h_bucket{le="+Inf"} 4`,
+		"h_count 4",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("output lacks %q:\n%s", line, body)
+		}
+	}
+}
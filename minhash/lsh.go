@@ -0,0 +1,145 @@
+package minhash
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// Index finds sets similar to a query among many, without comparing it to
+// all of them. Signatures are cut into b bands of r positions each, and sets
+// agreeing on all positions of at least one band become candidates. Two sets
+// of similarity s are candidates with probability 1 - (1 - s^r)^b, an S curve
+// rising steeply around the threshold (1/b)^(1/r); see Bands.
+type Index[ID comparable] struct {
+	bands, rows int
+	buckets     []map[uint64][]ID // by band, then by the hash of the band
+	signatures  map[ID]Signature
+}
+
+// NewIndex returns an empty index of signatures of length bands*rows
+func NewIndex[ID comparable](bands, rows int) *Index[ID] {
+	if bands < 1 || rows < 1 {
+		panic("minhash: index needs at least one band and row")
+	}
+	x := &Index[ID]{
+		bands:      bands,
+		rows:       rows,
+		buckets:    make([]map[uint64][]ID, bands),
+		signatures: make(map[ID]Signature),
+	}
+	for i := range x.buckets {
+		x.buckets[i] = make(map[uint64][]ID)
+	}
+	return x
+}
+
+// Bands returns the number of bands b and rows r, with b*r = k, for which the
+// threshold similarity (1/b)^(1/r) of an index is closest to threshold
+func Bands(k int, threshold float64) (b, r int) {
+	b, r = k, 1
+	best := math.Inf(1)
+	for rows := 1; rows <= k; rows++ {
+		if k%rows != 0 {
+			continue
+		}
+		bands := k / rows
+		t := math.Pow(1/float64(bands), 1/float64(rows))
+		if d := math.Abs(t - threshold); d < best {
+			best, b, r = d, bands, rows
+		}
+	}
+	return b, r
+}
+
+// Len returns the number of sets indexed
+func (x *Index[ID]) Len() int { return len(x.signatures) }
+
+// bandHash returns the hash of band i of sig
+func (x *Index[ID]) bandHash(sig Signature, i int) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, v := range sig[i*x.rows : (i+1)*x.rows] {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+func (x *Index[ID]) check(sig Signature) {
+	if len(sig) != x.bands*x.rows {
+		panic("minhash: signature length does not match the index")
+	}
+}
+
+// Add indexes the set id by its signature, replacing any previous signature
+// of id
+func (x *Index[ID]) Add(id ID, sig Signature) {
+	x.check(sig)
+	x.Remove(id)
+
+	x.signatures[id] = sig
+	for i, bucket := range x.buckets {
+		h := x.bandHash(sig, i)
+		bucket[h] = append(bucket[h], id)
+	}
+}
+
+// Remove removes the set id and reports whether it was indexed
+func (x *Index[ID]) Remove(id ID) bool {
+	sig, ok := x.signatures[id]
+	if !ok {
+		return false
+	}
+
+	delete(x.signatures, id)
+	for i, bucket := range x.buckets {
+		h := x.bandHash(sig, i)
+		ids := bucket[h]
+		for j := range ids {
+			if ids[j] == id {
+				ids[j] = ids[len(ids)-1]
+				ids = ids[:len(ids)-1]
+				break
+			}
+		}
+		if len(ids) == 0 {
+			delete(bucket, h)
+		} else {
+			bucket[h] = ids
+		}
+	}
+	return true
+}
+
+// Query returns the indexed sets that share a band with sig, each once.
+// Candidates may be less similar than intended, so callers that need a
+// strict threshold should check them with Similarity or exactly.
+func (x *Index[ID]) Query(sig Signature) []ID {
+	x.check(sig)
+
+	seen := make(map[ID]bool)
+	var ids []ID
+	for i, bucket := range x.buckets {
+		for _, id := range bucket[x.bandHash(sig, i)] {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// Similar returns the indexed sets that share a band with sig and whose
+// estimated similarity to it is at least threshold
+func (x *Index[ID]) Similar(sig Signature, threshold float64) []ID {
+	candidates := x.Query(sig)
+	ids := candidates[:0]
+	for _, id := range candidates {
+		if Similarity(sig, x.signatures[id]) >= threshold {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,55 @@
+package minhash
+
+import (
+	"testing"
+)
+
+func TestBands(t *testing.T) {
+	b, r := Bands(128, 0.5)
+	if b*r != 128 {
+		t.Fatalf("Bands(128, 0.5) = %d, %d, which do not multiply to 128", b, r)
+	}
+	// the threshold of 16 bands of 8 rows is 0.707, of 32 bands of 4 0.420
+	// and of 64 bands of 2 0.125; 0.420 is the closest to 0.5
+	if b != 32 || r != 4 {
+		t.Fatalf("Bands(128, 0.5) = %d, %d, want 32, 4", b, r)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	h := New(128, 1)
+	x := NewIndex[string](Bands(128, 0.5))
+
+	x.Add("near", h.SignStrings(numbers(0, 1000)))
+	x.Add("far", h.SignStrings(numbers(5000, 6000)))
+	x.Add("half", h.SignStrings(numbers(300, 1300)))
+	if x.Len() != 3 {
+		t.Fatalf("got %d sets, want 3", x.Len())
+	}
+
+	query := h.SignStrings(numbers(50, 1000))
+	found := make(map[string]bool)
+	for _, id := range x.Query(query) {
+		if found[id] {
+			t.Fatalf("%q returned twice", id)
+		}
+		found[id] = true
+	}
+	if !found["near"] || found["far"] {
+		t.Fatalf("Query found %v, want near and not far", found)
+	}
+
+	similar := x.Similar(query, 0.8)
+	if len(similar) != 1 || similar[0] != "near" {
+		t.Fatalf("Similar found %v, want [near]", similar)
+	}
+
+	if !x.Remove("near") || x.Remove("near") {
+		t.Fatal("Remove did not remove the set exactly once")
+	}
+	for _, id := range x.Query(query) {
+		if id == "near" {
+			t.Fatal("removed set still found")
+		}
+	}
+}
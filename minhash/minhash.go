@@ -0,0 +1,110 @@
+// Package minhash provides MinHash signatures, which estimate the Jaccard
+// similarity of sets, and a locality sensitive hashing index for finding
+// similar sets by their signatures.
+package minhash
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+)
+
+// Signature is the MinHash signature of a set: the least value of each of k
+// hash functions over its items
+type Signature []uint64
+
+// Hasher computes signatures with a fixed family of hash functions, of which
+// two signatures must share to be comparable.
+type Hasher struct {
+	mul, add []uint64
+}
+
+// New returns a hasher for signatures of length k, whose hash functions are
+// drawn from seed. Hashers with the same k and seed produce the same
+// signatures.
+func New(k int, seed int64) *Hasher {
+	r := rand.New(rand.NewSource(seed))
+	h := &Hasher{mul: make([]uint64, k), add: make([]uint64, k)}
+	for i := range h.mul {
+		h.mul[i] = r.Uint64() | 1
+		h.add[i] = r.Uint64()
+	}
+	return h
+}
+
+// Len returns the signature length
+func (h *Hasher) Len() int { return len(h.mul) }
+
+// hash returns the 64 bit FNV-1a hash of an item
+func hash(item []byte) uint64 {
+	f := fnv.New64a()
+	f.Write(item)
+	return f.Sum64()
+}
+
+// mix is the finalizer of MurmurHash3, which turns a*x+b into a hash in
+// which every bit depends on every bit of x
+func mix(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// Sign returns the signature of the set of items. Duplicates do not matter.
+// Time complexity: O(k n)
+func (h *Hasher) Sign(items [][]byte) Signature {
+	hashes := make([]uint64, len(items))
+	for i, item := range items {
+		hashes[i] = hash(item)
+	}
+	return h.SignHashes(hashes)
+}
+
+// SignStrings returns the signature of the set of items
+func (h *Hasher) SignStrings(items []string) Signature {
+	hashes := make([]uint64, len(items))
+	for i, item := range items {
+		hashes[i] = hash([]byte(item))
+	}
+	return h.SignHashes(hashes)
+}
+
+// SignHashes returns the signature of a set given by the 64 bit hashes of its
+// items, for items hashed by the caller
+func (h *Hasher) SignHashes(hashes []uint64) Signature {
+	sig := make(Signature, len(h.mul))
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for _, x := range hashes {
+		for i := range sig {
+			if v := mix(h.mul[i]*x + h.add[i]); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// Similarity estimates the Jaccard similarity of the sets of two signatures
+// of the same hasher, |A ∩ B| / |A ∪ B|, as the fraction of positions in
+// which they agree. The standard error is about 1/sqrt(k).
+func Similarity(a, b Signature) float64 {
+	if len(a) != len(b) {
+		panic("minhash: signatures differ in length")
+	}
+	if len(a) == 0 {
+		return 0
+	}
+
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a))
+}
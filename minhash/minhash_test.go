@@ -0,0 +1,59 @@
+package minhash
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// numbers returns the strings of the integers from lo up to but excluding hi
+func numbers(lo, hi int) []string {
+	var items []string
+	for i := lo; i < hi; i++ {
+		items = append(items, fmt.Sprint(i))
+	}
+	return items
+}
+
+func TestSimilarity(t *testing.T) {
+	h := New(256, 1)
+	a := h.SignStrings(numbers(0, 1000))
+
+	for _, c := range []struct {
+		items []string
+		want  float64
+	}{
+		{numbers(0, 1000), 1},
+		{numbers(500, 1500), 1.0 / 3},
+		{numbers(250, 1000), 0.75},
+		{numbers(1000, 2000), 0},
+	} {
+		// four standard errors of 1/16
+		if got := Similarity(a, h.SignStrings(c.items)); math.Abs(got-c.want) > 0.25 {
+			t.Errorf("estimated similarity %v, want %v", got, c.want)
+		}
+	}
+
+	// duplicates do not change the signature
+	b := h.SignStrings(append(numbers(0, 1000), numbers(0, 10)...))
+	if Similarity(a, b) != 1 {
+		t.Fatal("duplicates changed the signature")
+	}
+
+	// hashers with the same seed agree
+	if Similarity(a, New(256, 1).SignStrings(numbers(0, 1000))) != 1 {
+		t.Fatal("hashers with the same seed disagree")
+	}
+	if h.Len() != 256 || len(a) != 256 {
+		t.Fatalf("got signatures of length %d, want 256", len(a))
+	}
+}
+
+func TestSignaturesOfDifferentLength(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("comparing signatures of different lengths did not panic")
+		}
+	}()
+	Similarity(New(4, 1).SignStrings([]string{"a"}), New(8, 1).SignStrings([]string{"a"}))
+}
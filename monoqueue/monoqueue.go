@@ -0,0 +1,94 @@
+// Package monoqueue provides a monotonic queue, which tracks the minimum or
+// maximum of a sliding window over a stream.
+package monoqueue
+
+import (
+	"github.com/hanyangtay/go-datastructures/deque"
+)
+
+// Queue is a first in, first out queue that reports its least item, by a
+// less function, in O(1). Besides the items, it keeps the positions of those
+// that can still become the least one, those not greater than any item
+// pushed after them; they form a deque in increasing order, from which
+// pushes drop the items they beat at the back, and pops the expired item at
+// the front. Every push and pop takes O(1) amortized, so a window sliding
+// over n items costs O(n) in all. For the greatest item, order by the
+// reverse of less.
+type Queue[T any] struct {
+	items  deque.Deque[T]
+	minima deque.Deque[uint64] // sequence numbers of the candidates
+	popped uint64              // sequence number of the front item
+	less   func(a, b T) bool
+}
+
+// New returns an empty queue ordered by less
+func New[T any](less func(a, b T) bool) *Queue[T] {
+	return &Queue[T]{less: less}
+}
+
+// Len returns the number of items
+func (q *Queue[T]) Len() int { return q.items.Len() }
+
+// at returns the item with sequence number seq
+func (q *Queue[T]) at(seq uint64) T {
+	return q.items.At(int(seq - q.popped))
+}
+
+// Push adds x at the back. Time complexity: O(1) amortized
+func (q *Queue[T]) Push(x T) {
+	for q.minima.Len() > 0 && q.less(x, q.at(q.minima.Back())) {
+		q.minima.PopBack()
+	}
+	q.minima.PushBack(q.popped + uint64(q.items.Len()))
+	q.items.PushBack(x)
+}
+
+// Pop removes and returns the front item, the oldest. It panics if the
+// queue is empty. Time complexity: O(1)
+func (q *Queue[T]) Pop() T {
+	if q.items.Len() == 0 {
+		panic("monoqueue: Pop of an empty queue")
+	}
+
+	if q.minima.Front() == q.popped {
+		q.minima.PopFront()
+	}
+	q.popped++
+	return q.items.PopFront()
+}
+
+// Front returns the front item, the oldest. It panics if the queue is empty.
+func (q *Queue[T]) Front() T {
+	if q.items.Len() == 0 {
+		panic("monoqueue: Front of an empty queue")
+	}
+	return q.items.Front()
+}
+
+// PopWhile removes the items at the front for as long as pred holds for
+// them, and returns the number removed. With items carrying timestamps in
+// push order, it expires those that fell out of a time window.
+func (q *Queue[T]) PopWhile(pred func(x T) bool) int {
+	n := 0
+	for q.items.Len() > 0 && pred(q.items.Front()) {
+		q.Pop()
+		n++
+	}
+	return n
+}
+
+// Min returns the least item, the oldest of them if several are equal. It
+// panics if the queue is empty. Time complexity: O(1)
+func (q *Queue[T]) Min() T {
+	if q.items.Len() == 0 {
+		panic("monoqueue: Min of an empty queue")
+	}
+	return q.at(q.minima.Front())
+}
+
+// Reset removes all items, keeping the allocated capacity
+func (q *Queue[T]) Reset() {
+	q.items.Reset()
+	q.minima.Reset()
+	q.popped = 0
+}
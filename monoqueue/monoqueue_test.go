@@ -0,0 +1,73 @@
+package monoqueue
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func less(a, b int) bool { return a < b }
+
+// TestSlidingWindow checks the minimum of windows of random width against
+// a scan of the window
+func TestSlidingWindow(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	q := New(less)
+	var window []int
+
+	for i := 0; i < 5000; i++ {
+		if len(window) > 0 && rng.Intn(3) == 0 {
+			if x := q.Pop(); x != window[0] {
+				t.Fatalf("popped %d, want %d", x, window[0])
+			}
+			window = window[1:]
+		} else {
+			x := rng.Intn(100)
+			q.Push(x)
+			window = append(window, x)
+		}
+
+		if q.Len() != len(window) {
+			t.Fatalf("got length %d, want %d", q.Len(), len(window))
+		}
+		if len(window) == 0 {
+			continue
+		}
+		want := window[0]
+		for _, x := range window {
+			want = min(want, x)
+		}
+		if q.Min() != want || q.Front() != window[0] {
+			t.Fatalf("got minimum %d and front %d, want %d and %d", q.Min(), q.Front(), want, window[0])
+		}
+	}
+}
+
+func TestPopWhile(t *testing.T) {
+	type sample struct{ time, value int }
+	q := New(func(a, b sample) bool { return a.value < b.value })
+	for i, v := range []int{5, 1, 4, 2, 3} {
+		q.Push(sample{i, v})
+	}
+
+	if n := q.PopWhile(func(s sample) bool { return s.time < 2 }); n != 2 {
+		t.Fatalf("expired %d samples, want 2", n)
+	}
+	if m := q.Min(); m.value != 2 {
+		t.Fatalf("got minimum %d after expiry, want 2", m.value)
+	}
+
+	q.Reset()
+	q.Push(sample{0, 9})
+	if q.Len() != 1 || q.Min().value != 9 {
+		t.Fatalf("got length %d after Reset and one push", q.Len())
+	}
+}
+
+func TestEmptyPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Min of an empty queue did not panic")
+		}
+	}()
+	New(less).Min()
+}
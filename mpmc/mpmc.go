@@ -0,0 +1,197 @@
+// Package mpmc provides generic queues safe for any number of concurrent
+// producers and consumers without locks: a bounded queue over a ring of
+// slots, and an unbounded linked queue.
+package mpmc
+
+import (
+	"sync/atomic"
+)
+
+// cacheLine is the padding between fields written by different goroutines,
+// so that producers and consumers do not contend for the same cache line
+const cacheLine = 64
+
+type slot[T any] struct {
+	// seq is the position the slot is next written at, or that position
+	// plus one once it has been written and can be read
+	seq   atomic.Uint64
+	value T
+}
+
+// Bounded is a first-in first-out queue of at most a fixed number of items,
+// using Vyukov's bounded MPMC algorithm: producers and consumers claim
+// positions with a compare-and-swap on the tail or head counter, and every
+// slot carries a sequence number telling whether it is free to write or
+// ready to read. Push and Pop never allocate, and fail instead of waiting
+// when the queue is full or empty.
+//
+// The queue is not strictly lock-free: a producer preempted between claiming
+// a slot and filling it holds up consumers of that slot, but no others.
+type Bounded[T any] struct {
+	_     [cacheLine]byte
+	head  atomic.Uint64 // next position to read
+	_     [cacheLine - 8]byte
+	tail  atomic.Uint64 // next position to write
+	_     [cacheLine - 8]byte
+	slots []slot[T]
+	mask  uint64
+}
+
+// NewBounded returns an empty queue for capacity items, rounded up to a
+// power of two. It panics if capacity is not positive.
+func NewBounded[T any](capacity int) *Bounded[T] {
+	if capacity < 1 {
+		panic("mpmc: capacity must be positive")
+	}
+
+	n := 1
+	for n < capacity {
+		n <<= 1
+	}
+
+	q := &Bounded[T]{slots: make([]slot[T], n), mask: uint64(n - 1)}
+	for i := range q.slots {
+		q.slots[i].seq.Store(uint64(i))
+	}
+	return q
+}
+
+// Cap returns the greatest number of items
+func (q *Bounded[T]) Cap() int { return len(q.slots) }
+
+// Len returns the number of items, which may be outdated by the time it
+// returns if other goroutines use the queue
+func (q *Bounded[T]) Len() int {
+	for {
+		tail := q.tail.Load()
+		head := q.head.Load()
+		if q.tail.Load() == tail {
+			return int(min(tail-head, uint64(len(q.slots))))
+		}
+	}
+}
+
+// Push appends v and reports true, or reports false if the queue is full
+func (q *Bounded[T]) Push(v T) bool {
+	pos := q.tail.Load()
+	for {
+		s := &q.slots[pos&q.mask]
+		switch seq := s.seq.Load(); {
+		case seq == pos:
+			if q.tail.CompareAndSwap(pos, pos+1) {
+				s.value = v
+				s.seq.Store(pos + 1)
+				return true
+			}
+		case seq < pos:
+			// the slot still holds the item from one lap ago
+			return false
+		}
+		pos = q.tail.Load()
+	}
+}
+
+// Pop removes and returns the oldest item, or false if the queue is empty
+func (q *Bounded[T]) Pop() (T, bool) {
+	pos := q.head.Load()
+	for {
+		s := &q.slots[pos&q.mask]
+		switch seq := s.seq.Load(); {
+		case seq == pos+1:
+			if q.head.CompareAndSwap(pos, pos+1) {
+				v := s.value
+				var zero T
+				s.value = zero
+				s.seq.Store(pos + q.mask + 1)
+				return v, true
+			}
+		case seq < pos+1:
+			// the slot has not been written in this lap
+			var zero T
+			return zero, false
+		}
+		pos = q.head.Load()
+	}
+}
+
+type node[T any] struct {
+	value T
+	next  atomic.Pointer[node[T]]
+}
+
+// Unbounded is a first-in first-out queue without a capacity limit, using
+// the lock-free linked queue of Michael and Scott. The head always points to
+// a sentinel node whose successor holds the oldest item; goroutines finding
+// the tail lagging behind help advance it. Push allocates a node per item.
+type Unbounded[T any] struct {
+	_      [cacheLine]byte
+	head   atomic.Pointer[node[T]]
+	_      [cacheLine - 8]byte
+	tail   atomic.Pointer[node[T]]
+	_      [cacheLine - 8]byte
+	length atomic.Int64
+}
+
+// NewUnbounded returns an empty queue
+func NewUnbounded[T any]() *Unbounded[T] {
+	q := &Unbounded[T]{}
+	sentinel := &node[T]{}
+	q.head.Store(sentinel)
+	q.tail.Store(sentinel)
+	return q
+}
+
+// Len returns the number of items, which may be outdated by the time it
+// returns if other goroutines use the queue
+func (q *Unbounded[T]) Len() int { return int(max(q.length.Load(), 0)) }
+
+// Push appends v
+func (q *Unbounded[T]) Push(v T) {
+	n := &node[T]{value: v}
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+		if tail != q.tail.Load() {
+			continue
+		}
+
+		if next != nil {
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, n) {
+			q.tail.CompareAndSwap(tail, n)
+			q.length.Add(1)
+			return
+		}
+	}
+}
+
+// Pop removes and returns the oldest item, or false if the queue is empty
+func (q *Unbounded[T]) Pop() (T, bool) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+		if head != q.head.Load() {
+			continue
+		}
+
+		if next == nil {
+			var zero T
+			return zero, false
+		}
+		if head == tail {
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+
+		// next becomes the sentinel; its value is left in place since
+		// goroutines that lost the race may still read it
+		v := next.value
+		if q.head.CompareAndSwap(head, next) {
+			q.length.Add(-1)
+			return v, true
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package mpmc
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// queue is either kind of queue
+type queue interface {
+	Push(v int) bool
+	Pop() (int, bool)
+}
+
+// unbounded adapts Unbounded to queue
+type unbounded struct{ *Unbounded[int] }
+
+func (q unbounded) Push(v int) bool { q.Unbounded.Push(v); return true }
+
+// checkConcurrent runs producers and consumers on q and checks that every
+// item is popped exactly once, and in order per producer
+func checkConcurrent(t *testing.T, q queue) {
+	const producers, consumers, items = 4, 4, 1000
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < items; i++ {
+				for !q.Push(p*items + i) {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+
+	popped := make([][]int, consumers)
+	var mu sync.Mutex
+	total := 0
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			last := make([]int, producers)
+			for i := range last {
+				last[i] = -1
+			}
+			for {
+				mu.Lock()
+				done := total == producers*items
+				mu.Unlock()
+				if done {
+					return
+				}
+				v, ok := q.Pop()
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+				if p := v / items; v%items <= last[p] {
+					t.Errorf("consumer %d popped %d after %d", c, v, p*items+last[p])
+				} else {
+					last[p] = v % items
+				}
+				popped[c] = append(popped[c], v)
+				mu.Lock()
+				total++
+				mu.Unlock()
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	seen := make([]bool, producers*items)
+	for _, vs := range popped {
+		for _, v := range vs {
+			if seen[v] {
+				t.Fatalf("popped %d twice", v)
+			}
+			seen[v] = true
+		}
+	}
+}
+
+func TestBounded(t *testing.T) {
+	q := NewBounded[int](5)
+	if q.Cap() != 8 {
+		t.Fatalf("got capacity %d, want 5 rounded up to 8", q.Cap())
+	}
+	for i := 0; i < 8; i++ {
+		if !q.Push(i) {
+			t.Fatalf("push %d refused", i)
+		}
+	}
+	if q.Push(8) || q.Len() != 8 {
+		t.Fatalf("push to a full queue accepted, length %d", q.Len())
+	}
+	for i := 0; i < 8; i++ {
+		if v, ok := q.Pop(); !ok || v != i {
+			t.Fatalf("Pop() = %d, %v, want %d", v, ok, i)
+		}
+	}
+	if _, ok := q.Pop(); ok {
+		t.Fatal("popped from an empty queue")
+	}
+
+	checkConcurrent(t, NewBounded[int](64))
+}
+
+func TestUnbounded(t *testing.T) {
+	q := NewUnbounded[int]()
+	for i := 0; i < 100; i++ {
+		q.Push(i)
+	}
+	if q.Len() != 100 {
+		t.Fatalf("got length %d, want 100", q.Len())
+	}
+	for i := 0; i < 100; i++ {
+		if v, ok := q.Pop(); !ok || v != i {
+			t.Fatalf("Pop() = %d, %v, want %d", v, ok, i)
+		}
+	}
+	if _, ok := q.Pop(); ok {
+		t.Fatal("popped from an empty queue")
+	}
+
+	checkConcurrent(t, unbounded{NewUnbounded[int]()})
+}
@@ -0,0 +1,64 @@
+package ordered
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hanyangtay/go-datastructures/internal/wire"
+)
+
+// ErrInvalidData is returned when decoding data that is not an encoded map
+var ErrInvalidData = errors.New("ordered: invalid encoding")
+
+// encodingVersion is the version of the layout written by Marshal
+const encodingVersion = 1
+
+// entries is the payload of an encoded map
+type entries[K, V any] struct {
+	Keys   []K
+	Values []V
+}
+
+// Marshal encodes the entries of m in key order, keys and values with
+// encoding/gob. It implements the MarshalBinary methods of all maps of this
+// module, so data encoded from one kind of map decodes into any other.
+func Marshal[K, V any](m Map[K, V]) ([]byte, error) {
+	e := entries[K, V]{Keys: make([]K, 0, m.Len()), Values: make([]V, 0, m.Len())}
+	m.Ascend(func(key K, value V) bool {
+		e.Keys = append(e.Keys, key)
+		e.Values = append(e.Values, value)
+		return true
+	})
+	return wire.Marshal("ordered.Map", encodingVersion, &e)
+}
+
+// Unmarshal decodes data encoded by Marshal into its keys and values, in
+// the key order of the map encoded. ErrInvalidData is returned if data is
+// malformed.
+func Unmarshal[K, V any](data []byte) (keys []K, values []V, err error) {
+	var e entries[K, V]
+	if err := wire.Unmarshal(data, "ordered.Map", encodingVersion, &e); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	if len(e.Keys) != len(e.Values) {
+		return nil, nil, ErrInvalidData
+	}
+	return e.Keys, e.Values, nil
+}
+
+// UnmarshalInto replaces the entries of m with those of data, as encoded by
+// Marshal from any map of this module. reset, which must empty m, is only
+// called once data has decoded, so m is left as it was if ErrInvalidData is
+// returned. It implements the UnmarshalBinary methods of the maps.
+func UnmarshalInto[K, V any](m Map[K, V], data []byte, reset func()) error {
+	keys, values, err := Unmarshal[K, V](data)
+	if err != nil {
+		return err
+	}
+
+	reset()
+	for i := range keys {
+		m.Put(keys[i], values[i])
+	}
+	return nil
+}
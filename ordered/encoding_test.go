@@ -0,0 +1,82 @@
+package ordered_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hanyangtay/go-datastructures/avl"
+	"github.com/hanyangtay/go-datastructures/bplustree"
+	"github.com/hanyangtay/go-datastructures/btree"
+	"github.com/hanyangtay/go-datastructures/ordered"
+	"github.com/hanyangtay/go-datastructures/rbtree"
+	"github.com/hanyangtay/go-datastructures/skiplist"
+	"github.com/hanyangtay/go-datastructures/splay"
+	"github.com/hanyangtay/go-datastructures/treap"
+)
+
+// orderedMap is a map of this module with its binary encoding
+type orderedMap interface {
+	ordered.Map[string, int]
+	MarshalBinary() ([]byte, error)
+	UnmarshalBinary(data []byte) error
+}
+
+func newMaps() map[string]orderedMap {
+	less := ordered.Less[string]
+	return map[string]orderedMap{
+		"avl":       avl.New[string, int](less),
+		"bplustree": bplustree.New[string, int](3, less),
+		"btree":     btree.New[string, int](3, less),
+		"rbtree":    rbtree.New[string, int](less),
+		"skiplist":  skiplist.New[string, int](less),
+		"splay":     splay.New[string, int](less),
+		"treap":     treap.New[string, int](less),
+	}
+}
+
+// TestEncodingAcrossMaps encodes every kind of map and decodes the result
+// into every other kind
+func TestEncodingAcrossMaps(t *testing.T) {
+	words := []string{"pear", "apple", "fig", "kiwi", "banana", "cherry", "date"}
+
+	for fromName, from := range newMaps() {
+		for i, w := range words {
+			from.Put(w, i)
+		}
+		data, err := from.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: %v", fromName, err)
+		}
+
+		for toName, to := range newMaps() {
+			to.Put("stale", -1)
+			if err := to.UnmarshalBinary(data); err != nil {
+				t.Fatalf("%s to %s: %v", fromName, toName, err)
+			}
+			if to.Len() != len(words) {
+				t.Fatalf("%s to %s: got %d entries, want %d", fromName, toName, to.Len(), len(words))
+			}
+			for i, w := range words {
+				if v, ok := to.Get(w); !ok || v != i {
+					t.Fatalf("%s to %s: got %d, %v for %q, want %d", fromName, toName, v, ok, w, i)
+				}
+			}
+		}
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	m := avl.New[string, int](ordered.Less[string])
+	m.Put("kept", 1)
+
+	if err := m.UnmarshalBinary([]byte("garbage")); !errors.Is(err, ordered.ErrInvalidData) {
+		t.Fatalf("got %v, want ErrInvalidData", err)
+	}
+	if v, ok := m.Get("kept"); !ok || v != 1 || m.Len() != 1 {
+		t.Fatal("failed decoding changed the map")
+	}
+
+	if _, _, err := ordered.Unmarshal[string, int](nil); !errors.Is(err, ordered.ErrInvalidData) {
+		t.Fatalf("got %v for no data, want ErrInvalidData", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Package ordered defines the interface shared by the ordered maps of this
+// module, so that code can switch between implementations per workload
+// without changing call sites. The maps share their binary encoding too, so
+// data encoded from one decodes into any other.
+package ordered
+
+import (
+	"cmp"
+)
+
+// Map is an ordered map from keys to values, with keys ordered by the less
+// function the map was created with. Keys a and b are equal if neither is
+// less than the other.
+type Map[K, V any] interface {
+	// Len returns the number of entries
+	Len() int
+
+	// Get returns the value stored under key, and whether it was found
+	Get(key K) (V, bool)
+
+	// Put stores value under key, replacing any previous value
+	Put(key K, value V)
+
+	// Delete removes key and reports whether it was present
+	Delete(key K) bool
+
+	// Min and Max return the entries with the least and greatest key, or
+	// false if the map is empty
+	Min() (K, V, bool)
+	Max() (K, V, bool)
+
+	// Floor returns the entry with the greatest key not greater than key,
+	// Ceiling the one with the least key not less than key, or false if
+	// there is none
+	Floor(key K) (K, V, bool)
+	Ceiling(key K) (K, V, bool)
+
+	// Ascend calls fn for every entry in key order until fn returns false
+	Ascend(fn func(key K, value V) bool)
+
+	// Range calls fn in key order for every entry with a key from lo up to
+	// but excluding hi, until fn returns false
+	Range(lo, hi K, fn func(key K, value V) bool)
+}
+
+// Less is the natural ordering of K, for use as the less function of a map:
+//
+//	m := avl.New[string, int](ordered.Less[string])
+func Less[K cmp.Ordered](a, b K) bool {
+	return cmp.Less(a, b)
+}
@@ -0,0 +1,14 @@
+package ordered
+
+import (
+	"testing"
+)
+
+func TestLess(t *testing.T) {
+	if !Less(1, 2) || Less(2, 1) || Less(2, 2) {
+		t.Fatal("Less does not order ints")
+	}
+	if !Less("ab", "b") || Less("b", "ab") {
+		t.Fatal("Less does not order strings byte-wise")
+	}
+}
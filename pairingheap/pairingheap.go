@@ -0,0 +1,180 @@
+// Package pairingheap provides a generic pairing heap, a self-adjusting heap
+// with cheap insertion, melding and decrease-key.
+package pairingheap
+
+// Handle refers to an entry of a PairingHeap, and stays valid after the entry
+// has been removed
+type Handle[T, P any] struct {
+	value    T
+	priority P
+
+	// child is the first child, next the next sibling, and prev the
+	// previous sibling or, for a first child, the parent
+	child, next, prev *Handle[T, P]
+	queued            bool
+}
+
+// Value returns the value of the entry
+func (h *Handle[T, P]) Value() T { return h.value }
+
+// Priority returns the current priority of the entry
+func (h *Handle[T, P]) Priority() P { return h.priority }
+
+// PairingHeap is a min heap of values with priorities ordered by a less
+// function, stored as a tree whose root holds the least priority. Push, Meld
+// and DecreaseKey only link trees and run in O(1); Pop and Remove restructure
+// the tree in O(log n) amortized time.
+type PairingHeap[T, P any] struct {
+	root *Handle[T, P]
+	size int
+	less func(a, b P) bool
+}
+
+// New returns an empty heap ordered by less
+func New[T, P any](less func(a, b P) bool) *PairingHeap[T, P] {
+	return &PairingHeap[T, P]{less: less}
+}
+
+// Len returns the number of entries in the heap
+func (q *PairingHeap[T, P]) Len() int { return q.size }
+
+// Push adds value with the given priority and returns its handle.
+// Time complexity: O(1)
+func (q *PairingHeap[T, P]) Push(value T, priority P) *Handle[T, P] {
+	h := &Handle[T, P]{value: value, priority: priority, queued: true}
+	q.root = q.link(q.root, h)
+	q.size++
+	return h
+}
+
+// Peek returns the handle of the entry with the least priority without
+// removing it. It panics if the heap is empty.
+func (q *PairingHeap[T, P]) Peek() *Handle[T, P] {
+	if q.root == nil {
+		panic("pairingheap: Peek on empty heap")
+	}
+	return q.root
+}
+
+// Pop removes the entry with the least priority and returns its handle. It
+// panics if the heap is empty. Time complexity: O(log n) amortized
+func (q *PairingHeap[T, P]) Pop() *Handle[T, P] {
+	if q.root == nil {
+		panic("pairingheap: Pop on empty heap")
+	}
+
+	h := q.root
+	q.root = q.mergePairs(h.child)
+	q.size--
+	h.child, h.queued = nil, false
+	return h
+}
+
+// DecreaseKey lowers the priority of the entry of h. It panics if the entry
+// has been removed or the new priority is greater than the old one.
+// Time complexity: O(1) amortized
+func (q *PairingHeap[T, P]) DecreaseKey(h *Handle[T, P], priority P) {
+	if !h.queued {
+		panic("pairingheap: handle is not queued")
+	}
+	if q.less(h.priority, priority) {
+		panic("pairingheap: DecreaseKey to a greater priority")
+	}
+
+	h.priority = priority
+	if h == q.root {
+		return
+	}
+
+	q.cut(h)
+	q.root = q.link(q.root, h)
+}
+
+// Remove takes the entry of h out of the heap. It panics if the entry has
+// already been removed. Time complexity: O(log n) amortized
+func (q *PairingHeap[T, P]) Remove(h *Handle[T, P]) {
+	if !h.queued {
+		panic("pairingheap: handle is not queued")
+	}
+
+	if h == q.root {
+		q.Pop()
+		return
+	}
+
+	q.cut(h)
+	q.root = q.link(q.root, q.mergePairs(h.child))
+	q.size--
+	h.child, h.queued = nil, false
+}
+
+// Meld moves all entries of other into q, leaving other empty. Both heaps
+// must be ordered by the same less function. Handles of entries of other
+// remain valid for q. Time complexity: O(1)
+func (q *PairingHeap[T, P]) Meld(other *PairingHeap[T, P]) {
+	q.root = q.link(q.root, other.root)
+	q.size += other.size
+	other.root, other.size = nil, 0
+}
+
+// link makes the root with the greater priority the first child of the
+// other and returns the new root. Either may be nil.
+func (q *PairingHeap[T, P]) link(a, b *Handle[T, P]) *Handle[T, P] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if q.less(b.priority, a.priority) {
+		a, b = b, a
+	}
+
+	b.prev = a
+	b.next = a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	a.child = b
+	return a
+}
+
+// cut detaches the subtree rooted at h from its parent and siblings
+func (q *PairingHeap[T, P]) cut(h *Handle[T, P]) {
+	if h.prev.child == h {
+		h.prev.child = h.next
+	} else {
+		h.prev.next = h.next
+	}
+	if h.next != nil {
+		h.next.prev = h.prev
+	}
+	h.next, h.prev = nil, nil
+}
+
+// mergePairs combines a list of sibling trees into one: siblings are linked
+// in pairs from left to right, and the pairs then linked from right to left
+func (q *PairingHeap[T, P]) mergePairs(first *Handle[T, P]) *Handle[T, P] {
+	var pairs []*Handle[T, P]
+	for a := first; a != nil; {
+		b := a.next
+		var rest *Handle[T, P]
+		if b != nil {
+			rest = b.next
+			b.next, b.prev = nil, nil
+		}
+		a.next, a.prev = nil, nil
+
+		pairs = append(pairs, q.link(a, b))
+		a = rest
+	}
+
+	var root *Handle[T, P]
+	for i := len(pairs) - 1; i >= 0; i-- {
+		root = q.link(pairs[i], root)
+	}
+	if root != nil {
+		root.prev = nil
+	}
+	return root
+}
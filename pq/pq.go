@@ -0,0 +1,108 @@
+// Package pq provides a generic priority queue backed by a binary heap.
+package pq
+
+// PriorityQueue is a binary min heap of items ordered by a less function:
+// Pop returns an item that no other item is less than. Use New or From to
+// create one.
+type PriorityQueue[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+// New returns an empty queue ordered by less
+func New[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less}
+}
+
+// From returns a queue holding items, ordered by less, in O(n). The queue
+// takes ownership of the slice.
+func From[T any](items []T, less func(a, b T) bool) *PriorityQueue[T] {
+	q := &PriorityQueue[T]{items: items, less: less}
+	for i := len(items)/2 - 1; i >= 0; i-- {
+		q.down(i)
+	}
+	return q
+}
+
+// Len returns the number of items in the queue
+func (q *PriorityQueue[T]) Len() int { return len(q.items) }
+
+// Push adds x to the queue. Time complexity: O(log n)
+func (q *PriorityQueue[T]) Push(x T) {
+	q.items = append(q.items, x)
+	q.up(len(q.items) - 1)
+}
+
+// Pop removes and returns the least item. It panics if the queue is empty.
+// Time complexity: O(log n)
+func (q *PriorityQueue[T]) Pop() T {
+	top := q.items[0]
+	last := len(q.items) - 1
+
+	q.items[0] = q.items[last]
+
+	// clear the vacated slot so that it does not retain the item
+	var zero T
+	q.items[last] = zero
+	q.items = q.items[:last]
+
+	if last > 0 {
+		q.down(0)
+	}
+
+	return top
+}
+
+// Peek returns the least item without removing it. It panics if the queue is
+// empty.
+func (q *PriorityQueue[T]) Peek() T {
+	return q.items[0]
+}
+
+// Each calls fn for every item in the queue, in no particular order, until
+// fn returns false. The queue must not be changed during the calls.
+func (q *PriorityQueue[T]) Each(fn func(x T) bool) {
+	for _, x := range q.items {
+		if !fn(x) {
+			return
+		}
+	}
+}
+
+// Reset removes all items, keeping the allocated storage for reuse
+func (q *PriorityQueue[T]) Reset() {
+	var zero T
+	for i := range q.items {
+		q.items[i] = zero
+	}
+	q.items = q.items[:0]
+}
+
+func (q *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.items[i], q.items[parent]) {
+			return
+		}
+		q.items[i], q.items[parent] = q.items[parent], q.items[i]
+		i = parent
+	}
+}
+
+func (q *PriorityQueue[T]) down(i int) {
+	n := len(q.items)
+	for {
+		least := i
+		if l := 2*i + 1; l < n && q.less(q.items[l], q.items[least]) {
+			least = l
+		}
+		if r := 2*i + 2; r < n && q.less(q.items[r], q.items[least]) {
+			least = r
+		}
+		if least == i {
+			return
+		}
+		q.items[i], q.items[least] = q.items[least], q.items[i]
+		i = least
+	}
+}
@@ -0,0 +1,89 @@
+package pq
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func less(a, b int) bool { return a < b }
+
+func TestPushPop(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	q := New(less)
+
+	var want []int
+	for i := 0; i < 1000; i++ {
+		x := rng.Intn(100)
+		q.Push(x)
+		want = append(want, x)
+	}
+	sort.Ints(want)
+
+	if q.Len() != len(want) {
+		t.Fatalf("got length %d, want %d", q.Len(), len(want))
+	}
+	for i, w := range want {
+		if p := q.Peek(); p != w {
+			t.Fatalf("item %d: peeked %d, want %d", i, p, w)
+		}
+		if x := q.Pop(); x != w {
+			t.Fatalf("item %d: popped %d, want %d", i, x, w)
+		}
+	}
+	if q.Len() != 0 {
+		t.Fatalf("got length %d after popping all items", q.Len())
+	}
+}
+
+func TestFrom(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 100} {
+		items := rand.New(rand.NewSource(int64(n))).Perm(n)
+		q := From(items, less)
+
+		for i := 0; i < n; i++ {
+			if x := q.Pop(); x != i {
+				t.Fatalf("n=%d: popped %d, want %d", n, x, i)
+			}
+		}
+		if q.Len() != 0 {
+			t.Fatalf("n=%d: got length %d after popping all items", n, q.Len())
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	q := From([]int{3, 1, 2}, less)
+	q.Reset()
+	if q.Len() != 0 {
+		t.Fatalf("got length %d after Reset", q.Len())
+	}
+
+	q.Push(5)
+	q.Push(4)
+	if x := q.Pop(); x != 4 {
+		t.Fatalf("popped %d after Reset, want 4", x)
+	}
+}
+
+func TestEach(t *testing.T) {
+	q := From([]int{4, 2, 3, 1}, less)
+
+	sum := 0
+	q.Each(func(x int) bool {
+		sum += x
+		return true
+	})
+	if sum != 10 {
+		t.Fatalf("got sum %d, want 10", sum)
+	}
+
+	calls := 0
+	q.Each(func(x int) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Fatalf("Each went on for %d calls after fn returned false", calls)
+	}
+}
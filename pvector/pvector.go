@@ -0,0 +1,248 @@
+// Package pvector provides a generic persistent vector, an immutable
+// sequence whose updates return new versions sharing most of their memory
+// with the old ones.
+package pvector
+
+const (
+	bits  = 5
+	width = 1 << bits
+	mask  = width - 1
+)
+
+// node is an inner node with up to width children, or a leaf with width
+// values. Nodes are never modified once they are part of a vector.
+type node[T any] struct {
+	children []*node[T]
+	values   []T
+}
+
+// Vector is an immutable sequence stored in a trie of branching factor 32,
+// indexed by the bits of positions, five at a time, as in the vectors of
+// Clojure and Scala. The last up to 32 values are kept in a separate tail,
+// so that most appends copy just the tail. Get, Set, Append and Pop take
+// O(log32 n), which is at most 7 steps for 2^32 values, and Set, Append and
+// Pop copy only the nodes on one path, sharing all others with the original.
+//
+// The zero value and nil are empty vectors. Vectors are safe for concurrent
+// use since they never change.
+type Vector[T any] struct {
+	length int
+	shift  uint // bits of the index above the leaves
+	root   *node[T]
+	tail   []T
+}
+
+// New returns an empty vector
+func New[T any]() *Vector[T] {
+	return &Vector[T]{}
+}
+
+// From returns a vector of items
+func From[T any](items ...T) *Vector[T] {
+	v := New[T]()
+	for _, x := range items {
+		v = v.Append(x)
+	}
+	return v
+}
+
+// Len returns the number of values
+func (v *Vector[T]) Len() int {
+	if v == nil {
+		return 0
+	}
+	return v.length
+}
+
+// tailOffset returns the index of the first value in the tail
+func (v *Vector[T]) tailOffset() int {
+	return v.length - len(v.tail)
+}
+
+func (v *Vector[T]) check(i int) {
+	if i < 0 || i >= v.Len() {
+		panic("pvector: index out of range")
+	}
+}
+
+// leaf returns the values of the leaf or tail holding index i
+func (v *Vector[T]) leaf(i int) []T {
+	if i >= v.tailOffset() {
+		return v.tail
+	}
+	n := v.root
+	for level := v.shift; level > 0; level -= bits {
+		n = n.children[i>>level&mask]
+	}
+	return n.values
+}
+
+// Get returns value i. It panics if i is out of range.
+// Time complexity: O(log32 n)
+func (v *Vector[T]) Get(i int) T {
+	v.check(i)
+	return v.leaf(i)[i&mask]
+}
+
+// Set returns a vector with value i replaced by x. It panics if i is out of
+// range.
+// Time complexity: O(log32 n)
+func (v *Vector[T]) Set(i int, x T) *Vector[T] {
+	v.check(i)
+	w := *v
+
+	if i >= v.tailOffset() {
+		w.tail = append([]T(nil), v.tail...)
+		w.tail[i-v.tailOffset()] = x
+		return &w
+	}
+
+	w.root = set(v.root, v.shift, i, x)
+	return &w
+}
+
+// set returns a copy of the subtree at n with index i replaced by x
+func set[T any](n *node[T], level uint, i int, x T) *node[T] {
+	if level == 0 {
+		values := append([]T(nil), n.values...)
+		values[i&mask] = x
+		return &node[T]{values: values}
+	}
+
+	children := append([]*node[T](nil), n.children...)
+	j := i >> level & mask
+	children[j] = set(n.children[j], level-bits, i, x)
+	return &node[T]{children: children}
+}
+
+// Append returns a vector with x added at the end.
+// Time complexity: O(log32 n), O(1) for 31 of every 32 appends
+func (v *Vector[T]) Append(x T) *Vector[T] {
+	var w Vector[T]
+	if v != nil {
+		w = *v
+	}
+
+	if len(w.tail) < width {
+		tail := make([]T, len(w.tail)+1, max(len(w.tail)+1, 4))
+		copy(tail, w.tail)
+		tail[len(w.tail)] = x
+		w.tail = tail
+		w.length++
+		return &w
+	}
+
+	// move the full tail into the trie
+	leaf := &node[T]{values: w.tail}
+	switch {
+	case w.root == nil:
+		w.root = leaf
+	case w.tailOffset()>>bits == 1<<w.shift:
+		// the trie is full, add a level above it
+		w.root = &node[T]{children: []*node[T]{w.root, path(w.shift, leaf)}}
+		w.shift += bits
+	default:
+		w.root = push(w.root, w.shift, w.tailOffset(), leaf)
+	}
+
+	w.tail = []T{x}
+	w.length++
+	return &w
+}
+
+// path returns a chain of nodes from level down to leaf
+func path[T any](level uint, leaf *node[T]) *node[T] {
+	if level == 0 {
+		return leaf
+	}
+	return &node[T]{children: []*node[T]{path(level-bits, leaf)}}
+}
+
+// push returns a copy of the subtree at n with leaf added at index i
+func push[T any](n *node[T], level uint, i int, leaf *node[T]) *node[T] {
+	children := make([]*node[T], len(n.children), len(n.children)+1)
+	copy(children, n.children)
+
+	j := i >> level & mask
+	switch {
+	case level == bits:
+		children = append(children, leaf)
+	case j < len(children):
+		children[j] = push(children[j], level-bits, i, leaf)
+	default:
+		children = append(children, path(level-bits, leaf))
+	}
+	return &node[T]{children: children}
+}
+
+// Pop returns a vector without its last value. It panics if v is empty.
+// Time complexity: O(log32 n)
+func (v *Vector[T]) Pop() *Vector[T] {
+	if v.Len() == 0 {
+		panic("pvector: Pop of empty vector")
+	}
+	w := *v
+
+	if n := len(v.tail) - 1; n > 0 {
+		w.tail = v.tail[:n:n]
+		w.length--
+		return &w
+	}
+	if v.root == nil {
+		return New[T]()
+	}
+
+	// the last leaf of the trie becomes the tail
+	w.length--
+	w.tail = v.leaf(w.length - 1)
+	if v.shift == 0 {
+		w.root = nil
+		return &w
+	}
+
+	w.root = pop(v.root, v.shift, w.length-1)
+	if len(w.root.children) == 1 {
+		// drop a level the trie no longer needs
+		w.root = w.root.children[0]
+		w.shift -= bits
+	}
+	return &w
+}
+
+// pop returns a copy of the subtree at n without the leaf holding index i,
+// the last one, or nil if nothing is left
+func pop[T any](n *node[T], level uint, i int) *node[T] {
+	j := i >> level & mask
+	if level > bits {
+		if child := pop(n.children[j], level-bits, i); child != nil {
+			children := append([]*node[T](nil), n.children...)
+			children[j] = child
+			return &node[T]{children: children}
+		}
+	}
+	if j == 0 {
+		return nil
+	}
+	return &node[T]{children: n.children[:j:j]}
+}
+
+// Each calls fn with every index and value in order until fn returns false
+func (v *Vector[T]) Each(fn func(i int, x T) bool) {
+	for i := 0; i < v.Len(); i += width {
+		for j, x := range v.leaf(i) {
+			if !fn(i+j, x) {
+				return
+			}
+		}
+	}
+}
+
+// Slice returns the values as a new slice
+func (v *Vector[T]) Slice() []T {
+	s := make([]T, 0, v.Len())
+	v.Each(func(_ int, x T) bool {
+		s = append(s, x)
+		return true
+	})
+	return s
+}
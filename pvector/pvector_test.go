@@ -0,0 +1,107 @@
+package pvector
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// check fails unless v holds want
+func check(t *testing.T, v *Vector[int], want []int) {
+	t.Helper()
+	if v.Len() != len(want) {
+		t.Fatalf("got length %d, want %d", v.Len(), len(want))
+	}
+	for i, w := range want {
+		if x := v.Get(i); x != w {
+			t.Fatalf("Get(%d) = %d, want %d", i, x, w)
+		}
+	}
+}
+
+func TestAppendPop(t *testing.T) {
+	// enough values for a trie of three levels
+	const n = 32*32*32 + 100
+	var want []int
+	v := New[int]()
+	for i := 0; i < n; i++ {
+		v = v.Append(i)
+		want = append(want, i)
+	}
+	check(t, v, want)
+
+	s := v.Slice()
+	for i := range s {
+		if s[i] != i {
+			t.Fatalf("Slice()[%d] = %d, want %d", i, s[i], i)
+		}
+	}
+
+	for v.Len() > 0 {
+		v = v.Pop()
+		want = want[:len(want)-1]
+		if len(want)%997 == 0 {
+			check(t, v, want)
+		}
+	}
+}
+
+// TestPersistence checks that updates leave all earlier versions unchanged
+func TestPersistence(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	versions := []*Vector[int]{From[int]()}
+	models := [][]int{nil}
+
+	for i := 0; i < 3000; i++ {
+		j := rng.Intn(len(versions))
+		v, m := versions[j], append([]int(nil), models[j]...)
+		switch op := rng.Intn(4); {
+		case op == 0 && len(m) > 0:
+			k := rng.Intn(len(m))
+			v = v.Set(k, -i)
+			m[k] = -i
+		case op == 1 && len(m) > 0:
+			v = v.Pop()
+			m = m[:len(m)-1]
+		default:
+			for k := rng.Intn(70); k >= 0; k-- {
+				v = v.Append(i)
+				m = append(m, i)
+			}
+		}
+		versions = append(versions, v)
+		models = append(models, m)
+	}
+
+	for j := range versions {
+		check(t, versions[j], models[j])
+	}
+}
+
+func TestEach(t *testing.T) {
+	v := From(0, 1, 2, 3, 4)
+	sum := 0
+	v.Each(func(i, x int) bool {
+		if i != x {
+			t.Fatalf("got value %d at index %d", x, i)
+		}
+		sum += x
+		return x < 2
+	})
+	if sum != 3 {
+		t.Fatalf("got sum %d, want 3 stopping after 2", sum)
+	}
+
+	var nilVector *Vector[int]
+	if nilVector.Len() != 0 || nilVector.Append(1).Get(0) != 1 {
+		t.Fatal("nil is not an empty vector")
+	}
+}
+
+func TestGetOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Get(3) of a vector of length 3 did not panic")
+		}
+	}()
+	From(1, 2, 3).Get(3)
+}
@@ -0,0 +1,208 @@
+package quadtree
+
+// Item is a rectangle stored in a LooseTree, with an associated value
+type Item[V any] struct {
+	Rect  Rect
+	Value V
+
+	node  *looseNode[V]
+	index int // position in node.items
+	seq   uint64
+}
+
+// looseNode holds the items that fit in its loose bounds, its tight bounds
+// widened by half their size on every side, but not in those of a child
+type looseNode[V any] struct {
+	bounds   Rect // tight bounds
+	parent   *looseNode[V]
+	children [4]*looseNode[V]
+	items    []*Item[V]
+	depth    int
+}
+
+func (n *looseNode[V]) loose() Rect {
+	w, h := (n.bounds.Max.X-n.bounds.Min.X)/2, (n.bounds.Max.Y-n.bounds.Min.Y)/2
+	return Rect{
+		Min: Point{n.bounds.Min.X - w, n.bounds.Min.Y - h},
+		Max: Point{n.bounds.Max.X + w, n.bounds.Max.Y + h},
+	}
+}
+
+// empty reports whether n holds no items and has no children
+func (n *looseNode[V]) empty() bool {
+	return len(n.items) == 0 && n.children == [4]*looseNode[V]{}
+}
+
+// LooseTree is a loose quadtree of rectangles. Every node accepts items that
+// fit in its quadrant widened by half its size on every side, so an item is
+// stored at the deepest node of about its size that contains its center,
+// rather than stuck near the root when it straddles a split line. This keeps
+// inserting and moving items cheap, which suits the broad phase of collision
+// detection between objects of similar sizes, e.g. in games. Items outside
+// the bounds are kept at the root.
+type LooseTree[V any] struct {
+	root   *looseNode[V]
+	length int
+	seq    uint64
+}
+
+// NewLooseTree returns an empty tree over bounds
+func NewLooseTree[V any](bounds Rect) *LooseTree[V] {
+	return &LooseTree[V]{root: &looseNode[V]{bounds: bounds}}
+}
+
+// Len returns the number of items
+func (t *LooseTree[V]) Len() int { return t.length }
+
+// Bounds returns the area the tree is split over
+func (t *LooseTree[V]) Bounds() Rect { return t.root.bounds }
+
+// Insert adds an item for r and returns it, to be passed to Move and Remove
+func (t *LooseTree[V]) Insert(r Rect, value V) *Item[V] {
+	t.seq++
+	item := &Item[V]{Rect: r, Value: value, seq: t.seq}
+	t.place(item)
+	t.length++
+	return item
+}
+
+// place stores item at the deepest node whose loose bounds contain it,
+// following the quadrants of its center
+func (t *LooseTree[V]) place(item *Item[V]) {
+	center := item.Rect.center()
+	n := t.root
+	for n.depth < maxDepth && n.bounds.Contains(center) {
+		q := n.bounds.quadrant(center)
+		child := n.children[q]
+		if child == nil {
+			child = &looseNode[V]{bounds: n.bounds.child(q), parent: n, depth: n.depth + 1}
+		}
+		if !child.loose().ContainsRect(item.Rect) {
+			break
+		}
+		n.children[q] = child
+		n = child
+	}
+
+	item.node, item.index = n, len(n.items)
+	n.items = append(n.items, item)
+}
+
+// Remove removes item and reports whether it was in t
+func (t *LooseTree[V]) Remove(item *Item[V]) bool {
+	if !t.owns(item) {
+		return false
+	}
+	t.unlink(item)
+	t.length--
+	return true
+}
+
+// owns reports whether item is stored in t
+func (t *LooseTree[V]) owns(item *Item[V]) bool {
+	n := item.node
+	if n == nil {
+		return false
+	}
+	for n.parent != nil {
+		n = n.parent
+	}
+	return n == t.root
+}
+
+// unlink detaches item from its node and prunes nodes left empty
+func (t *LooseTree[V]) unlink(item *Item[V]) {
+	n := item.node
+	last := len(n.items) - 1
+	n.items[item.index] = n.items[last]
+	n.items[item.index].index = item.index
+	n.items[last] = nil
+	n.items = n.items[:last]
+	item.node = nil
+
+	for n.parent != nil && n.empty() {
+		p := n.parent
+		for q, c := range p.children {
+			if c == n {
+				p.children[q] = nil
+			}
+		}
+		n = p
+	}
+}
+
+// Move changes the rectangle of item to r, relocating it in the tree only if
+// it no longer fits its node. It panics if item is not in t.
+func (t *LooseTree[V]) Move(item *Item[V], r Rect) {
+	if !t.owns(item) {
+		panic("quadtree: Move of an item not in the tree")
+	}
+
+	item.Rect = r
+	n := item.node
+	if n != t.root && n.loose().ContainsRect(r) && n.bounds.Contains(r.center()) && !t.fitsChild(n, r) {
+		return
+	}
+	t.unlink(item)
+	t.place(item)
+}
+
+// fitsChild reports whether r would be stored below n
+func (t *LooseTree[V]) fitsChild(n *looseNode[V], r Rect) bool {
+	if n.depth >= maxDepth {
+		return false
+	}
+	q := n.bounds.quadrant(r.center())
+	child := looseNode[V]{bounds: n.bounds.child(q)}
+	return child.loose().ContainsRect(r)
+}
+
+// Query calls fn for every item intersecting r until fn returns false
+func (t *LooseTree[V]) Query(r Rect, fn func(item *Item[V]) bool) {
+	t.root.query(r, true, fn)
+}
+
+func (n *looseNode[V]) query(r Rect, root bool, fn func(item *Item[V]) bool) bool {
+	if !root && !n.loose().Intersects(r) {
+		return true
+	}
+
+	for _, item := range n.items {
+		if item.Rect.Intersects(r) && !fn(item) {
+			return false
+		}
+	}
+	for _, c := range n.children {
+		if c != nil && !c.query(r, false, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Pairs calls fn once for every two items whose rectangles intersect, until
+// fn returns false
+func (t *LooseTree[V]) Pairs(fn func(a, b *Item[V]) bool) {
+	var walk func(n *looseNode[V]) bool
+	walk = func(n *looseNode[V]) bool {
+		for _, a := range n.items {
+			done := false
+			t.Query(a.Rect, func(b *Item[V]) bool {
+				if a.seq < b.seq && !fn(a, b) {
+					done = true
+				}
+				return !done
+			})
+			if done {
+				return false
+			}
+		}
+		for _, c := range n.children {
+			if c != nil && !walk(c) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(t.root)
+}
@@ -0,0 +1,98 @@
+package quadtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestLooseTree checks queries and pairs against a list of the items after
+// random inserts, moves and removals
+func TestLooseTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tr := NewLooseTree[int](world)
+	var items []*Item[int]
+
+	for i := 0; i < 2000; i++ {
+		switch op := rng.Intn(4); {
+		case op == 0 && len(items) > 0:
+			j := rng.Intn(len(items))
+			if !tr.Remove(items[j]) || tr.Remove(items[j]) {
+				t.Fatalf("Remove did not remove item %d exactly once", items[j].Value)
+			}
+			items[j] = items[len(items)-1]
+			items = items[:len(items)-1]
+		case op == 1 && len(items) > 0:
+			tr.Move(items[rng.Intn(len(items))], randomRect(rng, 10))
+		default:
+			items = append(items, tr.Insert(randomRect(rng, 10), i))
+		}
+		if tr.Len() != len(items) {
+			t.Fatalf("got length %d, want %d", tr.Len(), len(items))
+		}
+
+		if i%50 == 0 {
+			r := randomRect(rng, 30)
+			want := make(map[*Item[int]]bool)
+			for _, item := range items {
+				if item.Rect.Intersects(r) {
+					want[item] = true
+				}
+			}
+			got := 0
+			tr.Query(r, func(item *Item[int]) bool {
+				if !want[item] {
+					t.Fatalf("Query(%v) returned %v", r, item.Rect)
+				}
+				got++
+				return true
+			})
+			if got != len(want) {
+				t.Fatalf("Query(%v) returned %d items, want %d", r, got, len(want))
+			}
+		}
+	}
+
+	want := 0
+	for j, a := range items {
+		for _, b := range items[j+1:] {
+			if a.Rect.Intersects(b.Rect) {
+				want++
+			}
+		}
+	}
+	seen := make(map[[2]*Item[int]]bool)
+	tr.Pairs(func(a, b *Item[int]) bool {
+		if !a.Rect.Intersects(b.Rect) || seen[[2]*Item[int]{a, b}] || seen[[2]*Item[int]{b, a}] {
+			t.Fatalf("Pairs returned %v and %v twice or disjoint", a.Rect, b.Rect)
+		}
+		seen[[2]*Item[int]{a, b}] = true
+		return true
+	})
+	if len(seen) != want {
+		t.Fatalf("Pairs returned %d pairs, want %d", len(seen), want)
+	}
+}
+
+// TestLooseTreeOutside checks that items outside the bounds are still found
+func TestLooseTreeOutside(t *testing.T) {
+	tr := NewLooseTree[string](world)
+	far := tr.Insert(Rect{Point{500, 500}, Point{510, 510}}, "far")
+	tr.Insert(Rect{Point{10, 10}, Point{20, 20}}, "near")
+
+	n := 0
+	tr.Query(Rect{Point{505, 505}, Point{600, 600}}, func(item *Item[string]) bool {
+		if item != far {
+			t.Fatalf("Query returned %q, want far", item.Value)
+		}
+		n++
+		return true
+	})
+	if n != 1 {
+		t.Fatalf("Query returned %d items, want 1", n)
+	}
+
+	other := NewLooseTree[string](world)
+	if other.Remove(far) {
+		t.Fatal("removed an item of another tree")
+	}
+}
@@ -0,0 +1,159 @@
+package quadtree
+
+// Entry is a point with an associated value
+type Entry[V any] struct {
+	Point Point
+	Value V
+}
+
+// pointNode is a leaf holding entries, or an inner node with four children
+// splitting its bounds into equal quadrants
+type pointNode[V any] struct {
+	bounds   Rect
+	entries  []Entry[V]
+	children *[4]pointNode[V]
+	count    int // number of entries in the subtree
+	depth    int
+}
+
+// PointTree is a point-region quadtree: the bounds are split into quadrants
+// recursively until every leaf holds at most a fixed number of points. The
+// tree adapts to where points are but not to their order, and its shape is
+// the same whatever the order of insertion, which suits points spread over
+// a known area, e.g. objects on a screen. Removing points merges leaves
+// again once a subtree fits in one.
+type PointTree[V any] struct {
+	root     pointNode[V]
+	capacity int
+}
+
+// NewPointTree returns an empty tree for points inside bounds, with at most
+// capacity points per leaf. It panics if capacity is not positive.
+func NewPointTree[V any](bounds Rect, capacity int) *PointTree[V] {
+	if capacity < 1 {
+		panic("quadtree: capacity must be positive")
+	}
+	return &PointTree[V]{root: pointNode[V]{bounds: bounds}, capacity: capacity}
+}
+
+// Len returns the number of entries
+func (t *PointTree[V]) Len() int { return t.root.count }
+
+// Bounds returns the area the tree covers
+func (t *PointTree[V]) Bounds() Rect { return t.root.bounds }
+
+// Insert adds value at p, keeping any other entries at the same point.
+// ErrOutOfBounds is returned if p lies outside Bounds.
+func (t *PointTree[V]) Insert(p Point, value V) error {
+	if !t.root.bounds.Contains(p) {
+		return ErrOutOfBounds
+	}
+
+	n := &t.root
+	for n.children != nil {
+		n.count++
+		n = &n.children[n.bounds.quadrant(p)]
+	}
+	n.count++
+	n.entries = append(n.entries, Entry[V]{p, value})
+
+	if len(n.entries) > t.capacity && n.depth < maxDepth {
+		t.split(n)
+	}
+	return nil
+}
+
+// split turns the leaf n into an inner node, splitting again any child that
+// receives all entries
+func (t *PointTree[V]) split(n *pointNode[V]) {
+	n.children = new([4]pointNode[V])
+	for q := range n.children {
+		n.children[q] = pointNode[V]{bounds: n.bounds.child(q), depth: n.depth + 1}
+	}
+	for _, e := range n.entries {
+		c := &n.children[n.bounds.quadrant(e.Point)]
+		c.entries = append(c.entries, e)
+		c.count++
+	}
+	n.entries = nil
+
+	for q := range n.children {
+		if c := &n.children[q]; len(c.entries) > t.capacity && c.depth < maxDepth {
+			t.split(c)
+		}
+	}
+}
+
+// Remove removes an entry at p and reports whether there was one
+func (t *PointTree[V]) Remove(p Point) bool {
+	if !t.root.bounds.Contains(p) {
+		return false
+	}
+
+	path := []*pointNode[V]{&t.root}
+	for n := &t.root; n.children != nil; {
+		n = &n.children[n.bounds.quadrant(p)]
+		path = append(path, n)
+	}
+
+	leaf := path[len(path)-1]
+	i := 0
+	for i < len(leaf.entries) && leaf.entries[i].Point != p {
+		i++
+	}
+	if i == len(leaf.entries) {
+		return false
+	}
+	last := len(leaf.entries) - 1
+	leaf.entries[i] = leaf.entries[last]
+	leaf.entries[last] = Entry[V]{}
+	leaf.entries = leaf.entries[:last]
+
+	// merge the topmost subtree that fits in a leaf
+	merged := false
+	for _, n := range path {
+		n.count--
+		if !merged && n.children != nil && n.count <= t.capacity {
+			n.entries = n.collect(make([]Entry[V], 0, n.count+1))
+			n.children = nil
+			merged = true
+		}
+	}
+	return true
+}
+
+// collect appends the entries below n to entries
+func (n *pointNode[V]) collect(entries []Entry[V]) []Entry[V] {
+	entries = append(entries, n.entries...)
+	if n.children != nil {
+		for q := range n.children {
+			entries = n.children[q].collect(entries)
+		}
+	}
+	return entries
+}
+
+// Query calls fn for every entry inside r until fn returns false
+func (t *PointTree[V]) Query(r Rect, fn func(p Point, value V) bool) {
+	t.root.query(r, fn)
+}
+
+func (n *pointNode[V]) query(r Rect, fn func(p Point, value V) bool) bool {
+	if n.count == 0 || !n.bounds.Intersects(r) {
+		return true
+	}
+
+	for _, e := range n.entries {
+		if r.Contains(e.Point) && !fn(e.Point, e.Value) {
+			return false
+		}
+	}
+	if n.children != nil {
+		for q := range n.children {
+			if !n.children[q].query(r, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
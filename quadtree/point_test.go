@@ -0,0 +1,73 @@
+package quadtree
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+var world = Rect{Point{0, 0}, Point{100, 100}}
+
+func randomRect(rng *rand.Rand, size float64) Rect {
+	x, y := rng.Float64()*100, rng.Float64()*100
+	return Rect{Point{x, y}, Point{x + rng.Float64()*size, y + rng.Float64()*size}}
+}
+
+// TestPointTree checks queries against a list of the points after random
+// inserts and removals, including many points at the same place
+func TestPointTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tr := NewPointTree[int](world, 4)
+	var points []Point
+
+	for i := 0; i < 3000; i++ {
+		if len(points) > 0 && rng.Intn(3) == 0 {
+			j := rng.Intn(len(points))
+			if !tr.Remove(points[j]) {
+				t.Fatalf("Remove(%v) found nothing", points[j])
+			}
+			points[j] = points[len(points)-1]
+			points = points[:len(points)-1]
+		} else {
+			p := Point{float64(rng.Intn(50)) * 2, rng.Float64() * 100}
+			if i%10 == 0 {
+				p = Point{50, 50}
+			}
+			if err := tr.Insert(p, i); err != nil {
+				t.Fatal(err)
+			}
+			points = append(points, p)
+		}
+		if tr.Len() != len(points) {
+			t.Fatalf("got length %d, want %d", tr.Len(), len(points))
+		}
+
+		if i%50 == 0 {
+			r := randomRect(rng, 40)
+			want := 0
+			for _, p := range points {
+				if r.Contains(p) {
+					want++
+				}
+			}
+			got := 0
+			tr.Query(r, func(p Point, _ int) bool {
+				if !r.Contains(p) {
+					t.Fatalf("Query(%v) returned %v outside", r, p)
+				}
+				got++
+				return true
+			})
+			if got != want {
+				t.Fatalf("Query(%v) returned %d points, want %d", r, got, want)
+			}
+		}
+	}
+
+	if err := tr.Insert(Point{101, 0}, 0); !errors.Is(err, ErrOutOfBounds) {
+		t.Fatalf("got %v inserting outside the bounds, want ErrOutOfBounds", err)
+	}
+	if tr.Remove(Point{-1, -1}) {
+		t.Fatal("removed a point outside the bounds")
+	}
+}
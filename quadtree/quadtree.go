@@ -0,0 +1,77 @@
+// Package quadtree provides quadtrees over the plane: a point quadtree for
+// point data, and a loose quadtree for rectangles such as the bounding boxes
+// of moving objects.
+package quadtree
+
+import (
+	"errors"
+)
+
+// maxDepth bounds the depth of both trees, so that many objects at the same
+// place do not split nodes without end
+const maxDepth = 24
+
+// ErrOutOfBounds is returned when inserting a point outside of the bounds of
+// a point quadtree
+var ErrOutOfBounds = errors.New("quadtree: point is out of bounds")
+
+// Point is a position in the plane
+type Point struct {
+	X, Y float64
+}
+
+// Rect is an axis aligned rectangle, including its boundary
+type Rect struct {
+	Min, Max Point
+}
+
+// Contains reports whether p lies inside r
+func (r Rect) Contains(p Point) bool {
+	return r.Min.X <= p.X && p.X <= r.Max.X && r.Min.Y <= p.Y && p.Y <= r.Max.Y
+}
+
+// ContainsRect reports whether s lies inside r
+func (r Rect) ContainsRect(s Rect) bool {
+	return r.Min.X <= s.Min.X && s.Max.X <= r.Max.X && r.Min.Y <= s.Min.Y && s.Max.Y <= r.Max.Y
+}
+
+// Intersects reports whether r and s share a point
+func (r Rect) Intersects(s Rect) bool {
+	return r.Min.X <= s.Max.X && s.Min.X <= r.Max.X && r.Min.Y <= s.Max.Y && s.Min.Y <= r.Max.Y
+}
+
+// center returns the midpoint of r
+func (r Rect) center() Point {
+	return Point{(r.Min.X + r.Max.X) / 2, (r.Min.Y + r.Max.Y) / 2}
+}
+
+// quadrant returns the index of the quadrant of r holding p: bit 0 is set
+// for the right half, bit 1 for the upper half
+func (r Rect) quadrant(p Point) int {
+	c := r.center()
+	q := 0
+	if p.X >= c.X {
+		q |= 1
+	}
+	if p.Y >= c.Y {
+		q |= 2
+	}
+	return q
+}
+
+// child returns quadrant q of r
+func (r Rect) child(q int) Rect {
+	c := r.center()
+	s := r
+	if q&1 == 0 {
+		s.Max.X = c.X
+	} else {
+		s.Min.X = c.X
+	}
+	if q&2 == 0 {
+		s.Max.Y = c.Y
+	} else {
+		s.Min.Y = c.Y
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package radix
+
+import "net/netip"
+
+// Table maps IP prefixes in CIDR notation to values and finds the most
+// specific prefix containing an address, as in a routing table. IPv4 and
+// IPv6 prefixes are kept apart, so an IPv4-mapped IPv6 address only matches
+// IPv6 prefixes.
+//
+// Prefixes are stored in a radix tree with one key byte per address bit,
+// which the path compression keeps compact.
+type Table[V any] struct {
+	tree *Tree[string, V]
+}
+
+// NewTable returns an empty table
+func NewTable[V any]() *Table[V] {
+	return &Table[V]{tree: New[string, V]()}
+}
+
+// Len returns the number of prefixes
+func (t *Table[V]) Len() int { return t.tree.Len() }
+
+// bitKey returns the key for the first bits bits of addr: its family,
+// followed by one byte per bit
+func bitKey(addr netip.Addr, bits int) string {
+	key := make([]byte, 1, 1+bits)
+	key[0] = '6'
+	if addr.Is4() {
+		key[0] = '4'
+	}
+
+	raw := addr.AsSlice()
+	for i := 0; i < bits; i++ {
+		key = append(key, '0'+raw[i/8]>>(7-i%8)&1)
+	}
+	return string(key)
+}
+
+func prefixKey(p netip.Prefix) string {
+	if !p.IsValid() {
+		panic("radix: invalid prefix")
+	}
+	return bitKey(p.Addr(), p.Bits())
+}
+
+// Insert stores value under prefix p, replacing any previous value. Host
+// bits of p are ignored. It panics if p is invalid.
+func (t *Table[V]) Insert(p netip.Prefix, value V) {
+	t.tree.Put(prefixKey(p), value)
+}
+
+// Get returns the value stored under prefix p, and whether it was found
+func (t *Table[V]) Get(p netip.Prefix) (V, bool) {
+	return t.tree.Get(prefixKey(p))
+}
+
+// Delete removes prefix p and reports whether it was present
+func (t *Table[V]) Delete(p netip.Prefix) bool {
+	return t.tree.Delete(prefixKey(p))
+}
+
+// Lookup returns the longest prefix containing addr, with its value, or
+// false if there is none.
+// Time complexity: O(address bits)
+func (t *Table[V]) Lookup(addr netip.Addr) (netip.Prefix, V, bool) {
+	if !addr.IsValid() {
+		var v V
+		return netip.Prefix{}, v, false
+	}
+
+	key, value, ok := t.tree.LongestPrefix(bitKey(addr, addr.BitLen()))
+	if !ok {
+		return netip.Prefix{}, value, false
+	}
+	p, _ := addr.Prefix(len(key) - 1)
+	return p, value, true
+}
@@ -0,0 +1,50 @@
+package radix
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestTableLookup(t *testing.T) {
+	table := NewTable[string]()
+	for _, p := range []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24", "0.0.0.0/0", "2001:db8::/32"} {
+		table.Insert(netip.MustParsePrefix(p), p)
+	}
+	// host bits are ignored
+	table.Insert(netip.MustParsePrefix("192.168.1.77/24"), "192.168.1.0/24")
+
+	for _, q := range []struct{ addr, want string }{
+		{"10.1.2.3", "10.1.2.0/24"},
+		{"10.1.3.3", "10.1.0.0/16"},
+		{"10.200.0.1", "10.0.0.0/8"},
+		{"192.168.1.200", "192.168.1.0/24"},
+		{"8.8.8.8", "0.0.0.0/0"},
+		{"2001:db8::1", "2001:db8::/32"},
+		{"2001:db9::1", ""},
+		{"::ffff:10.1.2.3", ""},
+	} {
+		p, v, ok := table.Lookup(netip.MustParseAddr(q.addr))
+		if q.want == "" {
+			if ok {
+				t.Errorf("Lookup(%s) = %s, want none", q.addr, p)
+			}
+			continue
+		}
+		if !ok || v != q.want || p.String() != q.want {
+			t.Errorf("Lookup(%s) = %s, %q, %v, want %s", q.addr, p, v, ok, q.want)
+		}
+	}
+
+	if table.Len() != 6 {
+		t.Fatalf("got %d prefixes, want 6", table.Len())
+	}
+	if !table.Delete(netip.MustParsePrefix("10.1.0.0/16")) {
+		t.Fatal("Delete of an existing prefix failed")
+	}
+	if _, ok := table.Get(netip.MustParsePrefix("10.1.0.0/16")); ok {
+		t.Fatal("deleted prefix is still found")
+	}
+	if p, _, _ := table.Lookup(netip.MustParseAddr("10.1.3.3")); p.String() != "10.0.0.0/8" {
+		t.Fatalf("Lookup after Delete = %s, want 10.0.0.0/8", p)
+	}
+}
@@ -0,0 +1,241 @@
+// Package radix provides a generic path-compressed radix tree keyed by
+// strings or byte slices, and a longest-prefix-match table for IP addresses
+// built on it.
+package radix
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/hanyangtay/go-datastructures/trie"
+)
+
+// node is reached from its parent by the bytes of prefix. Except for the
+// root, every node holds a key or has at least two children.
+type node[V any] struct {
+	prefix []byte
+	value  V
+	ok     bool // whether a key ends here
+
+	// children in order of the first byte of their prefix
+	labels   []byte
+	children []*node[V]
+}
+
+// child returns the index of the child whose prefix starts with b, and
+// whether it exists
+func (n *node[V]) child(b byte) (int, bool) {
+	i := sort.Search(len(n.labels), func(i int) bool { return n.labels[i] >= b })
+	return i, i < len(n.labels) && n.labels[i] == b
+}
+
+// commonPrefix returns the length of the common prefix of a and b
+func commonPrefix[K trie.Key](a []byte, b K) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// Tree maps keys to values like trie.Trie, with the same methods, but merges
+// every chain of nodes with a single child into one node labelled by the
+// whole chain. It holds at most two nodes per key, however long, so it takes
+// far less memory for sparse sets of long keys, e.g. URLs or file paths.
+type Tree[K trie.Key, V any] struct {
+	root   node[V]
+	length int
+}
+
+// New returns an empty tree
+func New[K trie.Key, V any]() *Tree[K, V] {
+	return &Tree[K, V]{}
+}
+
+// Len returns the number of keys
+func (t *Tree[K, V]) Len() int { return t.length }
+
+// find returns the node for key, or nil if there is none
+func (t *Tree[K, V]) find(key K) *node[V] {
+	n := &t.root
+	for len(key) > 0 {
+		j, ok := n.child(key[0])
+		if !ok {
+			return nil
+		}
+		c := n.children[j]
+		if commonPrefix(c.prefix, key) < len(c.prefix) {
+			return nil
+		}
+		n, key = c, key[len(c.prefix):]
+	}
+	return n
+}
+
+// Get returns the value stored under key, and whether it was found.
+// Time complexity: O(len(key))
+func (t *Tree[K, V]) Get(key K) (V, bool) {
+	if n := t.find(key); n != nil && n.ok {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores value under key, replacing any previous value.
+// Time complexity: O(len(key))
+func (t *Tree[K, V]) Put(key K, value V) {
+	n := &t.root
+	for len(key) > 0 {
+		j, ok := n.child(key[0])
+		if !ok {
+			leaf := &node[V]{prefix: []byte(string(key)), value: value, ok: true}
+			n.labels = slices.Insert(n.labels, j, key[0])
+			n.children = slices.Insert(n.children, j, leaf)
+			t.length++
+			return
+		}
+
+		c := n.children[j]
+		l := commonPrefix(c.prefix, key)
+		if l < len(c.prefix) {
+			// split the prefix of c where key diverges
+			mid := &node[V]{
+				prefix:   c.prefix[:l:l],
+				labels:   []byte{c.prefix[l]},
+				children: []*node[V]{c},
+			}
+			c.prefix = c.prefix[l:]
+			n.children[j] = mid
+			c = mid
+		}
+		n, key = c, key[l:]
+	}
+
+	if !n.ok {
+		t.length++
+	}
+	n.value, n.ok = value, true
+}
+
+// Delete removes key and reports whether it was present. Nodes are merged
+// again so that the tree stays compressed.
+// Time complexity: O(len(key))
+func (t *Tree[K, V]) Delete(key K) bool {
+	var parent *node[V]
+	n := &t.root
+	for len(key) > 0 {
+		j, ok := n.child(key[0])
+		if !ok {
+			return false
+		}
+		c := n.children[j]
+		if commonPrefix(c.prefix, key) < len(c.prefix) {
+			return false
+		}
+		parent, n, key = n, c, key[len(c.prefix):]
+	}
+	if !n.ok {
+		return false
+	}
+
+	var zero V
+	n.value, n.ok = zero, false
+	t.length--
+
+	if parent == nil {
+		return true
+	}
+	switch len(n.children) {
+	case 0:
+		j, _ := parent.child(n.prefix[0])
+		parent.labels = slices.Delete(parent.labels, j, j+1)
+		parent.children = slices.Delete(parent.children, j, j+1)
+		if parent != &t.root && !parent.ok && len(parent.children) == 1 {
+			t.compress(parent)
+		}
+	case 1:
+		t.compress(n)
+	}
+	return true
+}
+
+// compress merges n, which holds no key, with its only child
+func (t *Tree[K, V]) compress(n *node[V]) {
+	c := n.children[0]
+	prefix := make([]byte, 0, len(n.prefix)+len(c.prefix))
+	prefix = append(append(prefix, n.prefix...), c.prefix...)
+	*n = *c
+	n.prefix = prefix
+}
+
+// LongestPrefix returns the longest key that is a prefix of key, with its
+// value, or false if there is none.
+// Time complexity: O(len(key))
+func (t *Tree[K, V]) LongestPrefix(key K) (K, V, bool) {
+	n := &t.root
+	best, found := n, n.ok
+	length, depth := 0, 0
+	for depth < len(key) {
+		j, ok := n.child(key[depth])
+		if !ok {
+			break
+		}
+		c := n.children[j]
+		if commonPrefix(c.prefix, key[depth:]) < len(c.prefix) {
+			break
+		}
+		n = c
+		depth += len(c.prefix)
+		if n.ok {
+			best, found, length = n, true, depth
+		}
+	}
+
+	if !found {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return K(string(key[:length])), best.value, true
+}
+
+// WalkPrefix calls fn in key order for every key beginning with prefix,
+// until fn returns false. The key passed to fn may be retained.
+// Time complexity: O(len(prefix) + size of the subtree visited)
+func (t *Tree[K, V]) WalkPrefix(prefix K, fn func(key K, value V) bool) {
+	n := &t.root
+	key := make([]byte, 0, len(prefix))
+	for len(prefix) > 0 {
+		j, ok := n.child(prefix[0])
+		if !ok {
+			return
+		}
+		c := n.children[j]
+		l := commonPrefix(c.prefix, prefix)
+		if l < len(prefix) && l < len(c.prefix) {
+			return
+		}
+		n, prefix = c, prefix[l:]
+		key = append(key, c.prefix...)
+	}
+	walk(n, key, fn)
+}
+
+// Ascend calls fn for every key in order until fn returns false
+func (t *Tree[K, V]) Ascend(fn func(key K, value V) bool) {
+	walk(&t.root, nil, fn)
+}
+
+// walk visits in key order the keys below n, whose path spells key
+func walk[K trie.Key, V any](n *node[V], key []byte, fn func(key K, value V) bool) bool {
+	if n.ok && !fn(K(string(key)), n.value) {
+		return false
+	}
+	for _, c := range n.children {
+		if !walk(c, append(key, c.prefix...), fn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+// Package ringbuffer provides a generic fixed capacity circular queue.
+package ringbuffer
+
+import "errors"
+
+// ErrFull is returned when writing to a full buffer in Reject mode
+var ErrFull = errors.New("ringbuffer: buffer is full")
+
+// Mode selects what a full buffer does with new items
+type Mode int
+
+const (
+	// Reject refuses new items with ErrFull, e.g. to apply backpressure to a
+	// producer
+	Reject Mode = iota
+
+	// Overwrite discards the oldest item to make room, e.g. to keep the most
+	// recent samples of telemetry
+	Overwrite
+)
+
+// Buffer is a first-in first-out queue of at most a fixed number of items,
+// stored in a circular slice allocated once, so that it never allocates
+// after creation. It is not safe for concurrent use.
+type Buffer[T any] struct {
+	items       []T
+	head        int // index of the oldest item
+	length      int
+	mode        Mode
+	overwritten uint64
+}
+
+// New returns an empty buffer for capacity items. It panics if capacity is
+// not positive.
+func New[T any](capacity int, mode Mode) *Buffer[T] {
+	if capacity < 1 {
+		panic("ringbuffer: capacity must be positive")
+	}
+	return &Buffer[T]{items: make([]T, capacity), mode: mode}
+}
+
+// Len returns the number of items
+func (b *Buffer[T]) Len() int { return b.length }
+
+// Cap returns the greatest number of items
+func (b *Buffer[T]) Cap() int { return len(b.items) }
+
+// Full reports whether the buffer holds Cap items
+func (b *Buffer[T]) Full() bool { return b.length == len(b.items) }
+
+// Overwritten returns the number of items discarded to make room in
+// Overwrite mode
+func (b *Buffer[T]) Overwritten() uint64 { return b.overwritten }
+
+// index returns the slice index of item i, counted from the oldest
+func (b *Buffer[T]) index(i int) int {
+	i += b.head
+	if i >= len(b.items) {
+		i -= len(b.items)
+	}
+	return i
+}
+
+// Push appends v as the newest item. If the buffer is full, it discards the
+// oldest item in Overwrite mode, and returns ErrFull in Reject mode.
+func (b *Buffer[T]) Push(v T) error {
+	if b.Full() {
+		if b.mode == Reject {
+			return ErrFull
+		}
+		b.items[b.head] = v
+		b.head = b.index(1)
+		b.overwritten++
+		return nil
+	}
+
+	b.items[b.index(b.length)] = v
+	b.length++
+	return nil
+}
+
+// Pop removes and returns the oldest item, or false if the buffer is empty
+func (b *Buffer[T]) Pop() (T, bool) {
+	var zero T
+	if b.length == 0 {
+		return zero, false
+	}
+
+	v := b.items[b.head]
+	b.items[b.head] = zero
+	b.head = b.index(1)
+	b.length--
+	return v, true
+}
+
+// Peek returns the oldest item without removing it, or false if the buffer
+// is empty
+func (b *Buffer[T]) Peek() (T, bool) {
+	if b.length == 0 {
+		var zero T
+		return zero, false
+	}
+	return b.items[b.head], true
+}
+
+// At returns item i, counted from the oldest. It panics if i is out of
+// range.
+func (b *Buffer[T]) At(i int) T {
+	if i < 0 || i >= b.length {
+		panic("ringbuffer: index out of range")
+	}
+	return b.items[b.index(i)]
+}
+
+// Write appends items in order and returns the number written. In Reject
+// mode it writes as many as fit and returns ErrFull if that is not all; in
+// Overwrite mode it writes all of them, discarding the oldest items as
+// needed.
+func (b *Buffer[T]) Write(items []T) (int, error) {
+	total := len(items)
+	if b.mode == Reject {
+		items = items[:min(total, len(b.items)-b.length)]
+	} else {
+		// only the last Cap items survive
+		if total > len(b.items) {
+			b.overwritten += uint64(total - len(b.items))
+			items = items[total-len(b.items):]
+		}
+		if drop := b.length + len(items) - len(b.items); drop > 0 {
+			b.Discard(drop)
+			b.overwritten += uint64(drop)
+		}
+	}
+
+	// copy in at most two pieces, up to the end of the slice and from its
+	// start
+	copied := copy(b.items[b.index(b.length):], items)
+	copy(b.items, items[copied:])
+	b.length += len(items)
+
+	if b.mode == Reject && len(items) < total {
+		return len(items), ErrFull
+	}
+	return total, nil
+}
+
+// Read removes up to len(dst) of the oldest items into dst, in order, and
+// returns their number
+func (b *Buffer[T]) Read(dst []T) int {
+	n := min(len(dst), b.length)
+	first := copy(dst[:n], b.items[b.head:])
+	copy(dst[first:n], b.items)
+	b.Discard(n)
+	return n
+}
+
+// Discard removes the n oldest items, or all of them if there are fewer
+func (b *Buffer[T]) Discard(n int) {
+	n = min(n, b.length)
+	var zero T
+	for i := 0; i < n; i++ {
+		b.items[b.index(i)] = zero
+	}
+	b.head = b.index(n)
+	b.length -= n
+	if b.length == 0 {
+		b.head = 0
+	}
+}
+
+// Reset removes all items
+func (b *Buffer[T]) Reset() { b.Discard(b.length) }
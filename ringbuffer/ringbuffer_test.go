@@ -0,0 +1,102 @@
+package ringbuffer
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// TestAgainstSlice checks a buffer in each mode against a slice under a
+// random mix of operations
+func TestAgainstSlice(t *testing.T) {
+	for _, mode := range []Mode{Reject, Overwrite} {
+		rng := rand.New(rand.NewSource(1))
+		b := New[int](10, mode)
+		var want []int
+		var overwritten uint64
+
+		for i := 0; i < 5000; i++ {
+			switch rng.Intn(5) {
+			case 0:
+				err := b.Push(i)
+				switch {
+				case len(want) < 10:
+					want = append(want, i)
+				case mode == Overwrite:
+					want = append(want[1:], i)
+					overwritten++
+				case !errors.Is(err, ErrFull):
+					t.Fatalf("got %v pushing to a full buffer, want ErrFull", err)
+				}
+			case 1:
+				items := make([]int, rng.Intn(15))
+				for j := range items {
+					items[j] = i*100 + j
+				}
+				n, err := b.Write(items)
+				if mode == Reject {
+					fit := min(len(items), 10-len(want))
+					if n != fit || (err != nil) != (fit < len(items)) {
+						t.Fatalf("Write of %d items = %d, %v with %d free", len(items), n, err, 10-len(want))
+					}
+					want = append(want, items[:fit]...)
+				} else {
+					want = append(want, items...)
+					if len(want) > 10 {
+						overwritten += uint64(len(want) - 10)
+						want = want[len(want)-10:]
+					}
+				}
+			case 2:
+				dst := make([]int, rng.Intn(8))
+				n := b.Read(dst)
+				if n != min(len(dst), len(want)) {
+					t.Fatalf("Read %d items, want %d", n, min(len(dst), len(want)))
+				}
+				for j := 0; j < n; j++ {
+					if dst[j] != want[j] {
+						t.Fatalf("Read item %d = %d, want %d", j, dst[j], want[j])
+					}
+				}
+				want = want[n:]
+			case 3:
+				v, ok := b.Pop()
+				if ok != (len(want) > 0) || ok && v != want[0] {
+					t.Fatalf("Pop() = %d, %v, want %v", v, ok, want)
+				}
+				if ok {
+					want = want[1:]
+				}
+			default:
+				n := rng.Intn(4)
+				b.Discard(n)
+				want = want[min(n, len(want)):]
+			}
+
+			if b.Len() != len(want) || b.Full() != (len(want) == 10) || b.Overwritten() != overwritten {
+				t.Fatalf("got length %d and %d overwritten, want %d and %d", b.Len(), b.Overwritten(), len(want), overwritten)
+			}
+			for j, w := range want {
+				if x := b.At(j); x != w {
+					t.Fatalf("At(%d) = %d, want %d", j, x, w)
+				}
+			}
+		}
+
+		b.Reset()
+		if _, ok := b.Peek(); ok || b.Len() != 0 || b.Cap() != 10 {
+			t.Fatalf("got length %d after Reset", b.Len())
+		}
+	}
+}
+
+func TestAtOutOfRange(t *testing.T) {
+	b := New[int](4, Reject)
+	b.Push(1)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("At(1) of a buffer of length 1 did not panic")
+		}
+	}()
+	b.At(1)
+}
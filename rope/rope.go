@@ -0,0 +1,378 @@
+// Package rope provides a rope, a byte string stored as a balanced tree of
+// chunks, which can be edited anywhere in O(log n) time.
+package rope
+
+import (
+	"io"
+	"math/rand"
+	"strings"
+)
+
+// maxChunk is the largest number of bytes stored in a single node. Text
+// inserted next to a chunk with room left is added to it, so that typing or
+// appending in small pieces does not leave a node per piece.
+const maxChunk = 1024
+
+// node holds a chunk of the text, in heap order of random priorities, as in
+// an implicit treap. The spare capacity of a chunk, if any, belongs to the
+// node alone, so text can be appended to it in place; other chunks are cut
+// to their length.
+type node struct {
+	chunk       []byte
+	prio        int64
+	size        int // number of bytes in the subtree
+	left, right *node
+}
+
+func size(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *node) update() {
+	n.size = size(n.left) + len(n.chunk) + size(n.right)
+}
+
+// merge joins two trees where the text of a precedes that of b
+func merge(a, b *node) *node {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	if a.prio > b.prio {
+		a.right = merge(a.right, b)
+		a.update()
+		return a
+	}
+	b.left = merge(a, b.left)
+	b.update()
+	return b
+}
+
+// splitAt divides the tree at n into its first i bytes and the rest,
+// cutting a chunk in two if i falls inside of it
+func splitAt(n *node, i int) (*node, *node) {
+	if n == nil {
+		return nil, nil
+	}
+
+	left := size(n.left)
+	switch {
+	case i <= left:
+		l, r := splitAt(n.left, i)
+		n.left = r
+		n.update()
+		return l, n
+	case i >= left+len(n.chunk):
+		l, r := splitAt(n.right, i-left-len(n.chunk))
+		n.right = l
+		n.update()
+		return n, r
+	}
+
+	// the tail takes the priority of n, which is at least that of its
+	// right subtree, so heap order is kept
+	k := i - left
+	tail := &node{chunk: n.chunk[k:len(n.chunk):len(n.chunk)], prio: n.prio, right: n.right}
+	tail.update()
+	n.chunk = n.chunk[:k:k]
+	n.right = nil
+	n.update()
+	return n, tail
+}
+
+// ascend calls fn for the chunks below n in order, reporting whether all
+// calls returned true
+func ascend(n *node, fn func(chunk []byte) bool) bool {
+	if n == nil {
+		return true
+	}
+	return ascend(n.left, fn) && fn(n.chunk) && ascend(n.right, fn)
+}
+
+// Rope is a mutable byte string, such as the text of an editor buffer or a
+// log being assembled from pieces. Inserting, deleting, splitting and
+// concatenating take O(log n) expected time regardless of where they happen,
+// where a plain string or byte slice would copy the text after the edit.
+// Indexes count bytes, not runes.
+type Rope struct {
+	root *node
+	rng  *rand.Rand
+}
+
+// New returns an empty rope
+func New() *Rope {
+	return &Rope{rng: rand.New(rand.NewSource(1))}
+}
+
+// FromString returns a rope holding s
+func FromString(s string) *Rope {
+	r := New()
+	r.root = appendTo(r, nil, s)
+	return r
+}
+
+// Len returns the number of bytes
+func (r *Rope) Len() int { return size(r.root) }
+
+// appendTo adds text at the end of the tree at n and returns the new root
+func appendTo[S string | []byte](r *Rope, n *node, text S) *node {
+	if len(text) == 0 {
+		return n
+	}
+
+	// fill the last chunk if it has room, growing the sizes along the way
+	last := n
+	for last != nil && last.right != nil {
+		last = last.right
+	}
+	if last != nil && len(last.chunk)+len(text) <= maxChunk {
+		last.chunk = append(last.chunk, text...)
+		for m := n; m != nil; m = m.right {
+			m.size += len(text)
+		}
+		return n
+	}
+
+	for len(text) > 0 {
+		k := min(len(text), maxChunk)
+		chunk := make([]byte, k)
+		copy(chunk, text[:k])
+		n = merge(n, &node{chunk: chunk, prio: r.rng.Int63(), size: k})
+		text = text[k:]
+	}
+	return n
+}
+
+func (r *Rope) checkPos(i int) {
+	if i < 0 || i > r.Len() {
+		panic("rope: index out of range")
+	}
+}
+
+func (r *Rope) checkRange(i, j int) {
+	if i < 0 || i > j || j > r.Len() {
+		panic("rope: range out of bounds")
+	}
+}
+
+// At returns byte i. It panics if i is out of range.
+// Time complexity: O(log n)
+func (r *Rope) At(i int) byte {
+	if i < 0 || i >= r.Len() {
+		panic("rope: index out of range")
+	}
+
+	n := r.root
+	for {
+		switch left := size(n.left); {
+		case i < left:
+			n = n.left
+		case i < left+len(n.chunk):
+			return n.chunk[i-left]
+		default:
+			i -= left + len(n.chunk)
+			n = n.right
+		}
+	}
+}
+
+// Insert inserts text at byte i, shifting the bytes from i onwards. It
+// panics unless 0 <= i <= Len(). Time complexity: O(log n + len(text))
+func (r *Rope) Insert(i int, text string) {
+	r.checkPos(i)
+	l, rest := splitAt(r.root, i)
+	r.root = merge(appendTo(r, l, text), rest)
+}
+
+// Delete removes the bytes from i up to but excluding j. It panics unless
+// 0 <= i <= j <= Len(). Time complexity: O(log n)
+func (r *Rope) Delete(i, j int) {
+	r.checkRange(i, j)
+	l, rest := splitAt(r.root, i)
+	_, rest = splitAt(rest, j-i)
+	r.root = merge(l, rest)
+}
+
+// Split removes the bytes from i onwards and returns them as a new rope. It
+// panics unless 0 <= i <= Len(). Time complexity: O(log n)
+func (r *Rope) Split(i int) *Rope {
+	r.checkPos(i)
+	l, rest := splitAt(r.root, i)
+	r.root = l
+	return &Rope{root: rest, rng: rand.New(rand.NewSource(r.rng.Int63()))}
+}
+
+// Concat appends other to r, leaving other empty. Time complexity: O(log n)
+func (r *Rope) Concat(other *Rope) {
+	r.root = merge(r.root, other.root)
+	other.root = nil
+}
+
+// Slice returns the bytes from i up to but excluding j as a string. It
+// panics unless 0 <= i <= j <= Len(). Time complexity: O(log n + j - i)
+func (r *Rope) Slice(i, j int) string {
+	r.checkRange(i, j)
+
+	var b strings.Builder
+	b.Grow(j - i)
+	var walk func(n *node, offset int) bool
+	walk = func(n *node, offset int) bool {
+		if n == nil || offset >= j {
+			return true
+		}
+		start := offset + size(n.left)
+		end := start + len(n.chunk)
+		if i < start && !walk(n.left, offset) {
+			return false
+		}
+		if i < end && start < j {
+			b.Write(n.chunk[max(i-start, 0):min(j-start, len(n.chunk))])
+		}
+		if end >= j {
+			return false
+		}
+		return walk(n.right, end)
+	}
+	walk(r.root, 0)
+	return b.String()
+}
+
+// String returns the whole text. Time complexity: O(n)
+func (r *Rope) String() string {
+	var b strings.Builder
+	b.Grow(r.Len())
+	ascend(r.root, func(chunk []byte) bool {
+		b.Write(chunk)
+		return true
+	})
+	return b.String()
+}
+
+// Write appends p, implementing io.Writer. It never fails.
+func (r *Rope) Write(p []byte) (int, error) {
+	r.root = appendTo(r, r.root, p)
+	return len(p), nil
+}
+
+// WriteString appends s, implementing io.StringWriter. It never fails.
+func (r *Rope) WriteString(s string) (int, error) {
+	r.root = appendTo(r, r.root, s)
+	return len(s), nil
+}
+
+// ReadFrom appends the data read from src until EOF, implementing
+// io.ReaderFrom. It returns the number of bytes read and any error other
+// than EOF.
+func (r *Rope) ReadFrom(src io.Reader) (int64, error) {
+	var total int64
+	buf := make([]byte, maxChunk)
+	for {
+		n, err := src.Read(buf)
+		r.root = appendTo(r, r.root, buf[:n])
+		total += int64(n)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// WriteTo writes the whole text to w chunk by chunk, without assembling it,
+// implementing io.WriterTo
+func (r *Rope) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	var err error
+	ascend(r.root, func(chunk []byte) bool {
+		var n int
+		n, err = w.Write(chunk)
+		total += int64(n)
+		return err == nil
+	})
+	return total, err
+}
+
+// Reader returns a reader of the text from the start. The rope must not be
+// modified while the reader is in use.
+func (r *Rope) Reader() *Reader {
+	rd := &Reader{}
+	rd.descend(r.root)
+	return rd
+}
+
+// Reader reads the text of a rope, implementing io.Reader, io.ByteReader and
+// io.WriterTo
+type Reader struct {
+	stack []*node // nodes whose chunk and right subtree are still to be read
+	chunk []byte  // rest of the current chunk
+}
+
+// descend pushes n and the left spine below it
+func (rd *Reader) descend(n *node) {
+	for ; n != nil; n = n.left {
+		rd.stack = append(rd.stack, n)
+	}
+}
+
+// next makes the following chunk current, reporting false at the end
+func (rd *Reader) next() bool {
+	for len(rd.chunk) == 0 {
+		if len(rd.stack) == 0 {
+			return false
+		}
+		n := rd.stack[len(rd.stack)-1]
+		rd.stack = rd.stack[:len(rd.stack)-1]
+		rd.chunk = n.chunk
+		rd.descend(n.right)
+	}
+	return true
+}
+
+// Read reads up to len(p) bytes into p
+func (rd *Reader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	total := 0
+	for total < len(p) && rd.next() {
+		n := copy(p[total:], rd.chunk)
+		rd.chunk = rd.chunk[n:]
+		total += n
+	}
+	if total == 0 {
+		return 0, io.EOF
+	}
+	return total, nil
+}
+
+// ReadByte reads the next byte
+func (rd *Reader) ReadByte() (byte, error) {
+	if !rd.next() {
+		return 0, io.EOF
+	}
+	c := rd.chunk[0]
+	rd.chunk = rd.chunk[1:]
+	return c, nil
+}
+
+// WriteTo writes the rest of the text to w
+func (rd *Reader) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for rd.next() {
+		n, err := w.Write(rd.chunk)
+		rd.chunk = rd.chunk[n:]
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
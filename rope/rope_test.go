@@ -0,0 +1,115 @@
+package rope
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func randomText(rng *rand.Rand, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + rng.Intn(26))
+	}
+	return string(b)
+}
+
+// TestAgainstString checks the rope against a string under random edits,
+// with texts long enough to span many chunks
+func TestAgainstString(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	r := FromString(randomText(rng, 5000))
+	want := r.String()
+
+	for i := 0; i < 2000; i++ {
+		x, y := rng.Intn(len(want)+1), rng.Intn(len(want)+1)
+		x, y = min(x, y), max(x, y)
+
+		switch rng.Intn(4) {
+		case 0:
+			text := randomText(rng, rng.Intn(300))
+			r.Insert(x, text)
+			want = want[:x] + text + want[x:]
+		case 1:
+			r.Delete(x, y)
+			want = want[:x] + want[y:]
+		case 2:
+			tail := r.Split(x)
+			if tail.String() != want[x:] {
+				t.Fatalf("Split(%d) returned the wrong tail", x)
+			}
+			r.Concat(tail)
+			if tail.Len() != 0 {
+				t.Fatalf("Concat left %d bytes in its argument", tail.Len())
+			}
+		default:
+			if s := r.Slice(x, y); s != want[x:y] {
+				t.Fatalf("Slice(%d, %d) = %q, want %q", x, y, s, want[x:y])
+			}
+		}
+
+		if r.Len() != len(want) {
+			t.Fatalf("got length %d, want %d", r.Len(), len(want))
+		}
+		if len(want) > 0 {
+			if j := rng.Intn(len(want)); r.At(j) != want[j] {
+				t.Fatalf("At(%d) = %q, want %q", j, r.At(j), want[j])
+			}
+		}
+	}
+	if r.String() != want {
+		t.Fatal("text differs from the model after all edits")
+	}
+}
+
+func TestIO(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	text := randomText(rng, 20000)
+
+	r := New()
+	if n, err := r.ReadFrom(iotest.HalfReader(strings.NewReader(text[:10000]))); n != 10000 || err != nil {
+		t.Fatalf("ReadFrom = %d, %v, want 10000", n, err)
+	}
+	r.WriteString(text[10000:15000])
+	r.Write([]byte(text[15000:]))
+
+	var buf bytes.Buffer
+	if n, err := r.WriteTo(&buf); n != 20000 || err != nil || buf.String() != text {
+		t.Fatalf("WriteTo = %d, %v, want the whole text", n, err)
+	}
+
+	got, err := io.ReadAll(iotest.OneByteReader(r.Reader()))
+	if err != nil || string(got) != text {
+		t.Fatalf("Reader read %d bytes with %v, want the whole text", len(got), err)
+	}
+
+	rd := r.Reader()
+	for i := 0; i < 100; i++ {
+		if c, err := rd.ReadByte(); err != nil || c != text[i] {
+			t.Fatalf("ReadByte = %q, %v, want %q", c, err, text[i])
+		}
+	}
+	buf.Reset()
+	if _, err := rd.WriteTo(&buf); err != nil || buf.String() != text[100:] {
+		t.Fatal("Reader.WriteTo did not write the rest of the text")
+	}
+	if _, err := rd.ReadByte(); err != io.EOF {
+		t.Fatalf("got %v reading past the end, want io.EOF", err)
+	}
+
+	if err := iotest.TestReader(r.Reader(), []byte(text)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Delete(2, 1) did not panic")
+		}
+	}()
+	FromString("abc").Delete(2, 1)
+}
@@ -0,0 +1,70 @@
+package rtree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hanyangtay/go-datastructures/internal/wire"
+)
+
+// ErrInvalidData is returned when decoding data that is not an encoded tree
+var ErrInvalidData = errors.New("rtree: invalid encoding")
+
+// encodingVersion is the version of the layout written by MarshalBinary
+const encodingVersion = 1
+
+// payload is the content of an encoded tree
+type payload[T Object] struct {
+	MinBranch, MaxBranch int
+	Split                SplitStrategy
+	Objects              []T
+}
+
+// MarshalBinary encodes the branching and split strategy of the tree and its
+// objects, the latter with encoding/gob, which T must support as pointers to
+// structs with exported fields such as *RTreePoint do
+func (tree *Rtree[T]) MarshalBinary() ([]byte, error) {
+	p := payload[T]{MinBranch: tree.MinBranch, MaxBranch: tree.MaxBranch, Split: tree.Split}
+	p.Objects = tree.appendObjects(tree.Root, make([]T, 0, tree.Size))
+	return wire.Marshal("rtree.Rtree", encodingVersion, &p)
+}
+
+// appendObjects appends the objects of the subtree of n to objs, leaf by
+// leaf
+func (tree *Rtree[T]) appendObjects(n *rTreeNode[T], objs []T) []T {
+	for _, e := range n.entries {
+		if n.isLeaf {
+			objs = append(objs, e.obj)
+		} else {
+			objs = tree.appendObjects(e.child, objs)
+		}
+	}
+	return objs
+}
+
+// UnmarshalBinary replaces the tree with one encoded by MarshalBinary,
+// keeping its Metrics, Strict and arena. The objects are inserted one by
+// one, so the nodes may be laid out differently from the encoded tree, but
+// queries return the same objects. ErrInvalidData is returned if data is
+// malformed.
+func (tree *Rtree[T]) UnmarshalBinary(data []byte) error {
+	var p payload[T]
+	if err := wire.Unmarshal(data, "rtree.Rtree", encodingVersion, &p); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	if p.MaxBranch < 2 || p.MinBranch < 0 || p.MinBranch > p.MaxBranch {
+		return ErrInvalidData
+	}
+	if p.Split != QuadraticSplit && p.Split != LinearSplit {
+		return ErrInvalidData
+	}
+
+	tree.MinBranch, tree.MaxBranch, tree.Split = p.MinBranch, p.MaxBranch, p.Split
+	tree.Release()
+	for _, obj := range p.Objects {
+		if err := tree.Insert(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
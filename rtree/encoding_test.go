@@ -0,0 +1,85 @@
+package rtree
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/hanyangtay/go-datastructures/internal/wire"
+)
+
+func TestEncoding(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	a := New[*RTreePoint](WithBranching(3, 7), WithSplit(LinearSplit))
+	points := randomPoints(300, rng)
+	for _, p := range points {
+		a.Insert(p)
+	}
+
+	data, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := New[*RTreePoint](WithStrict())
+	if err := b.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if b.MinBranch != 3 || b.MaxBranch != 7 || b.Split != LinearSplit || !b.Strict {
+		t.Fatalf("decoded branching %d to %d, split %d and strict %t", b.MinBranch, b.MaxBranch, b.Split, b.Strict)
+	}
+	if err := b.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// decoded objects are copies, so compare coordinates
+	for i := 0; i < 20; i++ {
+		corners := randomPoints(2, rng)
+		bb := NewRect(corners[0], corners[1])
+		got, want := coordinates(b.SearchIntersect(bb)), coordinates(a.SearchIntersect(bb))
+		if len(got) != len(want) {
+			t.Fatalf("decoded SearchIntersect(%v) found %d points, want %d", bb, len(got), len(want))
+		}
+		for p := range want {
+			if !got[p] {
+				t.Fatalf("decoded SearchIntersect(%v) did not find %v", bb, p)
+			}
+		}
+	}
+}
+
+func coordinates(points []*RTreePoint) map[RTreePoint]bool {
+	m := make(map[RTreePoint]bool, len(points))
+	for _, p := range points {
+		m[*p] = true
+	}
+	return m
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	a := NewTree[*RTreePoint](2, 4)
+	a.Insert(&RTreePoint{X: 1, Y: 2})
+	data, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, bad := range map[string][]byte{
+		"empty":      nil,
+		"truncated":  data[:len(data)-3],
+		"headerless": data[len("GDSENC"):],
+	} {
+		b := NewTree[*RTreePoint](2, 4)
+		if err := b.UnmarshalBinary(bad); !errors.Is(err, ErrInvalidData) {
+			t.Errorf("%s: got %v, want ErrInvalidData", name, err)
+		}
+	}
+
+	var bad payload[*RTreePoint]
+	bad.MinBranch, bad.MaxBranch = 5, 4
+	if data, err = wire.Marshal("rtree.Rtree", encodingVersion, &bad); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewTree[*RTreePoint](2, 4).UnmarshalBinary(data); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("branching 5 to 4: got %v, want ErrInvalidData", err)
+	}
+}
@@ -0,0 +1,88 @@
+package rtree
+
+import (
+	"github.com/hanyangtay/go-datastructures/internal/arena"
+	"github.com/hanyangtay/go-datastructures/metrics"
+)
+
+// SplitStrategy selects how an Rtree splits a node that overflows
+type SplitStrategy int
+
+const (
+	// QuadraticSplit compares every pair of entries to pick the two to
+	// split around, and then assigns the entry with the strongest
+	// preference first. It is the default, and yields tighter nodes.
+	QuadraticSplit SplitStrategy = iota
+
+	// LinearSplit picks the two entries lying furthest apart along an axis
+	// and assigns the rest in order, so splits take time linear in
+	// MaxBranch. It suits trees with a high MaxBranch and frequent inserts.
+	LinearSplit
+)
+
+// Default branching of a tree created by New without WithBranching
+const (
+	defaultMinBranch = 6
+	defaultMaxBranch = 16
+)
+
+// Option configures a tree created by New
+type Option func(c *config)
+
+type config struct {
+	minBranch, maxBranch int
+	split                SplitStrategy
+	strict               bool
+	metrics              metrics.Metrics
+	arena                bool
+}
+
+// WithBranching sets the minimum and maximum number of entries of a node,
+// other than the root. The minimum should be at most half the maximum.
+func WithBranching(minBranch, maxBranch int) Option {
+	return func(c *config) {
+		c.minBranch = minBranch
+		c.maxBranch = maxBranch
+	}
+}
+
+// WithSplit sets the strategy for splitting overflowing nodes
+func WithSplit(strategy SplitStrategy) Option {
+	return func(c *config) { c.split = strategy }
+}
+
+// WithStrict makes the tree panic when it finds itself inconsistent, rather
+// than returning ErrInconsistent
+func WithStrict() Option {
+	return func(c *config) { c.strict = true }
+}
+
+// WithMetrics makes the tree report its operations to m
+func WithMetrics(m metrics.Metrics) Option {
+	return func(c *config) { c.metrics = m }
+}
+
+// WithArena makes the tree allocate its nodes in slabs, which spares the
+// garbage collector much of the work of tracking a large tree, and lets
+// Release free them together
+func WithArena() Option {
+	return func(c *config) { c.arena = true }
+}
+
+// New returns an empty tree configured by opts. Without options, nodes hold
+// 6 to 16 entries and are split quadratically.
+func New[T Object](opts ...Option) *Rtree[T] {
+	c := config{minBranch: defaultMinBranch, maxBranch: defaultMaxBranch}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	tree := NewTree[T](c.minBranch, c.maxBranch)
+	tree.Split = c.split
+	tree.Strict = c.strict
+	tree.Metrics = c.metrics
+	if c.arena {
+		tree.nodes = &arena.Arena[rTreeNode[T]]{}
+	}
+	return tree
+}
@@ -0,0 +1,40 @@
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tree := New[*RTreePoint]()
+	if tree.MinBranch != defaultMinBranch || tree.MaxBranch != defaultMaxBranch {
+		t.Fatalf("got branching %d to %d, want %d to %d", tree.MinBranch, tree.MaxBranch, defaultMinBranch, defaultMaxBranch)
+	}
+	if tree.Split != QuadraticSplit || tree.Strict {
+		t.Fatalf("got split %d and strict %t, want quadratic and not strict", tree.Split, tree.Strict)
+	}
+
+	tree = New[*RTreePoint](WithBranching(3, 7), WithSplit(LinearSplit), WithStrict())
+	if tree.MinBranch != 3 || tree.MaxBranch != 7 || tree.Split != LinearSplit || !tree.Strict {
+		t.Fatalf("options were not applied: %+v", tree)
+	}
+}
+
+func TestSplitStrategies(t *testing.T) {
+	for _, split := range []SplitStrategy{QuadraticSplit, LinearSplit} {
+		rng := rand.New(rand.NewSource(1))
+		tree := New[*RTreePoint](WithBranching(2, 6), WithSplit(split))
+		points := randomPoints(500, rng)
+		for _, p := range points {
+			if err := tree.Insert(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, n := range tree.Root.entries {
+			if k := len(n.child.entries); k < 2 || k > 6 {
+				t.Fatalf("split %d: got a node of %d entries", split, k)
+			}
+		}
+		checkQueries(t, tree, points, rng)
+	}
+}
@@ -8,31 +8,59 @@ Useful for querying nearest neighbours of an object.
 package rtree
 
 import (
+	"errors"
 	"math"
+	"sync"
+
+	"github.com/hanyangtay/go-datastructures/internal/arena"
+	"github.com/hanyangtay/go-datastructures/metrics"
 )
 
+// ErrInconsistent is returned when a node is missing from the entries of its
+// parent, or by Validate, which only happens to a tree corrupted by
+// unsynchronised concurrent use or by changes to an object's bounding box
+// while stored
+var ErrInconsistent = errors.New("rtree: tree is inconsistent")
+
 // Rtree represents the balanced search tree for storing and querying 2D data.
-type Rtree struct {
+// The objects stored are of type T, so queries return them without type
+// assertions.
+type Rtree[T Object] struct {
 	MinBranch int
 	MaxBranch int
-	Root      *rTreeNode
+	Root      *rTreeNode[T]
 	Size      int
 	Height    int
+
+	// Split selects how overflowing nodes are split
+	Split SplitStrategy
+
+	// Metrics, if set, receives counts of the tree operations and of the
+	// nodes visited by queries
+	Metrics metrics.Metrics
+
+	// Strict makes Insert and Delete panic with ErrInconsistent instead of
+	// returning it
+	Strict bool
+
+	nodes *arena.Arena[rTreeNode[T]] // nil to allocate nodes one by one
+
+	knnQueues sync.Pool // search queues of KNNInto, for reuse
 }
 
 // node represents a tree node of an R tree, which contains multiple entries
-type rTreeNode struct {
-	parent  *rTreeNode
+type rTreeNode[T Object] struct {
+	parent  *rTreeNode[T]
 	isLeaf  bool
-	entries []entry
+	entries []entry[T]
 	level   int
 }
 
 // entry represents a spatial index record stored in a tree node
-type entry struct {
+type entry[T Object] struct {
 	bb    *Rect // bounding-box of all children of this entry
-	child *rTreeNode
-	obj   Spatial
+	child *rTreeNode[T]
+	obj   T
 }
 
 // any spatial object can fulfill this interface - e.g. point, line, rectangle
@@ -44,13 +72,20 @@ type Spatial interface {
 	SquaredDist(*Rect) float64
 }
 
+// Object is the constraint on the objects of an Rtree: they are Spatial and
+// comparable, so that Delete can find them, as pointers to a struct are
+type Object interface {
+	Spatial
+	comparable
+}
+
 // NewTree initialises a new R-tree with a specified min and max number of branches.
-func NewTree(MinBranch, MaxBranch int) *Rtree {
-	return &Rtree{
+func NewTree[T Object](MinBranch, MaxBranch int) *Rtree[T] {
+	return &Rtree[T]{
 		MinBranch: MinBranch,
 		MaxBranch: MaxBranch,
-		Root: &rTreeNode{
-			entries: make([]entry, 0, MaxBranch),
+		Root: &rTreeNode[T]{
+			entries: make([]entry[T], 0, MaxBranch),
 			isLeaf:  true,
 			level:   1,
 		},
@@ -59,18 +94,55 @@ func NewTree(MinBranch, MaxBranch int) *Rtree {
 	}
 }
 
+// newNode returns an empty node, from the arena if the tree has one
+func (tree *Rtree[T]) newNode() *rTreeNode[T] {
+	if tree.nodes == nil {
+		return &rTreeNode[T]{}
+	}
+	return tree.nodes.Alloc()
+}
+
+// Release removes all objects at once, and with WithArena the nodes holding
+// them
+func (tree *Rtree[T]) Release() {
+	if tree.nodes != nil {
+		tree.nodes.Release()
+	}
+	tree.Root = tree.newNode()
+	tree.Root.entries = make([]entry[T], 0, tree.MaxBranch)
+	tree.Root.isLeaf = true
+	tree.Root.level = 1
+	tree.Size = 0
+	tree.Height = 1
+}
+
 /* Insertion */
 
 // Insert inserts a spatial object into the tree.
 // Tree is rebalanced if a leaf node overflows.
-func (tree *Rtree) Insert(obj Spatial) {
-	e := entry{obj.ToRect(), nil, obj}
-	tree.insert(e, 1)
-	tree.Size++
+// If the tree turns out to be corrupt it returns ErrInconsistent, or panics
+// with it if tree.Strict is set. Debug builds validate the tree afterwards.
+func (tree *Rtree[T]) Insert(obj T) error {
+	e := entry[T]{bb: obj.ToRect(), obj: obj}
+	err := tree.insert(e, 1)
+	if err == nil {
+		tree.Size++
+		metrics.Or(tree.Metrics).Count("rtree_inserts_total", 1)
+	}
+	return tree.afterMutation(err)
+}
+
+// check panics with err if it is set and the tree is strict, and returns it
+// otherwise
+func (tree *Rtree[T]) check(err error) error {
+	if err != nil && tree.Strict {
+		panic(err)
+	}
+	return err
 }
 
 // insert adds specified entry to the tree at the specified level
-func (tree *Rtree) insert(e entry, level int) {
+func (tree *Rtree[T]) insert(e entry[T], level int) error {
 	leaf := tree.chooseNode(tree.Root, e, level)
 	leaf.entries = append(leaf.entries, e)
 
@@ -80,17 +152,18 @@ func (tree *Rtree) insert(e entry, level int) {
 	}
 
 	// split leaf if it overflows
-	var split *rTreeNode
+	var split *rTreeNode[T]
 	if len(leaf.entries) > tree.MaxBranch {
-		leaf, split = leaf.split(tree.MinBranch)
+		leaf, split = tree.split(leaf)
 	}
 
 	// adjusts the tree and rebalances if necessary
-	_, _ = tree.adjustTree(leaf, split)
+	_, _, err := tree.adjustTree(leaf, split)
+	return err
 }
 
 // chooseNode finds the node at the specified level to which e should be added
-func (tree *Rtree) chooseNode(n *rTreeNode, e entry, level int) *rTreeNode {
+func (tree *Rtree[T]) chooseNode(n *rTreeNode[T], e entry[T], level int) *rTreeNode[T] {
 
 	if n.isLeaf || n.level == level {
 		return n
@@ -98,7 +171,7 @@ func (tree *Rtree) chooseNode(n *rTreeNode, e entry, level int) *rTreeNode {
 
 	// find the entry whose bb needs least enlargement to include obj
 	leastDiff := math.MaxFloat64
-	var chosen entry
+	var chosen entry[T]
 	var bb Rect
 	for _, e2 := range n.entries {
 		initBoundingBox(&bb, e2.bb, e.bb)
@@ -115,32 +188,36 @@ func (tree *Rtree) chooseNode(n *rTreeNode, e entry, level int) *rTreeNode {
 }
 
 // adjustTree splits overflowing nodes and propagates the changes upwards
-func (tree *Rtree) adjustTree(leaf, split *rTreeNode) (*rTreeNode, *rTreeNode) {
+func (tree *Rtree[T]) adjustTree(leaf, split *rTreeNode[T]) (*rTreeNode[T], *rTreeNode[T], error) {
 
 	// edge case: handle Root adjustments
 	if leaf == tree.Root {
 		if split != nil {
 			tree.Height++
-			tree.Root = &rTreeNode{
+			tree.Root = tree.newNode()
+			*tree.Root = rTreeNode[T]{
 				parent: nil,
 				isLeaf: false,
 				level:  tree.Height,
-				entries: []entry{
-					entry{bb: leaf.computeBoundingBox(), child: leaf},
-					entry{bb: split.computeBoundingBox(), child: split},
+				entries: []entry[T]{
+					entry[T]{bb: leaf.computeBoundingBox(), child: leaf},
+					entry[T]{bb: split.computeBoundingBox(), child: split},
 				},
 			}
 			leaf.parent = tree.Root
 			split.parent = tree.Root
 
-			return leaf, split
+			return leaf, split, nil
 		} else {
-			return nil, nil
+			return nil, nil, nil
 		}
 	}
 
 	// resize the bounding box of n from lower level changes
 	e := leaf.getEntry()
+	if e == nil {
+		return nil, nil, ErrInconsistent
+	}
 	e.bb = leaf.computeBoundingBox()
 
 	// if no split, just propagate changes upwards
@@ -149,31 +226,31 @@ func (tree *Rtree) adjustTree(leaf, split *rTreeNode) (*rTreeNode, *rTreeNode) {
 	}
 
 	// leaf was used as the "left" node, but need to add split to leaf's parent
-	new_entry := entry{split.computeBoundingBox(), split, nil}
+	new_entry := entry[T]{bb: split.computeBoundingBox(), child: split}
 	leaf.parent.entries = append(leaf.parent.entries, new_entry)
 
 	// if split entry overflows parent, split parent and propagate
 	if len(leaf.parent.entries) > tree.MaxBranch {
-		return tree.adjustTree(leaf.parent.split(tree.MinBranch))
+		return tree.adjustTree(tree.split(leaf.parent))
 	}
 
 	// otherwise continue to propagate changes upwards
 	return tree.adjustTree(leaf.parent, nil)
 }
 
-// getEntry returns a pointer to the entry for the node n from n's parent
-func (n *rTreeNode) getEntry() *entry {
+// getEntry returns a pointer to the entry for the node n from n's parent,
+// or nil if the parent has none
+func (n *rTreeNode[T]) getEntry() *entry[T] {
 	for i := range n.parent.entries {
 		if n.parent.entries[i].child == n {
 			return &n.parent.entries[i]
 		}
 	}
-	panic("getEntry returns nil pointer!")
 	return nil
 }
 
 // computeBoundingBox finds the bb of the children of n
-func (n *rTreeNode) computeBoundingBox() *Rect {
+func (n *rTreeNode[T]) computeBoundingBox() *Rect {
 	var bb Rect
 	for i, e := range n.entries {
 		if i == 0 {
@@ -187,10 +264,13 @@ func (n *rTreeNode) computeBoundingBox() *Rect {
 
 // split splits a node into two groups while attempting to minimise the
 // bounding box area of the split groups
-func (n *rTreeNode) split(minBranch int) (left, right *rTreeNode) {
+func (tree *Rtree[T]) split(n *rTreeNode[T]) (left, right *rTreeNode[T]) {
 
 	// finds the initial split
 	l, r := n.pickSeeds()
+	if tree.Split == LinearSplit {
+		l, r = n.pickSeedsLinear()
+	}
 	leftSeed, rightSeed := n.entries[l], n.entries[r]
 
 	// get remaining entries to be divided between left and right
@@ -199,13 +279,14 @@ func (n *rTreeNode) split(minBranch int) (left, right *rTreeNode) {
 
 	// initialise new split nodes (reuse n as left node)
 	left = n
-	left.entries = []entry{leftSeed}
+	left.entries = []entry[T]{leftSeed}
 
-	right = &rTreeNode{
+	right = tree.newNode()
+	*right = rTreeNode[T]{
 		parent:  n.parent,
 		isLeaf:  n.isLeaf,
 		level:   n.level,
-		entries: []entry{rightSeed},
+		entries: []entry[T]{rightSeed},
 	}
 
 	if rightSeed.child != nil {
@@ -217,7 +298,7 @@ func (n *rTreeNode) split(minBranch int) (left, right *rTreeNode) {
 	}
 
 	// distribute remaining entries into left and right split nodes
-	assignGroup(remaining, left, right, minBranch)
+	assignGroup(remaining, left, right, tree.MinBranch, tree.Split == LinearSplit)
 
 	return
 }
@@ -225,7 +306,7 @@ func (n *rTreeNode) split(minBranch int) (left, right *rTreeNode) {
 // pickSeeds chooses two child entries of n to start a split
 // by choosing the entries that result in least overlap
 // i.e. has greatest waste of space
-func (n *rTreeNode) pickSeeds() (int, int) {
+func (n *rTreeNode[T]) pickSeeds() (int, int) {
 	left, right := 0, 1
 	maxWastedSpace := -1.0
 	var bb Rect
@@ -243,16 +324,63 @@ func (n *rTreeNode) pickSeeds() (int, int) {
 	return left, right
 }
 
+// pickSeedsLinear chooses two child entries of n to start a split in linear
+// time: the pair lying furthest apart along either axis, relative to the
+// extent of all entries along it
+func (n *rTreeNode[T]) pickSeedsLinear() (int, int) {
+	left, right := 0, 1
+	bestSeparation := math.Inf(-1)
+
+	axes := []func(p RTreePoint) float64{
+		func(p RTreePoint) float64 { return p.X },
+		func(p RTreePoint) float64 { return p.Y },
+	}
+	for _, coord := range axes {
+
+		// the entry with the highest low side, and the one with the lowest
+		// high side
+		highLow, lowHigh := 0, 0
+		minLow, maxHigh := math.Inf(1), math.Inf(-1)
+		for i, e := range n.entries {
+			low, high := coord(e.bb.bottomLeft), coord(e.bb.topRight)
+			if low > coord(n.entries[highLow].bb.bottomLeft) {
+				highLow = i
+			}
+			if high < coord(n.entries[lowHigh].bb.topRight) {
+				lowHigh = i
+			}
+			minLow = math.Min(minLow, low)
+			maxHigh = math.Max(maxHigh, high)
+		}
+		if highLow == lowHigh {
+			continue
+		}
+
+		separation := coord(n.entries[highLow].bb.bottomLeft) - coord(n.entries[lowHigh].bb.topRight)
+		if width := maxHigh - minLow; width > 0 {
+			separation /= width
+		}
+		if separation > bestSeparation {
+			bestSeparation = separation
+			left, right = min(highLow, lowHigh), max(highLow, lowHigh)
+		}
+	}
+
+	return left, right
+}
+
 // assign adds an entry to a split node
-func assign(e entry, splitNode *rTreeNode) {
+func assign[T Object](e entry[T], splitNode *rTreeNode[T]) {
 	if e.child != nil {
 		e.child.parent = splitNode
 	}
 	splitNode.entries = append(splitNode.entries, e)
 }
 
-// assignGroup adds entries to either of the two split nodes
-func assignGroup(remaining []entry, left, right *rTreeNode, minBranch int) {
+// assignGroup adds entries to either of the two split nodes. If inOrder is
+// set the entries are taken as they come, else the one with the strongest
+// preference for either node is assigned first.
+func assignGroup[T Object](remaining []entry[T], left, right *rTreeNode[T], minBranch int, inOrder bool) {
 
 	var nextIdx int
 	var bestLeftDiff, bestRightDiff float64
@@ -264,7 +392,12 @@ func assignGroup(remaining []entry, left, right *rTreeNode, minBranch int) {
 		leftBB := left.computeBoundingBox()
 		rightBB := right.computeBoundingBox()
 
-		for i, e := range remaining {
+		candidates := remaining
+		if inOrder {
+			candidates = remaining[:1]
+		}
+
+		for i, e := range candidates {
 			leftDiff := boundingBox(leftBB, e.bb).size - leftBB.size
 			rightDiff := boundingBox(rightBB, e.bb).size - rightBB.size
 			diff := math.Abs(leftDiff - rightDiff)
@@ -312,13 +445,14 @@ func assignGroup(remaining []entry, left, right *rTreeNode, minBranch int) {
 
 /* Deletion */
 
-// Delete removes an object from the tree.
-// Returns true if object is not found, or false if it's a no-op
-func (tree *Rtree) Delete(obj Spatial) bool {
+// Delete removes an object from the tree and reports whether it was found.
+// If the tree turns out to be corrupt it returns ErrInconsistent, or panics
+// with it if tree.Strict is set. Debug builds validate the tree afterwards.
+func (tree *Rtree[T]) Delete(obj T) (bool, error) {
 	n := tree.findLeaf(tree.Root, obj)
 
 	if n == nil {
-		return false
+		return false, nil
 	}
 
 	idx := -1
@@ -328,24 +462,29 @@ func (tree *Rtree) Delete(obj Spatial) bool {
 		}
 	}
 	if idx == -1 {
-		return false
+		return false, nil
 	}
 
 	n.entries = append(n.entries[:idx], n.entries[idx+1:]...)
 
-	tree.condenseTree(n)
-	tree.Size--
+	err := tree.condenseTree(n)
+	if err == nil {
+		tree.Size--
+		metrics.Or(tree.Metrics).Count("rtree_deletes_total", 1)
+	}
 
 	// edge case: only Root is left and it's not a leaf node
-	if !tree.Root.isLeaf && len(tree.Root.entries) == 1 {
+	for !tree.Root.isLeaf && len(tree.Root.entries) == 1 {
 		tree.Root = tree.Root.entries[0].child
+		tree.Root.parent = nil
+		tree.Height--
 	}
 
-	return true
+	return true, tree.afterMutation(err)
 }
 
 // findLeaf finds the leaf node containing obj
-func (tree *Rtree) findLeaf(n *rTreeNode, obj Spatial) *rTreeNode {
+func (tree *Rtree[T]) findLeaf(n *rTreeNode[T], obj T) *rTreeNode[T] {
 	if n.isLeaf {
 		return n
 	}
@@ -368,14 +507,14 @@ func (tree *Rtree) findLeaf(n *rTreeNode, obj Spatial) *rTreeNode {
 }
 
 // condenseTree deletes underflowing nodes and propagates changes upwards
-func (tree *Rtree) condenseTree(n *rTreeNode) {
-	deletedNodes := []*rTreeNode{}
+func (tree *Rtree[T]) condenseTree(n *rTreeNode[T]) error {
+	deletedNodes := []*rTreeNode[T]{}
 
 	for n != tree.Root {
 		if len(n.entries) < tree.MinBranch {
 
 			// remove n from parent entries
-			entries := []entry{}
+			entries := []entry[T]{}
 			for _, e := range n.parent.entries {
 				if e.child != n {
 					entries = append(entries, e)
@@ -383,7 +522,7 @@ func (tree *Rtree) condenseTree(n *rTreeNode) {
 			}
 
 			if len(n.parent.entries) == len(entries) {
-				panic("RTree Delete: Failed to remove entry from parent.")
+				return ErrInconsistent
 			}
 			n.parent.entries = entries
 
@@ -393,15 +532,23 @@ func (tree *Rtree) condenseTree(n *rTreeNode) {
 			}
 		} else {
 			// child entry deletion, no underflow
-			n.getEntry().bb = n.computeBoundingBox()
+			e := n.getEntry()
+			if e == nil {
+				return ErrInconsistent
+			}
+			e.bb = n.computeBoundingBox()
 		}
 
 		n = n.parent
 	}
 
 	for _, n := range deletedNodes {
-		// reinsert entry at the same level
-		entry := entry{n.computeBoundingBox(), n, nil}
-		tree.insert(entry, n.level+1)
+		// reinsert the entries of n at its level, so that no node underflows
+		for _, e := range n.entries {
+			if err := tree.insert(e, n.level); err != nil {
+				return err
+			}
+		}
 	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package rtree
 
 import (
+	"fmt"
 	"math"
 )
 
@@ -44,7 +45,7 @@ func (n *RTreePoint) SquaredDist(r *Rect) float64 {
 
 // Dist returns the Euclidean distance of a point to a rectangle
 func (n *RTreePoint) Dist(r *Rect) float64 {
-	return math.sqrt(n.SquaredDist(r))
+	return math.Sqrt(n.SquaredDist(r))
 }
 
 // NewRect initialises a new rectangle from two points
@@ -66,11 +67,16 @@ func NewRect(u, v *RTreePoint) *Rect {
 	}
 }
 
+// String returns the corners of r as [(x1, y1), (x2, y2)]
+func (r *Rect) String() string {
+	return fmt.Sprintf("[(%v, %v), (%v, %v)]", r.bottomLeft.X, r.bottomLeft.Y, r.topRight.X, r.topRight.Y)
+}
+
 // containsRect tests whether r2 is located inside r1
 func (r1 *Rect) containsRect(r2 *Rect) bool {
 	if r1.bottomLeft.Y > r2.bottomLeft.Y || r1.bottomLeft.X > r2.bottomLeft.X {
 		return false
-	} else if r1.topRight.Y < r2.topRight.Y || r1.topRight.X < r1.topRight.X {
+	} else if r1.topRight.Y < r2.topRight.Y || r1.topRight.X < r2.topRight.X {
 		return false
 	}
 
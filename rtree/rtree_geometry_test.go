@@ -0,0 +1,84 @@
+package rtree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDist(t *testing.T) {
+	r := NewRect(&RTreePoint{X: 3, Y: 1}, &RTreePoint{X: 1, Y: 2})
+	tests := []struct {
+		p    RTreePoint
+		want float64
+	}{
+		{RTreePoint{X: 2, Y: 1.5}, 0},
+		{RTreePoint{X: 1, Y: 1}, 0},
+		{RTreePoint{X: 0, Y: 1.5}, 1},
+		{RTreePoint{X: 2, Y: 4}, 2},
+		{RTreePoint{X: 6, Y: -3}, 5},
+	}
+	for _, tt := range tests {
+		if d := tt.p.Dist(r); math.Abs(d-tt.want) > 1e-12 {
+			t.Errorf("Dist(%v, %v) = %v, want %v", tt.p, r, d, tt.want)
+		}
+		if d := tt.p.SquaredDist(r); math.Abs(d-tt.want*tt.want) > 1e-12 {
+			t.Errorf("SquaredDist(%v, %v) = %v, want %v", tt.p, r, d, tt.want*tt.want)
+		}
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	r := NewRect(&RTreePoint{X: 0, Y: 0}, &RTreePoint{X: 2, Y: 2})
+	tests := []struct {
+		other *Rect
+		want  bool
+	}{
+		{NewRect(&RTreePoint{X: 1, Y: 1}, &RTreePoint{X: 3, Y: 3}), true},
+		{NewRect(&RTreePoint{X: 2, Y: 0}, &RTreePoint{X: 3, Y: 1}), true},
+		{NewRect(&RTreePoint{X: -1, Y: -1}, &RTreePoint{X: 3, Y: 3}), true},
+		{NewRect(&RTreePoint{X: 2.5, Y: 0}, &RTreePoint{X: 3, Y: 1}), false},
+		{NewRect(&RTreePoint{X: 0, Y: -2}, &RTreePoint{X: 1, Y: -1}), false},
+	}
+	for _, tt := range tests {
+		if got := intersect(r, tt.other); got != tt.want {
+			t.Errorf("intersect(%v, %v) = %t, want %t", r, tt.other, got, tt.want)
+		}
+		if got := intersect(tt.other, r); got != tt.want {
+			t.Errorf("intersect(%v, %v) = %t, want %t", tt.other, r, got, tt.want)
+		}
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	a := NewRect(&RTreePoint{X: 0, Y: 1}, &RTreePoint{X: 2, Y: 2})
+	b := NewRect(&RTreePoint{X: 1, Y: -1}, &RTreePoint{X: 4, Y: 0})
+	bb := boundingBox(a, b)
+
+	want := NewRect(&RTreePoint{X: 0, Y: -1}, &RTreePoint{X: 4, Y: 2})
+	if *bb != *want {
+		t.Fatalf("boundingBox(%v, %v) = %v, want %v", a, b, bb, want)
+	}
+	if bb.size != 12 {
+		t.Fatalf("got size %v, want 12", bb.size)
+	}
+}
+
+func TestContainsRect(t *testing.T) {
+	r := NewRect(&RTreePoint{X: 0, Y: 0}, &RTreePoint{X: 2, Y: 2})
+	tests := []struct {
+		other *Rect
+		want  bool
+	}{
+		{NewRect(&RTreePoint{X: 0, Y: 0}, &RTreePoint{X: 2, Y: 2}), true},
+		{NewRect(&RTreePoint{X: 0.5, Y: 0.5}, &RTreePoint{X: 1, Y: 1}), true},
+		{NewRect(&RTreePoint{X: -1, Y: 0.5}, &RTreePoint{X: 1, Y: 1}), false},
+		{NewRect(&RTreePoint{X: 0.5, Y: -1}, &RTreePoint{X: 1, Y: 1}), false},
+		{NewRect(&RTreePoint{X: 0.5, Y: 0.5}, &RTreePoint{X: 3, Y: 1}), false},
+		{NewRect(&RTreePoint{X: 0.5, Y: 0.5}, &RTreePoint{X: 1, Y: 3}), false},
+	}
+	for _, tt := range tests {
+		if got := r.containsRect(tt.other); got != tt.want {
+			t.Errorf("%v.containsRect(%v) = %t, want %t", r, tt.other, got, tt.want)
+		}
+	}
+}
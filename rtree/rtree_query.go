@@ -1,24 +1,38 @@
 package rtree
 
 import (
-	"container/heap"
+	"github.com/hanyangtay/go-datastructures/metrics"
+	"github.com/hanyangtay/go-datastructures/pq"
 )
 
 /* Querying */
 
 // SearchIntersect returns all spatial objects that intersect the specified bounding box.
-func (tree *Rtree) SearchIntersect(bb *Rect) []Spatial {
-	results := []Spatial{}
-	return tree.searchIntersect(tree.Root, bb, results)
+func (tree *Rtree[T]) SearchIntersect(bb *Rect) []T {
+	return tree.SearchIntersectInto(bb, []T{})
 }
 
-func (tree *Rtree) searchIntersect(n *rTreeNode, bb *Rect, results []Spatial) []Spatial {
+// SearchIntersectInto is a variant of SearchIntersect that appends the
+// objects to buf[:0], so that a caller reusing the result between queries
+// does not allocate.
+func (tree *Rtree[T]) SearchIntersectInto(bb *Rect, buf []T) []T {
+	visited := 0
+	results := tree.searchIntersect(tree.Root, bb, buf[:0], &visited)
+
+	m := metrics.Or(tree.Metrics)
+	m.Count("rtree_queries_total", 1)
+	m.Observe("rtree_nodes_visited", float64(visited))
+	return results
+}
+
+func (tree *Rtree[T]) searchIntersect(n *rTreeNode[T], bb *Rect, results []T, visited *int) []T {
+	*visited++
 	for _, e := range n.entries {
 		if intersect(e.bb, bb) {
 			if n.isLeaf {
 				results = append(results, e.obj)
 			} else {
-				results = tree.searchIntersect(e.child, bb, results)
+				results = tree.searchIntersect(e.child, bb, results, visited)
 			}
 		}
 	}
@@ -28,58 +42,52 @@ func (tree *Rtree) searchIntersect(n *rTreeNode, bb *Rect, results []Spatial) []
 
 /* Priority queue for knn */
 
-type distRTreeNode struct {
-	rEntry entry
+type distRTreeNode[T Object] struct {
+	rEntry entry[T]
 	dist   float64
 }
 
-type priorityRQueue []*distRTreeNode
-
-func (q priorityRQueue) Len() int { return len(q) }
-
-func (q priorityRQueue) Less(i, j int) bool {
-	return q[i].dist < q[j].dist
-}
-
-func (q priorityRQueue) Swap(i, j int) {
-	q[i], q[j] = q[j], q[i]
+// KNearestNeighbours returns k nearest spatial objects and their distances
+func (tree *Rtree[T]) KNN(k int, point Spatial) []T {
+	return tree.KNNInto(k, point, make([]T, 0, k))
 }
 
-func (q *priorityRQueue) Push(n interface{}) {
-	*q = append(*q, n.(*distRTreeNode))
-}
+// KNNInto is a variant of KNN that appends the objects to buf[:0] and
+// reuses the search queue of earlier queries, so that a caller reusing the
+// result does not allocate.
+func (tree *Rtree[T]) KNNInto(k int, point Spatial, buf []T) []T {
 
-func (q *priorityRQueue) Pop() interface{} {
-	old := *q
-	n := len(old)
-	node := old[n-1]
-	*q = old[0 : n-1]
-	return node
-}
+	nearestNeighbours := buf[:0]
 
-// KNearestNeighbours returns k nearest spatial objects and their distances
-func (tree *Rtree) KNN(k int, point Spatial) []Spatial {
-
-	nearestNeighbours := make([]Spatial, 0, k)
+	Q, _ := tree.knnQueues.Get().(*pq.PriorityQueue[distRTreeNode[T]])
+	if Q == nil {
+		Q = pq.New(func(a, b distRTreeNode[T]) bool { return a.dist < b.dist })
+	}
+	defer func() {
+		Q.Reset()
+		tree.knnQueues.Put(Q)
+	}()
 
-	Q := priorityRQueue{}
 	for _, e := range tree.Root.entries {
-		newQNode := &distRTreeNode{e, point.SquaredDist(e.bb)}
-		heap.Push(&Q, newQNode)
+		Q.Push(distRTreeNode[T]{e, point.SquaredDist(e.bb)})
 	}
+	visited := 1
 
-	for len(Q) > 0 && len(nearestNeighbours) < k {
-		mid := heap.Pop(&Q).(*distRTreeNode)
+	for Q.Len() > 0 && len(nearestNeighbours) < k {
+		mid := Q.Pop()
 
-		if mid.rEntry.obj != nil {
+		if mid.rEntry.child == nil {
 			nearestNeighbours = append(nearestNeighbours, mid.rEntry.obj)
 		} else {
+			visited++
 			for _, e := range mid.rEntry.child.entries {
-				newQNode := &distRTreeNode{e, point.SquaredDist(e.bb)}
-				heap.Push(&Q, newQNode)
+				Q.Push(distRTreeNode[T]{e, point.SquaredDist(e.bb)})
 			}
 		}
 	}
 
+	m := metrics.Or(tree.Metrics)
+	m.Count("rtree_queries_total", 1)
+	m.Observe("rtree_nodes_visited", float64(visited))
 	return nearestNeighbours
 }
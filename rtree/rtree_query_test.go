@@ -0,0 +1,154 @@
+package rtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// randomPoints returns n points with coordinates in [0, 100)
+func randomPoints(n int, rng *rand.Rand) []*RTreePoint {
+	points := make([]*RTreePoint, n)
+	for i := range points {
+		points[i] = &RTreePoint{X: rng.Float64() * 100, Y: rng.Float64() * 100}
+	}
+	return points
+}
+
+// checkQueries compares random intersection and nearest neighbour queries
+// of tree with a linear scan of points, the objects it holds
+func checkQueries(t *testing.T, tree *Rtree[*RTreePoint], points []*RTreePoint, rng *rand.Rand) {
+	t.Helper()
+	for i := 0; i < 20; i++ {
+		corners := randomPoints(2, rng)
+		a, bb := corners[0], NewRect(corners[0], corners[1])
+
+		want := make(map[*RTreePoint]bool)
+		for _, p := range points {
+			if intersect(p.ToRect(), bb) {
+				want[p] = true
+			}
+		}
+		got := tree.SearchIntersect(bb)
+		if len(got) != len(want) {
+			t.Fatalf("SearchIntersect(%v) found %d points, want %d", bb, len(got), len(want))
+		}
+		for _, p := range got {
+			if !want[p] {
+				t.Fatalf("SearchIntersect(%v) found %v outside it", bb, p)
+			}
+		}
+
+		k := rng.Intn(10) + 1
+		dists := make([]float64, len(points))
+		for j, p := range points {
+			dists[j] = a.SquaredDist(p.ToRect())
+		}
+		sort.Float64s(dists)
+		if k > len(dists) {
+			k = len(dists)
+		}
+
+		nearest := tree.KNN(k, a)
+		if len(nearest) != k {
+			t.Fatalf("KNN(%d) found %d points", k, len(nearest))
+		}
+		for j, p := range nearest {
+			if d := a.SquaredDist(p.ToRect()); d != dists[j] {
+				t.Fatalf("neighbour %d of %v is at squared distance %v, want %v", j, a, d, dists[j])
+			}
+		}
+	}
+}
+
+func TestQueries(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, branching := range [][2]int{{2, 4}, {3, 8}, {6, 16}} {
+		tree := NewTree[*RTreePoint](branching[0], branching[1])
+		var points []*RTreePoint
+		for _, p := range randomPoints(500, rng) {
+			if err := tree.Insert(p); err != nil {
+				t.Fatal(err)
+			}
+			points = append(points, p)
+			if len(points)%100 == 1 {
+				checkQueries(t, tree, points, rng)
+			}
+		}
+		if tree.Size != len(points) {
+			t.Fatalf("got size %d, want %d", tree.Size, len(points))
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	tree := NewTree[*RTreePoint](2, 4)
+	bb := NewRect(&RTreePoint{X: 0, Y: 0}, &RTreePoint{X: 1, Y: 1})
+	if got := tree.SearchIntersect(bb); len(got) != 0 {
+		t.Fatalf("SearchIntersect of an empty tree found %v", got)
+	}
+	if got := tree.KNN(3, &RTreePoint{}); len(got) != 0 {
+		t.Fatalf("KNN of an empty tree found %v", got)
+	}
+}
+
+func TestInto(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := New[*RTreePoint](WithBranching(2, 4))
+	for _, p := range randomPoints(200, rng) {
+		tree.Insert(p)
+	}
+
+	var found, nearest []*RTreePoint
+	for i := 0; i < 20; i++ {
+		corners := randomPoints(2, rng)
+		bb := NewRect(corners[0], corners[1])
+
+		found = tree.SearchIntersectInto(bb, found)
+		if want := tree.SearchIntersect(bb); !samePoints(found, want) {
+			t.Fatalf("SearchIntersectInto(%v) = %v, want %v", bb, found, want)
+		}
+		nearest = tree.KNNInto(5, corners[0], nearest)
+		if want := tree.KNN(5, corners[0]); !samePoints(nearest, want) {
+			t.Fatalf("KNNInto(5, %v) = %v, want %v", corners[0], nearest, want)
+		}
+	}
+
+	// KNNInto is left to BenchmarkKNNInto, as the race detector makes
+	// sync.Pool drop queues at random
+	bb := NewRect(&RTreePoint{X: 0, Y: 0}, &RTreePoint{X: 50, Y: 50})
+	allocs := testing.AllocsPerRun(10, func() {
+		found = tree.SearchIntersectInto(bb, found)
+	})
+	if allocs != 0 {
+		t.Fatalf("got %v allocations per query, want 0", allocs)
+	}
+}
+
+func BenchmarkKNNInto(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	tree := New[*RTreePoint]()
+	for _, p := range randomPoints(10000, rng) {
+		tree.Insert(p)
+	}
+	points := randomPoints(1024, rng)
+
+	var nearest []*RTreePoint
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		nearest = tree.KNNInto(10, points[i%len(points)], nearest)
+	}
+}
+
+func samePoints(a, b []*RTreePoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package rtree
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/hanyangtay/go-datastructures/metrics/registry"
+)
+
+func TestDelete(t *testing.T) {
+	tree := NewTree[*RTreePoint](2, 4)
+	points := randomPoints(50, rand.New(rand.NewSource(1)))
+	for _, p := range points {
+		tree.Insert(p)
+	}
+
+	absent := &RTreePoint{X: points[0].X, Y: points[0].Y}
+	if found, err := tree.Delete(absent); found || err != nil {
+		t.Fatalf("Delete of an absent point = %t, %v, want false, nil", found, err)
+	}
+	if tree.Size != len(points) {
+		t.Fatalf("got size %d after deleting an absent point, want %d", tree.Size, len(points))
+	}
+
+	for i, p := range points {
+		if found, err := tree.Delete(p); !found || err != nil {
+			t.Fatalf("Delete(%v) = %t, %v, want true, nil", p, found, err)
+		}
+		if found, _ := tree.Delete(p); found {
+			t.Fatalf("deleted %v twice", p)
+		}
+		if want := len(points) - i - 1; tree.Size != want {
+			t.Fatalf("got size %d, want %d", tree.Size, want)
+		}
+	}
+	if got := tree.SearchIntersect(NewRect(&RTreePoint{X: 0, Y: 0}, &RTreePoint{X: 100, Y: 100})); len(got) != 0 {
+		t.Fatalf("found %d points after deleting all of them", len(got))
+	}
+}
+
+// corrupt detaches the children of the root from it, so that adjusting
+// their entries fails
+func corrupt(tree *Rtree[*RTreePoint]) {
+	for _, e := range tree.Root.entries {
+		e.child.parent = &rTreeNode[*RTreePoint]{}
+	}
+}
+
+func TestInconsistent(t *testing.T) {
+	tree := NewTree[*RTreePoint](2, 4)
+	points := randomPoints(20, rand.New(rand.NewSource(1)))
+	for _, p := range points {
+		tree.Insert(p)
+	}
+	corrupt(tree)
+
+	if err := tree.Insert(&RTreePoint{X: 50, Y: 50}); !errors.Is(err, ErrInconsistent) {
+		t.Fatalf("Insert into a corrupt tree returned %v, want ErrInconsistent", err)
+	}
+	if found, err := tree.Delete(points[0]); !errors.Is(err, ErrInconsistent) {
+		t.Fatalf("Delete from a corrupt tree returned %t, %v, want ErrInconsistent", found, err)
+	}
+	if tree.Size != len(points) {
+		t.Fatalf("got size %d after failed operations, want %d", tree.Size, len(points))
+	}
+
+	tree.Strict = true
+	defer func() {
+		if r := recover(); r != ErrInconsistent {
+			t.Fatalf("strict tree panicked with %v, want ErrInconsistent", r)
+		}
+	}()
+	tree.Insert(&RTreePoint{X: 50, Y: 50})
+}
+
+func TestMetrics(t *testing.T) {
+	r := registry.New()
+	tree := New[*RTreePoint](WithBranching(2, 4), WithMetrics(r))
+	points := randomPoints(30, rand.New(rand.NewSource(1)))
+	for _, p := range points {
+		tree.Insert(p)
+	}
+	tree.Delete(points[0])
+	tree.Delete(points[0])
+	tree.KNN(3, points[1])
+	tree.SearchIntersect(points[1].ToRect())
+
+	for name, want := range map[string]int64{
+		"rtree_inserts_total": 30,
+		"rtree_deletes_total": 1,
+		"rtree_queries_total": 2,
+	} {
+		if got := r.Counter(name); got != want {
+			t.Errorf("got %s %d, want %d", name, got, want)
+		}
+	}
+}
+
+func TestRelease(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithArena()}} {
+		tree := New[*RTreePoint](append(opts, WithBranching(2, 4))...)
+		rng := rand.New(rand.NewSource(1))
+		for round := 0; round < 3; round++ {
+			points := randomPoints(200, rng)
+			for _, p := range points[:100] {
+				tree.Insert(p)
+			}
+			for _, p := range points[:50] {
+				tree.Delete(p)
+			}
+			for _, p := range points[100:] {
+				tree.Insert(p)
+			}
+			checkQueries(t, tree, points[50:], rng)
+
+			tree.Release()
+			if tree.Size != 0 || tree.Height != 1 {
+				t.Fatalf("got size %d and height %d after Release", tree.Size, tree.Height)
+			}
+			checkQueries(t, tree, nil, rng)
+		}
+	}
+}
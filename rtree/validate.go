@@ -0,0 +1,105 @@
+package rtree
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hanyangtay/go-datastructures/internal/debug"
+)
+
+// Validate checks the invariants of the tree and returns an error wrapping
+// ErrInconsistent that describes the first violation found, or nil if there
+// is none:
+//
+//   - the bounding box of every entry contains those of the entries of its
+//     child, and in leaves that of its object
+//   - every node points to the node whose entry it is as its parent
+//   - levels decrease by one from Height at the root to 1 at the leaves
+//   - nodes other than the root hold MinBranch to MaxBranch entries
+//   - the tree holds Size objects
+//
+// A bounding box that no longer contains its object points at an object
+// changed while stored. Builds with the datastructures_debug tag validate
+// the tree after every Insert and Delete. Time complexity: O(n)
+func (tree *Rtree[T]) Validate() error {
+	root := tree.Root
+	if root.parent != nil {
+		return inconsistent("root", "has a parent")
+	}
+	if root.level != tree.Height {
+		return inconsistent("root", "is at level %d of a tree of height %d", root.level, tree.Height)
+	}
+	if len(root.entries) > tree.MaxBranch {
+		return inconsistent("root", "has %d entries, more than %d", len(root.entries), tree.MaxBranch)
+	}
+
+	objects := 0
+	if err := tree.validate(root, "root", &objects); err != nil {
+		return err
+	}
+	if objects != tree.Size {
+		return inconsistent("root", "holds %d objects, but Size is %d", objects, tree.Size)
+	}
+	return nil
+}
+
+// validate checks the subtree of n, found at path, and counts its objects
+func (tree *Rtree[T]) validate(n *rTreeNode[T], path string, objects *int) error {
+	if n.isLeaf != (n.level == 1) {
+		return inconsistent(path, "is at level %d, but isLeaf is %t", n.level, n.isLeaf)
+	}
+
+	if n.isLeaf {
+		for i, e := range n.entries {
+			if e.child != nil {
+				return inconsistent(path, "is a leaf, but entry %d has a child", i)
+			}
+			if bb := e.obj.ToRect(); !e.bb.containsRect(bb) {
+				return inconsistent(path, "entry %d has bounding box %v, but its object %v", i, e.bb, bb)
+			}
+		}
+		*objects += len(n.entries)
+		return nil
+	}
+
+	for i, e := range n.entries {
+		child, childPath := e.child, path+"/"+strconv.Itoa(i)
+		if child == nil {
+			return inconsistent(path, "entry %d has no child", i)
+		}
+		if child.parent != n {
+			return inconsistent(childPath, "does not point to its parent")
+		}
+		if child.level != n.level-1 {
+			return inconsistent(childPath, "is at level %d below a node at level %d", child.level, n.level)
+		}
+		if k := len(child.entries); k < tree.MinBranch || k > tree.MaxBranch {
+			return inconsistent(childPath, "has %d entries, outside %d to %d", k, tree.MinBranch, tree.MaxBranch)
+		}
+		if len(child.entries) > 0 {
+			if bb := child.computeBoundingBox(); !e.bb.containsRect(bb) {
+				return inconsistent(path, "entry %d has bounding box %v, but its child %v", i, e.bb, bb)
+			}
+		}
+
+		if err := tree.validate(child, childPath, objects); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// afterMutation validates the tree in debug builds, and returns err, which
+// the mutation returned, otherwise
+func (tree *Rtree[T]) afterMutation(err error) error {
+	if err == nil && debug.Enabled {
+		err = tree.Validate()
+	}
+	return tree.check(err)
+}
+
+// inconsistent returns an error wrapping ErrInconsistent that describes the
+// problem of the node path, the indices of the entries from the root to it
+func inconsistent(path, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: node %s %s", ErrInconsistent, path, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,25 @@
+//go:build datastructures_debug
+
+package rtree
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestDebugValidates(t *testing.T) {
+	tree := NewTree[*RTreePoint](2, 4)
+	points := randomPoints(50, rand.New(rand.NewSource(1)))
+	for _, p := range points {
+		tree.Insert(p)
+	}
+
+	tree.Size++
+	if err := tree.Insert(&RTreePoint{X: 50, Y: 50}); !errors.Is(err, ErrInconsistent) {
+		t.Fatalf("Insert into a tree of wrong size returned %v, want ErrInconsistent", err)
+	}
+	if _, err := tree.Delete(points[0]); !errors.Is(err, ErrInconsistent) {
+		t.Fatalf("Delete from a tree of wrong size returned %v, want ErrInconsistent", err)
+	}
+}
@@ -0,0 +1,77 @@
+package rtree
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// TestRandomOperations validates the tree and compares its queries with a
+// linear scan under a random mix of inserts and deletes
+func TestRandomOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, split := range []SplitStrategy{QuadraticSplit, LinearSplit} {
+		tree := New[*RTreePoint](WithBranching(2, 5), WithSplit(split))
+		var points []*RTreePoint
+
+		for i := 0; i < 2000; i++ {
+			if len(points) == 0 || rng.Intn(5) < 3 {
+				p := randomPoints(1, rng)[0]
+				if err := tree.Insert(p); err != nil {
+					t.Fatal(err)
+				}
+				points = append(points, p)
+			} else {
+				j := rng.Intn(len(points))
+				if found, err := tree.Delete(points[j]); !found || err != nil {
+					t.Fatalf("Delete(%v) = %t, %v, want true, nil", points[j], found, err)
+				}
+				points[j] = points[len(points)-1]
+				points = points[:len(points)-1]
+			}
+
+			if err := tree.Validate(); err != nil {
+				t.Fatalf("split %d, operation %d: %v", split, i, err)
+			}
+			if i%200 == 0 {
+				checkQueries(t, tree, points, rng)
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(tree *Rtree[*RTreePoint])
+	}{
+		{"moved object", func(tree *Rtree[*RTreePoint]) {
+			leaf := tree.Root
+			for !leaf.isLeaf {
+				leaf = leaf.entries[0].child
+			}
+			leaf.entries[0].obj.X += 1000
+		}},
+		{"wrong parent", corrupt},
+		{"wrong size", func(tree *Rtree[*RTreePoint]) { tree.Size++ }},
+		{"wrong height", func(tree *Rtree[*RTreePoint]) { tree.Height++ }},
+		{"underfull node", func(tree *Rtree[*RTreePoint]) {
+			child := tree.Root.entries[0].child
+			child.entries = child.entries[:1]
+		}},
+	}
+	for _, tt := range tests {
+		tree := NewTree[*RTreePoint](2, 4)
+		for _, p := range randomPoints(50, rand.New(rand.NewSource(1))) {
+			tree.Insert(p)
+		}
+		if err := tree.Validate(); err != nil {
+			t.Fatalf("%s: valid tree: %v", tt.name, err)
+		}
+
+		tt.corrupt(tree)
+		if err := tree.Validate(); !errors.Is(err, ErrInconsistent) {
+			t.Errorf("%s: Validate returned %v, want ErrInconsistent", tt.name, err)
+		}
+	}
+}
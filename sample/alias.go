@@ -0,0 +1,92 @@
+// Package sample provides random selection: alias tables for fixed weights,
+// a sampler whose weights can change, and reservoir samplers for streams
+// too large to hold.
+package sample
+
+import (
+	"math"
+	"math/rand"
+)
+
+// checkWeights panics unless all weights are finite and non-negative, and
+// returns their sum
+func checkWeights(weights []float64) float64 {
+	total := 0.0
+	for _, w := range weights {
+		if !(w >= 0) || math.IsInf(w, 1) {
+			panic("sample: weight must be finite and non-negative")
+		}
+		total += w
+	}
+	return total
+}
+
+// Alias is a table for drawing index i with probability proportional to
+// weight i in O(1), by Walker's alias method: every index is given a column
+// of equal height, holding part of its own weight and the rest of another
+// index's, its alias, so a draw picks a column and then one of its two
+// parts. Building the table takes O(n) with Vose's algorithm.
+type Alias struct {
+	prob  []float64 // share of column i kept by index i
+	alias []int
+}
+
+// NewAlias returns the table for weights. It panics if a weight is negative
+// or not finite, or if all weights are zero.
+func NewAlias(weights []float64) *Alias {
+	total := checkWeights(weights)
+	if total == 0 {
+		panic("sample: weights sum to zero")
+	}
+
+	n := len(weights)
+	a := &Alias{prob: make([]float64, n), alias: make([]int, n)}
+
+	// scale weights so that the average column is full, and split them into
+	// those that underfill their column and those that overfill it
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range weights {
+		scaled[i] = w * float64(n) / total
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	// top up every small column with part of a large one
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+
+		a.prob[s], a.alias[s] = scaled[s], l
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+
+	// what is left is full up to rounding
+	for _, i := range large {
+		a.prob[i], a.alias[i] = 1, i
+	}
+	for _, i := range small {
+		a.prob[i], a.alias[i] = 1, i
+	}
+
+	return a
+}
+
+// Len returns the number of indexes
+func (a *Alias) Len() int { return len(a.prob) }
+
+// Sample draws an index at random using rng. Time complexity: O(1)
+func (a *Alias) Sample(rng *rand.Rand) int {
+	i := rng.Intn(len(a.prob))
+	if rng.Float64() < a.prob[i] {
+		return i
+	}
+	return a.alias[i]
+}
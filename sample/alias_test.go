@@ -0,0 +1,64 @@
+package sample
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// sampler draws indexes with probability proportional to weights
+type sampler interface {
+	Sample(rng *rand.Rand) int
+}
+
+// checkFrequencies draws from s and checks that every index comes up about
+// as often as its weight says, and those of weight zero never
+func checkFrequencies(t *testing.T, s sampler, weights []float64) {
+	t.Helper()
+	const draws = 200000
+	rng := rand.New(rand.NewSource(1))
+	counts := make([]int, len(weights))
+	for i := 0; i < draws; i++ {
+		counts[s.Sample(rng)]++
+	}
+
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	for i, w := range weights {
+		p := w / total
+		// five standard deviations of a binomial count
+		if want := p * draws; math.Abs(float64(counts[i])-want) > 5*math.Sqrt(want*(1-p))+1 || w == 0 && counts[i] > 0 {
+			t.Fatalf("index %d of weight %v drawn %d times, want about %.0f", i, w, counts[i], want)
+		}
+	}
+}
+
+func TestAlias(t *testing.T) {
+	for _, weights := range [][]float64{
+		{1},
+		{1, 1, 1, 1},
+		{1, 2, 3, 4, 0, 10},
+		{1e-9, 1, 1e9},
+	} {
+		a := NewAlias(weights)
+		if a.Len() != len(weights) {
+			t.Fatalf("got length %d, want %d", a.Len(), len(weights))
+		}
+		checkFrequencies(t, a, weights)
+	}
+}
+
+func TestBadWeights(t *testing.T) {
+	for _, weights := range [][]float64{{0, 0}, {1, -1}, {math.NaN()}, {math.Inf(1)}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("NewAlias(%v) did not panic", weights)
+				}
+			}()
+			NewAlias(weights)
+		}()
+	}
+}
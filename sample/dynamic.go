@@ -0,0 +1,67 @@
+package sample
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/hanyangtay/go-datastructures/fenwick"
+)
+
+// Dynamic draws index i with probability proportional to weight i, like
+// Alias, but allows changing weights. The weights are kept in a Fenwick
+// tree, so that updates and draws both take O(log n). The tree is rebuilt
+// after every n updates to keep rounding errors from accumulating.
+type Dynamic struct {
+	weights []float64
+	sums    *fenwick.Tree[float64]
+	updates int
+}
+
+// NewDynamic returns a sampler for weights. It panics if a weight is
+// negative or not finite.
+func NewDynamic(weights []float64) *Dynamic {
+	checkWeights(weights)
+	w := append([]float64(nil), weights...)
+	return &Dynamic{weights: w, sums: fenwick.From(w)}
+}
+
+// Len returns the number of indexes
+func (d *Dynamic) Len() int { return len(d.weights) }
+
+// Weight returns weight i
+func (d *Dynamic) Weight(i int) float64 { return d.weights[i] }
+
+// Total returns the sum of the weights
+func (d *Dynamic) Total() float64 { return d.sums.Prefix(d.sums.Len()) }
+
+// Set changes weight i to w. It panics if w is negative or not finite.
+// Time complexity: O(log n) amortized
+func (d *Dynamic) Set(i int, w float64) {
+	checkWeights([]float64{w})
+	d.sums.Add(i, w-d.weights[i])
+	d.weights[i] = w
+
+	if d.updates++; d.updates >= len(d.weights) {
+		d.sums = fenwick.From(d.weights)
+		d.updates = 0
+	}
+}
+
+// Sample draws an index at random using rng. It panics if all weights are
+// zero. Time complexity: O(log n)
+func (d *Dynamic) Sample(rng *rand.Rand) int {
+	total := d.Total()
+	if !(total > 0) {
+		panic("sample: weights sum to zero")
+	}
+
+	for {
+		// the first index whose prefix sum exceeds the draw, where rounding
+		// may land past the end or on an index of weight zero
+		u := rng.Float64() * total
+		i := d.sums.LowerBound(math.Nextafter(u, math.Inf(1)))
+		if i < len(d.weights) && d.weights[i] > 0 {
+			return i
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package sample
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDynamic(t *testing.T) {
+	weights := []float64{1, 2, 3, 4, 0, 10}
+	d := NewDynamic(weights)
+	checkFrequencies(t, d, weights)
+
+	// enough updates to rebuild the tree several times
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		j := rng.Intn(len(weights))
+		weights[j] = float64(rng.Intn(20))
+		d.Set(j, weights[j])
+	}
+	weights[0] = 5
+	d.Set(0, 5)
+
+	total := 0.0
+	for i, w := range weights {
+		if d.Weight(i) != w {
+			t.Fatalf("Weight(%d) = %v, want %v", i, d.Weight(i), w)
+		}
+		total += w
+	}
+	if d.Total() != total || d.Len() != len(weights) {
+		t.Fatalf("got total %v, want %v", d.Total(), total)
+	}
+	checkFrequencies(t, d, weights)
+}
@@ -0,0 +1,177 @@
+package sample
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/hanyangtay/go-datastructures/pq"
+)
+
+// Reservoir keeps a uniform random sample of up to k items of a stream of
+// unknown length in O(k) memory, by Algorithm R: the first k items fill the
+// reservoir, and the n-th item after that replaces a random one of them
+// with probability k/n. Every subset of k items seen is then equally likely
+// to be the sample.
+type Reservoir[T any] struct {
+	k     int
+	items []T
+	seen  int
+	rng   *rand.Rand
+}
+
+// NewReservoir returns an empty reservoir of k items drawing from rng. It
+// panics if k is not positive.
+func NewReservoir[T any](k int, rng *rand.Rand) *Reservoir[T] {
+	if k < 1 {
+		panic("sample: reservoir size must be positive")
+	}
+	return &Reservoir[T]{k: k, items: make([]T, 0, k), rng: rng}
+}
+
+// Seen returns the number of items added
+func (r *Reservoir[T]) Seen() int { return r.seen }
+
+// Add offers item to the sample. Time complexity: O(1)
+func (r *Reservoir[T]) Add(item T) {
+	r.seen++
+	if len(r.items) < r.k {
+		r.items = append(r.items, item)
+		return
+	}
+	if j := r.rng.Intn(r.seen); j < r.k {
+		r.items[j] = item
+	}
+}
+
+// Sample returns the items in the sample, in no particular order. The
+// slice is owned by the reservoir and changes with later calls.
+func (r *Reservoir[T]) Sample() []T { return r.items }
+
+// Merge replaces the sample of r by a uniform sample of both streams, that
+// of r and that of other, as if all their items had been added to r. Each
+// item of the merged sample is drawn from one of the two samples with
+// probability proportional to the number of items of its stream not yet
+// drawn from, so samples of parts of a stream taken in parallel can be
+// combined. other is left unchanged. It panics if other holds a sample of a
+// different size. Time complexity: O(k)
+func (r *Reservoir[T]) Merge(other *Reservoir[T]) {
+	if other.k != r.k {
+		panic("sample: Merge of reservoirs of different sizes")
+	}
+
+	a := append([]T(nil), r.items...)
+	b := append([]T(nil), other.items...)
+	na, nb := r.seen, other.seen
+
+	// draw without replacement from a, of na items, and b, of nb items; a
+	// sample smaller than k holds its whole stream, so it is never drawn
+	// from more often than it has items
+	take := func(s []T) ([]T, T) {
+		j := r.rng.Intn(len(s))
+		item := s[j]
+		s[j] = s[len(s)-1]
+		return s[:len(s)-1], item
+	}
+
+	r.items = r.items[:0]
+	for len(r.items) < r.k && na+nb > 0 {
+		var item T
+		if r.rng.Intn(na+nb) < na {
+			a, item = take(a)
+			na--
+		} else {
+			b, item = take(b)
+			nb--
+		}
+		r.items = append(r.items, item)
+	}
+	r.seen += other.seen
+}
+
+// keyed is an item of a weighted sample with its random key
+type keyed[T any] struct {
+	item T
+	key  float64
+}
+
+// Weighted keeps a weighted random sample of up to k items of a stream,
+// without replacement, by the A-Res algorithm of Efraimidis and Spirakis:
+// every item gets the key u^(1/w) for its weight w and a uniform random u,
+// and the k items of largest key are kept. The sample is then distributed
+// as if items were drawn one by one with probability proportional to their
+// weight.
+type Weighted[T any] struct {
+	k    int
+	heap *pq.PriorityQueue[keyed[T]] // least key on top
+	seen int
+	rng  *rand.Rand
+}
+
+// NewWeighted returns an empty weighted sample of k items drawing from rng.
+// It panics if k is not positive.
+func NewWeighted[T any](k int, rng *rand.Rand) *Weighted[T] {
+	if k < 1 {
+		panic("sample: reservoir size must be positive")
+	}
+	return &Weighted[T]{
+		k:    k,
+		heap: pq.New(func(a, b keyed[T]) bool { return a.key < b.key }),
+		rng:  rng,
+	}
+}
+
+// Seen returns the number of items added
+func (w *Weighted[T]) Seen() int { return w.seen }
+
+// Add offers item with the given weight to the sample. Items of weight zero
+// are never sampled. It panics if the weight is negative or not finite.
+// Time complexity: O(log k)
+func (w *Weighted[T]) Add(item T, weight float64) {
+	checkWeights([]float64{weight})
+	w.seen++
+	if weight == 0 {
+		return
+	}
+
+	// the logarithm of the key, which keeps small weights from rounding
+	// every key to zero
+	key := math.Log(1-w.rng.Float64()) / weight
+	w.push(keyed[T]{item, key})
+}
+
+func (w *Weighted[T]) push(x keyed[T]) {
+	if w.heap.Len() < w.k {
+		w.heap.Push(x)
+		return
+	}
+	if x.key > w.heap.Peek().key {
+		w.heap.Pop()
+		w.heap.Push(x)
+	}
+}
+
+// Sample returns the items in the sample, in no particular order
+func (w *Weighted[T]) Sample() []T {
+	items := make([]T, 0, w.heap.Len())
+	w.heap.Each(func(x keyed[T]) bool {
+		items = append(items, x.item)
+		return true
+	})
+	return items
+}
+
+// Merge replaces the sample of w by a weighted sample of both streams, that
+// of w and that of other, as if all their items had been added to w: the
+// items of largest key among both samples are kept. other is left
+// unchanged. It panics if other holds a sample of a different size.
+// Time complexity: O(k log k)
+func (w *Weighted[T]) Merge(other *Weighted[T]) {
+	if other.k != w.k {
+		panic("sample: Merge of reservoirs of different sizes")
+	}
+	other.heap.Each(func(x keyed[T]) bool {
+		w.push(x)
+		return true
+	})
+	w.seen += other.seen
+}
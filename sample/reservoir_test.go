@@ -0,0 +1,98 @@
+package sample
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestReservoir checks that every item of a stream is about equally likely
+// to be sampled, whether it went through one reservoir or two merged ones
+func TestReservoir(t *testing.T) {
+	const n, k, runs = 50, 5, 20000
+	rng := rand.New(rand.NewSource(1))
+	single, merged := make([]int, n), make([]int, n)
+
+	for run := 0; run < runs; run++ {
+		r := NewReservoir[int](k, rng)
+		a, b := NewReservoir[int](k, rng), NewReservoir[int](k, rng)
+		for i := 0; i < n; i++ {
+			r.Add(i)
+			// split the stream unevenly
+			if i < 15 {
+				a.Add(i)
+			} else {
+				b.Add(i)
+			}
+		}
+		a.Merge(b)
+		if r.Seen() != n || a.Seen() != n || len(r.Sample()) != k || len(a.Sample()) != k {
+			t.Fatalf("got %d and %d seen, samples of %d and %d", r.Seen(), a.Seen(), len(r.Sample()), len(a.Sample()))
+		}
+
+		seen := make(map[int]bool)
+		for _, x := range a.Sample() {
+			if seen[x] {
+				t.Fatalf("merged sample holds %d twice", x)
+			}
+			seen[x] = true
+			merged[x]++
+		}
+		for _, x := range r.Sample() {
+			single[x]++
+		}
+	}
+
+	p := float64(k) / n
+	want := p * runs
+	for i := 0; i < n; i++ {
+		for _, counts := range [][]int{single, merged} {
+			if math.Abs(float64(counts[i])-want) > 5*math.Sqrt(want*(1-p)) {
+				t.Fatalf("item %d sampled %d times, want about %.0f", i, counts[i], want)
+			}
+		}
+	}
+
+	// merging a short stream keeps all of both
+	a, b := NewReservoir[int](k, rng), NewReservoir[int](k, rng)
+	a.Add(1)
+	b.Add(2)
+	a.Merge(b)
+	if len(a.Sample()) != 2 {
+		t.Fatalf("got a sample of %d items of two, want 2", len(a.Sample()))
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	const runs = 20000
+	rng := rand.New(rand.NewSource(1))
+	weights := []float64{1, 1, 2, 4, 8, 0}
+	counts := make([]int, len(weights))
+
+	for run := 0; run < runs; run++ {
+		w := NewWeighted[int](1, rng)
+		other := NewWeighted[int](1, rng)
+		for i, x := range weights {
+			if i%2 == 0 {
+				w.Add(i, x)
+			} else {
+				other.Add(i, x)
+			}
+		}
+		w.Merge(other)
+		if w.Seen() != len(weights) {
+			t.Fatalf("got %d seen, want %d", w.Seen(), len(weights))
+		}
+		for _, x := range w.Sample() {
+			counts[x]++
+		}
+	}
+
+	// a sample of one is a single weighted draw
+	for i, x := range weights {
+		p := x / 16
+		if want := p * runs; math.Abs(float64(counts[i])-want) > 5*math.Sqrt(want*(1-p))+1 {
+			t.Fatalf("item %d of weight %v sampled %d times, want about %.0f", i, x, counts[i], want)
+		}
+	}
+}
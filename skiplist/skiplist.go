@@ -0,0 +1,237 @@
+// Package skiplist provides a generic ordered map backed by a skip list.
+package skiplist
+
+import (
+	"math/rand"
+
+	"github.com/hanyangtay/go-datastructures/ordered"
+)
+
+var _ ordered.Map[int, int] = (*SkipList[int, int])(nil)
+
+const (
+	// maxLevel bounds the height of the towers, enough for 4^maxLevel keys
+	maxLevel = 32
+
+	// promote is the inverse of the probability that a tower grows by one
+	promote = 4
+)
+
+type node[K, V any] struct {
+	key   K
+	value V
+	next  []*node[K, V] // successor on every level of the tower
+}
+
+// SkipList is an ordered map from keys to values, with keys ordered by a less
+// function. Every entry is part of a sorted linked list, and each list level
+// skips over roughly three in four entries of the level below, so that
+// lookups, inserts and deletes take O(log n) expected time. It is simpler
+// than a balanced tree, and as updates only splice lists locally it is a
+// common base for concurrent maps.
+type SkipList[K, V any] struct {
+	head   *node[K, V] // sentinel, holding no entry
+	level  int         // number of levels in use
+	length int
+	less   func(a, b K) bool
+	rng    *rand.Rand
+}
+
+// New returns an empty skip list ordered by less
+func New[K, V any](less func(a, b K) bool) *SkipList[K, V] {
+	return &SkipList[K, V]{
+		head:  &node[K, V]{next: make([]*node[K, V], maxLevel)},
+		level: 1,
+		less:  less,
+		rng:   rand.New(rand.NewSource(1)),
+	}
+}
+
+// Len returns the number of entries
+func (s *SkipList[K, V]) Len() int { return s.length }
+
+// search returns the last node before key on the lowest level, and fills
+// update, if not nil, with the last node before key on every level
+func (s *SkipList[K, V]) search(key K, update []*node[K, V]) *node[K, V] {
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && s.less(x.next[i].key, key) {
+			x = x.next[i]
+		}
+		if update != nil {
+			update[i] = x
+		}
+	}
+	return x
+}
+
+// equal reports whether node x holds key
+func (s *SkipList[K, V]) equal(x *node[K, V], key K) bool {
+	return x != nil && !s.less(key, x.key)
+}
+
+// Get returns the value stored under key, and whether it was found
+func (s *SkipList[K, V]) Get(key K) (V, bool) {
+	if x := s.search(key, nil).next[0]; s.equal(x, key) {
+		return x.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores value under key, replacing any previous value
+func (s *SkipList[K, V]) Put(key K, value V) {
+	var update [maxLevel]*node[K, V]
+	if x := s.search(key, update[:]).next[0]; s.equal(x, key) {
+		x.value = value
+		return
+	}
+
+	height := 1
+	for height < maxLevel && s.rng.Intn(promote) == 0 {
+		height++
+	}
+	for ; s.level < height; s.level++ {
+		update[s.level] = s.head
+	}
+
+	x := &node[K, V]{key: key, value: value, next: make([]*node[K, V], height)}
+	for i := 0; i < height; i++ {
+		x.next[i] = update[i].next[i]
+		update[i].next[i] = x
+	}
+	s.length++
+}
+
+// Delete removes key and reports whether it was present
+func (s *SkipList[K, V]) Delete(key K) bool {
+	var update [maxLevel]*node[K, V]
+	x := s.search(key, update[:]).next[0]
+	if !s.equal(x, key) {
+		return false
+	}
+
+	for i := range x.next {
+		update[i].next[i] = x.next[i]
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+	s.length--
+	return true
+}
+
+// Min returns the entry with the least key, or false if the list is empty
+func (s *SkipList[K, V]) Min() (K, V, bool) {
+	return entry(s.head.next[0])
+}
+
+// Max returns the entry with the greatest key, or false if the list is empty
+func (s *SkipList[K, V]) Max() (K, V, bool) {
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil {
+			x = x.next[i]
+		}
+	}
+	if x == s.head {
+		return entry[K, V](nil)
+	}
+	return entry(x)
+}
+
+// Floor returns the entry with the greatest key not greater than key, or
+// false if there is none
+func (s *SkipList[K, V]) Floor(key K) (K, V, bool) {
+	x := s.search(key, nil)
+	if next := x.next[0]; s.equal(next, key) {
+		return entry(next)
+	}
+	if x == s.head {
+		return entry[K, V](nil)
+	}
+	return entry(x)
+}
+
+// Ceiling returns the entry with the least key not less than key, or false
+// if there is none
+func (s *SkipList[K, V]) Ceiling(key K) (K, V, bool) {
+	return entry(s.search(key, nil).next[0])
+}
+
+// Ascend calls fn for every entry in key order until fn returns false
+func (s *SkipList[K, V]) Ascend(fn func(key K, value V) bool) {
+	for x := s.head.next[0]; x != nil; x = x.next[0] {
+		if !fn(x.key, x.value) {
+			return
+		}
+	}
+}
+
+// Range calls fn in key order for every entry with a key from lo up to but
+// excluding hi, until fn returns false
+func (s *SkipList[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	for x := s.search(lo, nil).next[0]; x != nil && s.less(x.key, hi); x = x.next[0] {
+		if !fn(x.key, x.value) {
+			return
+		}
+	}
+}
+
+// Seek returns an iterator over the entries with keys not less than key, in
+// key order
+func (s *SkipList[K, V]) Seek(key K) *Iterator[K, V] {
+	return &Iterator[K, V]{first: s.search(key, nil).next[0]}
+}
+
+// Iterator streams the entries of a skip list in key order:
+//
+//	for it := s.Seek(key); it.Next(); {
+//		k, v := it.Key(), it.Value()
+//		...
+//	}
+//
+// The list may be modified while iterating, as long as the current entry is
+// not deleted.
+type Iterator[K, V any] struct {
+	first, cur *node[K, V]
+}
+
+// Next advances to the next entry, returning false once there are none left
+func (it *Iterator[K, V]) Next() bool {
+	if it.first != nil {
+		it.cur, it.first = it.first, nil
+	} else if it.cur != nil {
+		it.cur = it.cur.next[0]
+	}
+	return it.cur != nil
+}
+
+// Key returns the key of the current entry
+func (it *Iterator[K, V]) Key() K { return it.cur.key }
+
+// Value returns the value of the current entry
+func (it *Iterator[K, V]) Value() V { return it.cur.value }
+
+// entry unpacks x, reporting false if it is nil
+func entry[K, V any](x *node[K, V]) (K, V, bool) {
+	if x == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return x.key, x.value, true
+}
+
+// MarshalBinary encodes the list with ordered.Marshal
+func (s *SkipList[K, V]) MarshalBinary() ([]byte, error) {
+	return ordered.Marshal[K, V](s)
+}
+
+// UnmarshalBinary replaces the entries of the list as ordered.UnmarshalInto does
+func (s *SkipList[K, V]) UnmarshalBinary(data []byte) error {
+	return ordered.UnmarshalInto[K, V](s, data, func() {
+		s.head = &node[K, V]{next: make([]*node[K, V], maxLevel)}
+		s.level, s.length = 1, 0
+	})
+}
@@ -0,0 +1,143 @@
+package skiplist
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func less(a, b int) bool { return a < b }
+
+// TestAgainstMap applies random puts and deletes to a skip list and to a
+// built-in map, then checks every query of the skip list against the map
+func TestAgainstMap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	m := New[int, int](less)
+	want := make(map[int]int)
+
+	for i := 0; i < 3000; i++ {
+		k := rng.Intn(500)
+		if rng.Intn(3) == 0 {
+			_, ok := want[k]
+			if got := m.Delete(k); got != ok {
+				t.Fatalf("Delete(%d) = %v, want %v", k, got, ok)
+			}
+			delete(want, k)
+		} else {
+			m.Put(k, i)
+			want[k] = i
+		}
+		if m.Len() != len(want) {
+			t.Fatalf("got length %d, want %d", m.Len(), len(want))
+		}
+	}
+
+	checkEntries(t, m, want)
+}
+
+// checkEntries fails t unless m holds exactly the entries of want
+func checkEntries(t *testing.T, m *SkipList[int, int], want map[int]int) {
+	t.Helper()
+
+	keys := make([]int, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	var got []int
+	m.Ascend(func(k, v int) bool {
+		if v != want[k] {
+			t.Fatalf("Ascend gave %d for key %d, want %d", v, k, want[k])
+		}
+		got = append(got, k)
+		return true
+	})
+	if len(got) != len(keys) {
+		t.Fatalf("Ascend gave %d keys, want %d", len(got), len(keys))
+	}
+	for i := range keys {
+		if got[i] != keys[i] {
+			t.Fatalf("Ascend gave key %d at %d, want %d", got[i], i, keys[i])
+		}
+	}
+
+	for k := -1; k <= 501; k++ {
+		v, ok := m.Get(k)
+		if w, wok := want[k]; ok != wok || v != w {
+			t.Fatalf("Get(%d) = %d, %v, want %d, %v", k, v, ok, w, wok)
+		}
+
+		// keys[i] is the least key not less than k
+		i := sort.SearchInts(keys, k)
+
+		fk, _, fok := m.Floor(k)
+		switch {
+		case i < len(keys) && keys[i] == k:
+			if !fok || fk != k {
+				t.Fatalf("Floor(%d) = %d, %v, want %d", k, fk, fok, k)
+			}
+		case i > 0:
+			if !fok || fk != keys[i-1] {
+				t.Fatalf("Floor(%d) = %d, %v, want %d", k, fk, fok, keys[i-1])
+			}
+		case fok:
+			t.Fatalf("Floor(%d) = %d, want none", k, fk)
+		}
+
+		ck, _, cok := m.Ceiling(k)
+		if i < len(keys) {
+			if !cok || ck != keys[i] {
+				t.Fatalf("Ceiling(%d) = %d, %v, want %d", k, ck, cok, keys[i])
+			}
+		} else if cok {
+			t.Fatalf("Ceiling(%d) = %d, want none", k, ck)
+		}
+	}
+
+	minKey, _, minOK := m.Min()
+	maxKey, _, maxOK := m.Max()
+	if len(keys) == 0 {
+		if minOK || maxOK {
+			t.Fatal("Min or Max found an entry in an empty map")
+		}
+	} else if !minOK || !maxOK || minKey != keys[0] || maxKey != keys[len(keys)-1] {
+		t.Fatalf("got Min %d and Max %d, want %d and %d", minKey, maxKey, keys[0], keys[len(keys)-1])
+	}
+
+	lo, hi := sort.SearchInts(keys, 100), sort.SearchInts(keys, 200)
+	var inRange []int
+	m.Range(100, 200, func(k, v int) bool {
+		inRange = append(inRange, k)
+		return true
+	})
+	if len(inRange) != hi-lo {
+		t.Fatalf("Range(100, 200) gave %d keys, want %d", len(inRange), hi-lo)
+	}
+	for i, k := range inRange {
+		if k != keys[lo+i] {
+			t.Fatalf("Range(100, 200) gave key %d at %d, want %d", k, i, keys[lo+i])
+		}
+	}
+}
+
+func TestSeek(t *testing.T) {
+	s := New[int, int](less)
+	for k := 0; k < 100; k += 2 {
+		s.Put(k, k*k)
+	}
+
+	want := 32
+	for it := s.Seek(31); it.Next(); want += 2 {
+		if it.Key() != want || it.Value() != want*want {
+			t.Fatalf("got entry %d: %d, want %d: %d", it.Key(), it.Value(), want, want*want)
+		}
+	}
+	if want != 100 {
+		t.Fatalf("iteration ended before key %d", want)
+	}
+
+	if s.Seek(100).Next() {
+		t.Fatal("iterator past the greatest key found an entry")
+	}
+}
@@ -0,0 +1,48 @@
+package sparse
+
+import (
+	"github.com/hanyangtay/go-datastructures/graph"
+)
+
+// FromGraph returns the weighted adjacency matrix of g, whose entry at row i
+// and column j is the weight of the edge from node i to node j, or the sum
+// of their weights if there are several. Multiplying its transpose by a
+// vector of node values, as MulVecTrans does, pushes every value along the
+// outgoing edges, the step of PageRank-like iterations.
+// Time complexity: O(V + E log E)
+func FromGraph(g graph.Graph) *CSR {
+	n := g.Order()
+	var entries []Triplet
+	for i := 0; i < n; i++ {
+		g.Successors(i, func(j int, w float64) bool {
+			entries = append(entries, Triplet{i, j, w})
+			return true
+		})
+	}
+	return NewCSR(n, n, entries)
+}
+
+// Graph returns a graph with a node for every row of the square matrix m and
+// an edge from node i to node j, weighted by the entry, for every stored
+// entry off the diagonal. Entries on the diagonal are left out, as graphs
+// have no self edges. It panics if m is not square.
+func (m *CSR) Graph() *graph.DirectedGraph {
+	if m.rows != m.cols {
+		panic("sparse: matrix is not square")
+	}
+
+	g := graph.NewDirectedGraph()
+	for i := 0; i < m.rows; i++ {
+		g.AddNode(&graph.Node{})
+	}
+	for i := 0; i < m.rows; i++ {
+		m.each(i, func(j int, v float64) bool {
+			if i != j {
+				u, w := g.Nodes[i], g.Nodes[j]
+				g.AddDirectedEdge(&graph.Edge{ID: [2]int{i, j}, From: u, To: w, Weight: v})
+			}
+			return true
+		})
+	}
+	return g
+}
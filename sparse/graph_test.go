@@ -0,0 +1,40 @@
+package sparse
+
+import (
+	"testing"
+)
+
+func TestGraphRoundTrip(t *testing.T) {
+	m := NewCSR(4, 4, []Triplet{{0, 1, 2}, {1, 2, 3}, {2, 0, 1}, {2, 3, 5}, {3, 3, 7}})
+	g := m.Graph()
+	if len(g.Nodes) != 4 || g.EdgeCount() != 4 {
+		t.Fatalf("got %d nodes and %d edges, want 4 and 4 without the diagonal", len(g.Nodes), g.EdgeCount())
+	}
+
+	back := FromGraph(g)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			want := m.At(i, j)
+			if i == j {
+				want = 0
+			}
+			if back.At(i, j) != want {
+				t.Fatalf("At(%d, %d) = %v, want %v", i, j, back.At(i, j), want)
+			}
+		}
+	}
+
+	// one step of pushing values along the edges
+	if y := back.MulVecTrans(nil, []float64{1, 1, 1, 1}); y[0] != 1 || y[3] != 5 {
+		t.Fatalf("got %v pushing ones along the edges", y)
+	}
+}
+
+func TestGraphNotSquare(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("graph of a 2 by 3 matrix did not panic")
+		}
+	}()
+	NewCSR(2, 3, nil).Graph()
+}
@@ -0,0 +1,314 @@
+// Package sparse provides sparse matrices in compressed sparse row (CSR) and
+// compressed sparse column (CSC) form, with the basic operations of iterative
+// linear algebra.
+package sparse
+
+import (
+	"sort"
+)
+
+// Triplet is an entry of a matrix given by its position, used to build
+// matrices. Triplets at the same position are summed.
+type Triplet struct {
+	Row, Col int
+	Value    float64
+}
+
+// compressed holds the entries of a matrix grouped by major index, rows for
+// CSR and columns for CSC: the entries of major index i have the minor
+// indices indices[offsets[i]:offsets[i+1]], in increasing order, and the
+// matching values
+type compressed struct {
+	offsets []int32
+	indices []int32
+	values  []float64
+}
+
+// compress groups entries by major index with a counting sort, then sorts
+// each group by minor index and sums duplicates
+func compress(n int, entries []Triplet, major, minor func(Triplet) int) compressed {
+	c := compressed{offsets: make([]int32, n+1)}
+	for _, t := range entries {
+		c.offsets[major(t)+1]++
+	}
+	for i := 0; i < n; i++ {
+		c.offsets[i+1] += c.offsets[i]
+	}
+
+	next := append([]int32(nil), c.offsets[:n]...)
+	c.indices = make([]int32, len(entries))
+	c.values = make([]float64, len(entries))
+	for _, t := range entries {
+		k := next[major(t)]
+		next[major(t)]++
+		c.indices[k], c.values[k] = int32(minor(t)), t.Value
+	}
+
+	// sort every group and merge duplicates, compacting in place
+	out := int32(0)
+	for i := 0; i < n; i++ {
+		lo, hi := c.offsets[i], c.offsets[i+1]
+		sort.Sort(byIndex{c.indices[lo:hi], c.values[lo:hi]})
+
+		c.offsets[i] = out
+		for k := lo; k < hi; k++ {
+			if out > c.offsets[i] && c.indices[out-1] == c.indices[k] {
+				c.values[out-1] += c.values[k]
+				continue
+			}
+			c.indices[out], c.values[out] = c.indices[k], c.values[k]
+			out++
+		}
+	}
+	c.offsets[n] = out
+	c.indices, c.values = c.indices[:out:out], c.values[:out:out]
+	return c
+}
+
+// byIndex sorts a group of entries by minor index
+type byIndex struct {
+	indices []int32
+	values  []float64
+}
+
+func (b byIndex) Len() int           { return len(b.indices) }
+func (b byIndex) Less(i, j int) bool { return b.indices[i] < b.indices[j] }
+func (b byIndex) Swap(i, j int) {
+	b.indices[i], b.indices[j] = b.indices[j], b.indices[i]
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+}
+
+// transpose regroups c, of minor dimension m, by minor index. Entries are
+// visited in major order, so the new groups come out sorted.
+func (c compressed) transpose(m int) compressed {
+	t := compressed{
+		offsets: make([]int32, m+1),
+		indices: make([]int32, len(c.indices)),
+		values:  make([]float64, len(c.values)),
+	}
+	for _, j := range c.indices {
+		t.offsets[j+1]++
+	}
+	for j := 0; j < m; j++ {
+		t.offsets[j+1] += t.offsets[j]
+	}
+
+	next := append([]int32(nil), t.offsets[:m]...)
+	for i := 0; i+1 < len(c.offsets); i++ {
+		for k := c.offsets[i]; k < c.offsets[i+1]; k++ {
+			j := c.indices[k]
+			t.indices[next[j]], t.values[next[j]] = int32(i), c.values[k]
+			next[j]++
+		}
+	}
+	return t
+}
+
+// at returns the entry at major index i and minor index j
+func (c compressed) at(i, j int) float64 {
+	lo, hi := int(c.offsets[i]), int(c.offsets[i+1])
+	k := lo + sort.Search(hi-lo, func(k int) bool { return c.indices[lo+k] >= int32(j) })
+	if k < hi && c.indices[k] == int32(j) {
+		return c.values[k]
+	}
+	return 0
+}
+
+// each calls fn for the entries of major index i until fn returns false
+func (c compressed) each(i int, fn func(j int, v float64) bool) {
+	for k := c.offsets[i]; k < c.offsets[i+1]; k++ {
+		if !fn(int(c.indices[k]), c.values[k]) {
+			return
+		}
+	}
+}
+
+func checkTriplets(rows, cols int, entries []Triplet) {
+	if rows < 0 || cols < 0 {
+		panic("sparse: negative dimensions")
+	}
+	for _, t := range entries {
+		if t.Row < 0 || t.Row >= rows || t.Col < 0 || t.Col >= cols {
+			panic("sparse: triplet out of range")
+		}
+	}
+}
+
+func checkIndex(i, j, rows, cols int) {
+	if i < 0 || i >= rows || j < 0 || j >= cols {
+		panic("sparse: index out of range")
+	}
+}
+
+// CSR is an immutable sparse matrix in compressed sparse row form, which
+// stores only the nonzero entries, row by row. Multiplying a vector and
+// reading a row take time proportional to the entries involved.
+type CSR struct {
+	rows, cols int
+	compressed
+}
+
+// NewCSR returns the rows by cols matrix of entries, summing those at the
+// same position. It panics if an entry lies outside the matrix.
+// Time complexity: O(rows + n log n) for n entries
+func NewCSR(rows, cols int, entries []Triplet) *CSR {
+	checkTriplets(rows, cols, entries)
+	return &CSR{rows, cols, compress(rows, entries,
+		func(t Triplet) int { return t.Row }, func(t Triplet) int { return t.Col })}
+}
+
+// Dims returns the number of rows and of columns
+func (m *CSR) Dims() (rows, cols int) { return m.rows, m.cols }
+
+// NNZ returns the number of stored entries
+func (m *CSR) NNZ() int { return len(m.values) }
+
+// At returns the entry at row i and column j. It panics if the position is
+// out of range. Time complexity: O(log k) for k entries in the row
+func (m *CSR) At(i, j int) float64 {
+	checkIndex(i, j, m.rows, m.cols)
+	return m.at(i, j)
+}
+
+// Row calls fn for the stored entries of row i in column order until fn
+// returns false
+func (m *CSR) Row(i int, fn func(j int, v float64) bool) {
+	checkIndex(i, 0, m.rows, 1)
+	m.each(i, fn)
+}
+
+// MulVec stores the product of m and x in dst and returns it, allocating
+// dst if it is nil. x must have one element per column, dst one per row;
+// they must not overlap. Time complexity: O(rows + NNZ)
+func (m *CSR) MulVec(dst, x []float64) []float64 {
+	if len(x) != m.cols || dst != nil && len(dst) != m.rows {
+		panic("sparse: dimension mismatch")
+	}
+	if dst == nil {
+		dst = make([]float64, m.rows)
+	}
+
+	for i := 0; i < m.rows; i++ {
+		sum := 0.0
+		for k := m.offsets[i]; k < m.offsets[i+1]; k++ {
+			sum += m.values[k] * x[m.indices[k]]
+		}
+		dst[i] = sum
+	}
+	return dst
+}
+
+// MulVecTrans stores the product of the transpose of m and x in dst and
+// returns it, allocating dst if it is nil, without forming the transpose.
+// x must have one element per row, dst one per column; they must not
+// overlap. Time complexity: O(cols + NNZ)
+func (m *CSR) MulVecTrans(dst, x []float64) []float64 {
+	if len(x) != m.rows || dst != nil && len(dst) != m.cols {
+		panic("sparse: dimension mismatch")
+	}
+	if dst == nil {
+		dst = make([]float64, m.cols)
+	} else {
+		clear(dst)
+	}
+
+	for i := 0; i < m.rows; i++ {
+		for k := m.offsets[i]; k < m.offsets[i+1]; k++ {
+			dst[m.indices[k]] += m.values[k] * x[i]
+		}
+	}
+	return dst
+}
+
+// RowSums returns the sum of the entries of every row, e.g. the weighted
+// out-degrees of the nodes of an adjacency matrix
+func (m *CSR) RowSums() []float64 {
+	sums := make([]float64, m.rows)
+	for i := range sums {
+		for k := m.offsets[i]; k < m.offsets[i+1]; k++ {
+			sums[i] += m.values[k]
+		}
+	}
+	return sums
+}
+
+// Transpose returns the transpose of m. Time complexity: O(rows + cols + NNZ)
+func (m *CSR) Transpose() *CSR {
+	return &CSR{m.cols, m.rows, m.transpose(m.cols)}
+}
+
+// ToCSC returns m in compressed sparse column form.
+// Time complexity: O(rows + cols + NNZ)
+func (m *CSR) ToCSC() *CSC {
+	return &CSC{m.rows, m.cols, m.transpose(m.cols)}
+}
+
+// Triplets returns the stored entries in row major order
+func (m *CSR) Triplets() []Triplet {
+	out := make([]Triplet, 0, m.NNZ())
+	for i := 0; i < m.rows; i++ {
+		m.each(i, func(j int, v float64) bool {
+			out = append(out, Triplet{i, j, v})
+			return true
+		})
+	}
+	return out
+}
+
+// CSC is an immutable sparse matrix in compressed sparse column form, which
+// stores only the nonzero entries, column by column. It is the CSR form of
+// the transpose, and suits algorithms that read columns, such as solving
+// with a factorization.
+type CSC struct {
+	rows, cols int
+	compressed
+}
+
+// NewCSC returns the rows by cols matrix of entries, summing those at the
+// same position. It panics if an entry lies outside the matrix.
+// Time complexity: O(cols + n log n) for n entries
+func NewCSC(rows, cols int, entries []Triplet) *CSC {
+	checkTriplets(rows, cols, entries)
+	return &CSC{rows, cols, compress(cols, entries,
+		func(t Triplet) int { return t.Col }, func(t Triplet) int { return t.Row })}
+}
+
+// Dims returns the number of rows and of columns
+func (m *CSC) Dims() (rows, cols int) { return m.rows, m.cols }
+
+// NNZ returns the number of stored entries
+func (m *CSC) NNZ() int { return len(m.values) }
+
+// At returns the entry at row i and column j. It panics if the position is
+// out of range. Time complexity: O(log k) for k entries in the column
+func (m *CSC) At(i, j int) float64 {
+	checkIndex(i, j, m.rows, m.cols)
+	return m.at(j, i)
+}
+
+// Col calls fn for the stored entries of column j in row order until fn
+// returns false
+func (m *CSC) Col(j int, fn func(i int, v float64) bool) {
+	checkIndex(0, j, 1, m.cols)
+	m.each(j, fn)
+}
+
+// MulVec stores the product of m and x in dst and returns it, allocating
+// dst if it is nil. x must have one element per column, dst one per row;
+// they must not overlap. Time complexity: O(rows + NNZ)
+func (m *CSC) MulVec(dst, x []float64) []float64 {
+	// the CSR form of the transpose multiplies by the transpose
+	t := CSR{m.cols, m.rows, m.compressed}
+	return t.MulVecTrans(dst, x)
+}
+
+// Transpose returns the transpose of m. Time complexity: O(rows + cols + NNZ)
+func (m *CSC) Transpose() *CSC {
+	return &CSC{m.cols, m.rows, m.transpose(m.rows)}
+}
+
+// ToCSR returns m in compressed sparse row form.
+// Time complexity: O(rows + cols + NNZ)
+func (m *CSC) ToCSR() *CSR {
+	return &CSR{m.rows, m.cols, m.transpose(m.rows)}
+}
@@ -0,0 +1,120 @@
+package sparse
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// randomMatrix returns random entries of a rows by cols matrix, with
+// duplicates, and the dense matrix they sum to
+func randomMatrix(rng *rand.Rand, rows, cols int) ([]Triplet, [][]float64) {
+	dense := make([][]float64, rows)
+	for i := range dense {
+		dense[i] = make([]float64, cols)
+	}
+	var entries []Triplet
+	for k := 0; k < rows*cols/3; k++ {
+		e := Triplet{rng.Intn(rows), rng.Intn(cols), float64(rng.Intn(10) + 1)}
+		entries = append(entries, e)
+		dense[e.Row][e.Col] += e.Value
+	}
+	return entries, dense
+}
+
+func TestAgainstDense(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, dims := range [][2]int{{1, 1}, {5, 9}, {40, 30}} {
+		rows, cols := dims[0], dims[1]
+		entries, dense := randomMatrix(rng, rows, cols)
+		csr, csc := NewCSR(rows, cols, entries), NewCSC(rows, cols, entries)
+
+		nnz := 0
+		for i := range dense {
+			for j := range dense[i] {
+				if dense[i][j] != 0 {
+					nnz++
+				}
+				if csr.At(i, j) != dense[i][j] || csc.At(i, j) != dense[i][j] {
+					t.Fatalf("At(%d, %d) = %v and %v, want %v", i, j, csr.At(i, j), csc.At(i, j), dense[i][j])
+				}
+				if csr.Transpose().At(j, i) != dense[i][j] || csr.ToCSC().At(i, j) != dense[i][j] || csc.ToCSR().At(i, j) != dense[i][j] {
+					t.Fatalf("converted matrices differ at %d, %d", i, j)
+				}
+			}
+		}
+		if csr.NNZ() != nnz || csc.NNZ() != nnz {
+			t.Fatalf("got %d and %d stored entries, want %d", csr.NNZ(), csc.NNZ(), nnz)
+		}
+		if r, c := csc.Transpose().Dims(); r != cols || c != rows {
+			t.Fatalf("transpose has dimensions %d by %d, want %d by %d", r, c, cols, rows)
+		}
+
+		x := make([]float64, cols)
+		for j := range x {
+			x[j] = float64(rng.Intn(5))
+		}
+		y := make([]float64, rows)
+		for i := range y {
+			y[i] = float64(rng.Intn(5))
+		}
+		ax, bx, aty := csr.MulVec(nil, x), csc.MulVec(make([]float64, rows), x), csr.MulVecTrans(nil, y)
+		sums := csr.RowSums()
+		for i := range dense {
+			want, sum := 0.0, 0.0
+			for j := range dense[i] {
+				want += dense[i][j] * x[j]
+				sum += dense[i][j]
+			}
+			if ax[i] != want || bx[i] != want || sums[i] != sum {
+				t.Fatalf("row %d: products %v and %v and sum %v, want %v and %v", i, ax[i], bx[i], sums[i], want, sum)
+			}
+		}
+		for j := range x {
+			want := 0.0
+			for i := range dense {
+				want += dense[i][j] * y[i]
+			}
+			if aty[j] != want {
+				t.Fatalf("MulVecTrans[%d] = %v, want %v", j, aty[j], want)
+			}
+		}
+
+		prev := Triplet{-1, -1, 0}
+		for _, e := range csr.Triplets() {
+			if e.Row < prev.Row || e.Row == prev.Row && e.Col <= prev.Col || e.Value != dense[e.Row][e.Col] {
+				t.Fatalf("Triplets returned %v after %v", e, prev)
+			}
+			prev = e
+		}
+	}
+}
+
+func TestRowCol(t *testing.T) {
+	m := NewCSR(2, 4, []Triplet{{0, 3, 1}, {0, 1, 2}, {1, 2, 3}, {0, 3, 4}})
+	var cols []int
+	m.Row(0, func(j int, v float64) bool {
+		cols = append(cols, j)
+		return true
+	})
+	if len(cols) != 2 || cols[0] != 1 || cols[1] != 3 || m.At(0, 3) != 5 {
+		t.Fatalf("row 0 has columns %v and At(0, 3) = %v, want [1 3] and 5", cols, m.At(0, 3))
+	}
+
+	var rows []int
+	m.ToCSC().Col(3, func(i int, v float64) bool {
+		rows = append(rows, i)
+		return false
+	})
+	if len(rows) != 1 || rows[0] != 0 {
+		t.Fatalf("column 3 has rows %v, want [0]", rows)
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("an entry outside the matrix did not panic")
+		}
+	}()
+	NewCSR(2, 2, []Triplet{{2, 0, 1}})
+}
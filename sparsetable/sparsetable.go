@@ -0,0 +1,158 @@
+// Package sparsetable provides sparse tables for constant time range minimum
+// queries over arrays that do not change, and lowest common ancestors built
+// on them.
+package sparsetable
+
+import "math/bits"
+
+// Table answers range minimum queries over a fixed array in O(1), after an
+// O(n log n) build. Since it only compares values by a less function, range
+// maximum queries are answered by passing the reverse order.
+type Table[T any] struct {
+	values []T
+	less   func(a, b T) bool
+
+	// levels[k][i] is the index of the least value among i up to but
+	// excluding i+2^k
+	levels [][]int32
+}
+
+// New returns a table over values ordered by less. values must not be
+// modified afterwards.
+// Time complexity: O(n log n)
+func New[T any](values []T, less func(a, b T) bool) *Table[T] {
+	t := &Table[T]{values: values, less: less}
+	if len(values) == 0 {
+		return t
+	}
+
+	first := make([]int32, len(values))
+	for i := range first {
+		first[i] = int32(i)
+	}
+	t.levels = append(t.levels, first)
+
+	for k := 1; 1<<k <= len(values); k++ {
+		prev, half := t.levels[k-1], 1<<(k-1)
+		level := make([]int32, len(values)-1<<k+1)
+		for i := range level {
+			level[i] = t.min(prev[i], prev[i+half])
+		}
+		t.levels = append(t.levels, level)
+	}
+	return t
+}
+
+// min returns whichever of indexes a and b holds the lesser value, a on ties
+func (t *Table[T]) min(a, b int32) int32 {
+	if t.less(t.values[b], t.values[a]) {
+		return b
+	}
+	return a
+}
+
+// Len returns the number of values
+func (t *Table[T]) Len() int { return len(t.values) }
+
+// At returns value i
+func (t *Table[T]) At(i int) T { return t.values[i] }
+
+// Index returns the index of the least value among lo up to but excluding
+// hi, the first one of several equal values. It panics if the range is
+// empty or out of bounds.
+// Time complexity: O(1)
+func (t *Table[T]) Index(lo, hi int) int {
+	if lo < 0 || hi > len(t.values) || lo >= hi {
+		panic("sparsetable: invalid range")
+	}
+
+	// two possibly overlapping ranges of the same power of two size
+	k := bits.Len(uint(hi-lo)) - 1
+	return int(t.min(t.levels[k][lo], t.levels[k][hi-1<<k]))
+}
+
+// Min returns the least value among lo up to but excluding hi. It panics if
+// the range is empty or out of bounds.
+// Time complexity: O(1)
+func (t *Table[T]) Min(lo, hi int) T {
+	return t.values[t.Index(lo, hi)]
+}
+
+// LCA answers lowest common ancestor queries on a fixed forest in O(1),
+// after an O(n log n) build, by range minimum queries over the depths of an
+// Euler tour.
+type LCA struct {
+	first  []int // position of every node in the tour
+	tree   []int // root of the tree of every node
+	tour   []int
+	depths *Table[int]
+}
+
+// NewLCA returns an LCA structure for the forest whose nodes are numbered 0
+// to len(parent)-1, where parent[i] is the parent of node i, or -1 if i is a
+// root. It panics if parent contains a cycle.
+// Time complexity: O(n log n)
+func NewLCA(parent []int) *LCA {
+	n := len(parent)
+	children := make([][]int, n)
+	var roots []int
+	for i, p := range parent {
+		if p < 0 {
+			roots = append(roots, i)
+		} else {
+			children[p] = append(children[p], i)
+		}
+	}
+
+	l := &LCA{first: make([]int, n), tree: make([]int, n), tour: make([]int, 0, 2*n)}
+	depth := make([]int, 0, 2*n)
+
+	type frame struct{ node, next, depth int }
+	visited := 0
+	for _, root := range roots {
+		stack := []frame{{root, 0, 0}}
+		l.first[root] = len(l.tour)
+		l.tree[root] = root
+		visited++
+
+		for len(stack) > 0 {
+			f := &stack[len(stack)-1]
+			l.tour = append(l.tour, f.node)
+			depth = append(depth, f.depth)
+
+			if f.next < len(children[f.node]) {
+				c := children[f.node][f.next]
+				f.next++
+				l.first[c] = len(l.tour)
+				l.tree[c] = root
+				visited++
+				stack = append(stack, frame{c, 0, f.depth + 1})
+				continue
+			}
+
+			// the parent is recorded again when it is back on top
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if visited != n {
+		panic("sparsetable: parent contains a cycle")
+	}
+
+	l.depths = New(depth, func(a, b int) bool { return a < b })
+	return l
+}
+
+// Query returns the lowest common ancestor of nodes u and v, or -1 if they
+// are in different trees.
+// Time complexity: O(1)
+func (l *LCA) Query(u, v int) int {
+	if l.tree[u] != l.tree[v] {
+		return -1
+	}
+
+	a, b := l.first[u], l.first[v]
+	if a > b {
+		a, b = b, a
+	}
+	return l.tour[l.depths.Index(a, b+1)]
+}
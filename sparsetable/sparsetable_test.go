@@ -0,0 +1,85 @@
+package sparsetable
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRangeMinimum(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	values := make([]int, 200)
+	for i := range values {
+		values[i] = rng.Intn(50)
+	}
+	table := New(values, func(a, b int) bool { return a < b })
+	maxTable := New(values, func(a, b int) bool { return a > b })
+
+	for lo := 0; lo < len(values); lo++ {
+		for hi := lo + 1; hi <= len(values); hi += 1 + rng.Intn(10) {
+			want := lo
+			for i := lo; i < hi; i++ {
+				if values[i] < values[want] {
+					want = i
+				}
+			}
+			if got := table.Index(lo, hi); got != want {
+				t.Fatalf("Index(%d, %d) = %d, want %d", lo, hi, got, want)
+			}
+			if got := table.Min(lo, hi); got != values[want] {
+				t.Fatalf("Min(%d, %d) = %d, want %d", lo, hi, got, values[want])
+			}
+
+			m := values[lo]
+			for _, v := range values[lo:hi] {
+				if v > m {
+					m = v
+				}
+			}
+			if got := maxTable.Min(lo, hi); got != m {
+				t.Fatalf("maximum of [%d, %d) = %d, want %d", lo, hi, got, m)
+			}
+		}
+	}
+}
+
+func TestEmptyRangePanics(t *testing.T) {
+	table := New([]int{1, 2}, func(a, b int) bool { return a < b })
+	defer func() {
+		if recover() == nil {
+			t.Fatal("empty range did not panic")
+		}
+	}()
+	table.Min(1, 1)
+}
+
+func TestLCA(t *testing.T) {
+	//       0        5
+	//     /   \      |
+	//    1     2     6
+	//   / \    |
+	//  3   4   7
+	parent := []int{-1, 0, 0, 1, 1, -1, 5, 2}
+	l := NewLCA(parent)
+
+	for _, q := range []struct{ u, v, want int }{
+		{3, 4, 1},
+		{3, 7, 0},
+		{4, 1, 1},
+		{7, 7, 7},
+		{6, 5, 5},
+		{3, 6, -1},
+	} {
+		if got := l.Query(q.u, q.v); got != q.want {
+			t.Errorf("Query(%d, %d) = %d, want %d", q.u, q.v, got, q.want)
+		}
+	}
+}
+
+func TestLCACycle(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("cyclic parents did not panic")
+		}
+	}()
+	NewLCA([]int{1, 2, 0})
+}
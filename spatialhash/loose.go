@@ -0,0 +1,218 @@
+package spatialhash
+
+import (
+	"math"
+
+	"github.com/hanyangtay/go-datastructures/quadtree"
+)
+
+// maxLevels is the number of levels of a LooseGrid. Boxes too large for the
+// top level are kept in a list of their own, which every query scans.
+const maxLevels = 48
+
+// Box is a rectangle stored in a LooseGrid, with an associated value
+type Box[V any] struct {
+	Rect  quadtree.Rect
+	Value V
+
+	grid  *LooseGrid[V]
+	level int // -1 for boxes larger than the top level
+	cell  cell
+	index int // position in the bucket
+	seq   uint64
+}
+
+// LooseGrid is a hierarchical grid of rectangles, the flat counterpart of a
+// loose quadtree: level l is a spatial hash of cells 2^l times the base
+// size, and every box is stored in the cell holding its center at the
+// lowest level whose cells are at least as large as the box. Each cell
+// thereby holds boxes reaching up to half a cell beyond it, so a box moving
+// within its cell stays put, and any other move, insertion or removal is a
+// constant time computation of the new cell rather than a walk down a tree.
+// This suits the broad phase of collision detection between many moving
+// objects, e.g. in games, with the base size set to that of the smallest
+// objects. Like Grid it is unbounded.
+type LooseGrid[V any] struct {
+	size   float64
+	levels [maxLevels]map[cell][]*Box[V]
+	large  []*Box[V]
+	length int
+	seq    uint64
+}
+
+// NewLooseGrid returns an empty grid whose lowest level has cells of the
+// given size. It panics unless size is positive and finite.
+func NewLooseGrid[V any](size float64) *LooseGrid[V] {
+	if !(size > 0) || math.IsInf(size, 1) {
+		panic("spatialhash: cell size must be positive")
+	}
+	return &LooseGrid[V]{size: size}
+}
+
+// Len returns the number of boxes
+func (g *LooseGrid[V]) Len() int { return g.length }
+
+// CellSize returns the side length of the cells of the lowest level
+func (g *LooseGrid[V]) CellSize() float64 { return g.size }
+
+// slot returns the level and cell a box of rectangle r belongs in
+func (g *LooseGrid[V]) slot(r quadtree.Rect) (int, cell) {
+	extent := max(r.Max.X-r.Min.X, r.Max.Y-r.Min.Y)
+
+	level := 0
+	for level < maxLevels && math.Ldexp(g.size, level) < extent {
+		level++
+	}
+	if level == maxLevels || math.IsNaN(extent) {
+		return -1, cell{}
+	}
+
+	s := math.Ldexp(g.size, level)
+	return level, cell{coord(r.Min.X/2+r.Max.X/2, s), coord(r.Min.Y/2+r.Max.Y/2, s)}
+}
+
+// Insert adds a box for r and returns it, to be passed to Move and Remove.
+// Time complexity: O(1)
+func (g *LooseGrid[V]) Insert(r quadtree.Rect, value V) *Box[V] {
+	g.seq++
+	b := &Box[V]{Rect: r, Value: value, grid: g, seq: g.seq}
+	b.level, b.cell = g.slot(r)
+	g.place(b)
+	g.length++
+	return b
+}
+
+// place adds b to the bucket of its level and cell
+func (g *LooseGrid[V]) place(b *Box[V]) {
+	if b.level < 0 {
+		b.index = len(g.large)
+		g.large = append(g.large, b)
+		return
+	}
+
+	cells := g.levels[b.level]
+	if cells == nil {
+		cells = make(map[cell][]*Box[V])
+		g.levels[b.level] = cells
+	}
+	bucket := cells[b.cell]
+	b.index = len(bucket)
+	cells[b.cell] = append(bucket, b)
+}
+
+// unlink removes b from its bucket, dropping the bucket once empty
+func (g *LooseGrid[V]) unlink(b *Box[V]) {
+	if b.level < 0 {
+		g.large = removeBox(g.large, b)
+		return
+	}
+
+	cells := g.levels[b.level]
+	if bucket := removeBox(cells[b.cell], b); len(bucket) == 0 {
+		delete(cells, b.cell)
+	} else {
+		cells[b.cell] = bucket
+	}
+}
+
+// removeBox removes b from bucket by moving the last box into its place
+func removeBox[V any](bucket []*Box[V], b *Box[V]) []*Box[V] {
+	last := len(bucket) - 1
+	bucket[b.index] = bucket[last]
+	bucket[b.index].index = b.index
+	bucket[last] = nil
+	return bucket[:last]
+}
+
+// Remove removes b and reports whether it was in g. Time complexity: O(1)
+func (g *LooseGrid[V]) Remove(b *Box[V]) bool {
+	if b.grid != g {
+		return false
+	}
+	g.unlink(b)
+	b.grid = nil
+	g.length--
+	return true
+}
+
+// Move changes the rectangle of b to r, relocating it only if it changes
+// level or cell. It panics if b is not in g. Time complexity: O(1)
+func (g *LooseGrid[V]) Move(b *Box[V], r quadtree.Rect) {
+	if b.grid != g {
+		panic("spatialhash: Move of a box not in the grid")
+	}
+
+	b.Rect = r
+	level, c := g.slot(r)
+	if level == b.level && c == b.cell {
+		return
+	}
+	g.unlink(b)
+	b.level, b.cell = level, c
+	g.place(b)
+}
+
+// Query calls fn for every box intersecting r until fn returns false
+func (g *LooseGrid[V]) Query(r quadtree.Rect, fn func(b *Box[V]) bool) {
+	if r.Min.X > r.Max.X || r.Min.Y > r.Max.Y {
+		return
+	}
+
+	visit := func(bucket []*Box[V]) bool {
+		for _, b := range bucket {
+			if b.Rect.Intersects(r) && !fn(b) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for level, cells := range g.levels {
+		if len(cells) == 0 {
+			continue
+		}
+
+		// a box reaches at most half a cell beyond the cell of its center
+		s := math.Ldexp(g.size, level)
+		lo := cell{coord(r.Min.X-1.5*s, s), coord(r.Min.Y-1.5*s, s)}
+		hi := cell{coord(r.Max.X+s/2, s), coord(r.Max.Y+s/2, s)}
+		if !eachCell(cells, lo, hi, visit) {
+			return
+		}
+	}
+	visit(g.large)
+}
+
+// Pairs calls fn once for every two boxes whose rectangles intersect, until
+// fn returns false
+func (g *LooseGrid[V]) Pairs(fn func(a, b *Box[V]) bool) {
+	g.Each(func(a *Box[V]) bool {
+		done := false
+		g.Query(a.Rect, func(b *Box[V]) bool {
+			if a.seq < b.seq && !fn(a, b) {
+				done = true
+			}
+			return !done
+		})
+		return !done
+	})
+}
+
+// Each calls fn for every box, in no particular order, until fn returns
+// false
+func (g *LooseGrid[V]) Each(fn func(b *Box[V]) bool) {
+	for _, cells := range g.levels {
+		for _, bucket := range cells {
+			for _, b := range bucket {
+				if !fn(b) {
+					return
+				}
+			}
+		}
+	}
+	for _, b := range g.large {
+		if !fn(b) {
+			return
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package spatialhash
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/hanyangtay/go-datastructures/quadtree"
+)
+
+// randomBox returns a rectangle of a size from tiny to huge
+func randomBox(rng *rand.Rand) quadtree.Rect {
+	p := randomPoint(rng)
+	size := rng.ExpFloat64() * 5
+	if rng.Intn(50) == 0 {
+		size = 1e20
+	}
+	return quadtree.Rect{Min: p, Max: quadtree.Point{X: p.X + size*rng.Float64(), Y: p.Y + size*rng.Float64()}}
+}
+
+// TestLooseGrid checks queries and pairs against a list of the boxes after
+// random inserts, moves and removals
+func TestLooseGrid(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := NewLooseGrid[int](1)
+	var boxes []*Box[int]
+
+	for i := 0; i < 2000; i++ {
+		switch op := rng.Intn(4); {
+		case op == 0 && len(boxes) > 0:
+			j := rng.Intn(len(boxes))
+			if !g.Remove(boxes[j]) || g.Remove(boxes[j]) {
+				t.Fatalf("Remove did not remove box %d exactly once", boxes[j].Value)
+			}
+			boxes[j] = boxes[len(boxes)-1]
+			boxes = boxes[:len(boxes)-1]
+		case op == 1 && len(boxes) > 0:
+			g.Move(boxes[rng.Intn(len(boxes))], randomBox(rng))
+		default:
+			boxes = append(boxes, g.Insert(randomBox(rng), i))
+		}
+		if g.Len() != len(boxes) {
+			t.Fatalf("got length %d, want %d", g.Len(), len(boxes))
+		}
+
+		if i%50 == 0 {
+			r := randomBox(rng)
+			want := make(map[*Box[int]]bool)
+			for _, b := range boxes {
+				if b.Rect.Intersects(r) {
+					want[b] = true
+				}
+			}
+			n := 0
+			g.Query(r, func(b *Box[int]) bool {
+				if !want[b] {
+					t.Fatalf("Query(%v) returned %v", r, b.Rect)
+				}
+				n++
+				return true
+			})
+			if n != len(want) {
+				t.Fatalf("Query(%v) returned %d boxes, want %d", r, n, len(want))
+			}
+		}
+	}
+
+	want := 0
+	for j, a := range boxes {
+		for _, b := range boxes[j+1:] {
+			if a.Rect.Intersects(b.Rect) {
+				want++
+			}
+		}
+	}
+	n := 0
+	g.Pairs(func(a, b *Box[int]) bool {
+		if !a.Rect.Intersects(b.Rect) {
+			t.Fatalf("Pairs returned disjoint %v and %v", a.Rect, b.Rect)
+		}
+		n++
+		return true
+	})
+	if n != want {
+		t.Fatalf("Pairs returned %d pairs, want %d", n, want)
+	}
+}
@@ -0,0 +1,211 @@
+// Package spatialhash provides a spatial hash: points bucketed in a uniform
+// grid of square cells, found by hashing the cell coordinates, and a loose
+// hierarchical grid of rectangles for moving objects.
+package spatialhash
+
+import (
+	"math"
+
+	"github.com/hanyangtay/go-datastructures/quadtree"
+)
+
+// cell is the position of a grid cell, counted in cells from the origin
+type cell struct {
+	x, y int64
+}
+
+// Item is a point stored in a Grid, with an associated value
+type Item[V any] struct {
+	Point quadtree.Point
+	Value V
+
+	grid  *Grid[V]
+	cell  cell
+	index int // position in the cell's bucket
+}
+
+// Grid is a spatial hash of points. Space is divided into square cells of a
+// fixed size, and only cells holding points are stored, in a map, so the
+// grid is unbounded. Inserting, moving and removing a point takes O(1), and
+// a query visits the cells overlapping its area. For points spread about
+// evenly, with a few per cell, this beats trees, whose updates cost
+// O(log n): it is the usual broad phase of particle and crowd simulations,
+// with the cell size set to the typical query radius. Dense clusters make
+// single cells slow, though; prefer a quadtree for such data.
+type Grid[V any] struct {
+	size   float64
+	cells  map[cell][]*Item[V]
+	length int
+}
+
+// New returns an empty grid of cells of the given size. It panics unless
+// size is positive and finite.
+func New[V any](size float64) *Grid[V] {
+	if !(size > 0) || math.IsInf(size, 1) {
+		panic("spatialhash: cell size must be positive")
+	}
+	return &Grid[V]{size: size, cells: make(map[cell][]*Item[V])}
+}
+
+// Len returns the number of items
+func (g *Grid[V]) Len() int { return g.length }
+
+// CellSize returns the side length of the cells
+func (g *Grid[V]) CellSize() float64 { return g.size }
+
+// coord returns the coordinate of the cell of the given size holding v,
+// clamped to the range of cells
+func coord(v, size float64) int64 {
+	c := math.Floor(v / size)
+	switch {
+	case c >= math.MaxInt64:
+		return math.MaxInt64
+	case c <= math.MinInt64:
+		return math.MinInt64
+	}
+	return int64(c)
+}
+
+func (g *Grid[V]) cellOf(p quadtree.Point) cell {
+	return cell{coord(p.X, g.size), coord(p.Y, g.size)}
+}
+
+// Insert adds an item for p and returns it, to be passed to Move and Remove.
+// Time complexity: O(1)
+func (g *Grid[V]) Insert(p quadtree.Point, value V) *Item[V] {
+	item := &Item[V]{Point: p, Value: value, grid: g}
+	g.place(item)
+	g.length++
+	return item
+}
+
+// place adds item to the bucket of the cell of its point
+func (g *Grid[V]) place(item *Item[V]) {
+	item.cell = g.cellOf(item.Point)
+	bucket := g.cells[item.cell]
+	item.index = len(bucket)
+	g.cells[item.cell] = append(bucket, item)
+}
+
+// unlink removes item from its bucket, dropping the bucket once empty
+func (g *Grid[V]) unlink(item *Item[V]) {
+	bucket := g.cells[item.cell]
+	last := len(bucket) - 1
+	bucket[item.index] = bucket[last]
+	bucket[item.index].index = item.index
+	bucket[last] = nil
+
+	if last == 0 {
+		delete(g.cells, item.cell)
+	} else {
+		g.cells[item.cell] = bucket[:last]
+	}
+}
+
+// Remove removes item and reports whether it was in g.
+// Time complexity: O(1)
+func (g *Grid[V]) Remove(item *Item[V]) bool {
+	if item.grid != g {
+		return false
+	}
+	g.unlink(item)
+	item.grid = nil
+	g.length--
+	return true
+}
+
+// Move changes the point of item to p, rehashing it only if it leaves its
+// cell. It panics if item is not in g. Time complexity: O(1)
+func (g *Grid[V]) Move(item *Item[V], p quadtree.Point) {
+	if item.grid != g {
+		panic("spatialhash: Move of an item not in the grid")
+	}
+
+	item.Point = p
+	if g.cellOf(p) == item.cell {
+		return
+	}
+	g.unlink(item)
+	g.place(item)
+}
+
+// QueryRect calls fn for every item inside r, including its boundary, until
+// fn returns false. If r covers more cells than are occupied, the occupied
+// cells are scanned instead.
+func (g *Grid[V]) QueryRect(r quadtree.Rect, fn func(item *Item[V]) bool) {
+	lo, hi := g.cellOf(r.Min), g.cellOf(r.Max)
+	if lo.x > hi.x || lo.y > hi.y {
+		return
+	}
+
+	visit := func(bucket []*Item[V]) bool {
+		for _, item := range bucket {
+			if r.Contains(item.Point) && !fn(item) {
+				return false
+			}
+		}
+		return true
+	}
+
+	eachCell(g.cells, lo, hi, visit)
+}
+
+// eachCell calls visit for the buckets of the cells from lo to hi until
+// visit returns false, and reports whether all calls returned true. If the
+// cells outnumber the occupied ones, the occupied cells are scanned instead.
+func eachCell[B any](cells map[cell][]B, lo, hi cell, visit func(bucket []B) bool) bool {
+	// count in floating point, as the span may overflow an int64
+	if span := (float64(hi.x) - float64(lo.x) + 1) * (float64(hi.y) - float64(lo.y) + 1); span > float64(len(cells)) {
+		for c, bucket := range cells {
+			if lo.x <= c.x && c.x <= hi.x && lo.y <= c.y && c.y <= hi.y && !visit(bucket) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// the loops stop at the last cell rather than past it, which could
+	// overflow
+	for x := lo.x; ; x++ {
+		for y := lo.y; ; y++ {
+			if bucket, ok := cells[cell{x, y}]; ok && !visit(bucket) {
+				return false
+			}
+			if y == hi.y {
+				break
+			}
+		}
+		if x == hi.x {
+			break
+		}
+	}
+	return true
+}
+
+// QueryRadius calls fn for every item within radius of center, including
+// those at exactly that distance, until fn returns false
+func (g *Grid[V]) QueryRadius(center quadtree.Point, radius float64, fn func(item *Item[V]) bool) {
+	r := quadtree.Rect{
+		Min: quadtree.Point{X: center.X - radius, Y: center.Y - radius},
+		Max: quadtree.Point{X: center.X + radius, Y: center.Y + radius},
+	}
+	g.QueryRect(r, func(item *Item[V]) bool {
+		dx, dy := item.Point.X-center.X, item.Point.Y-center.Y
+		if dx*dx+dy*dy > radius*radius {
+			return true
+		}
+		return fn(item)
+	})
+}
+
+// Each calls fn for every item, in no particular order, until fn returns
+// false
+func (g *Grid[V]) Each(fn func(item *Item[V]) bool) {
+	for _, bucket := range g.cells {
+		for _, item := range bucket {
+			if !fn(item) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package spatialhash
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/hanyangtay/go-datastructures/quadtree"
+)
+
+func randomPoint(rng *rand.Rand) quadtree.Point {
+	return quadtree.Point{X: rng.Float64()*200 - 100, Y: rng.Float64()*200 - 100}
+}
+
+// TestGrid checks rectangle and radius queries against a list of the items
+// after random inserts, moves and removals, on both sides of the origin
+func TestGrid(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	g := New[int](7)
+	var items []*Item[int]
+
+	for i := 0; i < 3000; i++ {
+		switch op := rng.Intn(4); {
+		case op == 0 && len(items) > 0:
+			j := rng.Intn(len(items))
+			if !g.Remove(items[j]) || g.Remove(items[j]) {
+				t.Fatalf("Remove did not remove item %d exactly once", items[j].Value)
+			}
+			items[j] = items[len(items)-1]
+			items = items[:len(items)-1]
+		case op == 1 && len(items) > 0:
+			g.Move(items[rng.Intn(len(items))], randomPoint(rng))
+		default:
+			items = append(items, g.Insert(randomPoint(rng), i))
+		}
+		if g.Len() != len(items) {
+			t.Fatalf("got length %d, want %d", g.Len(), len(items))
+		}
+
+		if i%50 != 0 {
+			continue
+		}
+		c, radius := randomPoint(rng), rng.Float64()*30
+		r := quadtree.Rect{Min: c, Max: quadtree.Point{X: c.X + radius, Y: c.Y + radius}}
+		inRect, inRadius := 0, 0
+		for _, item := range items {
+			if r.Contains(item.Point) {
+				inRect++
+			}
+			if math.Hypot(item.Point.X-c.X, item.Point.Y-c.Y) <= radius {
+				inRadius++
+			}
+		}
+
+		n := 0
+		g.QueryRect(r, func(item *Item[int]) bool {
+			if !r.Contains(item.Point) {
+				t.Fatalf("QueryRect(%v) returned %v outside", r, item.Point)
+			}
+			n++
+			return true
+		})
+		if n != inRect {
+			t.Fatalf("QueryRect(%v) returned %d items, want %d", r, n, inRect)
+		}
+
+		n = 0
+		g.QueryRadius(c, radius, func(item *Item[int]) bool {
+			n++
+			return true
+		})
+		if n != inRadius {
+			t.Fatalf("QueryRadius(%v, %v) returned %d items, want %d", c, radius, n, inRadius)
+		}
+	}
+
+	n := 0
+	g.Each(func(item *Item[int]) bool {
+		n++
+		return true
+	})
+	if n != len(items) {
+		t.Fatalf("Each visited %d items, want %d", n, len(items))
+	}
+	if New[int](7).Remove(items[0]) {
+		t.Fatal("removed an item of another grid")
+	}
+}
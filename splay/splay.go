@@ -0,0 +1,334 @@
+// Package splay provides a generic self-adjusting splay tree ordered map.
+package splay
+
+import (
+	"github.com/hanyangtay/go-datastructures/ordered"
+)
+
+var _ ordered.Map[int, int] = (*Tree[int, int])(nil)
+
+type node[K, V any] struct {
+	key         K
+	value       V
+	size        int // number of nodes in the subtree
+	left, right *node[K, V]
+}
+
+func size[K, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *node[K, V]) update() {
+	n.size = 1 + size(n.left) + size(n.right)
+}
+
+// Tree is an ordered map from keys to values, with keys ordered by a less
+// function. Every access splays the entry it finds to the root, so that
+// recently used keys, and keys near them, are found quickly again. Any
+// sequence of m operations takes O(m log n) time, although a single one may
+// take O(n).
+//
+// Since lookups restructure the tree, a Tree must not be read concurrently
+// without synchronization either.
+type Tree[K, V any] struct {
+	root *node[K, V]
+	less func(a, b K) bool
+}
+
+// New returns an empty tree ordered by less
+func New[K, V any](less func(a, b K) bool) *Tree[K, V] {
+	return &Tree[K, V]{less: less}
+}
+
+// Len returns the number of entries
+func (t *Tree[K, V]) Len() int { return size(t.root) }
+
+// splay rearranges the tree at n so that the node with key, or else the last
+// node visited looking for it, becomes the root, and returns the new root.
+// This is the top-down variant: the nodes passed are hung off a left tree of
+// smaller keys and a right tree of greater keys, which are reassembled under
+// the new root at the end.
+func (t *Tree[K, V]) splay(n *node[K, V], key K) *node[K, V] {
+	if n == nil {
+		return nil
+	}
+
+	// header.right holds the left tree and header.left the right tree
+	var header node[K, V]
+	l, r := &header, &header
+	var lpath, rpath []*node[K, V]
+
+	for {
+		if t.less(key, n.key) {
+			if n.left == nil {
+				break
+			}
+			if t.less(key, n.left.key) {
+				// zig-zig: rotate right
+				y := n.left
+				n.left = y.right
+				y.right = n
+				n.update()
+				n = y
+				if n.left == nil {
+					break
+				}
+			}
+			r.left = n
+			r = n
+			rpath = append(rpath, n)
+			n = n.left
+		} else if t.less(n.key, key) {
+			if n.right == nil {
+				break
+			}
+			if t.less(n.right.key, key) {
+				// zig-zig: rotate left
+				y := n.right
+				n.right = y.left
+				y.left = n
+				n.update()
+				n = y
+				if n.right == nil {
+					break
+				}
+			}
+			l.right = n
+			l = n
+			lpath = append(lpath, n)
+			n = n.right
+		} else {
+			break
+		}
+	}
+
+	l.right, r.left = n.left, n.right
+	n.left, n.right = header.right, header.left
+
+	// the sizes along the spines of the left and right trees have changed
+	for i := len(lpath) - 1; i >= 0; i-- {
+		lpath[i].update()
+	}
+	for i := len(rpath) - 1; i >= 0; i-- {
+		rpath[i].update()
+	}
+	n.update()
+	return n
+}
+
+// found splays key to the root and reports whether it is present
+func (t *Tree[K, V]) found(key K) bool {
+	t.root = t.splay(t.root, key)
+	return t.root != nil && !t.less(key, t.root.key) && !t.less(t.root.key, key)
+}
+
+// Get returns the value stored under key, and whether it was found
+func (t *Tree[K, V]) Get(key K) (V, bool) {
+	if t.found(key) {
+		return t.root.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores value under key, replacing any previous value.
+// Time complexity: O(log n) amortized
+func (t *Tree[K, V]) Put(key K, value V) {
+	if t.found(key) {
+		t.root.value = value
+		return
+	}
+
+	n := &node[K, V]{key: key, value: value}
+	if root := t.root; root != nil {
+		if t.less(key, root.key) {
+			n.left, n.right = root.left, root
+			root.left = nil
+		} else {
+			n.left, n.right = root, root.right
+			root.right = nil
+		}
+		root.update()
+	}
+	n.update()
+	t.root = n
+}
+
+// Delete removes key and reports whether it was present.
+// Time complexity: O(log n) amortized
+func (t *Tree[K, V]) Delete(key K) bool {
+	if !t.found(key) {
+		return false
+	}
+	t.root = t.join(t.root.left, t.root.right, key)
+	return true
+}
+
+// join links two trees where every key of a is less than key and every key
+// of b greater, by splaying the greatest key of a to its root
+func (t *Tree[K, V]) join(a, b *node[K, V], key K) *node[K, V] {
+	if a == nil {
+		return b
+	}
+	a = t.splay(a, key)
+	a.right = b
+	a.update()
+	return a
+}
+
+// Min returns the entry with the least key, or false if the tree is empty
+func (t *Tree[K, V]) Min() (K, V, bool) {
+	n := t.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	return t.splayEntry(n)
+}
+
+// Max returns the entry with the greatest key, or false if the tree is empty
+func (t *Tree[K, V]) Max() (K, V, bool) {
+	n := t.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+	return t.splayEntry(n)
+}
+
+// Floor returns the entry with the greatest key not greater than key, or
+// false if there is none
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		if t.less(key, n.key) {
+			n = n.left
+		} else {
+			best = n
+			n = n.right
+		}
+	}
+	return t.splayEntry(best)
+}
+
+// Ceiling returns the entry with the least key not less than key, or false
+// if there is none
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		if t.less(n.key, key) {
+			n = n.right
+		} else {
+			best = n
+			n = n.left
+		}
+	}
+	return t.splayEntry(best)
+}
+
+// splayEntry splays n to the root and unpacks it, reporting false if it is
+// nil
+func (t *Tree[K, V]) splayEntry(n *node[K, V]) (K, V, bool) {
+	if n == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	t.root = t.splay(t.root, n.key)
+	return n.key, n.value, true
+}
+
+// Ascend calls fn for every entry in key order until fn returns false
+func (t *Tree[K, V]) Ascend(fn func(key K, value V) bool) {
+	t.walk(t.root, nil, fn)
+}
+
+// Range calls fn in key order for every entry with a key from lo up to but
+// excluding hi, until fn returns false. The tree is not restructured.
+func (t *Tree[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	var stack []*node[K, V]
+	for n := t.root; n != nil; {
+		if t.less(n.key, lo) {
+			n = n.right
+		} else {
+			stack = append(stack, n)
+			n = n.left
+		}
+	}
+	t.walk(nil, stack, func(key K, value V) bool {
+		return t.less(key, hi) && fn(key, value)
+	})
+}
+
+// walk visits in order the nodes below n, after those on stack and their
+// right subtrees, until fn returns false. An explicit stack is used since the
+// tree may be arbitrarily deep.
+func (t *Tree[K, V]) walk(n *node[K, V], stack []*node[K, V], fn func(key K, value V) bool) {
+	for n != nil || len(stack) > 0 {
+		for ; n != nil; n = n.left {
+			stack = append(stack, n)
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !fn(n.key, n.value) {
+			return
+		}
+		n = n.right
+	}
+}
+
+// Split removes the entries with keys not less than key from t and returns
+// them as a new tree. Time complexity: O(log n) amortized
+func (t *Tree[K, V]) Split(key K) *Tree[K, V] {
+	other := New[K, V](t.less)
+	root := t.splay(t.root, key)
+	if root == nil {
+		return other
+	}
+
+	if t.less(root.key, key) {
+		other.root, root.right = root.right, nil
+		root.update()
+		t.root = root
+	} else {
+		t.root, root.left = root.left, nil
+		root.update()
+		other.root = root
+	}
+	return other
+}
+
+// Join moves all entries of other into t, leaving other empty. Every key of
+// other must be greater than every key of t; Join panics otherwise.
+// Time complexity: O(log n) amortized
+func (t *Tree[K, V]) Join(other *Tree[K, V]) {
+	if t.root == nil {
+		t.root, other.root = other.root, nil
+		return
+	}
+	if other.root == nil {
+		return
+	}
+
+	// bring the greatest key of t and the least key of other to the roots
+	maxKey, _, _ := t.Max()
+	minKey, _, _ := other.Min()
+	if !t.less(maxKey, minKey) {
+		panic("splay: Join of overlapping key ranges")
+	}
+
+	t.root.right = other.root
+	t.root.update()
+	other.root = nil
+}
+
+// MarshalBinary encodes the tree with ordered.Marshal
+func (t *Tree[K, V]) MarshalBinary() ([]byte, error) {
+	return ordered.Marshal[K, V](t)
+}
+
+// UnmarshalBinary replaces the entries of the tree as ordered.UnmarshalInto does
+func (t *Tree[K, V]) UnmarshalBinary(data []byte) error {
+	return ordered.UnmarshalInto[K, V](t, data, func() { t.root = nil })
+}
@@ -0,0 +1,226 @@
+// Package suffixarray provides suffix arrays with longest common prefix
+// arrays, for substring search and repeat finding over a fixed text.
+package suffixarray
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Index is a suffix array over a text: the starting offsets of all its
+// suffixes in lexicographic order, along with the length of the longest
+// common prefix of every two adjacent suffixes.
+type Index struct {
+	text []byte
+	sa   []int
+	lcp  []int
+}
+
+// New builds an index over text, which must not be modified afterwards.
+// Time complexity: O(n log n)
+func New(text []byte) *Index {
+	symbols := make([]int, len(text))
+	for i, c := range text {
+		symbols[i] = int(c)
+	}
+	sa := build(symbols, 256)
+	return &Index{text: text, sa: sa, lcp: kasai(symbols, sa)}
+}
+
+// Text returns the indexed text
+func (x *Index) Text() []byte { return x.text }
+
+// Suffixes returns the suffix array: the offsets of all suffixes of the text
+// in lexicographic order. It must not be modified.
+func (x *Index) Suffixes() []int { return x.sa }
+
+// LCP returns the length of the longest common prefix of every suffix with
+// the one before it in the suffix array, 0 for the first one. It must not be
+// modified.
+func (x *Index) LCP() []int { return x.lcp }
+
+// bounds returns the range of the suffix array holding the suffixes that
+// start with pattern
+func (x *Index) bounds(pattern []byte) (int, int) {
+	prefix := func(i int) []byte {
+		s := x.text[x.sa[i]:]
+		if len(s) > len(pattern) {
+			s = s[:len(pattern)]
+		}
+		return s
+	}
+	lo := sort.Search(len(x.sa), func(i int) bool { return bytes.Compare(prefix(i), pattern) >= 0 })
+	hi := sort.Search(len(x.sa), func(i int) bool { return bytes.Compare(prefix(i), pattern) > 0 })
+	return lo, hi
+}
+
+// Lookup returns the offsets of all occurrences of pattern in the text, in
+// increasing order.
+// Time complexity: O(m log n + k log k) for k occurrences
+func (x *Index) Lookup(pattern []byte) []int {
+	lo, hi := x.bounds(pattern)
+	offsets := append([]int(nil), x.sa[lo:hi]...)
+	sort.Ints(offsets)
+	return offsets
+}
+
+// Count returns the number of occurrences of pattern in the text.
+// Time complexity: O(m log n)
+func (x *Index) Count(pattern []byte) int {
+	lo, hi := x.bounds(pattern)
+	return hi - lo
+}
+
+// LongestRepeated returns the offset and length of the longest substring
+// occurring at least twice in the text, which may overlap. The length is 0
+// if no byte repeats.
+// Time complexity: O(n)
+func (x *Index) LongestRepeated() (offset, length int) {
+	for i, l := range x.lcp {
+		if l > length {
+			offset, length = x.sa[i], l
+		}
+	}
+	return offset, length
+}
+
+// LongestCommonSubstring returns the offsets in a and b and the length of
+// their longest common substring, which is found in a suffix array of both
+// texts joined by a separator. The length is 0 if they have no byte in
+// common.
+// Time complexity: O((len(a)+len(b)) log(len(a)+len(b)))
+func LongestCommonSubstring(a, b []byte) (offsetA, offsetB, length int) {
+	symbols := make([]int, 0, len(a)+len(b)+1)
+	for _, c := range a {
+		symbols = append(symbols, int(c))
+	}
+	// the separator sorts after every byte and occurs once, so that no
+	// common prefix extends across it
+	symbols = append(symbols, 256)
+	for _, c := range b {
+		symbols = append(symbols, int(c))
+	}
+
+	sa := build(symbols, 257)
+	lcp := kasai(symbols, sa)
+
+	for i := 1; i < len(sa); i++ {
+		p, q := sa[i-1], sa[i]
+		if (p < len(a)) == (q < len(a)) || lcp[i] <= length {
+			continue
+		}
+		if p > q {
+			p, q = q, p
+		}
+		offsetA, offsetB, length = p, q-len(a)-1, lcp[i]
+	}
+	return offsetA, offsetB, length
+}
+
+// build returns the suffix array of symbols from 0 to alphabet-1 by prefix
+// doubling: suffixes are sorted by their first 2^k symbols for increasing k,
+// with rank pairs from the previous round as radix sort keys
+func build(symbols []int, alphabet int) []int {
+	n := len(symbols)
+	sa := make([]int, n)
+	if n == 0 {
+		return sa
+	}
+
+	rank := make([]int, n)
+	tmp := make([]int, n)
+	count := make([]int, max(alphabet, n)+1)
+
+	// sort by first symbol
+	for _, s := range symbols {
+		count[s+1]++
+	}
+	for i := 1; i <= alphabet; i++ {
+		count[i] += count[i-1]
+	}
+	for i, s := range symbols {
+		sa[count[s]] = i
+		count[s]++
+	}
+	copy(rank, symbols)
+	classes := alphabet
+
+	for k := 1; ; k *= 2 {
+		// order by second half: suffixes too short to have one come first
+		j := 0
+		for i := n - k; i < n; i++ {
+			tmp[j] = i
+			j++
+		}
+		for _, i := range sa {
+			if i >= k {
+				tmp[j] = i - k
+				j++
+			}
+		}
+
+		// stable counting sort by first half
+		clear(count[:classes+1])
+		for _, i := range tmp {
+			count[rank[i]+1]++
+		}
+		for i := 1; i <= classes; i++ {
+			count[i] += count[i-1]
+		}
+		for _, i := range tmp {
+			sa[count[rank[i]]] = i
+			count[rank[i]]++
+		}
+
+		// rank the suffixes by their first 2k symbols
+		second := func(i int) int {
+			if i+k < n {
+				return rank[i+k]
+			}
+			return -1
+		}
+		tmp[sa[0]] = 0
+		classes = 1
+		for j := 1; j < n; j++ {
+			p, q := sa[j-1], sa[j]
+			if rank[p] != rank[q] || second(p) != second(q) {
+				classes++
+			}
+			tmp[q] = classes - 1
+		}
+		rank, tmp = tmp, rank
+
+		if classes == n {
+			return sa
+		}
+	}
+}
+
+// kasai returns the LCP array of symbols for suffix array sa, in linear time
+// by the algorithm of Kasai et al.: the common prefix of a suffix with its
+// predecessor is at most one shorter than that of the previous suffix
+func kasai(symbols []int, sa []int) []int {
+	n := len(symbols)
+	rank := make([]int, n)
+	for i, s := range sa {
+		rank[s] = i
+	}
+
+	lcp := make([]int, n)
+	h := 0
+	for i := 0; i < n; i++ {
+		if rank[i] == 0 {
+			h = 0
+			continue
+		}
+		j := sa[rank[i]-1]
+		for i+h < n && j+h < n && symbols[i+h] == symbols[j+h] {
+			h++
+		}
+		lcp[rank[i]] = h
+		if h > 0 {
+			h--
+		}
+	}
+	return lcp
+}
@@ -0,0 +1,96 @@
+package suffixarray
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func randomText(rng *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = "ab"[rng.Intn(2)]
+	}
+	return b
+}
+
+func TestSuffixesAndLCP(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, text := range [][]byte{nil, []byte("banana"), []byte("aaaa"), randomText(rng, 300)} {
+		x := New(text)
+		sa, lcp := x.Suffixes(), x.LCP()
+
+		want := make([]int, len(text))
+		for i := range want {
+			want[i] = i
+		}
+		sort.Slice(want, func(i, j int) bool { return bytes.Compare(text[want[i]:], text[want[j]:]) < 0 })
+
+		if len(sa) != len(want) || len(lcp) != len(want) {
+			t.Fatalf("%q: got %d suffixes and %d LCPs, want %d", text, len(sa), len(lcp), len(want))
+		}
+		for i := range want {
+			if sa[i] != want[i] {
+				t.Fatalf("%q: suffix %d is at %d, want %d", text, i, sa[i], want[i])
+			}
+			if i == 0 {
+				continue
+			}
+			a, b := text[sa[i-1]:], text[sa[i]:]
+			l := 0
+			for l < len(a) && l < len(b) && a[l] == b[l] {
+				l++
+			}
+			if lcp[i] != l {
+				t.Fatalf("%q: LCP %d is %d, want %d", text, i, lcp[i], l)
+			}
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	text := randomText(rng, 500)
+	x := New(text)
+
+	for i := 0; i < 100; i++ {
+		pattern := randomText(rng, 1+rng.Intn(6))
+
+		var want []int
+		for j := 0; j+len(pattern) <= len(text); j++ {
+			if bytes.Equal(text[j:j+len(pattern)], pattern) {
+				want = append(want, j)
+			}
+		}
+
+		got := x.Lookup(pattern)
+		if len(got) != len(want) || x.Count(pattern) != len(want) {
+			t.Fatalf("%q: found %d occurrences, counted %d, want %d", pattern, len(got), x.Count(pattern), len(want))
+		}
+		for j := range want {
+			if got[j] != want[j] {
+				t.Fatalf("%q: occurrence %d at %d, want %d", pattern, j, got[j], want[j])
+			}
+		}
+	}
+}
+
+func TestRepeats(t *testing.T) {
+	offset, length := New([]byte("mississippi")).LongestRepeated()
+	if length != 4 || string("mississippi"[offset:offset+length]) != "issi" {
+		t.Fatalf("LongestRepeated = %d, %d, want issi", offset, length)
+	}
+	if _, length := New([]byte("abc")).LongestRepeated(); length != 0 {
+		t.Fatalf("LongestRepeated of distinct bytes has length %d", length)
+	}
+
+	a, b := []byte("the quick brown fox"), []byte("a quick brown dog")
+	oa, ob, l := LongestCommonSubstring(a, b)
+	if got := string(a[oa : oa+l]); got != " quick brown " || string(b[ob:ob+l]) != got {
+		t.Fatalf("LongestCommonSubstring = %q at %d and %d", got, oa, ob)
+	}
+	if _, _, l := LongestCommonSubstring([]byte("ab"), []byte("cd")); l != 0 {
+		t.Fatalf("got common substring of length %d, want 0", l)
+	}
+}
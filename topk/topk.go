@@ -0,0 +1,78 @@
+// Package topk provides a bounded heap that keeps the k greatest items of a
+// stream.
+package topk
+
+import (
+	"sort"
+
+	"github.com/hanyangtay/go-datastructures/pq"
+)
+
+// TopK keeps the k greatest items added to it, by a less function, in a
+// min-heap of at most k items: an item replaces the least one kept if it is
+// greater, so the stream takes O(k) memory and O(log k) time per item. To
+// keep the k smallest items, such as the nearest neighbours of a point,
+// order by the reverse of less.
+type TopK[T any] struct {
+	k    int
+	heap *pq.PriorityQueue[T]
+	less func(a, b T) bool
+}
+
+// New returns an empty top-k of capacity k, ordered by less. It panics if k
+// is not positive.
+func New[T any](k int, less func(a, b T) bool) *TopK[T] {
+	if k < 1 {
+		panic("topk: capacity must be positive")
+	}
+	return &TopK[T]{k: k, heap: pq.New(less), less: less}
+}
+
+// Len returns the number of items kept, at most Cap
+func (t *TopK[T]) Len() int { return t.heap.Len() }
+
+// Cap returns k, the number of items to keep
+func (t *TopK[T]) Cap() int { return t.k }
+
+// Full reports whether k items are kept, so that later items must beat Min
+// to be kept
+func (t *TopK[T]) Full() bool { return t.heap.Len() == t.k }
+
+// Add offers x and reports whether it was kept. When full, x replaces the
+// least item kept if it is greater. Time complexity: O(log k)
+func (t *TopK[T]) Add(x T) bool {
+	if t.heap.Len() < t.k {
+		t.heap.Push(x)
+		return true
+	}
+	if !t.less(t.heap.Peek(), x) {
+		return false
+	}
+	t.heap.Pop()
+	t.heap.Push(x)
+	return true
+}
+
+// Min returns the least item kept, the one to beat once full. It panics if
+// no item is kept.
+func (t *TopK[T]) Min() T {
+	if t.heap.Len() == 0 {
+		panic("topk: Min of an empty top-k")
+	}
+	return t.heap.Peek()
+}
+
+// Result returns the items kept, greatest first, leaving them in place.
+// Time complexity: O(k log k)
+func (t *TopK[T]) Result() []T {
+	items := make([]T, 0, t.heap.Len())
+	t.heap.Each(func(x T) bool {
+		items = append(items, x)
+		return true
+	})
+	sort.Slice(items, func(i, j int) bool { return t.less(items[j], items[i]) })
+	return items
+}
+
+// Reset removes all items, keeping the allocated storage for reuse
+func (t *TopK[T]) Reset() { t.heap.Reset() }
@@ -0,0 +1,63 @@
+package topk
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func less(a, b int) bool { return a < b }
+
+func TestTopK(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, k := range []int{1, 5, 100} {
+		top := New(k, less)
+		var all []int
+		for i := 0; i < 1000; i++ {
+			x := rng.Intn(500)
+			top.Add(x)
+			all = append(all, x)
+
+			if top.Len() != min(len(all), k) || top.Full() != (len(all) >= k) {
+				t.Fatalf("k=%d: got length %d after %d items", k, top.Len(), len(all))
+			}
+		}
+
+		sort.Sort(sort.Reverse(sort.IntSlice(all)))
+		got := top.Result()
+		for i := range got {
+			if got[i] != all[i] {
+				t.Fatalf("k=%d: Result()[%d] = %d, want %d", k, i, got[i], all[i])
+			}
+		}
+		if top.Min() != all[k-1] || top.Cap() != k {
+			t.Fatalf("k=%d: Min() = %d, want %d", k, top.Min(), all[k-1])
+		}
+
+		// the result leaves the items in place
+		if len(top.Result()) != k {
+			t.Fatalf("k=%d: second Result returned %d items", k, len(top.Result()))
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	top := New(2, less)
+	if !top.Add(1) || !top.Add(3) || top.Add(0) || top.Add(1) || !top.Add(2) {
+		t.Fatal("Add reported the wrong items as kept")
+	}
+	if got := top.Result(); got[0] != 3 || got[1] != 2 {
+		t.Fatalf("got %v, want [3 2]", got)
+	}
+
+	top.Reset()
+	if top.Len() != 0 {
+		t.Fatalf("got length %d after Reset", top.Len())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Min of an empty top-k did not panic")
+		}
+	}()
+	top.Min()
+}
@@ -0,0 +1,133 @@
+package treap
+
+import (
+	"math/rand"
+)
+
+// Sequence is a list of values addressed by position, stored as a treap
+// ordered by position instead of by key (an implicit treap). Access, insertion
+// and removal at any position take O(log n) expected time, and sequences can
+// be cut and concatenated in O(log n), which makes it suitable for rope-like
+// editing of long sequences.
+type Sequence[T any] struct {
+	root *node[struct{}, T]
+	rng  *rand.Rand
+}
+
+// NewSequence returns an empty sequence
+func NewSequence[T any]() *Sequence[T] {
+	return &Sequence[T]{rng: rand.New(rand.NewSource(1))}
+}
+
+// Len returns the number of values
+func (s *Sequence[T]) Len() int { return size(s.root) }
+
+// splitAt divides the tree at n into its first i nodes and the rest
+func splitAt[T any](n *node[struct{}, T], i int) (*node[struct{}, T], *node[struct{}, T]) {
+	if n == nil {
+		return nil, nil
+	}
+
+	left := size(n.left)
+	if i <= left {
+		l, r := splitAt(n.left, i)
+		n.left = r
+		n.update()
+		return l, n
+	}
+
+	l, r := splitAt(n.right, i-left-1)
+	n.right = l
+	n.update()
+	return n, r
+}
+
+// at returns the node at position i. It panics if i is out of range.
+func (s *Sequence[T]) at(i int) *node[struct{}, T] {
+	if i < 0 || i >= s.Len() {
+		panic("treap: index out of range")
+	}
+
+	n := s.root
+	for {
+		switch left := size(n.left); {
+		case i < left:
+			n = n.left
+		case i > left:
+			i -= left + 1
+			n = n.right
+		default:
+			return n
+		}
+	}
+}
+
+// At returns the value at position i. It panics if i is out of range.
+func (s *Sequence[T]) At(i int) T {
+	return s.at(i).value
+}
+
+// Set replaces the value at position i. It panics if i is out of range.
+func (s *Sequence[T]) Set(i int, value T) {
+	s.at(i).value = value
+}
+
+// Insert inserts value at position i, shifting the values from i onwards. It
+// panics unless 0 <= i <= Len().
+func (s *Sequence[T]) Insert(i int, value T) {
+	if i < 0 || i > s.Len() {
+		panic("treap: index out of range")
+	}
+
+	l, r := splitAt(s.root, i)
+	n := &node[struct{}, T]{value: value, prio: s.rng.Int63(), size: 1}
+	s.root = merge(merge(l, n), r)
+}
+
+// Append adds value at the end of the sequence
+func (s *Sequence[T]) Append(value T) {
+	s.Insert(s.Len(), value)
+}
+
+// Delete removes the value at position i and returns it. It panics if i is
+// out of range.
+func (s *Sequence[T]) Delete(i int) T {
+	if i < 0 || i >= s.Len() {
+		panic("treap: index out of range")
+	}
+
+	l, r := splitAt(s.root, i)
+	mid, r := splitAt(r, 1)
+	s.root = merge(l, r)
+	return mid.value
+}
+
+// Split removes the values from position i onwards and returns them as a new
+// sequence. It panics unless 0 <= i <= Len(). Time complexity: O(log n)
+func (s *Sequence[T]) Split(i int) *Sequence[T] {
+	if i < 0 || i > s.Len() {
+		panic("treap: index out of range")
+	}
+
+	l, r := splitAt(s.root, i)
+	s.root = l
+	return &Sequence[T]{root: r, rng: rand.New(rand.NewSource(s.rng.Int63()))}
+}
+
+// Concat appends all values of other to s, leaving other empty.
+// Time complexity: O(log n)
+func (s *Sequence[T]) Concat(other *Sequence[T]) {
+	s.root = merge(s.root, other.root)
+	other.root = nil
+}
+
+// Each calls fn with every position and value in order until fn returns
+// false
+func (s *Sequence[T]) Each(fn func(i int, value T) bool) {
+	i := 0
+	ascend(s.root, func(_ struct{}, value T) bool {
+		ok := fn(i, value)
+		i++
+		return ok
+	})
+}
@@ -0,0 +1,81 @@
+package treap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestSequence applies random edits to a sequence and to a slice, and checks
+// that they always hold the same values
+func TestSequence(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := NewSequence[int]()
+	var want []int
+
+	for i := 0; i < 2000; i++ {
+		switch op := rng.Intn(5); {
+		case op == 0 || len(want) == 0:
+			p := rng.Intn(len(want) + 1)
+			s.Insert(p, i)
+			want = append(want[:p], append([]int{i}, want[p:]...)...)
+		case op == 1:
+			s.Append(i)
+			want = append(want, i)
+		case op == 2:
+			p := rng.Intn(len(want))
+			if got := s.Delete(p); got != want[p] {
+				t.Fatalf("Delete(%d) = %d, want %d", p, got, want[p])
+			}
+			want = append(want[:p], want[p+1:]...)
+		case op == 3:
+			p := rng.Intn(len(want))
+			s.Set(p, -i)
+			want[p] = -i
+		default:
+			// cut the sequence in two and glue it back together
+			p := rng.Intn(len(want) + 1)
+			tail := s.Split(p)
+			if s.Len() != p || tail.Len() != len(want)-p {
+				t.Fatalf("Split(%d) gave sizes %d and %d", p, s.Len(), tail.Len())
+			}
+			s.Concat(tail)
+		}
+
+		if s.Len() != len(want) {
+			t.Fatalf("got length %d, want %d", s.Len(), len(want))
+		}
+	}
+
+	for i, w := range want {
+		if got := s.At(i); got != w {
+			t.Fatalf("At(%d) = %d, want %d", i, got, w)
+		}
+	}
+	s.Each(func(i, v int) bool {
+		if v != want[i] {
+			t.Fatalf("Each gave %d at %d, want %d", v, i, want[i])
+		}
+		return true
+	})
+}
+
+func TestSequenceOutOfRange(t *testing.T) {
+	s := NewSequence[int]()
+	s.Append(1)
+
+	for name, fn := range map[string]func(){
+		"At":     func() { s.At(1) },
+		"Insert": func() { s.Insert(2, 0) },
+		"Delete": func() { s.Delete(-1) },
+		"Split":  func() { s.Split(2) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s out of range did not panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,285 @@
+// Package treap provides randomised balanced binary search trees that can be
+// split and merged in O(log n): an ordered map, and a sequence addressed by
+// position.
+package treap
+
+import (
+	"math/rand"
+
+	"github.com/hanyangtay/go-datastructures/ordered"
+)
+
+var _ ordered.Map[int, int] = (*Treap[int, int])(nil)
+
+// node is a tree node, stored in binary search tree order of its keys and in
+// heap order of its random priorities, which keeps the tree balanced with
+// high probability
+type node[K, V any] struct {
+	key         K
+	value       V
+	prio        int64
+	size        int // number of nodes in the subtree
+	left, right *node[K, V]
+}
+
+func size[K, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *node[K, V]) update() {
+	n.size = 1 + size(n.left) + size(n.right)
+}
+
+// merge joins two trees where every node of a precedes every node of b
+func merge[K, V any](a, b *node[K, V]) *node[K, V] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	if a.prio > b.prio {
+		a.right = merge(a.right, b)
+		a.update()
+		return a
+	}
+	b.left = merge(a, b.left)
+	b.update()
+	return b
+}
+
+// Treap is an ordered map from keys to values, with keys ordered by a less
+// function. Besides the usual lookups and updates in O(log n) expected time,
+// it can be split at a key and merged with a treap of greater keys in
+// O(log n), e.g. to move a key range between maps.
+type Treap[K, V any] struct {
+	root *node[K, V]
+	less func(a, b K) bool
+	rng  *rand.Rand
+}
+
+// New returns an empty treap ordered by less
+func New[K, V any](less func(a, b K) bool) *Treap[K, V] {
+	return &Treap[K, V]{less: less, rng: rand.New(rand.NewSource(1))}
+}
+
+// Len returns the number of entries
+func (t *Treap[K, V]) Len() int { return size(t.root) }
+
+// split divides the tree at n into the nodes with keys less than key and the
+// rest
+func (t *Treap[K, V]) split(n *node[K, V], key K) (*node[K, V], *node[K, V]) {
+	if n == nil {
+		return nil, nil
+	}
+
+	if t.less(n.key, key) {
+		l, r := t.split(n.right, key)
+		n.right = l
+		n.update()
+		return n, r
+	}
+	l, r := t.split(n.left, key)
+	n.left = r
+	n.update()
+	return l, n
+}
+
+// find returns the node holding key, or nil
+func (t *Treap[K, V]) find(key K) *node[K, V] {
+	for n := t.root; n != nil; {
+		switch {
+		case t.less(key, n.key):
+			n = n.left
+		case t.less(n.key, key):
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Get returns the value stored under key, and whether it was found
+func (t *Treap[K, V]) Get(key K) (V, bool) {
+	if n := t.find(key); n != nil {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores value under key, replacing any previous value
+func (t *Treap[K, V]) Put(key K, value V) {
+	if n := t.find(key); n != nil {
+		n.value = value
+		return
+	}
+
+	l, r := t.split(t.root, key)
+	n := &node[K, V]{key: key, value: value, prio: t.rng.Int63(), size: 1}
+	t.root = merge(merge(l, n), r)
+}
+
+// Delete removes key and reports whether it was present
+func (t *Treap[K, V]) Delete(key K) bool {
+	link := &t.root
+	for n := t.root; n != nil; n = *link {
+		switch {
+		case t.less(key, n.key):
+			link = &n.left
+		case t.less(n.key, key):
+			link = &n.right
+		default:
+			*link = merge(n.left, n.right)
+			t.resize(key)
+			return true
+		}
+	}
+	return false
+}
+
+// resize recomputes the sizes along the search path of key
+func (t *Treap[K, V]) resize(key K) {
+	var path []*node[K, V]
+	for n := t.root; n != nil; {
+		path = append(path, n)
+		if t.less(key, n.key) {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		path[i].update()
+	}
+}
+
+// Min returns the entry with the least key, or false if the treap is empty
+func (t *Treap[K, V]) Min() (K, V, bool) {
+	n := t.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	return entry(n)
+}
+
+// Max returns the entry with the greatest key, or false if the treap is empty
+func (t *Treap[K, V]) Max() (K, V, bool) {
+	n := t.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+	return entry(n)
+}
+
+// Floor returns the entry with the greatest key not greater than key, or
+// false if there is none
+func (t *Treap[K, V]) Floor(key K) (K, V, bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		if t.less(key, n.key) {
+			n = n.left
+		} else {
+			best = n
+			n = n.right
+		}
+	}
+	return entry(best)
+}
+
+// Ceiling returns the entry with the least key not less than key, or false
+// if there is none
+func (t *Treap[K, V]) Ceiling(key K) (K, V, bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		if t.less(n.key, key) {
+			n = n.right
+		} else {
+			best = n
+			n = n.left
+		}
+	}
+	return entry(best)
+}
+
+// Ascend calls fn for every entry in key order until fn returns false
+func (t *Treap[K, V]) Ascend(fn func(key K, value V) bool) {
+	ascend(t.root, fn)
+}
+
+// Range calls fn in key order for every entry with a key from lo up to but
+// excluding hi, until fn returns false
+func (t *Treap[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	var walk func(n *node[K, V]) bool
+	walk = func(n *node[K, V]) bool {
+		if n == nil {
+			return true
+		}
+		if t.less(n.key, lo) {
+			return walk(n.right)
+		}
+		if !t.less(n.key, hi) {
+			return walk(n.left)
+		}
+		return walk(n.left) && fn(n.key, n.value) && walk(n.right)
+	}
+	walk(t.root)
+}
+
+// Split removes the entries with keys not less than key from t and returns
+// them as a new treap. Time complexity: O(log n)
+func (t *Treap[K, V]) Split(key K) *Treap[K, V] {
+	l, r := t.split(t.root, key)
+	t.root = l
+	return &Treap[K, V]{root: r, less: t.less, rng: rand.New(rand.NewSource(t.rng.Int63()))}
+}
+
+// Merge moves all entries of other into t, leaving other empty. Every key of
+// other must be greater than every key of t; Merge panics otherwise.
+// Time complexity: O(log n)
+func (t *Treap[K, V]) Merge(other *Treap[K, V]) {
+	if t.root != nil && other.root != nil {
+		maxKey, _, _ := t.Max()
+		minKey, _, _ := other.Min()
+		if !t.less(maxKey, minKey) {
+			panic("treap: Merge of overlapping key ranges")
+		}
+	}
+
+	t.root = merge(t.root, other.root)
+	other.root = nil
+}
+
+// ascend calls fn for every node below n in order, reporting whether all
+// calls returned true
+func ascend[K, V any](n *node[K, V], fn func(key K, value V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return ascend(n.left, fn) && fn(n.key, n.value) && ascend(n.right, fn)
+}
+
+// entry unpacks n, reporting false if it is nil
+func entry[K, V any](n *node[K, V]) (K, V, bool) {
+	if n == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return n.key, n.value, true
+}
+
+// MarshalBinary encodes the tree with ordered.Marshal
+func (t *Treap[K, V]) MarshalBinary() ([]byte, error) {
+	return ordered.Marshal[K, V](t)
+}
+
+// UnmarshalBinary replaces the entries of the tree as ordered.UnmarshalInto does
+func (t *Treap[K, V]) UnmarshalBinary(data []byte) error {
+	return ordered.UnmarshalInto[K, V](t, data, func() { t.root = nil })
+}
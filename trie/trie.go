@@ -0,0 +1,171 @@
+// Package trie provides a generic prefix tree keyed by strings or byte slices.
+package trie
+
+import (
+	"slices"
+	"sort"
+)
+
+// Key is the type of trie keys
+type Key interface {
+	~string | ~[]byte
+}
+
+type node[V any] struct {
+	value V
+	ok    bool // whether a key ends here
+
+	// children in order of the byte leading to them
+	labels   []byte
+	children []*node[V]
+}
+
+// child returns the index of the child for byte b, and whether it exists
+func (n *node[V]) child(b byte) (int, bool) {
+	i := sort.Search(len(n.labels), func(i int) bool { return n.labels[i] >= b })
+	return i, i < len(n.labels) && n.labels[i] == b
+}
+
+// Trie maps keys to values, storing one node per byte of every key, with
+// keys sharing a prefix sharing the nodes of that prefix. Lookups take
+// O(len(key)) regardless of the number of keys, and the keys beginning with
+// a prefix can be listed in order.
+type Trie[K Key, V any] struct {
+	root   node[V]
+	length int
+}
+
+// New returns an empty trie
+func New[K Key, V any]() *Trie[K, V] {
+	return &Trie[K, V]{}
+}
+
+// Len returns the number of keys
+func (t *Trie[K, V]) Len() int { return t.length }
+
+// find returns the node for key, or nil if there is none
+func (t *Trie[K, V]) find(key K) *node[V] {
+	n := &t.root
+	for i := 0; i < len(key); i++ {
+		j, ok := n.child(key[i])
+		if !ok {
+			return nil
+		}
+		n = n.children[j]
+	}
+	return n
+}
+
+// Get returns the value stored under key, and whether it was found.
+// Time complexity: O(len(key))
+func (t *Trie[K, V]) Get(key K) (V, bool) {
+	if n := t.find(key); n != nil && n.ok {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores value under key, replacing any previous value.
+// Time complexity: O(len(key))
+func (t *Trie[K, V]) Put(key K, value V) {
+	n := &t.root
+	for i := 0; i < len(key); i++ {
+		j, ok := n.child(key[i])
+		if !ok {
+			n.labels = slices.Insert(n.labels, j, key[i])
+			n.children = slices.Insert(n.children, j, &node[V]{})
+		}
+		n = n.children[j]
+	}
+
+	if !n.ok {
+		t.length++
+	}
+	n.value, n.ok = value, true
+}
+
+// Delete removes key and reports whether it was present. Nodes left without
+// keys below them are removed.
+// Time complexity: O(len(key))
+func (t *Trie[K, V]) Delete(key K) bool {
+	path := make([]*node[V], 0, len(key)+1)
+	n := &t.root
+	for i := 0; i < len(key); i++ {
+		path = append(path, n)
+		j, ok := n.child(key[i])
+		if !ok {
+			return false
+		}
+		n = n.children[j]
+	}
+	if !n.ok {
+		return false
+	}
+
+	var zero V
+	n.value, n.ok = zero, false
+	t.length--
+
+	// prune the branch up to the last node still in use
+	for i := len(key) - 1; i >= 0 && !n.ok && len(n.children) == 0; i-- {
+		n = path[i]
+		j, _ := n.child(key[i])
+		n.labels = slices.Delete(n.labels, j, j+1)
+		n.children = slices.Delete(n.children, j, j+1)
+	}
+	return true
+}
+
+// LongestPrefix returns the longest key that is a prefix of key, with its
+// value, or false if there is none, e.g. the most specific route for a path.
+// Time complexity: O(len(key))
+func (t *Trie[K, V]) LongestPrefix(key K) (K, V, bool) {
+	n := &t.root
+	best, found := n, n.ok
+	length := 0
+	for i := 0; i < len(key); i++ {
+		j, ok := n.child(key[i])
+		if !ok {
+			break
+		}
+		n = n.children[j]
+		if n.ok {
+			best, found, length = n, true, i+1
+		}
+	}
+
+	if !found {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return K(string(key[:length])), best.value, true
+}
+
+// WalkPrefix calls fn in key order for every key beginning with prefix,
+// until fn returns false. The key passed to fn may be retained.
+// Time complexity: O(len(prefix) + size of the subtree visited)
+func (t *Trie[K, V]) WalkPrefix(prefix K, fn func(key K, value V) bool) {
+	if n := t.find(prefix); n != nil {
+		walk(n, []byte(string(prefix)), fn)
+	}
+}
+
+// Ascend calls fn for every key in order until fn returns false
+func (t *Trie[K, V]) Ascend(fn func(key K, value V) bool) {
+	walk(&t.root, nil, fn)
+}
+
+// walk visits in key order the keys below n, whose path spells key
+func walk[K Key, V any](n *node[V], key []byte, fn func(key K, value V) bool) bool {
+	if n.ok && !fn(K(string(key)), n.value) {
+		return false
+	}
+	for i, c := range n.children {
+		if !walk(c, append(key, n.labels[i]), fn) {
+			return false
+		}
+	}
+	return true
+}
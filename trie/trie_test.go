@@ -0,0 +1,112 @@
+package trie
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// randomKey returns a short key over a small alphabet, so that keys share
+// prefixes
+func randomKey(rng *rand.Rand) string {
+	b := make([]byte, rng.Intn(6))
+	for i := range b {
+		b[i] = "abc"[rng.Intn(3)]
+	}
+	return string(b)
+}
+
+// TestAgainstMap applies random puts and deletes to a trie and to a
+// built-in map, then checks every query of the trie against the map
+func TestAgainstMap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	m := New[string, int]()
+	want := make(map[string]int)
+
+	for i := 0; i < 2000; i++ {
+		k := randomKey(rng)
+		if rng.Intn(3) == 0 {
+			_, ok := want[k]
+			if got := m.Delete(k); got != ok {
+				t.Fatalf("Delete(%q) = %v, want %v", k, got, ok)
+			}
+			delete(want, k)
+		} else {
+			m.Put(k, i)
+			want[k] = i
+		}
+		if m.Len() != len(want) {
+			t.Fatalf("got length %d, want %d", m.Len(), len(want))
+		}
+	}
+
+	keys := make([]string, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var got []string
+	m.Ascend(func(k string, v int) bool {
+		if v != want[k] {
+			t.Fatalf("Ascend gave %d for %q, want %d", v, k, want[k])
+		}
+		got = append(got, k)
+		return true
+	})
+	if strings.Join(got, ",") != strings.Join(keys, ",") {
+		t.Fatalf("Ascend gave %q, want %q", got, keys)
+	}
+
+	for i := 0; i < 300; i++ {
+		k := randomKey(rng) + randomKey(rng)
+
+		v, ok := m.Get(k)
+		if w, wok := want[k]; ok != wok || v != w {
+			t.Fatalf("Get(%q) = %d, %v, want %d, %v", k, v, ok, w, wok)
+		}
+
+		longest, found := "", false
+		for _, p := range keys {
+			if strings.HasPrefix(k, p) && len(p) >= len(longest) {
+				longest, found = p, true
+			}
+		}
+		if p, v, ok := m.LongestPrefix(k); ok != found || p != longest || (ok && v != want[p]) {
+			t.Fatalf("LongestPrefix(%q) = %q, %v, want %q, %v", k, p, ok, longest, found)
+		}
+
+		prefix := k[:len(k)/2]
+		var walked, wantWalked []string
+		m.WalkPrefix(prefix, func(k string, _ int) bool {
+			walked = append(walked, k)
+			return true
+		})
+		for _, p := range keys {
+			if strings.HasPrefix(p, prefix) {
+				wantWalked = append(wantWalked, p)
+			}
+		}
+		if strings.Join(walked, ",") != strings.Join(wantWalked, ",") {
+			t.Fatalf("WalkPrefix(%q) gave %q, want %q", prefix, walked, wantWalked)
+		}
+	}
+}
+
+func TestByteKeys(t *testing.T) {
+	m := New[[]byte, int]()
+	m.Put([]byte("ab"), 1)
+	m.Put([]byte("abc"), 2)
+	m.Put([]byte{}, 0)
+
+	if v, ok := m.Get([]byte("abc")); !ok || v != 2 {
+		t.Fatalf("Get(abc) = %d, %v, want 2", v, ok)
+	}
+	if k, v, ok := m.LongestPrefix([]byte("abd")); !ok || string(k) != "ab" || v != 1 {
+		t.Fatalf("LongestPrefix(abd) = %q, %d, %v, want ab", k, v, ok)
+	}
+	if k, _, ok := m.LongestPrefix([]byte("x")); !ok || len(k) != 0 {
+		t.Fatalf("LongestPrefix(x) = %q, %v, want the empty key", k, ok)
+	}
+}
@@ -0,0 +1,163 @@
+// Package vptree provides a vantage-point tree for nearest neighbour search
+// in any metric space.
+package vptree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/hanyangtay/go-datastructures/topk"
+)
+
+// Neighbor is an item returned by a query, with its distance to the query
+type Neighbor[T any] struct {
+	Item T
+	Dist float64
+}
+
+// node partitions the items of its subtree around a vantage point: those
+// nearer to it than mu go inside, the others outside
+type node[T any] struct {
+	item            T
+	mu              float64
+	inside, outside *node[T]
+}
+
+// Tree is a vantage-point tree over items compared only through a distance
+// function, which must be symmetric and satisfy the triangle inequality,
+// such as the edit distance between strings or a precomputed dissimilarity
+// matrix indexed by item. Every node splits the remaining items at the
+// median distance from a randomly chosen vantage point. The tree is static;
+// build a new one to change its items.
+type Tree[T any] struct {
+	root   *node[T]
+	dist   func(a, b T) float64
+	length int
+}
+
+// Build returns a tree of items under dist. The items slice is reordered.
+// Time complexity: O(n log n) distance computations
+func Build[T any](items []T, dist func(a, b T) float64) *Tree[T] {
+	t := &Tree[T]{dist: dist, length: len(items)}
+	rng := rand.New(rand.NewSource(1))
+	d := make([]float64, len(items))
+	t.root = t.build(items, d, rng)
+	return t
+}
+
+func (t *Tree[T]) build(items []T, d []float64, rng *rand.Rand) *node[T] {
+	if len(items) == 0 {
+		return nil
+	}
+
+	i := rng.Intn(len(items))
+	items[0], items[i] = items[i], items[0]
+	n := &node[T]{item: items[0]}
+
+	rest, d := items[1:], d[1:]
+	if len(rest) == 0 {
+		return n
+	}
+	for i, item := range rest {
+		d[i] = t.dist(n.item, item)
+	}
+	sort.Sort(byDist[T]{rest, d})
+
+	mid := len(rest) / 2
+	n.mu = d[mid]
+	n.inside = t.build(rest[:mid], d[:mid], rng)
+	n.outside = t.build(rest[mid:], d[mid:], rng)
+	return n
+}
+
+type byDist[T any] struct {
+	items []T
+	dist  []float64
+}
+
+func (s byDist[T]) Len() int           { return len(s.items) }
+func (s byDist[T]) Less(i, j int) bool { return s.dist[i] < s.dist[j] }
+func (s byDist[T]) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+	s.dist[i], s.dist[j] = s.dist[j], s.dist[i]
+}
+
+// Len returns the number of items
+func (t *Tree[T]) Len() int { return t.length }
+
+// slack widens the search bounds by a relative margin, since rounding can
+// break the triangle inequality for floating point distances
+const slack = 1e-9
+
+// KNN returns the k items nearest to q, nearest first
+func (t *Tree[T]) KNN(q T, k int) []Neighbor[T] {
+	if k < 1 {
+		return nil
+	}
+
+	best := topk.New(k, func(a, b Neighbor[T]) bool { return a.Dist > b.Dist })
+	tau := math.Inf(1)
+
+	var search func(n *node[T])
+	search = func(n *node[T]) {
+		if n == nil {
+			return
+		}
+
+		d := t.dist(q, n.item)
+		if best.Add(Neighbor[T]{n.item, d}) && best.Full() {
+			tau = best.Min().Dist
+		}
+
+		// inside holds distances from the vantage point up to mu, outside
+		// from mu on; search the side of q first
+		margin := func() float64 { return tau + slack*(d+n.mu) }
+		if d < n.mu {
+			if d-margin() <= n.mu {
+				search(n.inside)
+			}
+			if d+margin() >= n.mu {
+				search(n.outside)
+			}
+		} else {
+			if d+margin() >= n.mu {
+				search(n.outside)
+			}
+			if d-margin() <= n.mu {
+				search(n.inside)
+			}
+		}
+	}
+	search(t.root)
+
+	return best.Result()
+}
+
+// Radius returns all items within distance r of q, nearest first
+func (t *Tree[T]) Radius(q T, r float64) []Neighbor[T] {
+	var result []Neighbor[T]
+
+	var search func(n *node[T])
+	search = func(n *node[T]) {
+		if n == nil {
+			return
+		}
+
+		d := t.dist(q, n.item)
+		if d <= r {
+			result = append(result, Neighbor[T]{n.item, d})
+		}
+		margin := r + slack*(d+n.mu)
+		if d-margin <= n.mu {
+			search(n.inside)
+		}
+		if d+margin >= n.mu {
+			search(n.outside)
+		}
+	}
+	search(t.root)
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Dist < result[j].Dist })
+	return result
+}
@@ -0,0 +1,79 @@
+package vptree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) float64 {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return float64(prev[len(b)])
+}
+
+func randomWord(rng *rand.Rand) string {
+	b := make([]byte, 3+rng.Intn(6))
+	for i := range b {
+		b[i] = "abcde"[rng.Intn(5)]
+	}
+	return string(b)
+}
+
+// TestQueries compares KNN and Radius over words under edit distance
+// against the distances of all words
+func TestQueries(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	words := make([]string, 400)
+	for i := range words {
+		words[i] = randomWord(rng)
+	}
+	tr := Build(append([]string(nil), words...), editDistance)
+	if tr.Len() != len(words) {
+		t.Fatalf("got length %d, want %d", tr.Len(), len(words))
+	}
+
+	for i := 0; i < 50; i++ {
+		q := randomWord(rng)
+		want := make([]float64, len(words))
+		for j, w := range words {
+			want[j] = editDistance(q, w)
+		}
+		sort.Float64s(want)
+
+		got := tr.KNN(q, 10)
+		if len(got) != 10 {
+			t.Fatalf("KNN(%q) returned %d items, want 10", q, len(got))
+		}
+		for j, n := range got {
+			if n.Dist != want[j] || editDistance(q, n.Item) != n.Dist {
+				t.Fatalf("KNN(%q)[%d] = %q at distance %v, want %v", q, j, n.Item, n.Dist, want[j])
+			}
+		}
+
+		within := tr.Radius(q, 2)
+		if n := sort.SearchFloat64s(want, 2.5); len(within) != n {
+			t.Fatalf("Radius(%q, 2) returned %d items, want %d", q, len(within), n)
+		}
+		for j := 1; j < len(within); j++ {
+			if within[j].Dist < within[j-1].Dist {
+				t.Fatalf("Radius(%q, 2) returned items out of order", q)
+			}
+		}
+	}
+}